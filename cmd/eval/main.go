@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	"github.com/loukaspe/rag-golang/pkg/chunks"
+	"github.com/loukaspe/rag-golang/pkg/embeddings"
+	"github.com/loukaspe/rag-golang/pkg/evaluation"
+	"github.com/loukaspe/rag-golang/pkg/llm"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/vectordb"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// eval runs a golden question set through the retrieval path of the
+// MessageService and reports recall@k, MRR and nDCG@k. The retrieval runs fully
+// offline: the corpus is chunked with a word tokenizer, embedded with the local
+// embedder and stored in an in-memory vector store.
+//
+// With -answers every question also goes through GetAnswerForMessage and the
+// answer is graded for faithfulness and relevance by an LLM judge. This needs
+// OPENAI_API_KEY. With -baseline the report is diffed against a previous one
+// and regressions are highlighted.
+func main() {
+	ctx := context.Background()
+
+	// the env file is optional here, it only provides defaults for the flags
+	_ = godotenv.Load("./config/.env")
+
+	goldenPath := flag.String("golden", "", "path to the golden question set (.yaml, .yml or .jsonl)")
+	corpus := flag.String("corpus", "./dataVehicles.md", "comma separated list of knowledge base files")
+	k := flag.Int("k", envInt("TOP_K_RESULTS_NUMBER", 7), "number of chunks to retrieve")
+	threshold := flag.Float64("threshold", envFloat("SIMILARITY_SEARCH_THRESHOLD", 0.35), "similarity search threshold")
+	maxTokensPerChunk := flag.Int("max-tokens", envInt("MAX_TOKENS_PER_CHUNKS", 3000), "max tokens per chunk")
+	dimensions := flag.Int("dimensions", 512, "dimensions of the local embeddings")
+	answers := flag.Bool("answers", false, "generate and grade answers with an LLM judge")
+	judgeModel := flag.String("judge-model", string(openai.ChatModelGPT4_1Mini), "model of the LLM judge")
+	baselinePath := flag.String("baseline", "", "path to a previous JSON report to compare against")
+	tolerance := flag.Float64("tolerance", 0.05, "drop of a metric that counts as a regression")
+	failOnRegression := flag.Bool("fail-on-regression", false, "exit with status 1 when a regression is found")
+	out := flag.String("out", "eval-report.json", "path of the JSON report")
+	flag.Parse()
+
+	if *goldenPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	questions, err := evaluation.LoadGoldenSet(*goldenPath)
+	if err != nil {
+		log.Fatalf("Cannot load golden set: %v", err)
+	}
+
+	chunker, err := chunks.NewChunker(chunks.NewWordEncoder(), *maxTokensPerChunk)
+	if err != nil {
+		log.Fatal("Cannot create chunker: ", err)
+	}
+
+	embedder := embeddings.NewLocalEmbeddingService(*dimensions)
+	vectorDB := vectordb.NewMemoryVectorDB(float32(*threshold), *k)
+
+	corpusFiles := strings.Split(*corpus, ",")
+	for _, corpusFile := range corpusFiles {
+		inputKnowledgeBase(ctx, corpusFile, chunker, embedder, vectorDB)
+	}
+
+	var chatProvider services.ChatProvider
+	var judge *evaluation.Judge
+	if *answers {
+		chatProvider = llm.NewClient(llm.DefaultClientConfig(), option.WithAPIKey(os.Getenv("OPENAI_API_KEY")))
+		judge = evaluation.NewJudge(chatProvider, openai.ChatModel(*judgeModel))
+	}
+
+	store := newMemoryStore()
+	usageService := services.NewUsageService(logger.NewLogger(ctx), store, domain.UsageQuota{})
+	messageService := services.NewMessageService(logger.NewLogger(ctx), store, store, store, embedder, vectorDB, chatProvider, usageService, nil, services.DefaultAgentMaxSteps, nil)
+
+	results := make([]*evaluation.QuestionResult, 0, len(questions))
+	for _, question := range questions {
+		result, err := evaluateQuestion(ctx, messageService, store, judge, question, *k)
+		if err != nil {
+			log.Fatalf("Cannot evaluate question %s: %v", question.ID, err)
+		}
+
+		results = append(results, result)
+	}
+
+	config := evaluation.ReportConfig{
+		K:                 *k,
+		Threshold:         float32(*threshold),
+		MaxTokensPerChunk: *maxTokensPerChunk,
+		Embedder:          fmt.Sprintf("local-%d", *dimensions),
+		Corpus:            corpusFiles,
+	}
+	if judge != nil {
+		config.JudgeModel = *judgeModel
+	}
+
+	report := evaluation.NewReport(config, results)
+
+	err = report.WriteTable(os.Stdout)
+	if err != nil {
+		log.Fatalf("Cannot write report table: %v", err)
+	}
+
+	if *baselinePath != "" {
+		baseline, err := evaluation.LoadReport(*baselinePath)
+		if err != nil {
+			log.Fatalf("Cannot load baseline report: %v", err)
+		}
+
+		report.Comparison = evaluation.Compare(baseline, report, *tolerance)
+
+		fmt.Printf("\nComparison with %s\n\n", *baselinePath)
+		err = report.Comparison.WriteTable(os.Stdout)
+		if err != nil {
+			log.Fatalf("Cannot write comparison table: %v", err)
+		}
+	}
+
+	err = report.WriteJSON(*out)
+	if err != nil {
+		log.Fatalf("Cannot write JSON report: %v", err)
+	}
+
+	fmt.Printf("\nJSON report written to %s\n", *out)
+
+	if *failOnRegression && report.Comparison != nil && len(report.Comparison.Regressions()) > 0 {
+		os.Exit(1)
+	}
+}
+
+// evaluateQuestion scores the retrieval of the question and, when a judge is
+// given, asks the question in a new chat session and grades the answer.
+func evaluateQuestion(
+	ctx context.Context,
+	messageService *services.MessageService,
+	store *memoryStore,
+	judge *evaluation.Judge,
+	question *evaluation.GoldenQuestion,
+	k int,
+) (*evaluation.QuestionResult, error) {
+	result := &evaluation.QuestionResult{
+		ID:       question.ID,
+		Question: question.Question,
+	}
+
+	if judge == nil {
+		retrievedChunks, err := messageService.RetrieveContext(ctx, question.Question)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Retrieval = evaluation.ScoreRetrieval(question, retrievedChunks, k)
+
+		return result, nil
+	}
+
+	userID := uuid.New()
+
+	// the title is set so that no title generation request is made
+	chatSessionID, err := store.CreateChatSession(ctx, &domain.ChatSession{
+		UserID: userID,
+		Title:  question.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := messageService.CreateMessage(ctx, userID, &domain.Message{
+		ChatSessionID: chatSessionID,
+		Content:       question.Question,
+		Sender:        repositories.USER_SENDER,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	replyMessage, err := messageService.GetAnswerForMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Retrieval = evaluation.ScoreRetrieval(question, replyMessage.Sources, k)
+
+	contexts := make([]string, len(replyMessage.Sources))
+	for i, source := range replyMessage.Sources {
+		contexts[i] = source.Text
+	}
+
+	result.Answer, err = judge.Grade(ctx, question.Question, contexts, replyMessage.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func inputKnowledgeBase(ctx context.Context, path string, chunker *chunks.Chunker, embedder *embeddings.LocalEmbeddingService, vectorDB *vectordb.MemoryVectorDB) {
+	textBytes, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	domainEmbeddings, err := embedder.Embed(ctx, chunker.Chunk(string(textBytes)))
+	if err != nil {
+		log.Fatalf("Embedding error: %v", err)
+	}
+
+	_, err = vectorDB.StoreEmbeddings(ctx, domainEmbeddings, map[string]interface{}{
+		"source": path,
+	})
+	if err != nil {
+		log.Fatalf("Failed to store embeddings: %v", err)
+	}
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+func envFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps chat sessions and messages in memory so that the answer
+// evaluation can go through MessageService.GetAnswerForMessage without
+// Postgres. It implements ports.ChatSessionRepositoryInterface,
+// ports.MessageRepositoryInterface, ports.CuratedAnswerRepositoryInterface and
+// ports.UsageRepositoryInterface.
+type memoryStore struct {
+	mu             sync.Mutex
+	chatSessions   map[uuid.UUID]*domain.ChatSession
+	messages       map[uuid.UUID]*domain.Message
+	curatedAnswers map[uuid.UUID]*domain.CuratedAnswer
+	tokenUsages    []*domain.TokenUsage
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		chatSessions:   map[uuid.UUID]*domain.ChatSession{},
+		messages:       map[uuid.UUID]*domain.Message{},
+		curatedAnswers: map[uuid.UUID]*domain.CuratedAnswer{},
+	}
+}
+
+func (m *memoryStore) GetChatSession(ctx context.Context, id uuid.UUID) (*domain.ChatSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[id]
+	if !ok {
+		return &domain.ChatSession{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + id.String() + " not found"),
+		}
+	}
+
+	copied := *chatSession
+	copied.Messages = append([]*domain.Message{}, chatSession.Messages...)
+
+	return &copied, nil
+}
+
+func (m *memoryStore) GetUserChatSessionSummaries(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter *domain.ChatSessionFilter,
+) ([]*domain.ChatSessionSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var summaries []*domain.ChatSessionSummary
+	for _, chatSession := range m.chatSessions {
+		if chatSession.UserID != userID || !chatSessionMatches(chatSession, filter) {
+			continue
+		}
+
+		summary := &domain.ChatSessionSummary{
+			ID:           chatSession.ID,
+			UserID:       chatSession.UserID,
+			Title:        chatSession.Title,
+			CreatedAt:    chatSession.CreatedAt,
+			UpdatedAt:    chatSession.UpdatedAt,
+			ArchivedAt:   chatSession.ArchivedAt,
+			MessageCount: len(chatSession.Messages),
+		}
+		if len(chatSession.Messages) > 0 {
+			lastMessage := *chatSession.Messages[len(chatSession.Messages)-1]
+			if runes := []rune(lastMessage.Content); len(runes) > domain.ChatSessionPreviewLength {
+				lastMessage.Content = string(runes[:domain.ChatSessionPreviewLength])
+			}
+			summary.LastMessage = &lastMessage
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return chatSessionBefore(summaries[i].UpdatedAt, summaries[i].ID, summaries[j].UpdatedAt, summaries[j].ID) == filter.Ascending
+	})
+
+	if filter.Limit > 0 && len(summaries) > filter.Limit {
+		summaries = summaries[:filter.Limit]
+	}
+
+	return summaries, nil
+}
+
+// chatSessionMatches tells whether the chat session is listed by the filter,
+// like the where clause of the Postgres repository
+func chatSessionMatches(chatSession *domain.ChatSession, filter *domain.ChatSessionFilter) bool {
+	if filter.Archived != nil && *filter.Archived != (chatSession.ArchivedAt != nil) {
+		return false
+	}
+	if filter.UpdatedFrom != nil && chatSession.UpdatedAt.Before(*filter.UpdatedFrom) {
+		return false
+	}
+	if filter.UpdatedTo != nil && !chatSession.UpdatedAt.Before(*filter.UpdatedTo) {
+		return false
+	}
+	if filter.After == nil {
+		return true
+	}
+
+	if filter.Ascending {
+		return chatSessionBefore(filter.After.UpdatedAt, filter.After.ID, chatSession.UpdatedAt, chatSession.ID)
+	}
+
+	return chatSessionBefore(chatSession.UpdatedAt, chatSession.ID, filter.After.UpdatedAt, filter.After.ID)
+}
+
+func chatSessionBefore(updatedAt time.Time, id uuid.UUID, otherUpdatedAt time.Time, otherID uuid.UUID) bool {
+	if !updatedAt.Equal(otherUpdatedAt) {
+		return updatedAt.Before(otherUpdatedAt)
+	}
+
+	return bytes.Compare(id[:], otherID[:]) < 0
+}
+
+func (m *memoryStore) GetChatSessionUserID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[id]
+	if !ok {
+		return uuid.Nil, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + id.String() + " not found"),
+		}
+	}
+
+	return chatSession.UserID, nil
+}
+
+func (m *memoryStore) CreateChatSession(ctx context.Context, chatSession *domain.ChatSession) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	stored := &domain.ChatSession{
+		ID:        uuid.New(),
+		UserID:    chatSession.UserID,
+		Title:     chatSession.Title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.chatSessions[stored.ID] = stored
+
+	return stored.ID, nil
+}
+
+func (m *memoryStore) UpdateChatSessionTitle(ctx context.Context, id uuid.UUID, title string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[id]
+	if !ok {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + id.String() + " not found"),
+		}
+	}
+
+	chatSession.Title = title
+	chatSession.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (m *memoryStore) UpdateChatSessionArchivedAt(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[id]
+	if !ok {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + id.String() + " not found"),
+		}
+	}
+
+	chatSession.ArchivedAt = archivedAt
+
+	return nil
+}
+
+// DeleteChatSession deletes the chat session and its messages at once, there
+// is nothing to purge later in memory
+func (m *memoryStore) DeleteChatSession(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[id]
+	if !ok {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + id.String() + " not found"),
+		}
+	}
+
+	for _, message := range chatSession.Messages {
+		delete(m.messages, message.ID)
+	}
+	delete(m.chatSessions, id)
+
+	return nil
+}
+
+func (m *memoryStore) PurgeChatSessions(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryStore) CreateMessage(ctx context.Context, message *domain.Message) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[message.ChatSessionID]
+	if !ok {
+		return uuid.Nil, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + message.ChatSessionID.String() + " not found"),
+		}
+	}
+
+	stored := *message
+	stored.ID = uuid.New()
+	stored.CreatedAt = time.Now()
+
+	m.messages[stored.ID] = &stored
+	chatSession.Messages = append(chatSession.Messages, &stored)
+	chatSession.UpdatedAt = stored.CreatedAt
+
+	return stored.ID, nil
+}
+
+func (m *memoryStore) GetMessage(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	message, ok := m.messages[id]
+	if !ok {
+		return &domain.Message{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("messageID " + id.String() + " not found"),
+		}
+	}
+
+	copied := *message
+
+	return &copied, nil
+}
+
+// GetChatSessionMessages pages through the messages of the chat session in
+// the order they were created in
+func (m *memoryStore) GetChatSessionMessages(
+	ctx context.Context,
+	chatSessionID uuid.UUID,
+	filter *domain.MessageFilter,
+) ([]*domain.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chatSession, ok := m.chatSessions[chatSessionID]
+	if !ok {
+		return []*domain.Message{}, nil
+	}
+
+	cursorID := filter.Before
+	if filter.After != nil {
+		cursorID = filter.After
+	}
+
+	start, end := 0, len(chatSession.Messages)
+	if cursorID != nil {
+		position := -1
+		for i, message := range chatSession.Messages {
+			if message.ID == *cursorID {
+				position = i
+			}
+		}
+		if position < 0 {
+			return []*domain.Message{}, customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("messageID " + cursorID.String() + " not found in chatSession " + chatSessionID.String()),
+			}
+		}
+
+		if filter.After != nil {
+			start = position + 1
+		} else {
+			end = position
+		}
+	}
+
+	if filter.After != nil {
+		end = min(end, start+filter.Limit)
+	} else {
+		start = max(start, end-filter.Limit)
+	}
+
+	return append([]*domain.Message{}, chatSession.Messages[start:end]...), nil
+}
+
+func (m *memoryStore) UpdateMessageFeedback(ctx context.Context, id uuid.UUID, feedback *domain.MessageFeedback) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	message, ok := m.messages[id]
+	if !ok {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("messageID " + id.String() + " not found"),
+		}
+	}
+
+	message.Feedback = feedback
+
+	return nil
+}
+
+func (m *memoryStore) CreateCuratedAnswer(ctx context.Context, curatedAnswer *domain.CuratedAnswer) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *curatedAnswer
+	stored.ID = uuid.New()
+	stored.Status = domain.CuratedAnswerStatusPending
+	stored.CreatedAt = time.Now()
+
+	m.curatedAnswers[stored.ID] = &stored
+
+	return stored.ID, nil
+}
+
+func (m *memoryStore) GetCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	curatedAnswer, ok := m.curatedAnswers[id]
+	if !ok {
+		return &domain.CuratedAnswer{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("curatedAnswerID " + id.String() + " not found"),
+		}
+	}
+
+	copied := *curatedAnswer
+
+	return &copied, nil
+}
+
+func (m *memoryStore) GetCuratedAnswers(ctx context.Context, status string) ([]*domain.CuratedAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var curatedAnswers []*domain.CuratedAnswer
+	for _, curatedAnswer := range m.curatedAnswers {
+		if status == "" || curatedAnswer.Status == status {
+			curatedAnswers = append(curatedAnswers, curatedAnswer)
+		}
+	}
+
+	return curatedAnswers, nil
+}
+
+func (m *memoryStore) UpdateCuratedAnswerStatus(ctx context.Context, id uuid.UUID, status string, vectorID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	curatedAnswer, ok := m.curatedAnswers[id]
+	if !ok {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("curatedAnswerID " + id.String() + " not found"),
+		}
+	}
+
+	reviewedAt := time.Now()
+	curatedAnswer.Status = status
+	curatedAnswer.VectorID = vectorID
+	curatedAnswer.ReviewedAt = &reviewedAt
+
+	return nil
+}
+
+func (m *memoryStore) CreateTokenUsage(ctx context.Context, usage *domain.TokenUsage) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *usage
+	stored.ID = uuid.New()
+
+	m.tokenUsages = append(m.tokenUsages, &stored)
+
+	return stored.ID, nil
+}
+
+func (m *memoryStore) GetUserUsage(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]*domain.UsageAggregate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var aggregates []*domain.UsageAggregate
+	for _, usage := range m.tokenUsages {
+		if usage.UserID != userID || usage.CreatedAt.Before(from) || !usage.CreatedAt.Before(to) {
+			continue
+		}
+
+		period := domain.StartOfMonth(usage.CreatedAt)
+		if interval == domain.UsageIntervalDay {
+			period = usage.CreatedAt.UTC().Truncate(24 * time.Hour)
+		}
+
+		if len(aggregates) == 0 || !aggregates[len(aggregates)-1].Period.Equal(period) {
+			aggregates = append(aggregates, &domain.UsageAggregate{Period: period})
+		}
+
+		addUsage(aggregates[len(aggregates)-1], usage)
+	}
+
+	return aggregates, nil
+}
+
+func (m *memoryStore) GetUserUsageTotal(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.UsageAggregate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total := &domain.UsageAggregate{Period: from}
+	for _, usage := range m.tokenUsages {
+		if usage.UserID == userID && !usage.CreatedAt.Before(from) && usage.CreatedAt.Before(to) {
+			addUsage(total, usage)
+		}
+	}
+
+	return total, nil
+}
+
+func addUsage(aggregate *domain.UsageAggregate, usage *domain.TokenUsage) {
+	aggregate.Messages++
+	aggregate.PromptTokens += usage.PromptTokens
+	aggregate.CompletionTokens += usage.CompletionTokens
+	aggregate.EmbeddingTokens += usage.EmbeddingTokens
+}
@@ -3,13 +3,15 @@ package main
 import (
 	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"github.com/loukaspe/rag-golang/internal/repositories"
 	"github.com/loukaspe/rag-golang/pkg/chunks"
 	"github.com/loukaspe/rag-golang/pkg/embeddings"
+	"github.com/loukaspe/rag-golang/pkg/llm"
 	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/mcpclient"
 	http2 "github.com/loukaspe/rag-golang/pkg/server/http"
 	"github.com/loukaspe/rag-golang/pkg/server/mcp"
 	"github.com/loukaspe/rag-golang/pkg/vectordb"
@@ -24,6 +26,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
 func main() {
@@ -33,7 +36,7 @@ func main() {
 	//encoder := getEncoder()
 	client := getOpenAIClient()
 	//chunker := getChunker(encoder)
-	embedder := getEmbedder(&client)
+	embedder := getEmbedder(client)
 	pineconeVectorDB := getPineconeVectorDB()
 
 	//inputPeopleKnowledgeBase(ctx, chunker, embedder, pineconeVectorDB)
@@ -49,13 +52,29 @@ func main() {
 	mcpServer := mcp.NewServer(server.NewMCPServer(
 		os.Getenv("MCP_SERVER_NAME"),
 		os.Getenv("MCP_SERVER_VERSION"),
-	))
+	), logger)
 
-	server := http2.NewServer(db, router, httpServer, mcpServer, logger, &client, embedder, pineconeVectorDB)
+	externalTools := getExternalTools(ctx, logger)
+
+	server := http2.NewServer(db, router, httpServer, mcpServer, logger, client, embedder, pineconeVectorDB, externalTools)
 
 	server.Run()
 }
 
+// getExternalTools connects to the external MCP servers of
+// EXTERNAL_MCP_SERVERS, whose allowed tools the agent answer mode may call
+func getExternalTools(ctx context.Context, logger logger.LoggerInterface) *mcpclient.ToolSource {
+	configs, err := mcpclient.ParseServerConfigs(os.Getenv("EXTERNAL_MCP_SERVERS"))
+	if err != nil {
+		log.Fatal("Cannot read EXTERNAL_MCP_SERVERS: ", err)
+	}
+
+	externalTools := mcpclient.NewToolSource(logger)
+	externalTools.Connect(ctx, configs)
+
+	return externalTools
+}
+
 func getDB() *gorm.DB {
 	dbDsn := fmt.Sprintf(
 		"host=%s port=%s user=%s dbname=%s sslmode=disable password=%s TimeZone=Europe/Athens",
@@ -77,7 +96,15 @@ func getDB() *gorm.DB {
 
 	// Drops added in order to start with clean DB on App start for
 	// assessment reasons
+	db.Migrator().DropTable("token_usages")
+	db.Migrator().DropTable("api_keys")
+	db.Migrator().DropTable("revoked_tokens")
+	db.Migrator().DropTable("refresh_tokens")
 	db.Migrator().DropTable("users")
+	db.Migrator().DropTable("curated_answers")
+	db.Migrator().DropTable("vehicle_comparisons")
+	db.Migrator().DropTable("message_tool_calls")
+	db.Migrator().DropTable("message_sources")
 	db.Migrator().DropTable("messages")
 	db.Migrator().DropTable("chat_sessions")
 
@@ -86,17 +113,19 @@ func getDB() *gorm.DB {
 		log.Fatal("cannot migrate user table")
 	}
 
-	// hardcoded user just for testing purposes
-	admin := repositories.User{
-		ID:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
-		Username: "loukas",
-		Password: "loukastest",
+	err = db.AutoMigrate(&repositories.RefreshToken{})
+	if err != nil {
+		log.Fatal("cannot migrate refresh tokens table")
+	}
+
+	err = db.AutoMigrate(&repositories.RevokedToken{})
+	if err != nil {
+		log.Fatal("cannot migrate revoked tokens table")
 	}
 
-	fmt.Printf("Seeding user: %s\n", admin.ID)
-	err = db.Debug().Model(&repositories.User{}).Create(&admin).Error
+	err = db.AutoMigrate(&repositories.APIKey{})
 	if err != nil {
-		log.Fatalf("cannot seed users table: %v", err)
+		log.Fatal("cannot migrate api keys table")
 	}
 
 	err = db.AutoMigrate(&repositories.ChatSession{})
@@ -109,6 +138,31 @@ func getDB() *gorm.DB {
 		log.Fatal("cannot migrate messages table")
 	}
 
+	err = db.AutoMigrate(&repositories.MessageSource{})
+	if err != nil {
+		log.Fatal("cannot migrate message sources table")
+	}
+
+	err = db.AutoMigrate(&repositories.MessageToolCall{})
+	if err != nil {
+		log.Fatal("cannot migrate message tool calls table")
+	}
+
+	err = db.AutoMigrate(&repositories.CuratedAnswer{})
+	if err != nil {
+		log.Fatal("cannot migrate curated answers table")
+	}
+
+	err = db.AutoMigrate(&repositories.TokenUsage{})
+	if err != nil {
+		log.Fatal("cannot migrate token usages table")
+	}
+
+	err = db.AutoMigrate(&repositories.VehicleComparison{})
+	if err != nil {
+		log.Fatal("cannot migrate vehicle comparisons table")
+	}
+
 	return db
 }
 
@@ -145,12 +199,51 @@ func getChunker(encoder *tiktoken.Tiktoken) *chunks.Chunker {
 	return chunker
 }
 
-func getEmbedder(client *openai.Client) *embeddings.EmbeddingService {
+func getEmbedder(client *llm.Client) *embeddings.EmbeddingService {
 	return embeddings.NewEmbeddingService(client, openai.EmbeddingModel(os.Getenv("EMBEDDING_MODEL")))
 }
 
-func getOpenAIClient() openai.Client {
-	return openai.NewClient(option.WithAPIKey(os.Getenv("OPENAI_API_KEY")))
+// getOpenAIClient creates the client shared by the embeddings and the chat
+// completions. The OPENAI_* envs override the defaults of its retries,
+// concurrency and circuit breaker.
+func getOpenAIClient() *llm.Client {
+	config := llm.DefaultClientConfig()
+
+	config.MaxConcurrency = getIntEnv("OPENAI_MAX_CONCURRENCY", config.MaxConcurrency)
+	config.MaxRetries = getIntEnv("OPENAI_MAX_RETRIES", config.MaxRetries)
+	config.RequestTimeout = getDurationEnv("OPENAI_REQUEST_TIMEOUT", config.RequestTimeout)
+	config.FailureThreshold = getIntEnv("OPENAI_CIRCUIT_BREAKER_THRESHOLD", config.FailureThreshold)
+	config.OpenDuration = getDurationEnv("OPENAI_CIRCUIT_BREAKER_OPEN_DURATION", config.OpenDuration)
+
+	return llm.NewClient(config, option.WithAPIKey(os.Getenv("OPENAI_API_KEY")))
+}
+
+func getIntEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Cannot read %s: %v", name, err)
+	}
+
+	return number
+}
+
+func getDurationEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Cannot read %s: %v", name, err)
+	}
+
+	return duration
 }
 
 func getPineconeVectorDB() *vectordb.PineconeVectorDB {
@@ -200,13 +293,13 @@ func inputVehiclesKnowledgeBase(ctx context.Context, chunker *chunks.Chunker, em
 		ctx,
 		domainEmbeddings,
 		map[string]interface{}{
-			"type": "vehicles",
+			domain.ChunkMetadataType: domain.ChunkTypeVehicles,
 		})
 	if err != nil {
 		log.Fatalf("Failed to store embeddings: %v", err)
 	}
 
-	fmt.Sprintf("Stored %d embeddings in Pinecone index %s\n", count, os.Getenv("PINECONE_INDEX"))
+	fmt.Printf("Stored %d embeddings in Pinecone index %s\n", count, os.Getenv("PINECONE_INDEX"))
 }
 
 func inputPeopleKnowledgeBase(ctx context.Context, chunker *chunks.Chunker, embedder *embeddings.EmbeddingService, pineconeVectorDB *vectordb.PineconeVectorDB) {
@@ -228,11 +321,11 @@ func inputPeopleKnowledgeBase(ctx context.Context, chunker *chunks.Chunker, embe
 		ctx,
 		domainEmbeddings,
 		map[string]interface{}{
-			"type": "people",
+			domain.ChunkMetadataType: domain.ChunkTypePeople,
 		})
 	if err != nil {
 		log.Fatalf("Failed to store embeddings: %v", err)
 	}
 
-	fmt.Sprintf("Stored %d embeddings in Pinecone index %s\n", count, os.Getenv("PINECONE_INDEX"))
+	fmt.Printf("Stored %d embeddings in Pinecone index %s\n", count, os.Getenv("PINECONE_INDEX"))
 }
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/joho/godotenv"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/embeddings"
+	"github.com/loukaspe/rag-golang/pkg/llm"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/mcpclient"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/loukaspe/rag-golang/pkg/server/mcp"
+	"github.com/loukaspe/rag-golang/pkg/vectordb"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/pinecone-io/go-pinecone/v3/pinecone"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// mcp serves the MCP tools over stdio, for desktop MCP clients that start the
+// server as a subprocess. The tools are the ones the HTTP server serves over
+// SSE and streamable HTTP, on the same database and knowledge base, and they
+// run as the owner of the MCP_API_KEY API key, limited to its scopes.
+//
+// The HTTP transports are mounted in the HTTP server instead, next to the
+// authentication they need. stdout carries the protocol, so everything else is
+// logged to stderr. The schema is owned by cmd/http, this command never
+// migrates it.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	getEnv()
+
+	logger := logger.NewLoggerWithOutput(ctx, os.Stderr)
+	db := getDB()
+	client := getOpenAIClient()
+	embedder := getEmbedder(client)
+	pineconeVectorDB := getPineconeVectorDB()
+
+	userRepository := repositories.NewUserRepository(db)
+	apiKeyRepository := repositories.NewAPIKeyRepository(db)
+	apiKeyService := services.NewAPIKeyService(logger, apiKeyRepository, userRepository)
+
+	// every call authenticates the key again, so that a revoked key ends the
+	// session, a role change applies at once and its last use is recorded
+	authenticate := func(ctx context.Context) (jwt.MapClaims, error) {
+		apiKey, err := apiKeyService.Authenticate(ctx, os.Getenv("MCP_API_KEY"))
+		if err != nil {
+			return nil, err
+		}
+
+		return auth.APIKeyClaims(apiKey), nil
+	}
+
+	_, err := authenticate(ctx)
+	if err != nil {
+		log.Fatalf("Cannot authenticate MCP_API_KEY: %v", err)
+	}
+
+	chatSessionRepository := repositories.NewChatSessionRepository(db)
+	chatSessionService := services.NewChatSessionService(logger, chatSessionRepository, getDurationEnv("CHAT_SESSION_DELETED_RETENTION", services.DefaultDeletedChatSessionRetention))
+	messageRepository := repositories.NewMessageRepository(db)
+	curatedAnswerRepository := repositories.NewCuratedAnswerRepository(db)
+
+	usageQuota := domain.UsageQuota{
+		MonthlyTokens:   int64(getIntEnv("USAGE_MONTHLY_TOKEN_QUOTA", 0)),
+		MonthlyMessages: int64(getIntEnv("USAGE_MONTHLY_MESSAGE_QUOTA", 0)),
+	}
+	usageRepository := repositories.NewUsageRepository(db)
+	usageService := services.NewUsageService(logger, usageRepository, usageQuota)
+
+	externalTools := getExternalTools(ctx, logger)
+	defer externalTools.Close()
+
+	// the comparisons are ingested by cmd/http, with the rest of the schema
+	vehicleComparisonRepository := repositories.NewVehicleComparisonRepository(db)
+	vehicleComparisonService := services.NewVehicleComparisonService(logger, vehicleComparisonRepository)
+
+	messageService := services.NewMessageService(logger, messageRepository, chatSessionRepository, curatedAnswerRepository, embedder, pineconeVectorDB, client, usageService, vehicleComparisonService, getIntEnv("AGENT_MAX_STEPS", services.DefaultAgentMaxSteps), externalTools)
+	knowledgeBaseService := services.NewKnowledgeBaseService(logger, embedder, pineconeVectorDB, usageService, getKnowledgeBaseDocuments())
+
+	mcpServer := mcp.NewServer(server.NewMCPServer(
+		os.Getenv("MCP_SERVER_NAME"),
+		os.Getenv("MCP_SERVER_VERSION"),
+	), logger)
+
+	// the key may be used by several clients, so the tools calling OpenAI get
+	// the expensive limit of cmd/http too
+	expensiveRateLimiter := getRateLimiter("RATE_LIMIT_EXPENSIVE", 10, 5)
+
+	mcpServer.Register(mcp.Services{
+		KnowledgeBaseService:     knowledgeBaseService,
+		MessageService:           messageService,
+		ChatSessionService:       chatSessionService,
+		VehicleComparisonService: vehicleComparisonService,
+		UsageService:             usageService,
+		ExpensiveRateLimiter:     expensiveRateLimiter,
+	})
+
+	err = mcpServer.ServeStdio(ctx, authenticate, os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("MCP stdio server stopped: %v", err)
+	}
+}
+
+// getEnv loads ./config/.env when it exists. MCP clients usually start the
+// command from another directory and pass the envs themselves.
+func getEnv() {
+	err := godotenv.Load("./config/.env")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error getting env, not comming through %v", err)
+	}
+}
+
+func getDB() *gorm.DB {
+	dbDsn := fmt.Sprintf(
+		"host=%s port=%s user=%s dbname=%s sslmode=disable password=%s TimeZone=Europe/Athens",
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_PASSWORD"),
+	)
+
+	// the default logger of gorm writes to stdout
+	db, err := gorm.Open(postgres.Open(dbDsn), &gorm.Config{
+		Logger: gormlogger.New(log.StandardLogger(), gormlogger.Config{
+			SlowThreshold: 200 * time.Millisecond,
+			LogLevel:      gormlogger.Warn,
+		}),
+	})
+	if err != nil {
+		log.Fatal("Cannot connect to database: ", err)
+	}
+
+	return db
+}
+
+// getExternalTools connects to the external MCP servers of
+// EXTERNAL_MCP_SERVERS, whose allowed tools the agent answer mode may call
+func getExternalTools(ctx context.Context, logger logger.LoggerInterface) *mcpclient.ToolSource {
+	configs, err := mcpclient.ParseServerConfigs(os.Getenv("EXTERNAL_MCP_SERVERS"))
+	if err != nil {
+		log.Fatalf("Cannot read EXTERNAL_MCP_SERVERS: %v", err)
+	}
+
+	externalTools := mcpclient.NewToolSource(logger)
+	externalTools.Connect(ctx, configs)
+
+	return externalTools
+}
+
+// getKnowledgeBaseDocuments returns the documents the knowledge base was
+// ingested from, relative paths are relative to the working directory
+func getKnowledgeBaseDocuments() []*domain.Document {
+	value := os.Getenv("KNOWLEDGE_BASE_DOCUMENTS")
+	if value == "" {
+		value = domain.DefaultKnowledgeBaseDocuments
+	}
+
+	documents, err := domain.ParseDocuments(value)
+	if err != nil {
+		log.Fatalf("Cannot read KNOWLEDGE_BASE_DOCUMENTS: %v", err)
+	}
+
+	return documents
+}
+
+func getEmbedder(client *llm.Client) *embeddings.EmbeddingService {
+	return embeddings.NewEmbeddingService(client, openai.EmbeddingModel(os.Getenv("EMBEDDING_MODEL")))
+}
+
+func getOpenAIClient() *llm.Client {
+	config := llm.DefaultClientConfig()
+
+	config.MaxConcurrency = getIntEnv("OPENAI_MAX_CONCURRENCY", config.MaxConcurrency)
+	config.MaxRetries = getIntEnv("OPENAI_MAX_RETRIES", config.MaxRetries)
+	config.RequestTimeout = getDurationEnv("OPENAI_REQUEST_TIMEOUT", config.RequestTimeout)
+	config.FailureThreshold = getIntEnv("OPENAI_CIRCUIT_BREAKER_THRESHOLD", config.FailureThreshold)
+	config.OpenDuration = getDurationEnv("OPENAI_CIRCUIT_BREAKER_OPEN_DURATION", config.OpenDuration)
+
+	return llm.NewClient(config, option.WithAPIKey(os.Getenv("OPENAI_API_KEY")))
+}
+
+func getIntEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Cannot read %s: %v", name, err)
+	}
+
+	return number
+}
+
+func getDurationEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Cannot read %s: %v", name, err)
+	}
+
+	return duration
+}
+
+// getRateLimiter reads the <prefix>_PER_MINUTE rate and the <prefix>_BURST of
+// a limit like cmd/http does. A rate of 0 disables the limit.
+func getRateLimiter(prefix string, defaultPerMinute int, defaultBurst int) *ratelimit.Limiter {
+	perMinute := getIntEnv(prefix+"_PER_MINUTE", defaultPerMinute)
+	if perMinute <= 0 {
+		return nil
+	}
+
+	burst := getIntEnv(prefix+"_BURST", defaultBurst)
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return ratelimit.NewLimiter(float64(perMinute), burst)
+}
+
+func getPineconeVectorDB() *vectordb.PineconeVectorDB {
+	topKResultsNumber, err := strconv.Atoi(os.Getenv("TOP_K_RESULTS_NUMBER"))
+	if err != nil {
+		log.Fatal("Cannot read top k results number: ", err)
+	}
+
+	similaritySearchThreshold, err := strconv.ParseFloat(os.Getenv("SIMILARITY_SEARCH_THRESHOLD"), 32)
+	if err != nil {
+		log.Fatal("Cannot read similarity search threshold: ", err)
+	}
+
+	pineconeClient, err := pinecone.NewClient(pinecone.NewClientParams{
+		ApiKey: os.Getenv("PINECONE_API_KEY"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create pinecone Client: %v", err)
+	}
+
+	return vectordb.NewPineconeVectorDB(
+		float32(similaritySearchThreshold),
+		topKResultsNumber,
+		os.Getenv("PINECONE_INDEX"),
+		pineconeClient,
+	)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+)
+
+// role sets the role of an existing user:
+//
+//	go run ./cmd/role -username obi-wan -role admin
+//
+// New users always get the user role, so this is how a fresh deployment gets
+// its first admin, who then hands out roles at PUT /admin/users/{user_id}/role.
+// The user must have registered, or logged in once through the identity
+// provider, before.
+func main() {
+	username := flag.String("username", "", "username of the user")
+	role := flag.String("role", domain.RoleAdmin, "role to set, one of user, curator or admin")
+	flag.Parse()
+
+	if *username == "" {
+		log.Fatal("-username is required")
+	}
+
+	if !domain.IsRole(*role) {
+		log.Fatalf("unknown role %s", *role)
+	}
+
+	getEnv()
+
+	ctx := context.Background()
+	userRepository := repositories.NewUserRepository(getDB())
+
+	user, err := userRepository.GetUserByUsername(ctx, *username)
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if errors.As(err, &resourceNotFound) {
+		log.Fatalf("User %s not found, they have to register or log in first", *username)
+	}
+
+	if err != nil {
+		log.Fatalf("Cannot get user %s: %v", *username, err)
+	}
+
+	err = userRepository.UpdateUserRole(ctx, user.ID, *role)
+	if err != nil {
+		log.Fatalf("Cannot update the role of user %s: %v", *username, err)
+	}
+
+	log.Infof("User %s (%s) is now %s", user.Username, user.ID, *role)
+}
+
+// getEnv loads ./config/.env when it exists, like cmd/mcp
+func getEnv() {
+	err := godotenv.Load("./config/.env")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error getting env, not comming through %v", err)
+	}
+}
+
+func getDB() *gorm.DB {
+	dbDsn := fmt.Sprintf(
+		"host=%s port=%s user=%s dbname=%s sslmode=disable password=%s TimeZone=Europe/Athens",
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_PASSWORD"),
+	)
+
+	db, err := gorm.Open(postgres.Open(dbDsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Cannot connect to database: ", err)
+	}
+
+	return db
+}
@@ -24,268 +24,2028 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/chat-sessions/session_id": {
+        "/.well-known/jwks.json": {
             "get": {
+                "description": "JSON Web Key Set with the public keys that verify the tokens of this service, selected by the kid header of a token. Empty when tokens are signed with a shared secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Publishes the JWT verification keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/chat-sessions/purge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Gets chat session",
-                "summary": "Gets chat session",
+                "description": "Deletes for good the chat sessions deleted longer than CHAT_SESSION_DELETED_RETENTION ago, with their messages and the sources and tool calls of the messages",
+                "summary": "Purges deleted chat sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.PurgeChatSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.PurgeChatSessionsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.PurgeChatSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/curated-answers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets the answers corrected through feedback, optionally filtered by review status",
+                "summary": "Gets curated answers",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "session id",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswersResponse"
                         }
                     },
                     "400": {
-                        "description": "Error in message payload",
+                        "description": "Error in query parameters",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswersResponse"
                         }
                     },
                     "401": {
                         "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswersResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a curator or API key without the kb:manage scope",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswersResponse"
                         }
                     }
                 }
             }
         },
-        "/users/user_id/chat-sessions": {
-            "get": {
+        "/admin/curated-answers/{curated_answer_id}/approve": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets all User's chat sessions",
-                "summary": "Gets all User's chat sessions",
+                "description": "Ingests a pending curated answer in the vector store, tagged with its question, so that similar questions prefer it",
+                "summary": "Approves a curated answer",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "curated answer id",
+                        "name": "curated_answer_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     },
                     "400": {
-                        "description": "Error in message payload",
+                        "description": "Error in path parameters",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     },
                     "401": {
                         "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a curator or API key without the kb:manage scope",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Curated answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Curated answer already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded, retry after the Retry-After seconds",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/curated-answers/{curated_answer_id}/reject": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a chat session for User",
-                "summary": "Creates chat session",
+                "description": "Rejects a pending curated answer, it is never used as context",
+                "summary": "Rejects a curated answer",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "curated answer id",
+                        "name": "curated_answer_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     },
                     "400": {
-                        "description": "Error in message payload",
+                        "description": "Error in path parameters",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     },
                     "401": {
                         "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a curator or API key without the kb:manage scope",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Curated answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Curated answer already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                            "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                         }
                     }
                 }
             }
         },
-        "/users/user_id/chat-sessions/session_id/messages": {
-            "post": {
+        "/admin/feedback/analytics": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Sends message to a given chat session and gets response",
-                "summary": "Sends message to a given chat session and gets response",
+                "description": "Counts the answers and the feedback they received, grouped by day, week or month",
+                "summary": "Gets feedback analytics",
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "SendMessageRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SendMessageRequest"
-                        }
+                        "type": "string",
+                        "description": "start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days before to",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "end date (RFC 3339 or YYYY-MM-DD), defaults to now",
+                        "name": "to",
+                        "in": "query"
                     },
                     {
-                        "description": "session id",
-                        "name": "session_id",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "day, week or month, defaults to day",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/http_feedback.FeedbackAnalyticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_feedback.FeedbackAnalyticsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_feedback.FeedbackAnalyticsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin or API key without the kb:manage scope",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_feedback.FeedbackAnalyticsResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/feedback/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Exports every answer that received feedback as a (question, context, answer, feedback) JSON line.\nThe lines can be used as a golden set of cmd/eval.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "summary": "Exports feedback as JSONL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date (RFC 3339 or YYYY-MM-DD), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                            "$ref": "#/definitions/http_feedback.FeedbackExportRecord"
                         }
                     },
                     "400": {
-                        "description": "Error in message payload",
+                        "description": "Error in query parameters",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                            "$ref": "#/definitions/http_feedback.FeedbackExportErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                            "$ref": "#/definitions/http_feedback.FeedbackExportErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a curator or API key without the kb:manage scope",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                            "$ref": "#/definitions/http_feedback.FeedbackExportErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/user_id/chat-sessions/session_id/messages/message_id/feedback": {
-            "post": {
+        "/admin/users/{user_id}/role": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Submits a feedback to a message",
-                "summary": "Submits a feedback to a message",
+                "description": "Sets the role of a user. Tokens carry the role, so it applies to the tokens the user gets from then on. Admins only.",
+                "summary": "Changes the role of a user",
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "SubmitFeedbackRequest",
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new role",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackRequest"
+                            "$ref": "#/definitions/http_users.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Error in request",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UpdateUserRoleResponse"
                         }
                     },
-                    {
-                        "description": "message_id",
-                        "name": "message_id",
-                        "in": "body",
-                        "required": true,
+                    "401": {
+                        "description": "Authentication error",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/http_users.UpdateUserRoleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin or authenticated with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UpdateUserRoleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UpdateUserRoleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UpdateUserRoleResponse"
                         }
                     }
+                }
+            }
+        },
+        "/chat-sessions/session_id": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a chat session of the authenticated user",
+                "summary": "Gets chat session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
                         }
                     },
                     "400": {
                         "description": "Error in message payload",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
                         }
                     },
                     "401": {
                         "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "http_chatSessions.ChatSessionResponse": {
+        },
+        "/login": {
+            "post": {
+                "description": "Verifies the credentials against the stored bcrypt hash and returns a short-lived JWT whose subject is the user id, and a refresh token. Also served on /token.",
+                "summary": "Logs a user in",
+                "parameters": [
+                    {
+                        "description": "username and password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_users.CredentialsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in request body",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the access token of the request and, if given, the refresh token",
+                "summary": "Logs a user out",
+                "parameters": [
+                    {
+                        "description": "refresh token to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Error in request body",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.LogoutResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error or refresh token of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.LogoutResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.LogoutResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Creates a user account, the password is stored as a bcrypt hash",
+                "summary": "Registers a user",
+                "parameters": [
+                    {
+                        "description": "username and password (8 to 72 characters)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_users.CredentialsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in request body",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UserResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Username already taken",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UserResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. The given refresh token is revoked, and reusing a revoked one revokes all refresh tokens of the user.",
+                "summary": "Refreshes the tokens",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_users.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in request body",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, revoked or expired refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_users.TokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/user_id/chat-sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists summaries of User's chat sessions, with their message count and last message instead of their messages, most recently updated first. Pages are followed with the nextCursor of the previous page.",
+                "summary": "Lists User's chat sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only archived sessions when true, only the rest when false",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated at or after, RFC 3339 timestamp or YYYY-MM-DD date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated before, RFC 3339 timestamp or YYYY-MM-DD date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (default) or asc by updatedAt",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "sessions in a page, 1 to 100, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.UserChatSessionsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a chat session for User",
+                "summary": "Creates chat session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in message payload",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/user_id/chat-sessions/session_id/messages": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends message to a given chat session and gets response. In agent mode the LLM calls tools before answering, and the calls are returned with the answer",
+                "summary": "Sends message to a given chat session and gets response",
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "SendMessageRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "session id",
+                        "name": "session_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in message payload",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Monthly token quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or monthly message quota exceeded, retry after the Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SendMessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/user_id/chat-sessions/session_id/messages/message_id/feedback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Submits a feedback to a SYSTEM message. The answer is rated with either a thumb (up/down) or a 1-5 rating,\noptionally with a reason category, a corrected answer and a free text comment.",
+                "summary": "Submits a feedback to a message",
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "SubmitFeedbackRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackRequest"
+                        }
+                    },
+                    {
+                        "description": "message_id",
+                        "name": "message_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in message payload",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.SubmitFeedbackResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the API keys of the user, revoked ones included, newest first. Only the prefix of each key is returned.",
+                "summary": "Lists the API keys of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeysResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeysResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the authenticated user or authenticated with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeysResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeysResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an API key of the user with the given scopes. The key is only shown in this response, store it safely. API keys cannot manage API keys.",
+                "summary": "Creates an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "name and scopes of the key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in request body",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the authenticated user or authenticated with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/api-keys/{api_key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an active API key of the user, requests with it are rejected from then on",
+                "summary": "Revokes an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "api key id",
+                        "name": "api_key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Error in path parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the authenticated user or authenticated with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Active API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/chat-sessions/{session_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a chat session of the user. It is not found from then on, and is purged with its messages and their sources after the retention",
+                "summary": "Deletes chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "chat session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Error in path parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/chat-sessions/{session_id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archives a chat session of the user. Archived sessions keep their messages and can still be read and continued",
+                "summary": "Archives chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "chat session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/chat-sessions/{session_id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists a page of the messages of a chat session of the user, oldest first: the last ones, the ones before the message of before, or the ones after the message of after.\nhasMore tells whether there are more messages past the page, older ones unless paging with after, so that older history is loaded with the id of the first message as before.",
+                "summary": "Lists messages of chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "chat session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the message to list the messages before",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the message to list the messages after",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "messages in a page, 1 to 200, default 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session or cursor message not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.MessagesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/chat-sessions/{session_id}/title": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the title of a chat session of the user, which is then not replaced by a generated one",
+                "summary": "Renames chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "chat session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "RenameChatSessionRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.RenameChatSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path parameters or payload",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/chat-sessions/{session_id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Unarchives an archived chat session of the user",
+                "summary": "Unarchives chat session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "chat session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chat session of another user",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat session not found",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sums the OpenAI tokens spent on answering the messages of the user per day or month, and reports the usage of the current month against the monthly quota",
+                "summary": "Gets the token usage of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days (day) or a year (month) before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date (RFC 3339 or YYYY-MM-DD), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day or month, defaults to day",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_usage.UsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in path or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_usage.UsageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_usage.UsageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the authenticated user or API key without the sessions:read scope",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_usage.UsageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/comparisons": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Answers comparisons of the vehicles document exactly, from the statements parsed at ingestion. Every matching statement is returned, restated from the side of the subject: ratio is the attribute of the subject over the one of the object and percent how much more, or less when negative, it is",
+                "summary": "Compares vehicles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Vehicle to compare, like Sailboats",
+                        "name": "subject",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute to compare by, like slow",
+                        "name": "attribute",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vehicle to compare with, like Electric cars",
+                        "name": "object",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_vehicles.VehicleComparisonsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error in query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/http_vehicles.VehicleComparisonsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "$ref": "#/definitions/http_vehicles.VehicleComparisonsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "API key without the messages:send scope",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No matching comparisons",
+                        "schema": {
+                            "$ref": "#/definitions/http_vehicles.VehicleComparisonsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_vehicles.VehicleComparisonsResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "EC",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.JWK"
+                    }
+                }
+            }
+        },
+        "http_apiKeys.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is only returned when the key is created",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http_apiKeys.APIKeysResponse": {
+            "type": "object",
+            "properties": {
+                "apiKeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_apiKeys.APIKeyResponse"
+                    }
+                },
+                "errorMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_apiKeys.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "sessions:read",
+                            "messages:send",
+                            "kb:manage"
+                        ]
+                    }
+                }
+            }
+        },
+        "http_chatSessions.ChatSessionResponse": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_chatSessions.ChatSessionSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastMessage": {
+                    "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                },
+                "messageCount": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_chatSessions.MessageFeedbackResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "correctedAnswer": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "wrong",
+                        "incomplete",
+                        "hallucinated",
+                        "off-topic"
+                    ]
+                },
+                "submittedAt": {
+                    "type": "string"
+                },
+                "thumb": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ]
+                }
+            }
+        },
+        "http_chatSessions.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "feedback": {
+                    "$ref": "#/definitions/http_chatSessions.MessageFeedbackResponse"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sender": {
+                    "type": "string"
+                },
+                "toolCalls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_chatSessions.ToolCallResponse"
+                    }
+                }
+            }
+        },
+        "http_chatSessions.MessagesResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                },
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                    }
+                }
+            }
+        },
+        "http_chatSessions.PurgeChatSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                },
+                "purged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http_chatSessions.RenameChatSessionRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_chatSessions.SendMessageRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "rag",
+                        "agent"
+                    ]
+                }
+            }
+        },
+        "http_chatSessions.SendMessageResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                },
+                "systemMessage": {
+                    "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                },
+                "userMessage": {
+                    "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                }
+            }
+        },
+        "http_chatSessions.SubmitFeedbackRequest": {
+            "type": "object",
+            "properties": {
+                "correctedAnswer": {
+                    "type": "string"
+                },
+                "feedback": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "wrong",
+                        "incomplete",
+                        "hallucinated",
+                        "off-topic"
+                    ]
+                },
+                "thumb": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ]
+                }
+            }
+        },
+        "http_chatSessions.SubmitFeedbackResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_chatSessions.ToolCallResponse": {
+            "type": "object",
+            "properties": {
+                "arguments": {
+                    "type": "string"
+                },
+                "isError": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "step": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http_chatSessions.UserChatSessionsResponse": {
             "type": "object",
             "properties": {
+                "errorMessage": {
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_chatSessions.ChatSessionSummaryResponse"
+                    }
+                }
+            }
+        },
+        "http_curatedAnswers.CuratedAnswerResponse": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -295,71 +2055,241 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "messages": {
+                "messageId": {
+                    "type": "string"
+                },
+                "originalAnswer": {
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ]
+                },
+                "vectorId": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_curatedAnswers.CuratedAnswersResponse": {
+            "type": "object",
+            "properties": {
+                "curatedAnswers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                        "$ref": "#/definitions/http_curatedAnswers.CuratedAnswerResponse"
                     }
                 },
-                "title": {
+                "errorMessage": {
                     "type": "string"
+                }
+            }
+        },
+        "http_feedback.FeedbackAnalyticsBucket": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "integer"
                 },
-                "updatedAt": {
+                "feedback": {
+                    "type": "integer"
+                },
+                "feedbackRate": {
+                    "type": "number"
+                },
+                "period": {
                     "type": "string"
                 }
             }
         },
-        "http_chatSessions.MessageResponse": {
+        "http_feedback.FeedbackAnalyticsResponse": {
             "type": "object",
             "properties": {
-                "content": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_feedback.FeedbackAnalyticsBucket"
+                    }
+                },
+                "errorMessage": {
                     "type": "string"
                 },
-                "created_at": {
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "to": {
                     "type": "string"
+                }
+            }
+        },
+        "http_feedback.FeedbackExportContext": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
                 },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_feedback.FeedbackExportErrorResponse": {
+            "type": "object",
+            "properties": {
                 "errorMessage": {
                     "type": "string"
+                }
+            }
+        },
+        "http_feedback.FeedbackExportRecord": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "chat_session_id": {
+                    "type": "string"
+                },
+                "context": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_feedback.FeedbackExportContext"
+                    }
+                },
+                "corrected_answer": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "feedback": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "sender": {
+                "question": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "thumb": {
                     "type": "string"
                 }
             }
         },
-        "http_chatSessions.SendMessageRequest": {
+        "http_usage.QuotaResponse": {
             "type": "object",
             "properties": {
-                "content": {
+                "monthlyMessages": {
+                    "type": "integer"
+                },
+                "monthlyTokens": {
+                    "type": "integer"
+                },
+                "resetsAt": {
                     "type": "string"
+                },
+                "usedMessages": {
+                    "type": "integer"
+                },
+                "usedTokens": {
+                    "type": "integer"
                 }
             }
         },
-        "http_chatSessions.SendMessageResponse": {
+        "http_usage.UsageBucket": {
+            "type": "object",
+            "properties": {
+                "completionTokens": {
+                    "type": "integer"
+                },
+                "embeddingTokens": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "promptTokens": {
+                    "type": "integer"
+                },
+                "totalTokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http_usage.UsageResponse": {
             "type": "object",
             "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_usage.UsageBucket"
+                    }
+                },
                 "errorMessage": {
                     "type": "string"
                 },
-                "systemMessage": {
-                    "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                "from": {
+                    "type": "string"
                 },
-                "userMessage": {
-                    "$ref": "#/definitions/http_chatSessions.MessageResponse"
+                "interval": {
+                    "type": "string"
+                },
+                "quota": {
+                    "$ref": "#/definitions/http_usage.QuotaResponse"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "http_chatSessions.SubmitFeedbackRequest": {
+        "http_users.CredentialsRequest": {
             "type": "object",
             "properties": {
-                "feedback": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "http_chatSessions.SubmitFeedbackResponse": {
+        "http_users.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "description": "RefreshToken is revoked together with the access token, if given",
+                    "type": "string"
+                }
+            }
+        },
+        "http_users.LogoutResponse": {
             "type": "object",
             "properties": {
                 "errorMessage": {
@@ -367,22 +2297,128 @@ const docTemplate = `{
                 }
             }
         },
-        "http_chatSessions.UserChatSessionsResponse": {
+        "http_users.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_users.TokenResponse": {
             "type": "object",
             "properties": {
                 "errorMessage": {
                     "type": "string"
                 },
-                "sessions": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "refreshTokenExpiresAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_users.UpdateUserRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "curator",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "http_users.UpdateUserRoleResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_users.UserResponse": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "curator",
+                        "admin"
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_vehicles.VehicleComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "type": "string"
+                },
+                "inverse": {
+                    "type": "boolean"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "object": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "ratio": {
+                    "type": "number"
+                },
+                "statement": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_vehicles.VehicleComparisonsResponse": {
+            "type": "object",
+            "properties": {
+                "comparisons": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/http_chatSessions.ChatSessionResponse"
+                        "$ref": "#/definitions/http_vehicles.VehicleComparisonResponse"
                     }
+                },
+                "errorMessage": {
+                    "type": "string"
                 }
             }
         }
     },
     "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key created at ` + "`" + `/users/{user_id}/api-keys` + "`" + `, limited to its scopes",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Header value should be in the form of ` + "`" + `Bearer \u003cJWT access token\u003e` + "`" + `",
             "type": "apiKey",
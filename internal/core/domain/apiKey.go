@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// The scopes an API key can be granted. Access tokens of users are not
+// scoped, they can do whatever their user can.
+const (
+	ScopeReadSessions = "sessions:read"
+	ScopeSendMessages = "messages:send"
+	ScopeManageKB     = "kb:manage"
+)
+
+var APIKeyScopes = []string{ScopeReadSessions, ScopeSendMessages, ScopeManageKB}
+
+// APIKey is the stored side of an API key. The key itself is shown once on
+// creation; only its hash and a short prefix that identifies it are kept.
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	// UserRole is the current role of the owner, filled in on authentication,
+	// since a key can never do more than its owner
+	UserRole string
+}
+
+func (apiKey *APIKey) HasScope(scope string) bool {
+	return slices.Contains(apiKey.Scopes, scope)
+}
+
+func IsAPIKeyScope(scope string) bool {
+	return slices.Contains(APIKeyScopes, scope)
+}
+
+// HashAPIKey hashes an API key for storage and lookup. Like refresh tokens,
+// API keys are long random strings, so a fast hash is enough.
+func HashAPIKey(key string) string {
+	return HashRefreshToken(key)
+}
@@ -1,17 +1,105 @@
 package domain
 
 import (
+	"encoding/base64"
+	"errors"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+// MaxChatSessionTitleLength is the maximum number of characters of a title
+// given by the user
+const MaxChatSessionTitleLength = 200
+
 type ChatSession struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Title     string
-	CreatedAt time.Time
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Title      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArchivedAt *time.Time
+	Messages   []*Message
+}
+
+// ChatSessionPreviewLength is the maximum number of characters of the last
+// message shown in a chat session summary
+const ChatSessionPreviewLength = 200
+
+// ChatSessionSummary is a chat session in a listing, with the number of its
+// messages and the last one, its Content cut to ChatSessionPreviewLength,
+// instead of all of them. LastMessage is nil for a session without messages.
+type ChatSessionSummary struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Title        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ArchivedAt   *time.Time
+	MessageCount int
+	LastMessage  *Message
+}
+
+// ChatSessionFilter selects the chat sessions of a user to list, sorted by
+// updated_at, newest first unless Ascending. Archived keeps only the archived
+// sessions when true and only the rest when false. UpdatedFrom and UpdatedTo
+// keep the sessions updated in [UpdatedFrom, UpdatedTo). The page starts after
+// the session of the cursor.
+type ChatSessionFilter struct {
+	Archived    *bool
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Ascending   bool
+	After       *ChatSessionCursor
+	Limit       int
+}
+
+// ChatSessionCursor is the position of a chat session in a listing, its id
+// tells apart the sessions updated at the same time
+type ChatSessionCursor struct {
 	UpdatedAt time.Time
-	Messages  []*Message
+	ID        uuid.UUID
+}
+
+// String encodes the cursor into an opaque token for the clients
+func (cursor *ChatSessionCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(cursor.UpdatedAt.Format(time.RFC3339Nano) + "|" + cursor.ID.String()),
+	)
+}
+
+// ParseChatSessionCursor decodes a token of ChatSessionCursor.String
+func ParseChatSessionCursor(token string) (*ChatSessionCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+
+	updatedAt, id, found := strings.Cut(string(decoded), "|")
+	if !found {
+		return nil, errors.New("malformed cursor")
+	}
+
+	cursor := &ChatSessionCursor{}
+
+	cursor.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+
+	cursor.ID, err = uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+
+	return cursor, nil
+}
+
+// ChatSessionPage is a page of a chat session listing, NextCursor is nil on
+// the last page
+type ChatSessionPage struct {
+	Sessions   []*ChatSessionSummary
+	NextCursor *ChatSessionCursor
 }
 
 type Message struct {
@@ -20,5 +108,44 @@ type Message struct {
 	Sender        string
 	Content       string
 	CreatedAt     time.Time
-	Feedback      *string
+	Feedback      *MessageFeedback
+	Sources       []*RetrievedChunk
+	ToolCalls     []*ToolCall
+}
+
+// MessageFilter selects a page of the messages of a chat session. Before and
+// After are ids of messages of the session, at most one of them: the page is
+// the Limit messages sent right before Before, right after After, or the last
+// Limit messages without either.
+type MessageFilter struct {
+	Before *uuid.UUID
+	After  *uuid.UUID
+	Limit  int
+}
+
+// MessagePage is a page of the messages of a chat session, oldest first.
+// HasMore tells whether there are more messages past the page, newer ones when
+// paging After and older ones otherwise.
+type MessagePage struct {
+	Messages []*Message
+	HasMore  bool
+}
+
+const (
+	// AnswerModeRAG answers with a single retrieve then generate pass
+	AnswerModeRAG = "rag"
+	// AnswerModeAgent lets the LLM call tools, like searching the knowledge
+	// base, for a few steps before answering
+	AnswerModeAgent = "agent"
+)
+
+// ToolCall is a call of a tool by the LLM while answering in AnswerModeAgent.
+// Arguments are the JSON the LLM generated, Result is what the LLM was given
+// back, the error message when IsError.
+type ToolCall struct {
+	Step      int
+	Name      string
+	Arguments string
+	Result    string
+	IsError   bool
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	CuratedAnswerStatusPending  = "pending"
+	CuratedAnswerStatusApproved = "approved"
+	CuratedAnswerStatusRejected = "rejected"
+)
+
+// Metadata keys of the curated answer chunks in the vector store
+const (
+	ChunkMetadataCurated         = "curated"
+	ChunkMetadataQuestion        = "question"
+	ChunkMetadataCuratedAnswerID = "curated_answer_id"
+)
+
+// CuratedAnswer is an answer corrected by a user through feedback. Once an
+// admin approves it, it is ingested in the vector store tagged with the
+// question it answers. MessageID is uuid.Nil once the chat session of the
+// message was purged.
+type CuratedAnswer struct {
+	ID             uuid.UUID
+	MessageID      uuid.UUID
+	Question       string
+	OriginalAnswer string
+	Answer         string
+	Status         string
+	VectorID       string
+	CreatedAt      time.Time
+	ReviewedAt     *time.Time
+}
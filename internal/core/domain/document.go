@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultKnowledgeBaseDocuments are the documents of the knowledge base when
+// none are configured, the vehicles one is the only one in the repo
+const DefaultKnowledgeBaseDocuments = "vehicles=./dataVehicles.md"
+
+// Document is a file ingested in the knowledge base. Its chunks carry its type
+// in their ChunkMetadataType metadata.
+type Document struct {
+	Type     string
+	Path     string
+	MIMEType string
+}
+
+// ParseDocuments parses a comma separated list of "type=path" documents. The
+// MIME type is guessed from the extension of the path, markdown by default.
+func ParseDocuments(value string) ([]*Document, error) {
+	var documents []*Document
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		documentType, path, ok := strings.Cut(entry, "=")
+		documentType, path = strings.TrimSpace(documentType), strings.TrimSpace(path)
+		if !ok || documentType == "" || path == "" {
+			return nil, fmt.Errorf("malformed document %q, expected type=path", entry)
+		}
+
+		mimeType := "text/markdown"
+		if extension := filepath.Ext(path); extension != ".md" && mime.TypeByExtension(extension) != "" {
+			mimeType = mime.TypeByExtension(extension)
+		}
+
+		documents = append(documents, &Document{
+			Type:     documentType,
+			Path:     path,
+			MIMEType: mimeType,
+		})
+	}
+
+	return documents, nil
+}
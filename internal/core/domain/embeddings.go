@@ -3,4 +3,6 @@ package domain
 type Embeddings struct {
 	Embeddings []float64 `json:"embedding"`
 	Text       string    `json:"text"`
+	// Tokens is what embedding the text cost, 0 for the local embedder
+	Tokens int64 `json:"tokens,omitempty"`
 }
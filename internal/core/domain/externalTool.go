@@ -0,0 +1,9 @@
+package domain
+
+// ExternalTool is a tool of an external MCP server that the LLM may call in
+// AnswerModeAgent. Parameters is the JSON schema of its arguments.
+type ExternalTool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+}
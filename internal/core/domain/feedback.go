@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	FeedbackIntervalDay   = "day"
+	FeedbackIntervalWeek  = "week"
+	FeedbackIntervalMonth = "month"
+)
+
+const (
+	FeedbackThumbUp   = "up"
+	FeedbackThumbDown = "down"
+
+	FeedbackRatingMin = 1
+	FeedbackRatingMax = 5
+)
+
+const (
+	FeedbackReasonWrong        = "wrong"
+	FeedbackReasonIncomplete   = "incomplete"
+	FeedbackReasonHallucinated = "hallucinated"
+	FeedbackReasonOffTopic     = "off-topic"
+)
+
+// MessageFeedback is the feedback of a user on a SYSTEM answer. It is rated
+// either with a thumb (up/down) or with a 1-5 rating; the reason, the
+// corrected answer and the comment are optional.
+type MessageFeedback struct {
+	Thumb           string
+	Rating          int
+	Reason          string
+	CorrectedAnswer string
+	Comment         string
+	SubmittedAt     time.Time
+}
+
+func (feedback *MessageFeedback) Validate() error {
+	if feedback.Thumb == "" && feedback.Rating == 0 {
+		return errors.New("feedback needs a thumb or a rating")
+	}
+
+	if feedback.Thumb != "" && feedback.Rating != 0 {
+		return errors.New("feedback cannot have both a thumb and a rating")
+	}
+
+	if feedback.Thumb != "" && feedback.Thumb != FeedbackThumbUp && feedback.Thumb != FeedbackThumbDown {
+		return errors.New("thumb must be up or down")
+	}
+
+	if feedback.Thumb == "" && (feedback.Rating < FeedbackRatingMin || feedback.Rating > FeedbackRatingMax) {
+		return errors.New("rating must be between 1 and 5")
+	}
+
+	switch feedback.Reason {
+	case "", FeedbackReasonWrong, FeedbackReasonIncomplete, FeedbackReasonHallucinated, FeedbackReasonOffTopic:
+	default:
+		return errors.New("reason must be one of wrong, incomplete, hallucinated, off-topic")
+	}
+
+	return nil
+}
+
+// FeedbackAnalytics aggregates the SYSTEM answers of a period and the feedback
+// they received.
+type FeedbackAnalytics struct {
+	Period   time.Time
+	Answers  int
+	Feedback int
+}
+
+// FeedbackEntry is a SYSTEM answer that received feedback, together with the
+// question it answered and the context it was generated from.
+type FeedbackEntry struct {
+	MessageID     uuid.UUID
+	ChatSessionID uuid.UUID
+	Question      string
+	Context       []*RetrievedChunk
+	Answer        string
+	Feedback      *MessageFeedback
+	CreatedAt     time.Time
+}
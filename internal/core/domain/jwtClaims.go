@@ -7,10 +7,11 @@ import (
 
 type JwtClaims struct {
 	*jwt.RegisteredClaims
+	Role     string `json:"role,omitempty"`
 	UserInfo interface{}
 }
 type JwtClaimsInterface interface {
-	CreateToken(sub string, userInfo interface{}) (string, error)
+	CreateToken(sub string, role string, userInfo interface{}) (string, error)
 	GetClaimsFromToken(tokenString string) (jwt.MapClaims, error)
 	SetJWTClaimsContext(ctx context.Context, claims jwt.MapClaims) context.Context
 }
@@ -0,0 +1,44 @@
+package domain
+
+// Metadata key and values of the type of the knowledge base chunks in the
+// vector store. Curated answers carry ChunkMetadataCurated instead, and are
+// searched with the ChunkTypeCurated type.
+const (
+	ChunkMetadataType = "type"
+	ChunkTypePeople   = "people"
+	ChunkTypeVehicles = "vehicles"
+	ChunkTypeCurated  = "curated"
+)
+
+// SearchOptions narrow a semantic search. Zero values keep the defaults of the
+// vector store: its own top k and every type of chunk.
+type SearchOptions struct {
+	TopK int
+	Type string
+}
+
+type RetrievedChunk struct {
+	ID       string
+	Text     string
+	Score    float32
+	Metadata map[string]interface{}
+}
+
+// IsCurated reports whether the chunk was ingested from an approved curated
+// answer.
+func (chunk *RetrievedChunk) IsCurated() bool {
+	curated, _ := chunk.Metadata[ChunkMetadataCurated].(bool)
+
+	return curated
+}
+
+// Type returns the type of the chunk, ChunkTypeCurated for curated answers
+func (chunk *RetrievedChunk) Type() string {
+	if chunk.IsCurated() {
+		return ChunkTypeCurated
+	}
+
+	chunkType, _ := chunk.Metadata[ChunkMetadataType].(string)
+
+	return chunkType
+}
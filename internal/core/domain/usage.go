@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	UsageIntervalDay   = "day"
+	UsageIntervalMonth = "month"
+)
+
+// TokenUsage is what answering a message cost in OpenAI tokens: the prompt and
+// completion tokens of the chat completions, the title included, and the tokens
+// of the embedded question. MessageID is the SYSTEM answer, uuid.Nil when
+// answering failed after spending tokens. A search of the knowledge base
+// answers no message, its ChatSessionID and MessageID are uuid.Nil. Usages
+// without a MessageID are not counted as messages.
+type TokenUsage struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	ChatSessionID    uuid.UUID
+	MessageID        uuid.UUID
+	PromptTokens     int64
+	CompletionTokens int64
+	EmbeddingTokens  int64
+	CreatedAt        time.Time
+}
+
+func (usage *TokenUsage) TotalTokens() int64 {
+	return usage.PromptTokens + usage.CompletionTokens + usage.EmbeddingTokens
+}
+
+// UsageAggregate sums the token usage of the messages answered in a period
+type UsageAggregate struct {
+	Period           time.Time
+	Messages         int64
+	PromptTokens     int64
+	CompletionTokens int64
+	EmbeddingTokens  int64
+}
+
+func (aggregate *UsageAggregate) TotalTokens() int64 {
+	return aggregate.PromptTokens + aggregate.CompletionTokens + aggregate.EmbeddingTokens
+}
+
+// UsageQuota limits the tokens and the answered messages of every user per
+// calendar month (UTC). A limit of 0 means no limit.
+type UsageQuota struct {
+	MonthlyTokens   int64
+	MonthlyMessages int64
+}
+
+// QuotaStatus is the usage of a user in the current month against the quota
+type QuotaStatus struct {
+	Quota        UsageQuota
+	UsedTokens   int64
+	UsedMessages int64
+	ResetsAt     time.Time
+}
+
+// StartOfMonth is the start of the calendar month (UTC) of t, when the monthly
+// quotas reset
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
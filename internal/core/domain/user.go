@@ -1,14 +1,49 @@
 package domain
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
 
+// User is either a local account with a bcrypt password, or an account
+// provisioned on the first login through an OpenID Connect provider, which has
+// an ExternalID and no usable password.
 type User struct {
-	Username string
-	Password string
+	ID         uuid.UUID
+	Username   string
+	Password   string
+	ExternalID string
+	Role       string
 }
 
 const HashCost = 10
 
+// Roles are ordered, each one can do everything the previous ones can: users
+// chat, curators also manage the knowledge base and review feedback, admins
+// also see analytics and manage the roles of users.
+const (
+	RoleUser    = "user"
+	RoleCurator = "curator"
+	RoleAdmin   = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleUser:    1,
+	RoleCurator: 2,
+	RoleAdmin:   3,
+}
+
+func IsRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole tells whether a role grants the required one. Unknown roles grant
+// nothing.
+func HasRole(role string, required string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
 func (user *User) CheckPassword(providedPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(providedPassword))
 }
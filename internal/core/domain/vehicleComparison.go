@@ -0,0 +1,35 @@
+package domain
+
+// VehicleComparison is a row of the vehicles document, "Subject are Percent%
+// Attribute than Object.", meaning that the Attribute of the Subject is
+// 1 + Percent/100 times the one of the Object. Line is the line of the row.
+type VehicleComparison struct {
+	Line      int
+	Subject   string
+	Percent   float64
+	Attribute string
+	Object    string
+}
+
+// VehicleComparisonFilter selects the comparisons of the Subject, with the
+// Object and by the Attribute when they are set, in either direction
+type VehicleComparisonFilter struct {
+	Subject   string
+	Attribute string
+	Object    string
+}
+
+// VehicleComparisonFact is a comparison restated from the side of the subject
+// that was asked for: Ratio is the Attribute of Subject over the one of
+// Object, Percent how much more (or less, when negative) it is. Inverse is
+// set when the row of the document compares the Object to the Subject.
+type VehicleComparisonFact struct {
+	Subject   string
+	Attribute string
+	Object    string
+	Ratio     float64
+	Percent   float64
+	Inverse   bool
+	Statement string
+	Line      int
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(context.Context, *domain.APIKey) (*domain.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	UpdateAPIKeyLastUsed(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
 )
 
 type ChatSessionRepositoryInterface interface {
 	GetChatSession(context.Context, uuid.UUID) (*domain.ChatSession, error)
-	GetUserChatSessions(context.Context, uuid.UUID) ([]*domain.ChatSession, error)
+	GetChatSessionUserID(context.Context, uuid.UUID) (uuid.UUID, error)
+	GetUserChatSessionSummaries(ctx context.Context, userID uuid.UUID, filter *domain.ChatSessionFilter) ([]*domain.ChatSessionSummary, error)
 	CreateChatSession(context.Context, *domain.ChatSession) (uuid.UUID, error)
 	UpdateChatSessionTitle(context.Context, uuid.UUID, string) error
+	UpdateChatSessionArchivedAt(context.Context, uuid.UUID, *time.Time) error
+	DeleteChatSession(context.Context, uuid.UUID) error
+	PurgeChatSessions(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type CuratedAnswerRepositoryInterface interface {
+	CreateCuratedAnswer(context.Context, *domain.CuratedAnswer) (uuid.UUID, error)
+	GetCuratedAnswer(context.Context, uuid.UUID) (*domain.CuratedAnswer, error)
+	GetCuratedAnswers(ctx context.Context, status string) ([]*domain.CuratedAnswer, error)
+	UpdateCuratedAnswerStatus(ctx context.Context, id uuid.UUID, status string, vectorID string) error
+}
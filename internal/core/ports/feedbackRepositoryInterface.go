@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type FeedbackRepositoryInterface interface {
+	GetFeedbackAnalytics(ctx context.Context, from, to time.Time, interval string) ([]*domain.FeedbackAnalytics, error)
+	GetFeedbackEntries(ctx context.Context, from, to time.Time) ([]*domain.FeedbackEntry, error)
+}
@@ -9,5 +9,6 @@ import (
 type MessageRepositoryInterface interface {
 	CreateMessage(context.Context, *domain.Message) (uuid.UUID, error)
 	GetMessage(context.Context, uuid.UUID) (*domain.Message, error)
-	UpdateMessageFeedback(context.Context, uuid.UUID, string) error
+	GetChatSessionMessages(ctx context.Context, chatSessionID uuid.UUID, filter *domain.MessageFilter) ([]*domain.Message, error)
+	UpdateMessageFeedback(context.Context, uuid.UUID, *domain.MessageFeedback) error
 }
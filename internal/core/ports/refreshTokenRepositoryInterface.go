@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type RefreshTokenRepositoryInterface interface {
+	CreateRefreshToken(context.Context, *domain.RefreshToken) (uuid.UUID, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RevokeRefreshToken(context.Context, uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type RevokedTokenRepositoryInterface interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type UsageRepositoryInterface interface {
+	CreateTokenUsage(ctx context.Context, usage *domain.TokenUsage) (uuid.UUID, error)
+	GetUserUsage(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]*domain.UsageAggregate, error)
+	GetUserUsageTotal(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.UsageAggregate, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type UserRepositoryInterface interface {
+	CreateUser(context.Context, *domain.User) (uuid.UUID, error)
+	GetUser(context.Context, uuid.UUID) (*domain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	GetUserByExternalID(ctx context.Context, externalID string) (*domain.User, error)
+	UpdateUserRole(ctx context.Context, userID uuid.UUID, role string) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type VehicleComparisonRepositoryInterface interface {
+	ReplaceVehicleComparisons(context.Context, []*domain.VehicleComparison) error
+	GetVehicleComparisons(context.Context, *domain.VehicleComparisonFilter) ([]*domain.VehicleComparison, error)
+}
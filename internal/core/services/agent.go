@@ -0,0 +1,565 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/helpers"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAgentMaxSteps is the number of completions with tool calls an agent
+// answer may take before it has to answer with what it found
+const DefaultAgentMaxSteps = 5
+
+const (
+	agentToolSearchKnowledgeBase = "search_knowledge_base"
+	agentToolCalculate           = "calculate"
+	agentToolGetSessionHistory   = "get_session_history"
+	agentToolCompareVehicles     = "compare_vehicles"
+
+	defaultAgentSearchTopK   = 5
+	maxAgentSearchTopK       = 20
+	defaultAgentHistoryLimit = 10
+	maxAgentHistoryLimit     = 50
+)
+
+// the operations of the calculate tool, the ones of the calculator MCP tools
+// the agent replaces
+const (
+	calculateOperationAdd     = "add"
+	calculateOperationSub     = "subtract"
+	calculateOperationMul     = "multiply"
+	calculateOperationDiv     = "divide"
+	calculateOperationPercent = "percentage"
+)
+
+// ExternalToolProvider provides the tools of external MCP servers to the agent,
+// next to its own ones
+type ExternalToolProvider interface {
+	Tools() []*domain.ExternalTool
+	CallTool(ctx context.Context, name string, arguments string) (string, error)
+}
+
+// agentTools are the function tools the LLM may call in AnswerModeAgent, its
+// own and the external ones
+func (s *MessageService) agentTools() []openai.ChatCompletionToolParam {
+	tools := builtInAgentTools()
+	if s.vehicleComparisons != nil {
+		tools = append(tools, compareVehiclesAgentTool())
+	}
+
+	if s.externalTools == nil {
+		return tools
+	}
+
+	for _, externalTool := range s.externalTools.Tools() {
+		tools = append(tools, openai.ChatCompletionToolParam{
+			Function: shared.FunctionDefinitionParam{
+				Name:        externalTool.Name,
+				Description: openai.String(externalTool.Description),
+				Parameters:  externalTool.Parameters,
+			},
+		})
+	}
+
+	return tools
+}
+
+func builtInAgentTools() []openai.ChatCompletionToolParam {
+	return []openai.ChatCompletionToolParam{
+		{
+			Function: shared.FunctionDefinitionParam{
+				Name:        agentToolSearchKnowledgeBase,
+				Description: openai.String("Search the Star Wars knowledge base for the passages most similar to the query"),
+				Parameters: shared.FunctionParameters{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"query": map[string]interface{}{
+							"type":        "string",
+							"description": "Text to search for",
+						},
+						"type": map[string]interface{}{
+							"type":        "string",
+							"description": "Only search passages of this type",
+							"enum":        []string{domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated},
+						},
+						"top_k": map[string]interface{}{
+							"type":        "integer",
+							"description": "Maximum number of passages, up to " + strconv.Itoa(maxAgentSearchTopK),
+							"minimum":     1,
+							"maximum":     maxAgentSearchTopK,
+						},
+					},
+					"required": []string{"query"},
+				},
+			},
+		},
+		{
+			Function: shared.FunctionDefinitionParam{
+				Name:        agentToolCalculate,
+				Description: openai.String("Calculate with two numbers, percentage is what percentage a is of b"),
+				Parameters: shared.FunctionParameters{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"operation": map[string]interface{}{
+							"type": "string",
+							"enum": []string{
+								calculateOperationAdd,
+								calculateOperationSub,
+								calculateOperationMul,
+								calculateOperationDiv,
+								calculateOperationPercent,
+							},
+						},
+						"a": map[string]interface{}{
+							"type": "number",
+						},
+						"b": map[string]interface{}{
+							"type": "number",
+						},
+					},
+					"required": []string{"operation", "a", "b"},
+				},
+			},
+		},
+		{
+			Function: shared.FunctionDefinitionParam{
+				Name:        agentToolGetSessionHistory,
+				Description: openai.String("Get the latest earlier messages of the chat session, oldest first"),
+				Parameters: shared.FunctionParameters{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"limit": map[string]interface{}{
+							"type":        "integer",
+							"description": "Maximum number of messages, up to " + strconv.Itoa(maxAgentHistoryLimit),
+							"minimum":     1,
+							"maximum":     maxAgentHistoryLimit,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func compareVehiclesAgentTool() openai.ChatCompletionToolParam {
+	return openai.ChatCompletionToolParam{
+		Function: shared.FunctionDefinitionParam{
+			Name: agentToolCompareVehicles,
+			Description: openai.String("Compare vehicles exactly from the statements of the vehicles document, like \"Sailboats are 88% slow than Electric cars.\". " +
+				"Every matching statement is returned, restated from the side of the subject: ratio is the attribute of the subject over the one of the object, " +
+				"percent how much more, or less when negative, it is. The document may hold more than one statement for the same vehicles"),
+			Parameters: shared.FunctionParameters{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"subject": map[string]interface{}{
+						"type":        "string",
+						"description": "Vehicle to compare, in the plural like the document names it, like Sailboats",
+					},
+					"attribute": map[string]interface{}{
+						"type":        "string",
+						"description": "Attribute to compare by, like slow or quick to accelerate",
+					},
+					"object": map[string]interface{}{
+						"type":        "string",
+						"description": "Vehicle to compare with, like Electric cars",
+					},
+				},
+				"required": []string{"subject"},
+			},
+		},
+	}
+}
+
+// agentRun is the state of an agent answer: the chunks the searches
+// retrieved become the sources of the answer, every call is recorded
+type agentRun struct {
+	initialMessage *domain.Message
+	chatSession    *domain.ChatSession
+	usage          *domain.TokenUsage
+	sources        []*domain.RetrievedChunk
+	sourceIDs      map[string]bool
+	toolCalls      []*domain.ToolCall
+}
+
+// generateAgentAnswer answers in AnswerModeAgent: the LLM calls the tools it
+// needs, for up to agentMaxSteps completions, and is then asked to answer
+// without tools if it has not yet
+func (s *MessageService) generateAgentAnswer(
+	ctx context.Context,
+	initialMessage *domain.Message,
+	chatSession *domain.ChatSession,
+	usage *domain.TokenUsage,
+) (*domain.Message, error) {
+	run := &agentRun{
+		initialMessage: initialMessage,
+		chatSession:    chatSession,
+		usage:          usage,
+		sourceIDs:      map[string]bool{},
+	}
+
+	completionParams := openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(AgentSystemPrompt),
+			openai.UserMessage(initialMessage.Content),
+		},
+		Tools: s.agentTools(),
+		Model: openai.ChatModelGPT4_1Nano,
+	}
+
+	for step := 1; step <= s.agentMaxSteps; step++ {
+		message, err := s.agentCompletion(ctx, completionParams, usage)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(message.ToolCalls) == 0 {
+			return run.answer(message.Content)
+		}
+
+		completionParams.Messages = append(completionParams.Messages, message.ToParam())
+		for _, toolCall := range message.ToolCalls {
+			result := s.callAgentTool(ctx, run, step, toolCall.Function.Name, toolCall.Function.Arguments)
+			completionParams.Messages = append(completionParams.Messages, openai.ToolMessage(result, toolCall.ID))
+		}
+	}
+
+	completionParams.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
+		OfAuto: openai.String("none"),
+	}
+
+	message, err := s.agentCompletion(ctx, completionParams, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return run.answer(message.Content)
+}
+
+func (s *MessageService) agentCompletion(
+	ctx context.Context,
+	completionParams openai.ChatCompletionNewParams,
+	usage *domain.TokenUsage,
+) (*openai.ChatCompletionMessage, error) {
+	chatCompletion, err := s.chatProvider.NewChatCompletion(ctx, completionParams)
+	if err != nil {
+		return nil, err
+	}
+
+	addCompletionUsage(usage, chatCompletion)
+
+	if len(chatCompletion.Choices) == 0 {
+		return nil, errors.New("received no choices from LLM")
+	}
+
+	return &chatCompletion.Choices[0].Message, nil
+}
+
+func (run *agentRun) answer(content string) (*domain.Message, error) {
+	if content == "" {
+		return nil, errors.New("received empty response from LLM")
+	}
+
+	// like in the RAG answers, curated sources come first
+	sort.SliceStable(run.sources, func(i, j int) bool {
+		return run.sources[i].IsCurated() && !run.sources[j].IsCurated()
+	})
+
+	return &domain.Message{
+		Content:   content,
+		Sources:   run.sources,
+		ToolCalls: run.toolCalls,
+	}, nil
+}
+
+// callAgentTool calls the tool and records the call. The errors are returned
+// to the LLM as the result of the call, so that it can try again.
+func (s *MessageService) callAgentTool(ctx context.Context, run *agentRun, step int, name string, arguments string) string {
+	var result string
+	var err error
+
+	switch name {
+	case agentToolSearchKnowledgeBase:
+		result, err = s.searchKnowledgeBaseTool(ctx, run, arguments)
+	case agentToolCalculate:
+		result, err = calculateTool(arguments)
+	case agentToolGetSessionHistory:
+		result, err = sessionHistoryTool(run, arguments)
+	case agentToolCompareVehicles:
+		if s.vehicleComparisons == nil {
+			err = errors.New("unknown tool " + name)
+			break
+		}
+
+		result, err = s.compareVehiclesTool(ctx, arguments)
+	default:
+		if s.externalTools == nil {
+			err = errors.New("unknown tool " + name)
+			break
+		}
+
+		result, err = s.externalTools.CallTool(ctx, name, arguments)
+	}
+
+	toolCall := &domain.ToolCall{
+		Step:      step,
+		Name:      name,
+		Arguments: arguments,
+		Result:    result,
+	}
+
+	if err != nil {
+		s.logger.Warn("Error in calling agent tool",
+			map[string]interface{}{
+				"tool":         name,
+				"errorMessage": err.Error(),
+			})
+
+		toolCall.Result = "error: " + err.Error()
+		toolCall.IsError = true
+	}
+
+	run.toolCalls = append(run.toolCalls, toolCall)
+
+	return toolCall.Result
+}
+
+type searchKnowledgeBaseArguments struct {
+	Query string `json:"query"`
+	Type  string `json:"type"`
+	TopK  int    `json:"top_k"`
+}
+
+type agentSearchHit struct {
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+	Type  string  `json:"type,omitempty"`
+}
+
+func (s *MessageService) searchKnowledgeBaseTool(ctx context.Context, run *agentRun, arguments string) (string, error) {
+	searchArguments := &searchKnowledgeBaseArguments{}
+	err := json.Unmarshal([]byte(arguments), searchArguments)
+	if err != nil {
+		return "", errors.New("malformed arguments")
+	}
+
+	if searchArguments.Query == "" {
+		return "", errors.New("empty query")
+	}
+
+	switch searchArguments.Type {
+	case "", domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated:
+	default:
+		return "", errors.New("unknown type " + searchArguments.Type)
+	}
+
+	if searchArguments.TopK == 0 {
+		searchArguments.TopK = defaultAgentSearchTopK
+	}
+	searchArguments.TopK = min(max(searchArguments.TopK, 1), maxAgentSearchTopK)
+
+	domainEmbeddings, err := s.embedder.Embed(ctx, []string{searchArguments.Query})
+	if err != nil {
+		return "", err
+	}
+
+	run.usage.EmbeddingTokens += domainEmbeddings[0].Tokens
+
+	retrievedChunks, err := s.vectorDB.Search(ctx, helpers.Float64ToFloat32(domainEmbeddings[0].Embeddings), domain.SearchOptions{
+		TopK: searchArguments.TopK,
+		Type: searchArguments.Type,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	texts := ContextTexts(retrievedChunks)
+	hits := make([]agentSearchHit, len(retrievedChunks))
+	for i, retrievedChunk := range retrievedChunks {
+		hits[i] = agentSearchHit{
+			Text:  texts[i],
+			Score: retrievedChunk.Score,
+			Type:  retrievedChunk.Type(),
+		}
+
+		if !run.sourceIDs[retrievedChunk.ID] {
+			run.sourceIDs[retrievedChunk.ID] = true
+			run.sources = append(run.sources, retrievedChunk)
+		}
+	}
+
+	return marshalToolResult(hits)
+}
+
+type calculateArguments struct {
+	Operation string   `json:"operation"`
+	A         *float64 `json:"a"`
+	B         *float64 `json:"b"`
+}
+
+func calculateTool(arguments string) (string, error) {
+	calculation := &calculateArguments{}
+	err := json.Unmarshal([]byte(arguments), calculation)
+	if err != nil {
+		return "", errors.New("malformed arguments")
+	}
+
+	if calculation.A == nil || calculation.B == nil {
+		return "", errors.New("missing a or b")
+	}
+
+	a, b := *calculation.A, *calculation.B
+
+	var result float64
+	switch calculation.Operation {
+	case calculateOperationAdd:
+		result = a + b
+	case calculateOperationSub:
+		result = a - b
+	case calculateOperationMul:
+		result = a * b
+	case calculateOperationDiv:
+		if b == 0 {
+			return "", errors.New("division by zero")
+		}
+		result = a / b
+	case calculateOperationPercent:
+		if b == 0 {
+			return "", errors.New("percentage of zero")
+		}
+		result = a / b * 100
+	default:
+		return "", errors.New("unknown operation " + calculation.Operation)
+	}
+
+	return strconv.FormatFloat(result, 'f', -1, 64), nil
+}
+
+type compareVehiclesArguments struct {
+	Subject   string `json:"subject"`
+	Attribute string `json:"attribute"`
+	Object    string `json:"object"`
+}
+
+type agentVehicleComparison struct {
+	Subject   string  `json:"subject"`
+	Attribute string  `json:"attribute"`
+	Object    string  `json:"object"`
+	Ratio     float64 `json:"ratio"`
+	Percent   float64 `json:"percent"`
+	Statement string  `json:"statement"`
+}
+
+func (s *MessageService) compareVehiclesTool(ctx context.Context, arguments string) (string, error) {
+	comparisonArguments := &compareVehiclesArguments{}
+	err := json.Unmarshal([]byte(arguments), comparisonArguments)
+	if err != nil {
+		return "", errors.New("malformed arguments")
+	}
+
+	filter := &domain.VehicleComparisonFilter{
+		Subject:   strings.TrimSpace(comparisonArguments.Subject),
+		Attribute: strings.TrimSpace(comparisonArguments.Attribute),
+		Object:    strings.TrimSpace(comparisonArguments.Object),
+	}
+	if filter.Subject == "" {
+		return "", errors.New("empty subject")
+	}
+
+	facts, err := s.vehicleComparisons.CompareVehicles(ctx, filter)
+	// the message of ResourceNotFoundErrorWrapper is the one it wraps
+	if notFoundError, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		return "", notFoundError.Unwrap()
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	comparisons := make([]agentVehicleComparison, len(facts))
+	for i, fact := range facts {
+		comparisons[i] = agentVehicleComparison{
+			Subject:   fact.Subject,
+			Attribute: fact.Attribute,
+			Object:    fact.Object,
+			Ratio:     fact.Ratio,
+			Percent:   fact.Percent,
+			Statement: fact.Statement,
+		}
+	}
+
+	return marshalToolResult(comparisons)
+}
+
+type sessionHistoryArguments struct {
+	Limit int `json:"limit"`
+}
+
+type agentHistoryMessage struct {
+	Sender    string `json:"sender"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// sessionHistoryTool returns the latest messages of the session that were
+// sent before the initial message, the initial message is already the question
+func sessionHistoryTool(run *agentRun, arguments string) (string, error) {
+	historyArguments := &sessionHistoryArguments{}
+	if arguments != "" {
+		err := json.Unmarshal([]byte(arguments), historyArguments)
+		if err != nil {
+			return "", errors.New("malformed arguments")
+		}
+	}
+
+	if historyArguments.Limit == 0 {
+		historyArguments.Limit = defaultAgentHistoryLimit
+	}
+	historyArguments.Limit = min(max(historyArguments.Limit, 1), maxAgentHistoryLimit)
+
+	var earlierMessages []*domain.Message
+	for _, message := range run.chatSession.Messages {
+		if message.ID == run.initialMessage.ID || message.CreatedAt.After(run.initialMessage.CreatedAt) {
+			continue
+		}
+
+		earlierMessages = append(earlierMessages, message)
+	}
+
+	sort.SliceStable(earlierMessages, func(i, j int) bool {
+		return earlierMessages[i].CreatedAt.Before(earlierMessages[j].CreatedAt)
+	})
+
+	if len(earlierMessages) > historyArguments.Limit {
+		earlierMessages = earlierMessages[len(earlierMessages)-historyArguments.Limit:]
+	}
+
+	history := make([]agentHistoryMessage, len(earlierMessages))
+	for i, message := range earlierMessages {
+		history[i] = agentHistoryMessage{
+			Sender:    message.Sender,
+			Content:   message.Content,
+			CreatedAt: message.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return marshalToolResult(history)
+}
+
+func marshalToolResult(result interface{}) (string, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal result: %w", err)
+	}
+
+	return string(resultJSON), nil
+}
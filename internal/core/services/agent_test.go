@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// stubChatProvider returns the scripted completions in order and records the
+// requests it got
+type stubChatProvider struct {
+	completions []*openai.ChatCompletion
+	requests    []openai.ChatCompletionNewParams
+}
+
+func (provider *stubChatProvider) NewChatCompletion(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	provider.requests = append(provider.requests, params)
+
+	if len(provider.completions) == 0 {
+		return nil, errors.New("no more completions")
+	}
+
+	completion := provider.completions[0]
+	provider.completions = provider.completions[1:]
+	if completion == nil {
+		return nil, errors.New("openai is down")
+	}
+
+	return completion, nil
+}
+
+// toolChoices returns the tool choice of every request, empty when the LLM
+// was free to call tools
+func (provider *stubChatProvider) toolChoices() []string {
+	toolChoices := make([]string, len(provider.requests))
+	for i, request := range provider.requests {
+		toolChoices[i] = request.ToolChoice.OfAuto.Value
+	}
+
+	return toolChoices
+}
+
+// toolResults returns the tool results the last request gave back to the LLM
+func (provider *stubChatProvider) toolResults() []string {
+	if len(provider.requests) == 0 {
+		return nil
+	}
+
+	var toolResults []string
+	for _, message := range provider.requests[len(provider.requests)-1].Messages {
+		if message.OfTool != nil {
+			toolResults = append(toolResults, message.OfTool.Content.OfString.Value)
+		}
+	}
+
+	return toolResults
+}
+
+type stubEmbedder struct{}
+
+func (embedder *stubEmbedder) Embed(ctx context.Context, texts []string) ([]*domain.Embeddings, error) {
+	return []*domain.Embeddings{{Embeddings: []float64{0.5}, Text: texts[0], Tokens: 3}}, nil
+}
+
+// stubVectorDB returns the scripted search results in order and records the
+// options of the searches
+type stubVectorDB struct {
+	VectorDB
+	searchResults [][]*domain.RetrievedChunk
+	searchOptions []domain.SearchOptions
+}
+
+func (vectorDB *stubVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]*domain.RetrievedChunk, error) {
+	return vectorDB.Search(ctx, embeddings, domain.SearchOptions{})
+}
+
+func (vectorDB *stubVectorDB) Search(ctx context.Context, embeddings []float32, options domain.SearchOptions) ([]*domain.RetrievedChunk, error) {
+	vectorDB.searchOptions = append(vectorDB.searchOptions, options)
+
+	if len(vectorDB.searchResults) == 0 {
+		return nil, errors.New("no more search results")
+	}
+
+	searchResult := vectorDB.searchResults[0]
+	vectorDB.searchResults = vectorDB.searchResults[1:]
+
+	return searchResult, nil
+}
+
+type stubMessageRepository struct {
+	ports.MessageRepositoryInterface
+	initialMessage *domain.Message
+	createdMessage *domain.Message
+}
+
+func (repository *stubMessageRepository) GetMessage(ctx context.Context, messageID uuid.UUID) (*domain.Message, error) {
+	return repository.initialMessage, nil
+}
+
+func (repository *stubMessageRepository) CreateMessage(ctx context.Context, message *domain.Message) (uuid.UUID, error) {
+	repository.createdMessage = message
+
+	return uuid.UUID{0x22, 0x34, 0x56, 0x78}, nil
+}
+
+type stubChatSessionRepository struct {
+	ports.ChatSessionRepositoryInterface
+	chatSession *domain.ChatSession
+}
+
+func (repository *stubChatSessionRepository) GetChatSession(ctx context.Context, chatSessionID uuid.UUID) (*domain.ChatSession, error) {
+	return repository.chatSession, nil
+}
+
+type stubUsageService struct {
+	UsageServiceInterface
+	recordedUsage *domain.TokenUsage
+}
+
+func (service *stubUsageService) RecordUsage(ctx context.Context, usage *domain.TokenUsage) error {
+	service.recordedUsage = usage
+
+	return nil
+}
+
+func toolCallsCompletion(toolCalls ...openai.ChatCompletionMessageToolCall) *openai.ChatCompletion {
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", ToolCalls: toolCalls}}},
+		Usage:   openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 2},
+	}
+}
+
+func answerCompletion(content string) *openai.ChatCompletion {
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: content}}},
+		Usage:   openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 5},
+	}
+}
+
+func toolCall(id string, name string, arguments string) openai.ChatCompletionMessageToolCall {
+	return openai.ChatCompletionMessageToolCall{
+		ID:       id,
+		Type:     "function",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: name, Arguments: arguments},
+	}
+}
+
+func TestMessageService_GetAgentAnswerForMessage(t *testing.T) {
+	initialMessageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	replyMessageID := uuid.UUID{0x22, 0x34, 0x56, 0x78}
+	chatSessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	luke := &domain.RetrievedChunk{
+		ID:       "doc1-chunk-0",
+		Text:     "Luke Skywalker is a Jedi",
+		Score:    0.5,
+		Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+	}
+	leia := &domain.RetrievedChunk{
+		ID:       "doc1-chunk-1",
+		Text:     "Leia Organa is a princess",
+		Score:    0.25,
+		Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+	}
+	curated := &domain.RetrievedChunk{
+		ID:       "curated-1",
+		Text:     "Luke is the son of Anakin",
+		Score:    0.4,
+		Metadata: map[string]interface{}{domain.ChunkMetadataCurated: true},
+	}
+
+	tests := []struct {
+		name                string
+		agentMaxSteps       int
+		completions         []*openai.ChatCompletion
+		searchResults       [][]*domain.RetrievedChunk
+		expected            *domain.Message
+		expectedToolChoices []string
+		expectedToolResults []string
+		expectedUsage       *domain.TokenUsage
+		expectedError       string
+	}{
+		{
+			name:          "answers without tools",
+			agentMaxSteps: 5,
+			completions:   []*openai.ChatCompletion{answerCompletion("Luke is a Jedi")},
+			expected: &domain.Message{
+				ID:            replyMessageID,
+				ChatSessionID: chatSessionID,
+				Sender:        repositories.SYSTEM_SENDER,
+				Content:       "Luke is a Jedi",
+			},
+			expectedToolChoices: []string{""},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				MessageID:        replyMessageID,
+				PromptTokens:     10,
+				CompletionTokens: 5,
+			},
+		},
+		{
+			name:          "deduplicates the sources of the searches",
+			agentMaxSteps: 5,
+			completions: []*openai.ChatCompletion{
+				toolCallsCompletion(toolCall("call-1", agentToolSearchKnowledgeBase, `{"query":"Luke"}`)),
+				toolCallsCompletion(toolCall("call-2", agentToolSearchKnowledgeBase, `{"query":"Skywalkers","type":"people","top_k":2}`)),
+				answerCompletion("Luke is a Jedi and the son of Anakin"),
+			},
+			searchResults: [][]*domain.RetrievedChunk{
+				{luke, curated},
+				{luke, leia},
+			},
+			expected: &domain.Message{
+				ID:            replyMessageID,
+				ChatSessionID: chatSessionID,
+				Sender:        repositories.SYSTEM_SENDER,
+				Content:       "Luke is a Jedi and the son of Anakin",
+				Sources:       []*domain.RetrievedChunk{curated, luke, leia},
+				ToolCalls: []*domain.ToolCall{
+					{
+						Step:      1,
+						Name:      agentToolSearchKnowledgeBase,
+						Arguments: `{"query":"Luke"}`,
+						Result:    `[{"text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"},{"text":"[Curated answer] Luke is the son of Anakin","score":0.4,"type":"curated"}]`,
+					},
+					{
+						Step:      2,
+						Name:      agentToolSearchKnowledgeBase,
+						Arguments: `{"query":"Skywalkers","type":"people","top_k":2}`,
+						Result:    `[{"text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"},{"text":"Leia Organa is a princess","score":0.25,"type":"people"}]`,
+					},
+				},
+			},
+			expectedToolChoices: []string{"", "", ""},
+			expectedToolResults: []string{
+				`[{"text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"},{"text":"[Curated answer] Luke is the son of Anakin","score":0.4,"type":"curated"}]`,
+				`[{"text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"},{"text":"Leia Organa is a princess","score":0.25,"type":"people"}]`,
+			},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				MessageID:        replyMessageID,
+				PromptTokens:     30,
+				CompletionTokens: 9,
+				EmbeddingTokens:  6,
+			},
+		},
+		{
+			name:          "records tool errors and gives them back to the LLM",
+			agentMaxSteps: 5,
+			completions: []*openai.ChatCompletion{
+				toolCallsCompletion(
+					toolCall("call-1", agentToolCalculate, `{"operation":"divide","a":1,"b":0}`),
+					toolCall("call-2", "fly", `{}`),
+				),
+				answerCompletion("I cannot divide by zero"),
+			},
+			expected: &domain.Message{
+				ID:            replyMessageID,
+				ChatSessionID: chatSessionID,
+				Sender:        repositories.SYSTEM_SENDER,
+				Content:       "I cannot divide by zero",
+				ToolCalls: []*domain.ToolCall{
+					{
+						Step:      1,
+						Name:      agentToolCalculate,
+						Arguments: `{"operation":"divide","a":1,"b":0}`,
+						Result:    "error: division by zero",
+						IsError:   true,
+					},
+					{
+						Step:      1,
+						Name:      "fly",
+						Arguments: `{}`,
+						Result:    "error: unknown tool fly",
+						IsError:   true,
+					},
+				},
+			},
+			expectedToolChoices: []string{"", ""},
+			expectedToolResults: []string{"error: division by zero", "error: unknown tool fly"},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				MessageID:        replyMessageID,
+				PromptTokens:     20,
+				CompletionTokens: 7,
+			},
+		},
+		{
+			name:          "answers without tools after the max steps",
+			agentMaxSteps: 2,
+			completions: []*openai.ChatCompletion{
+				toolCallsCompletion(toolCall("call-1", agentToolCalculate, `{"operation":"add","a":1,"b":2}`)),
+				toolCallsCompletion(toolCall("call-2", agentToolCalculate, `{"operation":"multiply","a":2,"b":3}`)),
+				answerCompletion("3 and 6"),
+			},
+			expected: &domain.Message{
+				ID:            replyMessageID,
+				ChatSessionID: chatSessionID,
+				Sender:        repositories.SYSTEM_SENDER,
+				Content:       "3 and 6",
+				ToolCalls: []*domain.ToolCall{
+					{
+						Step:      1,
+						Name:      agentToolCalculate,
+						Arguments: `{"operation":"add","a":1,"b":2}`,
+						Result:    "3",
+					},
+					{
+						Step:      2,
+						Name:      agentToolCalculate,
+						Arguments: `{"operation":"multiply","a":2,"b":3}`,
+						Result:    "6",
+					},
+				},
+			},
+			expectedToolChoices: []string{"", "", "none"},
+			expectedToolResults: []string{"3", "6"},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				MessageID:        replyMessageID,
+				PromptTokens:     30,
+				CompletionTokens: 9,
+			},
+		},
+		{
+			name:          "empty answer after the max steps",
+			agentMaxSteps: 1,
+			completions: []*openai.ChatCompletion{
+				toolCallsCompletion(toolCall("call-1", agentToolCalculate, `{"operation":"add","a":1,"b":2}`)),
+				answerCompletion(""),
+			},
+			expectedToolChoices: []string{"", "none"},
+			expectedToolResults: []string{"3"},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				PromptTokens:     20,
+				CompletionTokens: 7,
+			},
+			expectedError: "received empty response from LLM",
+		},
+		{
+			name:          "completion error after a search",
+			agentMaxSteps: 5,
+			completions: []*openai.ChatCompletion{
+				toolCallsCompletion(toolCall("call-1", agentToolSearchKnowledgeBase, `{"query":"Luke"}`)),
+				nil,
+			},
+			searchResults:       [][]*domain.RetrievedChunk{{luke}},
+			expectedToolChoices: []string{"", ""},
+			expectedToolResults: []string{`[{"text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"}]`},
+			// the tokens of the first step were spent all the same
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				PromptTokens:     10,
+				CompletionTokens: 2,
+				EmbeddingTokens:  3,
+			},
+			expectedError: "openai is down",
+		},
+		{
+			name:                "completion error",
+			agentMaxSteps:       5,
+			completions:         []*openai.ChatCompletion{nil},
+			expectedToolChoices: []string{""},
+			expectedError:       "openai is down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatProvider := &stubChatProvider{completions: tt.completions}
+			messageRepository := &stubMessageRepository{
+				initialMessage: &domain.Message{
+					ID:            initialMessageID,
+					ChatSessionID: chatSessionID,
+					Sender:        repositories.USER_SENDER,
+					Content:       "Who is Luke?",
+				},
+			}
+			chatSessionRepository := &stubChatSessionRepository{
+				chatSession: &domain.ChatSession{ID: chatSessionID, UserID: userID, Title: "Luke"},
+			}
+			usageService := &stubUsageService{}
+
+			messageService := NewMessageService(
+				logger.NewLogger(context.Background()),
+				messageRepository,
+				chatSessionRepository,
+				nil,
+				&stubEmbedder{},
+				&stubVectorDB{searchResults: tt.searchResults},
+				chatProvider,
+				usageService,
+				nil,
+				tt.agentMaxSteps,
+				nil,
+			)
+
+			actual, err := messageService.GetAgentAnswerForMessage(context.Background(), initialMessageID)
+
+			assert.Equal(t, tt.expectedToolChoices, chatProvider.toolChoices())
+			assert.Equal(t, tt.expectedToolResults, chatProvider.toolResults())
+			assert.Equal(t, tt.expectedUsage, usageService.recordedUsage)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.Nil(t, messageRepository.createdMessage)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			// the tool calls are stored with the answer
+			assert.Equal(t, tt.expected, messageRepository.createdMessage)
+		})
+	}
+}
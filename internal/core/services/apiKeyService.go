@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"time"
+)
+
+const apiKeyPrefix = "rag_"
+
+const apiKeyBytes = 32
+
+// apiKeyDisplayLength is how much of the key is kept in clear to tell keys
+// apart in listings, the "rag_" prefix and 8 random characters
+const apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+// lastUsedInterval bounds how often the last use of a key is written, so that
+// a busy key does not cost a write per request
+const lastUsedInterval = time.Minute
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, userID uuid.UUID, name string, scopes []string) (*domain.APIKey, string, error)
+	GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID uuid.UUID, apiKeyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+type APIKeyService struct {
+	logger         logger.LoggerInterface
+	repository     ports.APIKeyRepositoryInterface
+	userRepository ports.UserRepositoryInterface
+}
+
+func NewAPIKeyService(
+	logger logger.LoggerInterface,
+	repository ports.APIKeyRepositoryInterface,
+	userRepository ports.UserRepositoryInterface,
+) *APIKeyService {
+	return &APIKeyService{
+		logger:         logger,
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+// CreateAPIKey generates a key for the user and stores its hash. The key is
+// returned only here, it cannot be recovered afterwards.
+func (s *APIKeyService) CreateAPIKey(
+	ctx context.Context,
+	userID uuid.UUID,
+	name string,
+	scopes []string,
+) (*domain.APIKey, string, error) {
+	bytes := make([]byte, apiKeyBytes)
+
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(bytes)
+
+	apiKey, err := s.repository.CreateAPIKey(ctx, &domain.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  key[:apiKeyDisplayLength],
+		KeyHash: domain.HashAPIKey(key),
+		Scopes:  scopes,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (s *APIKeyService) GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	return s.repository.GetUserAPIKeys(ctx, userID)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID uuid.UUID, apiKeyID uuid.UUID) error {
+	return s.repository.RevokeAPIKey(ctx, apiKeyID, userID)
+}
+
+// Authenticate returns the active API key matching the given key, together
+// with the role of its owner, and records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	apiKey, err := s.repository.GetAPIKeyByHash(ctx, domain.HashAPIKey(key))
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if errors.As(err, &resourceNotFound) {
+		return nil, customerrors.NewInvalidAPIKeyError()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, customerrors.NewInvalidAPIKeyError()
+	}
+
+	user, err := s.userRepository.GetUser(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.UserRole = user.Role
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedInterval {
+		// failing to record the use should not fail the request
+		err = s.repository.UpdateAPIKeyLastUsed(ctx, apiKey.ID, now)
+		if err != nil {
+			s.logger.Warn("Cannot update last use of api key",
+				map[string]interface{}{
+					"apiKeyID":     apiKey.ID.String(),
+					"errorMessage": err.Error(),
+				})
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return apiKey, nil
+}
@@ -5,28 +5,50 @@ import (
 	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
+	"time"
+)
+
+// DefaultDeletedChatSessionRetention is how long deleted chat sessions are
+// kept before PurgeDeletedChatSessions deletes them for good
+const DefaultDeletedChatSessionRetention = 30 * 24 * time.Hour
+
+const (
+	// DefaultChatSessionPageSize is the number of chat sessions listed when no
+	// limit is asked for
+	DefaultChatSessionPageSize = 20
+	// MaxChatSessionPageSize is the most chat sessions listed in a page
+	MaxChatSessionPageSize = 100
 )
 
 type ChatSessionServiceInterface interface {
-	GetChatSession(context.Context, uuid.UUID) (*domain.ChatSession, error)
-	GetUserChatSessions(context.Context, uuid.UUID) ([]*domain.ChatSession, error)
+	GetChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error)
+	GetUserChatSessions(ctx context.Context, userID uuid.UUID, filter *domain.ChatSessionFilter) (*domain.ChatSessionPage, error)
 	CreateChatSession(context.Context, *domain.ChatSession) (uuid.UUID, error)
 	UpdateChatSessionTitle(context.Context, uuid.UUID, string) error
+	RenameChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID, title string) (*domain.ChatSession, error)
+	ArchiveChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error)
+	UnarchiveChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error)
+	DeleteChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) error
+	PurgeDeletedChatSessions(ctx context.Context) (int64, error)
 }
 
 type ChatSessionService struct {
-	logger     logger.LoggerInterface
-	repository ports.ChatSessionRepositoryInterface
+	logger           logger.LoggerInterface
+	repository       ports.ChatSessionRepositoryInterface
+	deletedRetention time.Duration
 }
 
 func NewChatSessionService(
 	logger logger.LoggerInterface,
 	repository ports.ChatSessionRepositoryInterface,
+	deletedRetention time.Duration,
 ) *ChatSessionService {
 	return &ChatSessionService{
-		logger:     logger,
-		repository: repository,
+		logger:           logger,
+		repository:       repository,
+		deletedRetention: deletedRetention,
 	}
 }
 
@@ -38,10 +60,159 @@ func (s ChatSessionService) UpdateChatSessionTitle(ctx context.Context, uuid uui
 	return s.repository.UpdateChatSessionTitle(ctx, uuid, title)
 }
 
-func (s ChatSessionService) GetChatSession(ctx context.Context, uuid uuid.UUID) (*domain.ChatSession, error) {
-	return s.repository.GetChatSession(ctx, uuid)
+// GetChatSession returns the chat session only when it belongs to the user
+func (s ChatSessionService) GetChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error) {
+	chatSession, err := s.repository.GetChatSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chatSession.UserID != userID {
+		return nil, customerrors.NewUserMismatchError(sessionID.String(), userID.String())
+	}
+
+	return chatSession, nil
+}
+
+// GetUserChatSessions lists a page of the summaries of the user's chat
+// sessions. One more session than the limit is fetched to know whether there
+// is a next page.
+func (s ChatSessionService) GetUserChatSessions(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter *domain.ChatSessionFilter,
+) (*domain.ChatSessionPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultChatSessionPageSize
+	}
+	if limit > MaxChatSessionPageSize {
+		limit = MaxChatSessionPageSize
+	}
+
+	pageFilter := *filter
+	pageFilter.Limit = limit + 1
+
+	summaries, err := s.repository.GetUserChatSessionSummaries(ctx, userID, &pageFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.ChatSessionPage{
+		Sessions: summaries,
+	}
+
+	if len(summaries) > limit {
+		page.Sessions = summaries[:limit]
+
+		last := page.Sessions[limit-1]
+		page.NextCursor = &domain.ChatSessionCursor{
+			UpdatedAt: last.UpdatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	return page, nil
+}
+
+// RenameChatSession sets the title of a chat session of the user. A session
+// with a title is not given a generated one anymore.
+func (s ChatSessionService) RenameChatSession(
+	ctx context.Context,
+	sessionID uuid.UUID,
+	userID uuid.UUID,
+	title string,
+) (*domain.ChatSession, error) {
+	chatSession, err := s.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.UpdateChatSessionTitle(ctx, sessionID, title)
+	if err != nil {
+		return nil, err
+	}
+
+	chatSession.Title = title
+	chatSession.UpdatedAt = time.Now()
+
+	return chatSession, nil
 }
 
-func (s ChatSessionService) GetUserChatSessions(ctx context.Context, uuid uuid.UUID) ([]*domain.ChatSession, error) {
-	return s.repository.GetUserChatSessions(ctx, uuid)
+// ArchiveChatSession archives a chat session of the user, archiving an
+// archived session keeps when it was first archived
+func (s ChatSessionService) ArchiveChatSession(
+	ctx context.Context,
+	sessionID uuid.UUID,
+	userID uuid.UUID,
+) (*domain.ChatSession, error) {
+	chatSession, err := s.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chatSession.ArchivedAt != nil {
+		return chatSession, nil
+	}
+
+	archivedAt := time.Now()
+
+	err = s.repository.UpdateChatSessionArchivedAt(ctx, sessionID, &archivedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	chatSession.ArchivedAt = &archivedAt
+
+	return chatSession, nil
+}
+
+func (s ChatSessionService) UnarchiveChatSession(
+	ctx context.Context,
+	sessionID uuid.UUID,
+	userID uuid.UUID,
+) (*domain.ChatSession, error) {
+	chatSession, err := s.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chatSession.ArchivedAt == nil {
+		return chatSession, nil
+	}
+
+	err = s.repository.UpdateChatSessionArchivedAt(ctx, sessionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	chatSession.ArchivedAt = nil
+
+	return chatSession, nil
+}
+
+// DeleteChatSession soft deletes a chat session of the user. It is not found
+// from then on, and is purged with its messages after the retention.
+func (s ChatSessionService) DeleteChatSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) error {
+	_, err := s.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.DeleteChatSession(ctx, sessionID)
+}
+
+// PurgeDeletedChatSessions deletes for good the chat sessions that were
+// deleted longer than the retention ago, with their messages
+func (s ChatSessionService) PurgeDeletedChatSessions(ctx context.Context) (int64, error) {
+	purged, err := s.repository.PurgeChatSessions(ctx, time.Now().Add(-s.deletedRetention))
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("Purged deleted chat sessions", map[string]interface{}{
+		"count": purged,
+	})
+
+	return purged, nil
 }
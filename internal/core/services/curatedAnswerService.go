@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+)
+
+type CuratedAnswerServiceInterface interface {
+	GetCuratedAnswers(ctx context.Context, status string) ([]*domain.CuratedAnswer, error)
+	ApproveCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error)
+	RejectCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error)
+}
+
+// ChunkStore stores single chunks under a known ID, so that re-ingesting a
+// curated answer replaces its previous chunk instead of duplicating it.
+type ChunkStore interface {
+	UpsertChunk(ctx context.Context, id string, embedding *domain.Embeddings, metadata map[string]interface{}) error
+}
+
+type CuratedAnswerService struct {
+	logger     logger.LoggerInterface
+	repository ports.CuratedAnswerRepositoryInterface
+	embedder   Embedder
+	chunkStore ChunkStore
+}
+
+func NewCuratedAnswerService(
+	logger logger.LoggerInterface,
+	repository ports.CuratedAnswerRepositoryInterface,
+	embedder Embedder,
+	chunkStore ChunkStore,
+) *CuratedAnswerService {
+	return &CuratedAnswerService{
+		logger:     logger,
+		repository: repository,
+		embedder:   embedder,
+		chunkStore: chunkStore,
+	}
+}
+
+func (s *CuratedAnswerService) GetCuratedAnswers(ctx context.Context, status string) ([]*domain.CuratedAnswer, error) {
+	return s.repository.GetCuratedAnswers(ctx, status)
+}
+
+// ApproveCuratedAnswer ingests a pending curated answer in the vector store as
+// a chunk tagged with its question and marks it as approved.
+func (s *CuratedAnswerService) ApproveCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	curatedAnswer, err := s.getPendingCuratedAnswer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the question is embedded together with the answer so that the chunk is
+	// close to similar questions
+	text := fmt.Sprintf("Question: %s\nAnswer: %s", curatedAnswer.Question, curatedAnswer.Answer)
+
+	domainEmbeddings, err := s.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	vectorID := "curated-" + curatedAnswer.ID.String()
+
+	err = s.chunkStore.UpsertChunk(ctx, vectorID, domainEmbeddings[0], map[string]interface{}{
+		"source":                            "curated",
+		domain.ChunkMetadataCurated:         true,
+		domain.ChunkMetadataQuestion:        curatedAnswer.Question,
+		domain.ChunkMetadataCuratedAnswerID: curatedAnswer.ID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.UpdateCuratedAnswerStatus(ctx, id, domain.CuratedAnswerStatusApproved, vectorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetCuratedAnswer(ctx, id)
+}
+
+func (s *CuratedAnswerService) RejectCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	_, err := s.getPendingCuratedAnswer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.UpdateCuratedAnswerStatus(ctx, id, domain.CuratedAnswerStatusRejected, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetCuratedAnswer(ctx, id)
+}
+
+func (s *CuratedAnswerService) getPendingCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	curatedAnswer, err := s.repository.GetCuratedAnswer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if curatedAnswer.Status != domain.CuratedAnswerStatusPending {
+		return nil, customerrors.NewCuratedAnswerNotPendingError(id.String(), curatedAnswer.Status)
+	}
+
+	return curatedAnswer, nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"time"
+)
+
+type FeedbackServiceInterface interface {
+	GetFeedbackAnalytics(ctx context.Context, from, to time.Time, interval string) ([]*domain.FeedbackAnalytics, error)
+	GetFeedbackEntries(ctx context.Context, from, to time.Time) ([]*domain.FeedbackEntry, error)
+}
+
+type FeedbackService struct {
+	logger     logger.LoggerInterface
+	repository ports.FeedbackRepositoryInterface
+}
+
+func NewFeedbackService(
+	logger logger.LoggerInterface,
+	repository ports.FeedbackRepositoryInterface,
+) *FeedbackService {
+	return &FeedbackService{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+func (s FeedbackService) GetFeedbackAnalytics(ctx context.Context, from, to time.Time, interval string) ([]*domain.FeedbackAnalytics, error) {
+	return s.repository.GetFeedbackAnalytics(ctx, from, to, interval)
+}
+
+func (s FeedbackService) GetFeedbackEntries(ctx context.Context, from, to time.Time) ([]*domain.FeedbackEntry, error) {
+	return s.repository.GetFeedbackEntries(ctx, from, to)
+}
@@ -13,8 +13,13 @@ func NewJwtService(domain domain.JwtClaimsInterface) *JwtService {
 	return &JwtService{jwtDomain: domain}
 }
 
+// CreateJwtTokenService issues a token whose subject is the user's ID and that
+// carries the role of the user. Only the username is added as user info, the
+// password hash never leaves the service.
 func (j *JwtService) CreateJwtTokenService(user domain.User) (string, error) {
-	tokenValue, err := j.jwtDomain.CreateToken(user.Username, user)
+	tokenValue, err := j.jwtDomain.CreateToken(user.ID.String(), user.Role, map[string]interface{}{
+		"username": user.Username,
+	})
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/helpers"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"os"
+)
+
+type KnowledgeBaseServiceInterface interface {
+	Search(ctx context.Context, userID uuid.UUID, query string, options domain.SearchOptions) ([]*domain.RetrievedChunk, error)
+	GetDocuments() []*domain.Document
+	GetDocumentContent(ctx context.Context, documentType string) (*domain.Document, string, error)
+}
+
+// KnowledgeBaseService searches the knowledge base directly, without
+// answering, and reads the documents it was ingested from, for clients that
+// build their own answer like the MCP tools
+type KnowledgeBaseService struct {
+	logger       logger.LoggerInterface
+	embedder     Embedder
+	vectorDB     VectorDB
+	usageService UsageServiceInterface
+	documents    []*domain.Document
+}
+
+func NewKnowledgeBaseService(
+	logger logger.LoggerInterface,
+	embedder Embedder,
+	vectorDB VectorDB,
+	usageService UsageServiceInterface,
+	documents []*domain.Document,
+) *KnowledgeBaseService {
+	return &KnowledgeBaseService{
+		logger:       logger,
+		embedder:     embedder,
+		vectorDB:     vectorDB,
+		usageService: usageService,
+		documents:    documents,
+	}
+}
+
+// Search returns the chunks most similar to the query ordered by descending
+// score. Embedding the query costs tokens, so the search counts against the
+// quota of the user like answering a message does.
+func (s *KnowledgeBaseService) Search(
+	ctx context.Context,
+	userID uuid.UUID,
+	query string,
+	options domain.SearchOptions,
+) ([]*domain.RetrievedChunk, error) {
+	err := s.usageService.CheckQuota(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	domainEmbeddings, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	// the tokens are already spent, failing to meter them should not fail the
+	// search
+	err = s.usageService.RecordUsage(ctx, &domain.TokenUsage{
+		UserID:          userID,
+		EmbeddingTokens: domainEmbeddings[0].Tokens,
+	})
+	if err != nil {
+		s.logger.Error("Error in recording token usage",
+			map[string]interface{}{
+				"userID":       userID.String(),
+				"errorMessage": err.Error(),
+			})
+	}
+
+	vectorToFloat32 := helpers.Float64ToFloat32(domainEmbeddings[0].Embeddings)
+
+	return s.vectorDB.Search(ctx, vectorToFloat32, options)
+}
+
+func (s *KnowledgeBaseService) GetDocuments() []*domain.Document {
+	return s.documents
+}
+
+// GetDocumentContent returns the document of the type with its content, a
+// document whose file is missing is not found like an unknown one
+func (s *KnowledgeBaseService) GetDocumentContent(ctx context.Context, documentType string) (*domain.Document, string, error) {
+	for _, document := range s.documents {
+		if document.Type != documentType {
+			continue
+		}
+
+		content, err := os.ReadFile(document.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("file " + document.Path + " of document " + documentType + " not found"),
+			}
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return document, string(content), nil
+	}
+
+	return nil, "", customerrors.ResourceNotFoundErrorWrapper{
+		OriginalError: errors.New("document " + documentType + " not found"),
+	}
+}
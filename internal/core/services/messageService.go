@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"github.com/loukaspe/rag-golang/internal/core/ports"
@@ -12,13 +11,24 @@ import (
 	"github.com/loukaspe/rag-golang/pkg/helpers"
 	"github.com/loukaspe/rag-golang/pkg/logger"
 	"github.com/openai/openai-go"
-	"strings"
+	"time"
+)
+
+const (
+	// DefaultMessagePageSize is the number of messages of a chat session
+	// listed when no limit is asked for
+	DefaultMessagePageSize = 50
+	// MaxMessagePageSize is the most messages of a chat session listed in a page
+	MaxMessagePageSize = 200
 )
 
 type MessageServiceInterface interface {
 	CreateMessage(context.Context, uuid.UUID, *domain.Message) (uuid.UUID, error)
 	GetAnswerForMessage(context.Context, uuid.UUID) (*domain.Message, error)
+	GetAgentAnswerForMessage(context.Context, uuid.UUID) (*domain.Message, error)
 	UpdateMessageFeedback(ctx context.Context, message *domain.Message, userID uuid.UUID) error
+	GetChatSessionMessages(ctx context.Context, chatSessionID uuid.UUID, userID uuid.UUID, filter *domain.MessageFilter) (*domain.MessagePage, error)
+	RetrieveContext(ctx context.Context, query string) ([]*domain.RetrievedChunk, error)
 }
 
 type Embedder interface {
@@ -26,33 +36,55 @@ type Embedder interface {
 }
 
 type VectorDB interface {
-	SemanticSearch(ctx context.Context, embeddings []float32) ([]string, error)
+	SemanticSearch(ctx context.Context, embeddings []float32) ([]*domain.RetrievedChunk, error)
+	Search(ctx context.Context, embeddings []float32, options domain.SearchOptions) ([]*domain.RetrievedChunk, error)
+}
+
+// ChatProvider generates chat completions. It is an interface so that another
+// LLM provider, or a stub in tests, can be used instead of OpenAI.
+type ChatProvider interface {
+	NewChatCompletion(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error)
 }
 
 type MessageService struct {
-	logger                logger.LoggerInterface
-	messageRepository     ports.MessageRepositoryInterface
-	chatSessionRepository ports.ChatSessionRepositoryInterface
-	embedder              Embedder
-	vectorDB              VectorDB
-	openAIClient          *openai.Client
+	logger                  logger.LoggerInterface
+	messageRepository       ports.MessageRepositoryInterface
+	chatSessionRepository   ports.ChatSessionRepositoryInterface
+	curatedAnswerRepository ports.CuratedAnswerRepositoryInterface
+	embedder                Embedder
+	vectorDB                VectorDB
+	chatProvider            ChatProvider
+	usageService            UsageServiceInterface
+	vehicleComparisons      VehicleComparisonServiceInterface
+	agentMaxSteps           int
+	externalTools           ExternalToolProvider
 }
 
 func NewMessageService(
 	logger logger.LoggerInterface,
 	messageRepositoryInterface ports.MessageRepositoryInterface,
 	chatSessionRepository ports.ChatSessionRepositoryInterface,
+	curatedAnswerRepository ports.CuratedAnswerRepositoryInterface,
 	embedder Embedder,
 	vectorDB VectorDB,
-	openAIClient *openai.Client,
+	chatProvider ChatProvider,
+	usageService UsageServiceInterface,
+	vehicleComparisons VehicleComparisonServiceInterface,
+	agentMaxSteps int,
+	externalTools ExternalToolProvider,
 ) *MessageService {
 	return &MessageService{
-		logger:                logger,
-		messageRepository:     messageRepositoryInterface,
-		chatSessionRepository: chatSessionRepository,
-		embedder:              embedder,
-		vectorDB:              vectorDB,
-		openAIClient:          openAIClient,
+		logger:                  logger,
+		messageRepository:       messageRepositoryInterface,
+		chatSessionRepository:   chatSessionRepository,
+		curatedAnswerRepository: curatedAnswerRepository,
+		embedder:                embedder,
+		vectorDB:                vectorDB,
+		chatProvider:            chatProvider,
+		usageService:            usageService,
+		vehicleComparisons:      vehicleComparisons,
+		agentMaxSteps:           agentMaxSteps,
+		externalTools:           externalTools,
 	}
 }
 
@@ -66,28 +98,162 @@ func (s *MessageService) CreateMessage(ctx context.Context, userID uuid.UUID, me
 		return uuid.Nil, customerrors.NewUserMismatchError(message.ChatSessionID.String(), userID.String())
 	}
 
+	// a USER message is going to be answered, which costs tokens
+	if message.Sender == repositories.USER_SENDER {
+		err = s.usageService.CheckQuota(ctx, userID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
 	return s.messageRepository.CreateMessage(ctx, message)
 }
 
+// GetChatSessionMessages lists a page of the messages of a chat session of the
+// user, without loading the rest of them. One more message than the limit is
+// fetched to know whether there are more past the page.
+func (s *MessageService) GetChatSessionMessages(
+	ctx context.Context,
+	chatSessionID uuid.UUID,
+	userID uuid.UUID,
+	filter *domain.MessageFilter,
+) (*domain.MessagePage, error) {
+	chatSessionUserID, err := s.chatSessionRepository.GetChatSessionUserID(ctx, chatSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chatSessionUserID != userID {
+		return nil, customerrors.NewUserMismatchError(chatSessionID.String(), userID.String())
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultMessagePageSize
+	}
+	if limit > MaxMessagePageSize {
+		limit = MaxMessagePageSize
+	}
+
+	pageFilter := *filter
+	pageFilter.Limit = limit + 1
+
+	messages, err := s.messageRepository.GetChatSessionMessages(ctx, chatSessionID, &pageFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.MessagePage{
+		Messages: messages,
+		HasMore:  len(messages) > limit,
+	}
+
+	// the extra message is the one furthest from the cursor, the oldest
+	// unless paging after it
+	if page.HasMore && filter.After != nil {
+		page.Messages = messages[:limit]
+	}
+	if page.HasMore && filter.After == nil {
+		page.Messages = messages[1:]
+	}
+
+	return page, nil
+}
+
+// UpdateMessageFeedback validates and stores the feedback of a user on a
+// SYSTEM message of one of their chat sessions.
 func (s *MessageService) UpdateMessageFeedback(ctx context.Context, message *domain.Message, userID uuid.UUID) error {
 	chatSession, err := s.chatSessionRepository.GetChatSession(ctx, message.ChatSessionID)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.chatSessionRepository.GetChatSession(ctx, message.ChatSessionID)
+	if chatSession.UserID != userID {
+		return customerrors.NewUserMismatchError(message.ChatSessionID.String(), userID.String())
+	}
+
+	storedMessage, err := s.messageRepository.GetMessage(ctx, message.ID)
 	if err != nil {
 		return err
 	}
 
-	if chatSession.UserID != userID {
-		return customerrors.NewUserMismatchError(message.ChatSessionID.String(), userID.String())
+	if storedMessage.ChatSessionID != message.ChatSessionID {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("messageID " + message.ID.String() + " not found in chatSession " + message.ChatSessionID.String()),
+		}
 	}
 
-	return s.messageRepository.UpdateMessageFeedback(ctx, message.ID, *message.Feedback)
+	if storedMessage.Sender != repositories.SYSTEM_SENDER {
+		return customerrors.NewFeedbackNotAllowedError(message.ID.String(), storedMessage.Sender)
+	}
+
+	message.Feedback.SubmittedAt = time.Now()
+
+	err = s.messageRepository.UpdateMessageFeedback(ctx, message.ID, message.Feedback)
+	if err != nil {
+		return err
+	}
+
+	if message.Feedback.CorrectedAnswer == "" {
+		return nil
+	}
+
+	// the correction waits for an admin to approve it before it is used
+	// as context
+	_, err = s.curatedAnswerRepository.CreateCuratedAnswer(ctx, &domain.CuratedAnswer{
+		MessageID:      message.ID,
+		Question:       questionOfAnswer(chatSession.Messages, storedMessage),
+		OriginalAnswer: storedMessage.Content,
+		Answer:         message.Feedback.CorrectedAnswer,
+	})
+
+	return err
+}
+
+// questionOfAnswer returns the latest USER message of the session that was
+// sent before the answer.
+func questionOfAnswer(messages []*domain.Message, answer *domain.Message) string {
+	var question *domain.Message
+	for _, message := range messages {
+		if message.Sender != repositories.USER_SENDER || message.CreatedAt.After(answer.CreatedAt) {
+			continue
+		}
+
+		if question == nil || message.CreatedAt.After(question.CreatedAt) {
+			question = message
+		}
+	}
+
+	if question == nil {
+		return ""
+	}
+
+	return question.Content
 }
 
 func (s *MessageService) GetAnswerForMessage(ctx context.Context, initialMessageID uuid.UUID) (*domain.Message, error) {
+	return s.answerMessage(ctx, initialMessageID, s.generateRAGAnswer)
+}
+
+// GetAgentAnswerForMessage answers in AnswerModeAgent, see generateAgentAnswer
+func (s *MessageService) GetAgentAnswerForMessage(ctx context.Context, initialMessageID uuid.UUID) (*domain.Message, error) {
+	return s.answerMessage(ctx, initialMessageID, s.generateAgentAnswer)
+}
+
+// answerGenerator generates the SYSTEM message that answers the initial
+// message of the chat session, adding the tokens it spends to the usage
+type answerGenerator func(
+	ctx context.Context,
+	initialMessage *domain.Message,
+	chatSession *domain.ChatSession,
+	usage *domain.TokenUsage,
+) (*domain.Message, error)
+
+// answerMessage titles the chat session when it has no title yet, then stores
+// the answer of the generator. The tokens spent are recorded however it ends,
+// without a message when no answer was stored, so that failing answers count
+// against the quota too.
+func (s *MessageService) answerMessage(ctx context.Context, initialMessageID uuid.UUID, generateAnswer answerGenerator) (*domain.Message, error) {
 	initialMessage, err := s.messageRepository.GetMessage(ctx, initialMessageID)
 	if err != nil {
 		return nil, err
@@ -98,8 +264,14 @@ func (s *MessageService) GetAnswerForMessage(ctx context.Context, initialMessage
 		return nil, err
 	}
 
+	usage := &domain.TokenUsage{
+		UserID:        chatSession.UserID,
+		ChatSessionID: chatSession.ID,
+	}
+	defer s.recordUsage(ctx, usage)
+
 	if chatSession.Title == "" {
-		title, err := s.generateTitleFromOpenAI(ctx, initialMessage.Content)
+		title, err := s.generateTitleFromOpenAI(ctx, initialMessage.Content, usage)
 		if err != nil {
 			return nil, err
 		}
@@ -110,59 +282,131 @@ func (s *MessageService) GetAnswerForMessage(ctx context.Context, initialMessage
 		}
 	}
 
-	domainEmbeddings, err := s.embedder.Embed(ctx, []string{initialMessage.Content})
+	replyMessage, err := generateAnswer(ctx, initialMessage, chatSession, usage)
 	if err != nil {
 		return nil, err
 	}
 
-	// we only have on text so we only care for the first embedding row
-	vectorToFloat32 := helpers.Float64ToFloat32(domainEmbeddings[0].Embeddings)
+	replyMessage.ChatSessionID = initialMessage.ChatSessionID
+	replyMessage.Sender = repositories.SYSTEM_SENDER
 
-	accumulatedTextFromSearch, err := s.vectorDB.SemanticSearch(ctx, vectorToFloat32)
+	insertedMessageID, err := s.messageRepository.CreateMessage(ctx, replyMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	replyMessage.ID = insertedMessageID
+	usage.MessageID = insertedMessageID
+
+	return replyMessage, nil
+}
+
+// recordUsage records the tokens of the usage, if any were spent. Failing to
+// meter them should not fail the answer, which may already be stored.
+func (s *MessageService) recordUsage(ctx context.Context, usage *domain.TokenUsage) {
+	if usage.TotalTokens() == 0 {
+		return
+	}
+
+	err := s.usageService.RecordUsage(ctx, usage)
+	if err != nil {
+		s.logger.Error("Error in recording token usage",
+			map[string]interface{}{
+				"messageID":    usage.MessageID.String(),
+				"errorMessage": err.Error(),
+			})
+	}
+}
+
+// generateRAGAnswer answers in AnswerModeRAG, from the chunks most similar to
+// the initial message
+func (s *MessageService) generateRAGAnswer(
+	ctx context.Context,
+	initialMessage *domain.Message,
+	chatSession *domain.ChatSession,
+	usage *domain.TokenUsage,
+) (*domain.Message, error) {
+	retrievedChunks, err := s.retrieveContext(ctx, initialMessage.Content, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	accumulatedTextFromSearch := ContextTexts(retrievedChunks)
 
 	var answer string
 	if len(accumulatedTextFromSearch) == 0 {
 		answer = "The force is not strong enough for me to answer that question based on my context."
 	} else {
-		answer, err = s.generateAnswerFromOpenAI(ctx, accumulatedTextFromSearch, initialMessage.Content, chatSession.Messages)
+		answer, err = s.generateAnswerFromOpenAI(ctx, accumulatedTextFromSearch, initialMessage.Content, chatSession.Messages, usage)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	replyMessage := &domain.Message{
-		ChatSessionID: initialMessage.ChatSessionID,
-		Content:       answer,
-		Sender:        repositories.SYSTEM_SENDER,
+	return &domain.Message{
+		Content: answer,
+		Sources: retrievedChunks,
+	}, nil
+}
+
+// RetrieveContext embeds the query and returns the chunks of the knowledge base
+// that are most similar to it: curated answers first, then the rest, each
+// ordered by descending score.
+func (s *MessageService) RetrieveContext(ctx context.Context, query string) ([]*domain.RetrievedChunk, error) {
+	return s.retrieveContext(ctx, query, &domain.TokenUsage{})
+}
+
+// retrieveContext adds the tokens of embedding the query to the usage
+func (s *MessageService) retrieveContext(ctx context.Context, query string, usage *domain.TokenUsage) ([]*domain.RetrievedChunk, error) {
+	domainEmbeddings, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
 	}
 
-	insertedMessageID, err := s.messageRepository.CreateMessage(ctx, replyMessage)
+	usage.EmbeddingTokens += domainEmbeddings[0].Tokens
+
+	// we only have on text so we only care for the first embedding row
+	vectorToFloat32 := helpers.Float64ToFloat32(domainEmbeddings[0].Embeddings)
+
+	generalChunks, err := s.vectorDB.SemanticSearch(ctx, vectorToFloat32)
 	if err != nil {
 		return nil, err
 	}
 
-	replyMessage.ID = insertedMessageID
+	// curated answers were approved by an admin, so they take precedence
+	// over the rest of the knowledge base. They are searched on their own,
+	// as they would not make the top k of the general search when its
+	// passages score higher.
+	curatedChunks, err := s.vectorDB.Search(ctx, vectorToFloat32, domain.SearchOptions{Type: domain.ChunkTypeCurated})
+	if err != nil {
+		return nil, err
+	}
 
-	return replyMessage, nil
-}
+	retrievedChunks := make([]*domain.RetrievedChunk, 0, len(curatedChunks)+len(generalChunks))
+	seen := make(map[string]bool, len(curatedChunks)+len(generalChunks))
+	for _, chunk := range append(curatedChunks, generalChunks...) {
+		if seen[chunk.ID] {
+			continue
+		}
+		seen[chunk.ID] = true
 
-func (s *MessageService) generateAnswerFromOpenAI(ctx context.Context, text []string, initialMessage string, previousMessages []*domain.Message) (string, error) {
-	prompt := fmt.Sprintf(`Use the following context to answer the question.
-		Context:
-		%s
-		
-		Question:
-		%s
-		
-		Answer:`,
-		strings.Join(text, "\n"),
-		initialMessage,
-	)
+		retrievedChunks = append(retrievedChunks, chunk)
+	}
 
+	return retrievedChunks, nil
+}
+
+func (s *MessageService) generateAnswerFromOpenAI(
+	ctx context.Context,
+	text []string,
+	initialMessage string,
+	previousMessages []*domain.Message,
+	usage *domain.TokenUsage,
+) (string, error) {
 	completionParams := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-			openai.SystemMessage("Use only the provided context for answering the question."),
+			openai.UserMessage(AnswerPrompt(text, initialMessage)),
+			openai.SystemMessage(AnswerSystemPrompt),
 		},
 		Model: openai.ChatModelGPT4_1Nano,
 	}
@@ -179,11 +423,17 @@ func (s *MessageService) generateAnswerFromOpenAI(ctx context.Context, text []st
 		}
 	}
 
-	chatCompletion, err := s.openAIClient.Chat.Completions.New(ctx, completionParams)
+	chatCompletion, err := s.chatProvider.NewChatCompletion(ctx, completionParams)
 	if err != nil {
 		return "", err
 	}
 
+	addCompletionUsage(usage, chatCompletion)
+
+	if len(chatCompletion.Choices) == 0 {
+		return "", errors.New("received no choices from LLM")
+	}
+
 	if chatCompletion.Choices[0].Message.Content == "" {
 		return "", errors.New("received empty response from LLM")
 
@@ -192,16 +442,10 @@ func (s *MessageService) generateAnswerFromOpenAI(ctx context.Context, text []st
 	return chatCompletion.Choices[0].Message.Content, nil
 }
 
-func (s *MessageService) generateTitleFromOpenAI(ctx context.Context, initialMessage string) (string, error) {
-	prompt := fmt.Sprintf(`Summarize the following user message into a short, descriptive chat title (max 5 words):
-		%s
-		Answer:`,
-		initialMessage,
-	)
-
-	chatCompletion, err := s.openAIClient.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+func (s *MessageService) generateTitleFromOpenAI(ctx context.Context, initialMessage string, usage *domain.TokenUsage) (string, error) {
+	chatCompletion, err := s.chatProvider.NewChatCompletion(ctx, openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
+			openai.UserMessage(TitlePrompt(initialMessage)),
 		},
 		Model: openai.ChatModelGPT4_1Nano,
 	})
@@ -209,6 +453,12 @@ func (s *MessageService) generateTitleFromOpenAI(ctx context.Context, initialMes
 		return "", err
 	}
 
+	addCompletionUsage(usage, chatCompletion)
+
+	if len(chatCompletion.Choices) == 0 {
+		return "", errors.New("received no choices from LLM")
+	}
+
 	if chatCompletion.Choices[0].Message.Content == "" {
 		return "", errors.New("received empty response from LLM")
 
@@ -216,3 +466,8 @@ func (s *MessageService) generateTitleFromOpenAI(ctx context.Context, initialMes
 
 	return chatCompletion.Choices[0].Message.Content, nil
 }
+
+func addCompletionUsage(usage *domain.TokenUsage, chatCompletion *openai.ChatCompletion) {
+	usage.PromptTokens += chatCompletion.Usage.PromptTokens
+	usage.CompletionTokens += chatCompletion.Usage.CompletionTokens
+}
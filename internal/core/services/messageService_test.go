@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageService_RetrieveContext(t *testing.T) {
+	luke := &domain.RetrievedChunk{
+		ID:       "doc1-chunk-0",
+		Text:     "Luke Skywalker is a Jedi",
+		Score:    0.5,
+		Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+	}
+	leia := &domain.RetrievedChunk{
+		ID:       "doc1-chunk-1",
+		Text:     "Leia Organa is a princess",
+		Score:    0.45,
+		Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+	}
+	curated := &domain.RetrievedChunk{
+		ID:       "curated-1",
+		Text:     "Luke is the son of Anakin",
+		Score:    0.4,
+		Metadata: map[string]interface{}{domain.ChunkMetadataCurated: true},
+	}
+
+	tests := []struct {
+		name          string
+		searchResults [][]*domain.RetrievedChunk
+		expected      []*domain.RetrievedChunk
+		expectedError error
+	}{
+		{
+			name: "curated answer outside the general top k",
+			// the general search then the curated one
+			searchResults: [][]*domain.RetrievedChunk{
+				{luke, leia},
+				{curated},
+			},
+			expected: []*domain.RetrievedChunk{curated, luke, leia},
+		},
+		{
+			name: "curated answer in both searches",
+			searchResults: [][]*domain.RetrievedChunk{
+				{luke, curated, leia},
+				{curated},
+			},
+			expected: []*domain.RetrievedChunk{curated, luke, leia},
+		},
+		{
+			name: "no curated answers",
+			searchResults: [][]*domain.RetrievedChunk{
+				{luke, leia},
+				nil,
+			},
+			expected: []*domain.RetrievedChunk{luke, leia},
+		},
+		{
+			name: "curated search error",
+			searchResults: [][]*domain.RetrievedChunk{
+				{luke, leia},
+			},
+			expectedError: errors.New("no more search results"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectorDB := &stubVectorDB{searchResults: tt.searchResults}
+
+			messageService := NewMessageService(
+				logger.NewLogger(context.Background()),
+				nil,
+				nil,
+				nil,
+				&stubEmbedder{},
+				vectorDB,
+				nil,
+				nil,
+				nil,
+				DefaultAgentMaxSteps,
+				nil,
+			)
+
+			actual, err := messageService.RetrieveContext(context.Background(), "Who is Luke?")
+
+			assert.Equal(t, []domain.SearchOptions{{}, {Type: domain.ChunkTypeCurated}}, vectorDB.searchOptions)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMessageService_GetAnswerForMessage(t *testing.T) {
+	initialMessageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	replyMessageID := uuid.UUID{0x22, 0x34, 0x56, 0x78}
+	chatSessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	luke := &domain.RetrievedChunk{
+		ID:       "doc1-chunk-0",
+		Text:     "Luke Skywalker is a Jedi",
+		Score:    0.5,
+		Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+	}
+
+	tests := []struct {
+		name          string
+		completion    *openai.ChatCompletion
+		expected      *domain.Message
+		expectedUsage *domain.TokenUsage
+		expectedError string
+	}{
+		{
+			name:       "valid",
+			completion: answerCompletion("Luke is a Jedi"),
+			expected: &domain.Message{
+				ID:            replyMessageID,
+				ChatSessionID: chatSessionID,
+				Sender:        repositories.SYSTEM_SENDER,
+				Content:       "Luke is a Jedi",
+				Sources:       []*domain.RetrievedChunk{luke},
+			},
+			expectedUsage: &domain.TokenUsage{
+				UserID:           userID,
+				ChatSessionID:    chatSessionID,
+				MessageID:        replyMessageID,
+				PromptTokens:     10,
+				CompletionTokens: 5,
+				EmbeddingTokens:  3,
+			},
+		},
+		{
+			name:       "no choices",
+			completion: &openai.ChatCompletion{Usage: openai.CompletionUsage{PromptTokens: 10}},
+			expectedUsage: &domain.TokenUsage{
+				UserID:          userID,
+				ChatSessionID:   chatSessionID,
+				PromptTokens:    10,
+				EmbeddingTokens: 3,
+			},
+			expectedError: "received no choices from LLM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageRepository := &stubMessageRepository{
+				initialMessage: &domain.Message{
+					ID:            initialMessageID,
+					ChatSessionID: chatSessionID,
+					Sender:        repositories.USER_SENDER,
+					Content:       "Who is Luke?",
+				},
+			}
+			usageService := &stubUsageService{}
+
+			messageService := NewMessageService(
+				logger.NewLogger(context.Background()),
+				messageRepository,
+				&stubChatSessionRepository{
+					chatSession: &domain.ChatSession{ID: chatSessionID, UserID: userID, Title: "Luke"},
+				},
+				nil,
+				&stubEmbedder{},
+				&stubVectorDB{searchResults: [][]*domain.RetrievedChunk{{luke}, nil}},
+				&stubChatProvider{completions: []*openai.ChatCompletion{tt.completion}},
+				usageService,
+				nil,
+				DefaultAgentMaxSteps,
+				nil,
+			)
+
+			actual, err := messageService.GetAnswerForMessage(context.Background(), initialMessageID)
+
+			assert.Equal(t, tt.expectedUsage, usageService.recordedUsage)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.Nil(t, messageRepository.createdMessage)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+)
+
+type OIDCAuthServiceInterface interface {
+	Issuer() string
+	Authenticate(ctx context.Context, token string) (jwt.MapClaims, error)
+}
+
+// OIDCAuthService accepts bearer tokens of an OpenID Connect provider and maps
+// them to local users through the usernameClaim of the token. Users are
+// provisioned on their first request, so nothing has to be created upfront.
+type OIDCAuthService struct {
+	logger        logger.LoggerInterface
+	repository    ports.UserRepositoryInterface
+	verifier      *auth.OIDCVerifier
+	usernameClaim string
+}
+
+func NewOIDCAuthService(
+	logger logger.LoggerInterface,
+	repository ports.UserRepositoryInterface,
+	verifier *auth.OIDCVerifier,
+	usernameClaim string,
+) *OIDCAuthService {
+	return &OIDCAuthService{
+		logger:        logger,
+		repository:    repository,
+		verifier:      verifier,
+		usernameClaim: usernameClaim,
+	}
+}
+
+func (s *OIDCAuthService) Issuer() string {
+	return s.verifier.Issuer()
+}
+
+// Authenticate verifies the token and returns its claims with the subject and
+// the role replaced by the ID and the role of the local user, so that the rest
+// of the application handles provider tokens exactly like local ones. Roles
+// are managed locally, a role claim of the provider is never trusted.
+func (s *OIDCAuthService) Authenticate(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims, err := s.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, customerrors.NewInvalidTokenError(err.Error())
+	}
+
+	username, _ := claims[s.usernameClaim].(string)
+	if username == "" {
+		return nil, customerrors.NewInvalidTokenError("claim " + s.usernameClaim + " is missing")
+	}
+
+	user, err := s.findOrProvisionUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	claims["sub"] = user.ID.String()
+	claims[auth.RoleClaim] = user.Role
+
+	return claims, nil
+}
+
+// findOrProvisionUser looks the user up by an external ID namespaced by the
+// issuer, so that a provider identity can never take over a local account
+// with the same username
+func (s *OIDCAuthService) findOrProvisionUser(ctx context.Context, username string) (*domain.User, error) {
+	externalID := s.verifier.Issuer() + "#" + username
+
+	user, err := s.repository.GetUserByExternalID(ctx, externalID)
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if !errors.As(err, &resourceNotFound) {
+		return user, err
+	}
+
+	// provisioned users have no password, so they can never log in locally
+	user = &domain.User{
+		Username:   username,
+		ExternalID: externalID,
+		Role:       domain.RoleUser,
+	}
+
+	user.ID, err = s.repository.CreateUser(ctx, user)
+
+	var usernameTaken *customerrors.UsernameTakenError
+	if errors.As(err, &usernameTaken) {
+		// a concurrent request of the same identity provisioned it first
+		provisionedUser, getErr := s.repository.GetUserByExternalID(ctx, externalID)
+		if getErr == nil {
+			return provisionedUser, nil
+		}
+
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Provisioned user from identity provider",
+		map[string]interface{}{
+			"userID":     user.ID.String(),
+			"externalID": externalID,
+		})
+
+	return user, nil
+}
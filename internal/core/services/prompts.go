@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"strings"
+)
+
+// AnswerSystemPrompt keeps the answers to the context of AnswerPrompt
+const AnswerSystemPrompt = "Use only the provided context for answering the question. " +
+	"Context marked as [Curated answer] has been verified, prefer it when it answers the question."
+
+// ContextTexts returns the texts of the retrieved chunks to put in the
+// context of AnswerPrompt, marking the curated answers
+func ContextTexts(retrievedChunks []*domain.RetrievedChunk) []string {
+	texts := make([]string, len(retrievedChunks))
+	for i, retrievedChunk := range retrievedChunks {
+		texts[i] = retrievedChunk.Text
+		if retrievedChunk.IsCurated() {
+			texts[i] = "[Curated answer] " + retrievedChunk.Text
+		}
+	}
+
+	return texts
+}
+
+// AnswerPrompt asks to answer the question from the context
+func AnswerPrompt(context []string, question string) string {
+	return fmt.Sprintf(`Use the following context to answer the question.
+		Context:
+		%s
+		
+		Question:
+		%s
+		
+		Answer:`,
+		strings.Join(context, "\n"),
+		question,
+	)
+}
+
+// TitlePrompt asks to summarize the first message of a chat session into its
+// title
+func TitlePrompt(message string) string {
+	return fmt.Sprintf(`Summarize the following user message into a short, descriptive chat title (max 5 words):
+		%s
+		Answer:`,
+		message,
+	)
+}
+
+// AgentSystemPrompt lets the LLM decide which tools it needs before answering
+const AgentSystemPrompt = "You answer questions about Star Wars from a knowledge base. " +
+	"Use the tools to search the knowledge base, to compare vehicles, to calculate and to read the earlier messages of the chat session, " +
+	"prefer comparing vehicles to searching for how much one is faster, slower or else than another, " +
+	"then answer only from what the tools returned. " +
+	"Passages marked as [Curated answer] have been verified, prefer them when they answer the question."
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"sync"
+	"time"
+)
+
+type TokenDenylistServiceInterface interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+type denylistEntry struct {
+	revoked   bool
+	checkedAt time.Time
+}
+
+// TokenDenylistService checks revoked access tokens on every authenticated
+// request, so lookups are cached for cacheTTL. Tokens revoked through this
+// instance are denied immediately; tokens revoked through another instance are
+// denied at most cacheTTL later.
+type TokenDenylistService struct {
+	logger     logger.LoggerInterface
+	repository ports.RevokedTokenRepositoryInterface
+	cacheTTL   time.Duration
+
+	mu          sync.RWMutex
+	cache       map[string]denylistEntry
+	lastEvicted time.Time
+}
+
+func NewTokenDenylistService(
+	logger logger.LoggerInterface,
+	repository ports.RevokedTokenRepositoryInterface,
+	cacheTTL time.Duration,
+) *TokenDenylistService {
+	return &TokenDenylistService{
+		logger:     logger,
+		repository: repository,
+		cacheTTL:   cacheTTL,
+		cache:      make(map[string]denylistEntry),
+	}
+}
+
+func (s *TokenDenylistService) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	err := s.repository.RevokeToken(ctx, tokenID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cache[tokenID] = denylistEntry{revoked: true, checkedAt: time.Now()}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *TokenDenylistService) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	entry, ok := s.cache[tokenID]
+	s.mu.RUnlock()
+
+	if ok && now.Sub(entry.checkedAt) < s.cacheTTL {
+		return entry.revoked, nil
+	}
+
+	revoked, err := s.repository.IsTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.evictExpired(now)
+	s.cache[tokenID] = denylistEntry{revoked: revoked, checkedAt: now}
+	s.mu.Unlock()
+
+	return revoked, nil
+}
+
+// evictExpired drops the stale answers, at most once per cacheTTL, so that the
+// cache does not grow with every token ever seen. Must be called with mu held.
+func (s *TokenDenylistService) evictExpired(now time.Time) {
+	if now.Sub(s.lastEvicted) < s.cacheTTL {
+		return
+	}
+
+	for tokenID, entry := range s.cache {
+		if now.Sub(entry.checkedAt) >= s.cacheTTL {
+			delete(s.cache, tokenID)
+		}
+	}
+
+	s.lastEvicted = now
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"time"
+)
+
+type UsageServiceInterface interface {
+	RecordUsage(ctx context.Context, usage *domain.TokenUsage) error
+	CheckQuota(ctx context.Context, userID uuid.UUID) error
+	GetUserUsage(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]*domain.UsageAggregate, error)
+	GetQuotaStatus(ctx context.Context, userID uuid.UUID) (*domain.QuotaStatus, error)
+}
+
+// UsageService meters the OpenAI tokens spent on answering the messages of
+// every user and enforces the monthly quota on them
+type UsageService struct {
+	logger     logger.LoggerInterface
+	repository ports.UsageRepositoryInterface
+	quota      domain.UsageQuota
+}
+
+func NewUsageService(
+	logger logger.LoggerInterface,
+	repository ports.UsageRepositoryInterface,
+	quota domain.UsageQuota,
+) *UsageService {
+	return &UsageService{
+		logger:     logger,
+		repository: repository,
+		quota:      quota,
+	}
+}
+
+func (s *UsageService) RecordUsage(ctx context.Context, usage *domain.TokenUsage) error {
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	id, err := s.repository.CreateTokenUsage(ctx, usage)
+	if err != nil {
+		return err
+	}
+
+	usage.ID = id
+
+	return nil
+}
+
+// CheckQuota returns a QuotaExceededError when the user has used up a quota of
+// the current month. The check happens before a message is answered, so the
+// answer that crosses the quota is still served.
+func (s *UsageService) CheckQuota(ctx context.Context, userID uuid.UUID) error {
+	if s.quota.MonthlyTokens <= 0 && s.quota.MonthlyMessages <= 0 {
+		return nil
+	}
+
+	status, err := s.GetQuotaStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if s.quota.MonthlyTokens > 0 && status.UsedTokens >= s.quota.MonthlyTokens {
+		return customerrors.NewQuotaExceededError(customerrors.QuotaTokens, s.quota.MonthlyTokens, status.ResetsAt)
+	}
+
+	if s.quota.MonthlyMessages > 0 && status.UsedMessages >= s.quota.MonthlyMessages {
+		return customerrors.NewQuotaExceededError(customerrors.QuotaMessages, s.quota.MonthlyMessages, status.ResetsAt)
+	}
+
+	return nil
+}
+
+func (s *UsageService) GetUserUsage(
+	ctx context.Context,
+	userID uuid.UUID,
+	from time.Time,
+	to time.Time,
+	interval string,
+) ([]*domain.UsageAggregate, error) {
+	return s.repository.GetUserUsage(ctx, userID, from, to, interval)
+}
+
+func (s *UsageService) GetQuotaStatus(ctx context.Context, userID uuid.UUID) (*domain.QuotaStatus, error) {
+	monthStart := domain.StartOfMonth(time.Now())
+	resetsAt := monthStart.AddDate(0, 1, 0)
+
+	total, err := s.repository.GetUserUsageTotal(ctx, userID, monthStart, resetsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.QuotaStatus{
+		Quota:        s.quota,
+		UsedTokens:   total.TotalTokens(),
+		UsedMessages: total.Messages,
+		ResetsAt:     resetsAt,
+	}, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"time"
+)
+
+// dummyPasswordHash is compared against when the username does not exist, so
+// that a login for an unknown user costs as much as one with a wrong password.
+const dummyPasswordHash = "$2a$10$K3KohgU8Us19cdE/qRczRO2EJAG2hiq9KvuUK7p0u.WoqpN5PwkdG"
+
+const refreshTokenBytes = 32
+
+type UserServiceInterface interface {
+	Register(ctx context.Context, username string, password string) (*domain.User, error)
+	Login(ctx context.Context, username string, password string) (*domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
+	Logout(ctx context.Context, userID uuid.UUID, refreshToken string, accessTokenID string, accessTokenExpiresAt time.Time) error
+	UpdateUserRole(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+type UserService struct {
+	logger                 logger.LoggerInterface
+	repository             ports.UserRepositoryInterface
+	refreshTokenRepository ports.RefreshTokenRepositoryInterface
+	tokenDenylist          TokenDenylistServiceInterface
+	jwtService             *JwtService
+	accessTokenTTL         time.Duration
+	refreshTokenTTL        time.Duration
+}
+
+func NewUserService(
+	logger logger.LoggerInterface,
+	repository ports.UserRepositoryInterface,
+	refreshTokenRepository ports.RefreshTokenRepositoryInterface,
+	tokenDenylist TokenDenylistServiceInterface,
+	jwtService *JwtService,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+) *UserService {
+	return &UserService{
+		logger:                 logger,
+		repository:             repository,
+		refreshTokenRepository: refreshTokenRepository,
+		tokenDenylist:          tokenDenylist,
+		jwtService:             jwtService,
+		accessTokenTTL:         accessTokenTTL,
+		refreshTokenTTL:        refreshTokenTTL,
+	}
+}
+
+// Register stores a new user with a bcrypt hash of the password
+func (s *UserService) Register(ctx context.Context, username string, password string) (*domain.User, error) {
+	user := &domain.User{
+		Username: username,
+		Password: password,
+		Role:     domain.RoleUser,
+	}
+
+	err := user.HashPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID, err = s.repository.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Login verifies the password against the stored hash and returns an access
+// token whose subject is the user's ID, together with a refresh token
+func (s *UserService) Login(ctx context.Context, username string, password string) (*domain.TokenPair, error) {
+	user, err := s.repository.GetUserByUsername(ctx, username)
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if errors.As(err, &resourceNotFound) {
+		unknownUser := &domain.User{Password: dummyPasswordHash}
+		_ = unknownUser.CheckPassword(password)
+
+		return nil, customerrors.NewInvalidCredentialsError()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = user.CheckPassword(password); err != nil {
+		return nil, customerrors.NewInvalidCredentialsError()
+	}
+
+	return s.issueTokenPair(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new pair and revokes it, so every
+// refresh token is used once. Presenting an already revoked refresh token means
+// that it leaked, so all the refresh tokens of its user are revoked.
+func (s *UserService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	storedRefreshToken, err := s.refreshTokenRepository.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if errors.As(err, &resourceNotFound) {
+		return nil, customerrors.NewInvalidRefreshTokenError()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if storedRefreshToken.RevokedAt != nil {
+		return nil, s.revokeReusedRefreshToken(ctx, storedRefreshToken)
+	}
+
+	if !storedRefreshToken.IsActive(time.Now()) {
+		return nil, customerrors.NewInvalidRefreshTokenError()
+	}
+
+	err = s.refreshTokenRepository.RevokeRefreshToken(ctx, storedRefreshToken.ID)
+	if errors.As(err, &resourceNotFound) {
+		// another request rotated the same token in the meantime
+		return nil, s.revokeReusedRefreshToken(ctx, storedRefreshToken)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repository.GetUser(ctx, storedRefreshToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(ctx, user)
+}
+
+// Logout revokes the access token of the request and, if given, the refresh
+// token of the same user
+func (s *UserService) Logout(
+	ctx context.Context,
+	userID uuid.UUID,
+	refreshToken string,
+	accessTokenID string,
+	accessTokenExpiresAt time.Time,
+) error {
+	err := s.tokenDenylist.RevokeToken(ctx, accessTokenID, accessTokenExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	storedRefreshToken, err := s.refreshTokenRepository.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+
+	var resourceNotFound customerrors.ResourceNotFoundErrorWrapper
+	if errors.As(err, &resourceNotFound) || (err == nil && storedRefreshToken.UserID != userID) {
+		return customerrors.NewInvalidRefreshTokenError()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// logging out twice is not an error
+	if storedRefreshToken.RevokedAt != nil {
+		return nil
+	}
+
+	err = s.refreshTokenRepository.RevokeRefreshToken(ctx, storedRefreshToken.ID)
+	if errors.As(err, &resourceNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// UpdateUserRole changes the role of a user. Access tokens carry the role, so
+// the change applies to the user's tokens issued from then on.
+func (s *UserService) UpdateUserRole(ctx context.Context, userID uuid.UUID, role string) error {
+	return s.repository.UpdateUserRole(ctx, userID, role)
+}
+
+func (s *UserService) issueTokenPair(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	now := time.Now()
+
+	accessToken, err := s.jwtService.CreateJwtTokenService(*user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenExpiresAt := now.Add(s.refreshTokenTTL)
+
+	_, err = s.refreshTokenRepository.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: domain.HashRefreshToken(refreshToken),
+		ExpiresAt: refreshTokenExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  now.Add(s.accessTokenTTL),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
+	}, nil
+}
+
+func (s *UserService) revokeReusedRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) error {
+	s.logger.Warn("Revoked refresh token reused, revoking all refresh tokens of the user",
+		map[string]interface{}{
+			"userID":         refreshToken.UserID.String(),
+			"refreshTokenID": refreshToken.ID.String(),
+		})
+
+	err := s.refreshTokenRepository.RevokeUserRefreshTokens(ctx, refreshToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	return customerrors.NewInvalidRefreshTokenError()
+}
+
+func generateRefreshToken() (string, error) {
+	bytes := make([]byte, refreshTokenBytes)
+
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
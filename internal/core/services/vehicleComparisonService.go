@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/ports"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/vehicles"
+	"math"
+	"strings"
+)
+
+type VehicleComparisonServiceInterface interface {
+	IngestVehicleComparisons(ctx context.Context, text string) (int, error)
+	CompareVehicles(ctx context.Context, filter *domain.VehicleComparisonFilter) ([]*domain.VehicleComparisonFact, error)
+}
+
+type VehicleComparisonService struct {
+	logger     logger.LoggerInterface
+	repository ports.VehicleComparisonRepositoryInterface
+}
+
+func NewVehicleComparisonService(
+	logger logger.LoggerInterface,
+	repository ports.VehicleComparisonRepositoryInterface,
+) *VehicleComparisonService {
+	return &VehicleComparisonService{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// IngestVehicleComparisons parses the rows of the vehicles document and
+// replaces the stored comparisons with them, returning how many were stored.
+// Rows that cannot be parsed are logged and skipped.
+func (s *VehicleComparisonService) IngestVehicleComparisons(ctx context.Context, text string) (int, error) {
+	comparisons, unparsed := vehicles.ParseComparisons(text)
+	if len(unparsed) > 0 {
+		s.logger.Warn("Skipped vehicle comparisons that could not be parsed", map[string]interface{}{
+			"lines": unparsed,
+		})
+	}
+
+	err := s.repository.ReplaceVehicleComparisons(ctx, comparisons)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(comparisons), nil
+}
+
+// CompareVehicles returns every statement of the vehicles document matching
+// the filter, restated from the side of the filter's subject. The document is
+// not always consistent, so all the matching statements are returned instead
+// of being chained or merged.
+func (s *VehicleComparisonService) CompareVehicles(
+	ctx context.Context,
+	filter *domain.VehicleComparisonFilter,
+) ([]*domain.VehicleComparisonFact, error) {
+	comparisons, err := s.repository.GetVehicleComparisons(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(comparisons) == 0 {
+		return nil, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("no comparisons of " + filter.Subject + " found"),
+		}
+	}
+
+	facts := make([]*domain.VehicleComparisonFact, len(comparisons))
+	for i, comparison := range comparisons {
+		facts[i] = comparisonFact(comparison, filter.Subject)
+	}
+
+	return facts, nil
+}
+
+// comparisonFact restates the comparison so that its subject is the given
+// one: "A are 25% fast than B" means that B is 1/1.25 as fast as A, so 20%
+// less fast.
+func comparisonFact(comparison *domain.VehicleComparison, subject string) *domain.VehicleComparisonFact {
+	ratio := 1 + comparison.Percent/100
+
+	fact := &domain.VehicleComparisonFact{
+		Subject:   comparison.Subject,
+		Attribute: comparison.Attribute,
+		Object:    comparison.Object,
+		Ratio:     ratio,
+		Percent:   comparison.Percent,
+		Statement: vehicles.Statement(comparison),
+		Line:      comparison.Line,
+	}
+
+	if !strings.EqualFold(comparison.Subject, subject) {
+		fact.Subject, fact.Object = comparison.Object, comparison.Subject
+		fact.Ratio = 1 / ratio
+		fact.Percent = (fact.Ratio - 1) * 100
+		fact.Inverse = true
+	}
+
+	fact.Ratio = math.Round(fact.Ratio*10000) / 10000
+	fact.Percent = math.Round(fact.Percent*100) / 100
+
+	return fact
+}
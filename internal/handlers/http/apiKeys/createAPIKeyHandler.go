@@ -0,0 +1,113 @@
+package apiKeys
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type CreateAPIKeyHandler struct {
+	APIKeyService services.APIKeyServiceInterface
+	logger        logger.LoggerInterface
+}
+
+func NewCreateAPIKeyHandler(
+	service services.APIKeyServiceInterface,
+	logger logger.LoggerInterface,
+) *CreateAPIKeyHandler {
+	return &CreateAPIKeyHandler{
+		APIKeyService: service,
+		logger:        logger,
+	}
+}
+
+// @Summary		Creates an API key
+// @Description	Creates an API key of the user with the given scopes. The key is only shown in this response, store it safely. API keys cannot manage API keys.
+// @Security		BearerAuth
+// @Param			user_id	path		string				true	"user id"
+// @Param			request	body		CreateAPIKeyRequest	true	"name and scopes of the key"
+// @Success		201		{object}	APIKeyResponse
+// @Failure		400		{object}	APIKeyResponse	"Error in request body"
+// @Failure		401		{object}	APIKeyResponse	"Authentication error"
+// @Failure		403		{object}	APIKeyResponse	"Not the authenticated user or authenticated with an API key"
+// @Failure		500		{object}	APIKeyResponse	"Internal Server Error"
+// @Router			/users/{user_id}/api-keys [post]
+func (handler *CreateAPIKeyHandler) CreateAPIKeyController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &APIKeyResponse{}
+	request := &CreateAPIKeyRequest{}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed user uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in creating api key",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed api key request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	apiKey, key, err := handler.APIKeyService.CreateAPIKey(ctx, userID, request.Name, request.Scopes)
+	if err != nil {
+		handler.logger.Error("Error in creating api key",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in creating api key"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = APIKeyResponseFromModel(apiKey)
+	response.Key = key
+
+	handler.JsonResponse(w, http.StatusCreated, response)
+}
+
+func (handler *CreateAPIKeyHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *APIKeyResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in creating api key - json response"
+
+		handler.logger.Error("Error in creating api key - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
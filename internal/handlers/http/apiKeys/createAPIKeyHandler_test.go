@@ -0,0 +1,131 @@
+package apiKeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKeyHandler_CreateAPIKeyController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockAPIKeyServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                string
+		body                string
+		mockServiceCalled   bool
+		mockServiceResponse *domain.APIKey
+		mockServiceKey      string
+		mockServiceError    error
+		expected            []byte
+		expectedStatusCode  int
+	}{
+		{
+			name:              "valid",
+			body:              `{"name":"batch job","scopes":["sessions:read","messages:send"]}`,
+			mockServiceCalled: true,
+			mockServiceResponse: &domain.APIKey{
+				ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				UserID:    userID,
+				Name:      "batch job",
+				Prefix:    "rag_abcd1234",
+				Scopes:    []string{"sessions:read", "messages:send"},
+				CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+			},
+			mockServiceKey: "rag_abcd1234secret",
+			expected: json.RawMessage(`{"id":"32345678-0000-0000-0000-000000000000","name":"batch job","prefix":"rag_abcd1234","scopes":["sessions:read","messages:send"],"key":"rag_abcd1234secret","createdAt":"2025-05-29T10:00:00Z"}
+`),
+			expectedStatusCode: 201,
+		},
+		{
+			name: "malformed body",
+			body: `{"name":`,
+			expected: json.RawMessage(`{"errorMessage":"malformed api key request"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "missing name",
+			body: `{"scopes":["sessions:read"]}`,
+			expected: json.RawMessage(`{"errorMessage":"name must be between 1 and 100 characters"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "no scopes",
+			body: `{"name":"batch job"}`,
+			expected: json.RawMessage(`{"errorMessage":"at least one scope is required"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "unknown scope",
+			body: `{"name":"batch job","scopes":["users:delete"]}`,
+			expected: json.RawMessage(`{"errorMessage":"unknown scope users:delete, expected one of sessions:read, messages:send, kb:manage"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:              "service error",
+			body:              `{"name":"batch job","scopes":["kb:manage"]}`,
+			mockServiceCalled: true,
+			mockServiceError:  errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in creating api key"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/users/"+userID.String()+"/api-keys", bytes.NewBuffer([]byte(tt.body)))
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": userID.String()})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			if tt.mockServiceCalled {
+				request := &CreateAPIKeyRequest{}
+				_ = json.Unmarshal([]byte(tt.body), request)
+
+				mockService.EXPECT().
+					CreateAPIKey(gomock.Any(), userID, request.Name, request.Scopes).
+					Return(tt.mockServiceResponse, tt.mockServiceKey, tt.mockServiceError)
+			}
+
+			handler := &CreateAPIKeyHandler{
+				APIKeyService: mockService,
+				logger:        logger,
+			}
+			sut := handler.CreateAPIKeyController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package apiKeys
+
+import (
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"strings"
+	"time"
+)
+
+const apiKeyNameMaxLength = 100
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes" enums:"sessions:read,messages:send,kb:manage"`
+}
+
+func (request *CreateAPIKeyRequest) Validate() error {
+	if request.Name == "" || len(request.Name) > apiKeyNameMaxLength {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+
+	if len(request.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+
+	for _, scope := range request.Scopes {
+		if !domain.IsAPIKeyScope(scope) {
+			return errors.New("unknown scope " + scope + ", expected one of " + strings.Join(domain.APIKeyScopes, ", "))
+		}
+	}
+
+	return nil
+}
+
+type APIKeyResponse struct {
+	ID     string   `json:"id,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Prefix string   `json:"prefix,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	// Key is only returned when the key is created
+	Key          string `json:"key,omitempty"`
+	LastUsedAt   string `json:"lastUsedAt,omitempty"`
+	RevokedAt    string `json:"revokedAt,omitempty"`
+	CreatedAt    string `json:"createdAt,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+func APIKeyResponseFromModel(apiKey *domain.APIKey) *APIKeyResponse {
+	response := &APIKeyResponse{
+		ID:        apiKey.ID.String(),
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+	}
+
+	if apiKey.LastUsedAt != nil {
+		response.LastUsedAt = apiKey.LastUsedAt.Format(time.RFC3339)
+	}
+
+	if apiKey.RevokedAt != nil {
+		response.RevokedAt = apiKey.RevokedAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+type APIKeysResponse struct {
+	APIKeys      []APIKeyResponse `json:"apiKeys"`
+	ErrorMessage string           `json:"errorMessage,omitempty"`
+}
+
+func APIKeysResponseFromModel(apiKeys []*domain.APIKey) *APIKeysResponse {
+	responses := make([]APIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		responses[i] = *APIKeyResponseFromModel(apiKey)
+	}
+
+	return &APIKeysResponse{
+		APIKeys: responses,
+	}
+}
@@ -0,0 +1,86 @@
+package apiKeys
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type GetAPIKeysHandler struct {
+	APIKeyService services.APIKeyServiceInterface
+	logger        logger.LoggerInterface
+}
+
+func NewGetAPIKeysHandler(
+	service services.APIKeyServiceInterface,
+	logger logger.LoggerInterface,
+) *GetAPIKeysHandler {
+	return &GetAPIKeysHandler{
+		APIKeyService: service,
+		logger:        logger,
+	}
+}
+
+// @Summary		Lists the API keys of a user
+// @Description	Lists the API keys of the user, revoked ones included, newest first. Only the prefix of each key is returned.
+// @Security		BearerAuth
+// @Param			user_id	path		string	true	"user id"
+// @Success		200		{object}	APIKeysResponse
+// @Failure		400		{object}	APIKeysResponse	"Error in path parameters"
+// @Failure		401		{object}	APIKeysResponse	"Authentication error"
+// @Failure		403		{object}	APIKeysResponse	"Not the authenticated user or authenticated with an API key"
+// @Failure		500		{object}	APIKeysResponse	"Internal Server Error"
+// @Router			/users/{user_id}/api-keys [get]
+func (handler *GetAPIKeysHandler) GetAPIKeysController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &APIKeysResponse{}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed user uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	apiKeys, err := handler.APIKeyService.GetUserAPIKeys(ctx, userID)
+	if err != nil {
+		handler.logger.Error("Error in getting api keys",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting api keys"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = APIKeysResponseFromModel(apiKeys)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *GetAPIKeysHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *APIKeysResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in getting api keys - json response"
+
+		handler.logger.Error("Error in getting api keys - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,103 @@
+package apiKeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAPIKeysHandler_GetAPIKeysController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockAPIKeyServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	lastUsedAt := time.Date(2025, 5, 30, 9, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                string
+		mockServiceResponse []*domain.APIKey
+		mockServiceError    error
+		expected            []byte
+		expectedStatusCode  int
+	}{
+		{
+			name: "valid",
+			mockServiceResponse: []*domain.APIKey{
+				{
+					ID:         uuid.UUID{0x32, 0x34, 0x56, 0x78},
+					UserID:     userID,
+					Name:       "batch job",
+					Prefix:     "rag_abcd1234",
+					KeyHash:    "hash",
+					Scopes:     []string{"sessions:read"},
+					LastUsedAt: &lastUsedAt,
+					RevokedAt:  &revokedAt,
+					CreatedAt:  time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			expected: json.RawMessage(`{"apiKeys":[{"id":"32345678-0000-0000-0000-000000000000","name":"batch job","prefix":"rag_abcd1234","scopes":["sessions:read"],"lastUsedAt":"2025-05-30T09:00:00Z","revokedAt":"2025-05-30T10:00:00Z","createdAt":"2025-05-29T10:00:00Z"}]}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                "no keys",
+			mockServiceResponse: []*domain.APIKey{},
+			expected: json.RawMessage(`{"apiKeys":[]}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:             "service error",
+			mockServiceError: errors.New("random error"),
+			expected: json.RawMessage(`{"apiKeys":null,"errorMessage":"error in getting api keys"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/users/"+userID.String()+"/api-keys", nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": userID.String()})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().
+				GetUserAPIKeys(gomock.Any(), userID).
+				Return(tt.mockServiceResponse, tt.mockServiceError)
+
+			handler := &GetAPIKeysHandler{
+				APIKeyService: mockService,
+				logger:        logger,
+			}
+			sut := handler.GetAPIKeysController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
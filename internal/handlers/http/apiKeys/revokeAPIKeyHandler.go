@@ -0,0 +1,110 @@
+package apiKeys
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type RevokeAPIKeyHandler struct {
+	APIKeyService services.APIKeyServiceInterface
+	logger        logger.LoggerInterface
+}
+
+func NewRevokeAPIKeyHandler(
+	service services.APIKeyServiceInterface,
+	logger logger.LoggerInterface,
+) *RevokeAPIKeyHandler {
+	return &RevokeAPIKeyHandler{
+		APIKeyService: service,
+		logger:        logger,
+	}
+}
+
+// @Summary		Revokes an API key
+// @Description	Revokes an active API key of the user, requests with it are rejected from then on
+// @Security		BearerAuth
+// @Param			user_id		path	string	true	"user id"
+// @Param			api_key_id	path	string	true	"api key id"
+// @Success		204
+// @Failure		400	{object}	APIKeyResponse	"Error in path parameters"
+// @Failure		401	{object}	APIKeyResponse	"Authentication error"
+// @Failure		403	{object}	APIKeyResponse	"Not the authenticated user or authenticated with an API key"
+// @Failure		404	{object}	APIKeyResponse	"Active API key not found"
+// @Failure		500	{object}	APIKeyResponse	"Internal Server Error"
+// @Router			/users/{user_id}/api-keys/{api_key_id} [delete]
+func (handler *RevokeAPIKeyHandler) RevokeAPIKeyController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &APIKeyResponse{}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed user uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	apiKeyID, err := uuid.Parse(mux.Vars(r)["api_key_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed api key uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = handler.APIKeyService.RevokeAPIKey(ctx, userID, apiKeyID)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in revoking api key",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = resourceNotFound.Unwrap().Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in revoking api key",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in revoking api key"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *RevokeAPIKeyHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *APIKeyResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in revoking api key - json response"
+
+		handler.logger.Error("Error in revoking api key - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
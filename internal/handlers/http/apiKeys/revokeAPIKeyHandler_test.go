@@ -0,0 +1,107 @@
+package apiKeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRevokeAPIKeyHandler_RevokeAPIKeyController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockAPIKeyServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name               string
+		apiKeyID           string
+		mockServiceCalled  bool
+		mockServiceError   error
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:               "valid",
+			apiKeyID:           "32345678-0000-0000-0000-000000000000",
+			mockServiceCalled:  true,
+			expected:           []byte{},
+			expectedStatusCode: 204,
+		},
+		{
+			name:     "malformed api key id",
+			apiKeyID: "key",
+			expected: json.RawMessage(`{"errorMessage":"malformed api key uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:              "revoked or another user's key",
+			apiKeyID:          "32345678-0000-0000-0000-000000000000",
+			mockServiceCalled: true,
+			mockServiceError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("active api key 32345678-0000-0000-0000-000000000000 not found"),
+			},
+			expected: json.RawMessage(`{"errorMessage":"active api key 32345678-0000-0000-0000-000000000000 not found"}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:              "service error",
+			apiKeyID:          "32345678-0000-0000-0000-000000000000",
+			mockServiceCalled: true,
+			mockServiceError:  errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in revoking api key"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("DELETE", "/users/"+userID.String()+"/api-keys/"+tt.apiKeyID, nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"user_id":    userID.String(),
+				"api_key_id": tt.apiKeyID,
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			if tt.mockServiceCalled {
+				mockService.EXPECT().
+					RevokeAPIKey(gomock.Any(), userID, uuid.MustParse(tt.apiKeyID)).
+					Return(tt.mockServiceError)
+			}
+
+			handler := &RevokeAPIKeyHandler{
+				APIKeyService: mockService,
+				logger:        logger,
+			}
+			sut := handler.RevokeAPIKeyController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
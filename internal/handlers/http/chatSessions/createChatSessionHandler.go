@@ -29,6 +29,7 @@ func NewCreateUserChatSessionHandler(
 // @Summary		Creates chat session
 // @Description	Creates a chat session for User
 // @Security		BearerAuth
+// @Security		ApiKeyAuth
 // @Param			user_id	path		int	true	"user id"
 // @Success		201		{object}	ChatSessionResponse
 // @Failure		400		{object}	ChatSessionResponse	"Error in message payload"
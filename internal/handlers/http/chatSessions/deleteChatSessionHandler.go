@@ -0,0 +1,113 @@
+package chatSessions
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type DeleteChatSessionHandler struct {
+	ChatSessionService services.ChatSessionServiceInterface
+	logger             logger.LoggerInterface
+}
+
+func NewDeleteChatSessionHandler(
+	service services.ChatSessionServiceInterface,
+	logger logger.LoggerInterface,
+) *DeleteChatSessionHandler {
+	return &DeleteChatSessionHandler{
+		ChatSessionService: service,
+		logger:             logger,
+	}
+}
+
+// @Summary		Deletes chat session
+// @Description	Deletes a chat session of the user. It is not found from then on, and is purged with its messages and their sources after the retention
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id		path	string	true	"user id"
+// @Param			session_id	path	string	true	"chat session id"
+// @Success		204
+// @Failure		400	{object}	ChatSessionResponse	"Error in path parameters"
+// @Failure		401	{object}	ChatSessionResponse	"Authentication error"
+// @Failure		403	{object}	ChatSessionResponse	"Chat session of another user"
+// @Failure		404	{object}	ChatSessionResponse	"Chat session not found"
+// @Failure		500	{object}	ChatSessionResponse	"Internal Server Error"
+// @Router			/users/{user_id}/chat-sessions/{session_id} [delete]
+func (handler *DeleteChatSessionHandler) DeleteChatSessionController(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	response := &ChatSessionResponse{}
+
+	sessionID, userID, errorMessage := chatSessionPathIDs(r)
+	if errorMessage != "" {
+		response.ErrorMessage = errorMessage
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err := handler.ChatSessionService.DeleteChatSession(ctx, sessionID, userID)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in deleting chat session",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
+		handler.logger.Error("Error in deleting chat session",
+			map[string]interface{}{
+				"errorMessage": userMismatchError.Error(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusForbidden, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in deleting chat session",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in deleting chat session"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *DeleteChatSessionHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *ChatSessionResponse,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in deleting chat session - json response"
+
+		handler.logger.Error("Error in deleting chat session - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,134 @@
+package chatSessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeleteChatSessionHandler_DeleteChatSessionController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	sessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                     string
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name:               "valid",
+			expected:           json.RawMessage(``),
+			expectedStatusCode: 204,
+		},
+		{
+			name: "not found",
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chatSessionID 32345678-0000-0000-0000-000000000000 not found"),
+			},
+			expected: json.RawMessage(`{}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:                     "chat session of another user",
+			mockServiceResponseError: customerrors.NewUserMismatchError(sessionID.String(), userID.String()),
+			expected: json.RawMessage(`{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000"}
+`),
+			expectedStatusCode: 403,
+		},
+		{
+			name:                     "service random error",
+			mockServiceResponseError: errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in deleting chat session"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("DELETE", "/users/"+userID.String()+"/chat-sessions/"+sessionID.String(), nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"user_id":    userID.String(),
+				"session_id": sessionID.String(),
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().DeleteChatSession(
+				gomock.Any(),
+				sessionID,
+				userID,
+			).Return(tt.mockServiceResponseError)
+
+			handler := &DeleteChatSessionHandler{
+				ChatSessionService: mockService,
+				logger:             logger,
+			}
+			sut := handler.DeleteChatSessionController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestDeleteChatSessionHandler_DeleteChatSessionControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest("DELETE", "/users/user_id/chat-sessions/55", nil)
+	mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+		"user_id":    "42345678-0000-0000-0000-000000000000",
+		"session_id": "55",
+	})
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &DeleteChatSessionHandler{
+		ChatSessionService: mockService,
+		logger:             logger,
+	}
+	sut := handler.DeleteChatSessionController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"malformed session uuid"}
+`, string(actual))
+	assert.Equal(t, 400, mockResponse.StatusCode)
+}
@@ -1,12 +1,34 @@
 package chatSessions
 
 import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"net/http"
+	"strconv"
+	"time"
 )
 
+// UserChatSessionsResponse is a page of chat session summaries, NextCursor is
+// given to the next request for the next page and is empty on the last one
 type UserChatSessionsResponse struct {
-	Sessions     []ChatSessionResponse `json:"sessions,omitempty"`
-	ErrorMessage string                `json:"errorMessage,omitempty"`
+	Sessions     []ChatSessionSummaryResponse `json:"sessions,omitempty"`
+	NextCursor   string                       `json:"nextCursor,omitempty"`
+	ErrorMessage string                       `json:"errorMessage,omitempty"`
+}
+
+// ChatSessionSummaryResponse is a chat session without its messages, the
+// content of LastMessage is cut to its first 200 characters
+type ChatSessionSummaryResponse struct {
+	ID           string           `json:"id"`
+	Title        string           `json:"title,omitempty"`
+	CreatedAt    string           `json:"createdAt"`
+	UpdatedAt    string           `json:"updatedAt"`
+	ArchivedAt   string           `json:"archivedAt,omitempty"`
+	MessageCount int              `json:"messageCount"`
+	LastMessage  *MessageResponse `json:"lastMessage,omitempty"`
 }
 
 type ChatSessionResponse struct {
@@ -14,6 +36,7 @@ type ChatSessionResponse struct {
 	Title        string            `json:"title,omitempty"`
 	CreatedAt    string            `json:"createdAt,omitempty"`
 	UpdatedAt    string            `json:"updatedAt,omitempty"`
+	ArchivedAt   string            `json:"archivedAt,omitempty"`
 	Messages     []MessageResponse `json:"messages,omitempty"`
 	ErrorMessage string            `json:"errorMessage,omitempty"`
 }
@@ -26,35 +49,190 @@ func ChatSessionResponseFromModel(domainChatSession *domain.ChatSession) *ChatSe
 			Sender:    msg.Sender,
 			Content:   msg.Content,
 			CreatedAt: msg.CreatedAt.String(),
+			Feedback:  MessageFeedbackResponseFromModel(msg.Feedback),
+			ToolCalls: ToolCallResponsesFromModel(msg.ToolCalls),
 		}
 	}
 
-	return &ChatSessionResponse{
+	response := &ChatSessionResponse{
 		ID:        domainChatSession.ID.String(),
 		Title:     domainChatSession.Title,
 		CreatedAt: domainChatSession.CreatedAt.String(),
 		UpdatedAt: domainChatSession.UpdatedAt.String(),
 		Messages:  messages,
 	}
+
+	if domainChatSession.ArchivedAt != nil {
+		response.ArchivedAt = domainChatSession.ArchivedAt.String()
+	}
+
+	return response
 }
 
-func UserChatSessionsResponseFromModel(domainChatSession []*domain.ChatSession) *UserChatSessionsResponse {
-	sessions := make([]ChatSessionResponse, len(domainChatSession))
-	for i, session := range domainChatSession {
-		sessions[i] = *ChatSessionResponseFromModel(session)
+func UserChatSessionsResponseFromModel(page *domain.ChatSessionPage) *UserChatSessionsResponse {
+	sessions := make([]ChatSessionSummaryResponse, len(page.Sessions))
+	for i, summary := range page.Sessions {
+		sessions[i] = ChatSessionSummaryResponse{
+			ID:           summary.ID.String(),
+			Title:        summary.Title,
+			CreatedAt:    summary.CreatedAt.String(),
+			UpdatedAt:    summary.UpdatedAt.String(),
+			MessageCount: summary.MessageCount,
+		}
+
+		if summary.ArchivedAt != nil {
+			sessions[i].ArchivedAt = summary.ArchivedAt.String()
+		}
+
+		if summary.LastMessage != nil {
+			sessions[i].LastMessage = MessageResponseFromModel(summary.LastMessage)
+		}
 	}
 
-	return &UserChatSessionsResponse{
+	response := &UserChatSessionsResponse{
 		Sessions: sessions,
 	}
+
+	if page.NextCursor != nil {
+		response.NextCursor = page.NextCursor.String()
+	}
+
+	return response
+}
+
+// chatSessionFilterFromRequest reads the optional query parameters of the
+// chat session listing: "archived" as true or false, "from" and "to" as RFC
+// 3339 timestamps or YYYY-MM-DD dates bounding updatedAt, "order" as desc or
+// asc, "cursor" and "limit".
+func chatSessionFilterFromRequest(r *http.Request) (*domain.ChatSessionFilter, error) {
+	query := r.URL.Query()
+	filter := &domain.ChatSessionFilter{}
+
+	if value := query.Get("archived"); value != "" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("archived must be true or false")
+		}
+		filter.Archived = &archived
+	}
+
+	if value := query.Get("from"); value != "" {
+		from, err := parseTime(value)
+		if err != nil {
+			return nil, errors.New("malformed from date")
+		}
+		filter.UpdatedFrom = &from
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err := parseTime(value)
+		if err != nil {
+			return nil, errors.New("malformed to date")
+		}
+		filter.UpdatedTo = &to
+	}
+
+	if filter.UpdatedFrom != nil && filter.UpdatedTo != nil && !filter.UpdatedFrom.Before(*filter.UpdatedTo) {
+		return nil, errors.New("from date must be before to date")
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return nil, errors.New("order must be asc or desc")
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := domain.ParseChatSessionCursor(value)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > services.MaxChatSessionPageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", services.MaxChatSessionPageSize)
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
 }
 
 type MessageResponse struct {
-	ID           string `json:"id,omitempty"`
-	Sender       string `json:"sender,omitempty" enum:"USER,SYSTEM"`
-	Content      string `json:"content,omitempty"`
-	CreatedAt    string `json:"created_at,omitempty"`
-	ErrorMessage string `json:"errorMessage,omitempty"`
+	ID           string                   `json:"id,omitempty"`
+	Sender       string                   `json:"sender,omitempty" enum:"USER,SYSTEM"`
+	Content      string                   `json:"content,omitempty"`
+	CreatedAt    string                   `json:"created_at,omitempty"`
+	Feedback     *MessageFeedbackResponse `json:"feedback,omitempty"`
+	ToolCalls    []ToolCallResponse       `json:"toolCalls,omitempty"`
+	ErrorMessage string                   `json:"errorMessage,omitempty"`
+}
+
+// ToolCallResponse is a tool call of an answer in agent mode, Arguments are
+// the JSON the LLM generated
+type ToolCallResponse struct {
+	Step      int    `json:"step"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Result    string `json:"result"`
+	IsError   bool   `json:"isError,omitempty"`
+}
+
+func ToolCallResponsesFromModel(toolCalls []*domain.ToolCall) []ToolCallResponse {
+	if len(toolCalls) == 0 {
+		return nil
+	}
+
+	responses := make([]ToolCallResponse, len(toolCalls))
+	for i, toolCall := range toolCalls {
+		responses[i] = ToolCallResponse{
+			Step:      toolCall.Step,
+			Name:      toolCall.Name,
+			Arguments: toolCall.Arguments,
+			Result:    toolCall.Result,
+			IsError:   toolCall.IsError,
+		}
+	}
+
+	return responses
+}
+
+type MessageFeedbackResponse struct {
+	Thumb           string `json:"thumb,omitempty" enums:"up,down"`
+	Rating          int    `json:"rating,omitempty"`
+	Reason          string `json:"reason,omitempty" enums:"wrong,incomplete,hallucinated,off-topic"`
+	CorrectedAnswer string `json:"correctedAnswer,omitempty"`
+	Comment         string `json:"comment,omitempty"`
+	SubmittedAt     string `json:"submittedAt,omitempty"`
+}
+
+func MessageFeedbackResponseFromModel(feedback *domain.MessageFeedback) *MessageFeedbackResponse {
+	if feedback == nil {
+		return nil
+	}
+
+	return &MessageFeedbackResponse{
+		Thumb:           feedback.Thumb,
+		Rating:          feedback.Rating,
+		Reason:          feedback.Reason,
+		CorrectedAnswer: feedback.CorrectedAnswer,
+		Comment:         feedback.Comment,
+		SubmittedAt:     feedback.SubmittedAt.Format(time.RFC3339),
+	}
 }
 
 func MessageResponseFromModel(msg *domain.Message) *MessageResponse {
@@ -63,11 +241,72 @@ func MessageResponseFromModel(msg *domain.Message) *MessageResponse {
 		Sender:    msg.Sender,
 		Content:   msg.Content,
 		CreatedAt: msg.CreatedAt.String(),
+		Feedback:  MessageFeedbackResponseFromModel(msg.Feedback),
+		ToolCalls: ToolCallResponsesFromModel(msg.ToolCalls),
 	}
 }
 
+// MessagesResponse is a page of the messages of a chat session, oldest first
+type MessagesResponse struct {
+	Messages     []MessageResponse `json:"messages,omitempty"`
+	HasMore      bool              `json:"hasMore,omitempty"`
+	ErrorMessage string            `json:"errorMessage,omitempty"`
+}
+
+func MessagesResponseFromModel(page *domain.MessagePage) *MessagesResponse {
+	messages := make([]MessageResponse, len(page.Messages))
+	for i, msg := range page.Messages {
+		messages[i] = *MessageResponseFromModel(msg)
+	}
+
+	return &MessagesResponse{
+		Messages: messages,
+		HasMore:  page.HasMore,
+	}
+}
+
+// messageFilterFromRequest reads the optional "before", "after" and "limit"
+// query parameters of the message listing, before and after being message ids
+func messageFilterFromRequest(r *http.Request) (*domain.MessageFilter, error) {
+	query := r.URL.Query()
+	filter := &domain.MessageFilter{}
+
+	if query.Get("before") != "" && query.Get("after") != "" {
+		return nil, errors.New("only one of before and after can be given")
+	}
+
+	if value := query.Get("before"); value != "" {
+		before, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errors.New("malformed before message uuid")
+		}
+		filter.Before = &before
+	}
+
+	if value := query.Get("after"); value != "" {
+		after, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errors.New("malformed after message uuid")
+		}
+		filter.After = &after
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > services.MaxMessagePageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", services.MaxMessagePageSize)
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+// SendMessageRequest answers in rag mode unless Mode asks for the agent, that
+// calls tools before answering
 type SendMessageRequest struct {
 	Content string `json:"content"`
+	Mode    string `json:"mode,omitempty" enums:"rag,agent"`
 }
 
 type SendMessageResponse struct {
@@ -76,10 +315,35 @@ type SendMessageResponse struct {
 	ErrorMessage  string           `json:"errorMessage,omitempty"`
 }
 
+// SubmitFeedbackRequest rates an answer with either a thumb or a 1-5 rating.
+// Feedback is an optional free text comment.
 type SubmitFeedbackRequest struct {
-	Feedback string `json:"feedback"`
+	Thumb           string `json:"thumb,omitempty" enums:"up,down"`
+	Rating          int    `json:"rating,omitempty" minimum:"1" maximum:"5"`
+	Reason          string `json:"reason,omitempty" enums:"wrong,incomplete,hallucinated,off-topic"`
+	CorrectedAnswer string `json:"correctedAnswer,omitempty"`
+	Feedback        string `json:"feedback,omitempty"`
+}
+
+func (request *SubmitFeedbackRequest) ToModel() *domain.MessageFeedback {
+	return &domain.MessageFeedback{
+		Thumb:           request.Thumb,
+		Rating:          request.Rating,
+		Reason:          request.Reason,
+		CorrectedAnswer: request.CorrectedAnswer,
+		Comment:         request.Feedback,
+	}
 }
 
 type SubmitFeedbackResponse struct {
 	ErrorMessage string `json:"errorMessage,omitempty"`
 }
+
+type RenameChatSessionRequest struct {
+	Title string `json:"title"`
+}
+
+type PurgeChatSessionsResponse struct {
+	Purged       int64  `json:"purged"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
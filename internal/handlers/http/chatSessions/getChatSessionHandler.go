@@ -2,9 +2,11 @@ package chatSessions
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
 	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
 	"net/http"
@@ -25,14 +27,21 @@ func NewGetChatSessionHandler(
 	}
 }
 
-// @Summary		Gets all User's chat sessions
-// @Description	Gets all User's chat sessions
+// @Summary		Lists User's chat sessions
+// @Description	Lists summaries of User's chat sessions, with their message count and last message instead of their messages, most recently updated first. Pages are followed with the nextCursor of the previous page.
 // @Security		BearerAuth
-// @Param			user_id	path		int	true	"user id"
-// @Success		201		{object}	UserChatSessionsResponse
-// @Failure		400		{object}	UserChatSessionsResponse	"Error in message payload"
-// @Failure		401		{object}	UserChatSessionsResponse	"Authentication error"
-// @Failure		500		{object}	UserChatSessionsResponse	"Internal Server Error"
+// @Security		ApiKeyAuth
+// @Param			user_id		path		int		true	"user id"
+// @Param			archived	query		bool	false	"only archived sessions when true, only the rest when false"
+// @Param			from		query		string	false	"updated at or after, RFC 3339 timestamp or YYYY-MM-DD date"
+// @Param			to			query		string	false	"updated before, RFC 3339 timestamp or YYYY-MM-DD date"
+// @Param			order		query		string	false	"desc (default) or asc by updatedAt"
+// @Param			cursor		query		string	false	"nextCursor of the previous page"
+// @Param			limit		query		int		false	"sessions in a page, 1 to 100, default 20"
+// @Success		200			{object}	UserChatSessionsResponse
+// @Failure		400			{object}	UserChatSessionsResponse	"Error in path or query parameters"
+// @Failure		401			{object}	UserChatSessionsResponse	"Authentication error"
+// @Failure		500			{object}	UserChatSessionsResponse	"Internal Server Error"
 // @Router			/users/user_id/chat-sessions [get]
 func (handler *GetChatSessionHandler) GetUserChatSessionsController(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -65,19 +74,16 @@ func (handler *GetChatSessionHandler) GetUserChatSessionsController(w http.Respo
 		return
 	}
 
-	usersChatSessions, err := handler.ChatSessionService.GetUserChatSessions(ctx, userId)
-	if userNotFoundError, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
-		handler.logger.Error("Error in getting users chat sessions",
-			map[string]interface{}{
-				"errorMessage": userNotFoundError.Unwrap(),
-			})
-
+	filter, err := chatSessionFilterFromRequest(r)
+	if err != nil {
 		response.ErrorMessage = err.Error()
-		handler.JsonUserChatSessionResponse(w, http.StatusNotFound, response)
+
+		handler.JsonUserChatSessionResponse(w, http.StatusBadRequest, response)
 
 		return
 	}
 
+	usersChatSessions, err := handler.ChatSessionService.GetUserChatSessions(ctx, userId, filter)
 	if err != nil {
 		handler.logger.Error("Error in getting user's chat sessions",
 			map[string]interface{}{
@@ -95,12 +101,15 @@ func (handler *GetChatSessionHandler) GetUserChatSessionsController(w http.Respo
 }
 
 // @Summary		Gets chat session
-// @Description	Gets chat session
+// @Description	Gets a chat session of the authenticated user
 // @Security		BearerAuth
+// @Security		ApiKeyAuth
 // @Param			session_id	path		int	true	"session id"
 // @Success		201			{object}	ChatSessionResponse
 // @Failure		400			{object}	ChatSessionResponse	"Error in message payload"
 // @Failure		401			{object}	ChatSessionResponse	"Authentication error"
+// @Failure		403			{object}	ChatSessionResponse	"Chat session of another user"
+// @Failure		404			{object}	ChatSessionResponse	"Chat session not found"
 // @Failure		500			{object}	ChatSessionResponse	"Internal Server Error"
 // @Router			/chat-sessions/session_id [get]
 func (handler *GetChatSessionHandler) GetChatSessionController(w http.ResponseWriter, r *http.Request) {
@@ -134,7 +143,16 @@ func (handler *GetChatSessionHandler) GetChatSessionController(w http.ResponseWr
 		return
 	}
 
-	chatSession, err := handler.ChatSessionService.GetChatSession(ctx, sessionID)
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		response.ErrorMessage = "Not Authorized"
+
+		handler.JsonChatSessionResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	chatSession, err := handler.ChatSessionService.GetChatSession(ctx, sessionID, userID)
 	if chatSessionNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
 		handler.logger.Error("Error in getting chat session",
 			map[string]interface{}{
@@ -147,6 +165,19 @@ func (handler *GetChatSessionHandler) GetChatSessionController(w http.ResponseWr
 		return
 	}
 
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
+		handler.logger.Error("Error in getting chat session",
+			map[string]interface{}{
+				"errorMessage": userMismatchError.Error(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonChatSessionResponse(w, http.StatusForbidden, response)
+
+		return
+	}
+
 	if err != nil {
 		handler.logger.Error("Error in getting chat session",
 			map[string]interface{}{
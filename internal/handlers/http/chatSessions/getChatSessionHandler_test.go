@@ -2,16 +2,21 @@ package chatSessions
 
 import (
 	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"io"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
@@ -23,6 +28,7 @@ func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
 
 	type args struct {
 		chatSessionID uuid.UUID
+		userID        uuid.UUID
 	}
 
 	tests := []struct {
@@ -37,6 +43,7 @@ func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
 			name: "valid",
 			args: args{
 				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				userID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
 			},
 			mockServiceResponseData: &domain.ChatSession{
 				ID:     uuid.UUID{0x32, 0x34, 0x56, 0x78},
@@ -48,6 +55,17 @@ func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
 `,
 			expectedStatusCode: 200,
 		},
+		{
+			name: "chat session of another user",
+			args: args{
+				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				userID:        uuid.UUID{0x52, 0x34, 0x56, 0x78},
+			},
+			mockServiceResponseError: customerrors.NewUserMismatchError("32345678-0000-0000-0000-000000000000", "52345678-0000-0000-0000-000000000000"),
+			expected: `{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 52345678-0000-0000-0000-000000000000"}
+`,
+			expectedStatusCode: 403,
+		},
 	}
 
 	for _, tt := range tests {
@@ -61,11 +79,16 @@ func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
 				"session_id": tt.args.chatSessionID.String(),
 			}
 			mockRequest = mux.SetURLVars(mockRequest, vars)
+			mockRequest = mockRequest.WithContext(auth.ContextWithClaims(
+				mockRequest.Context(),
+				jwt.MapClaims{"sub": tt.args.userID.String()},
+			))
 			mockResponseRecorder := httptest.NewRecorder()
 
 			mockService.EXPECT().GetChatSession(
 				gomock.Any(),
 				tt.args.chatSessionID,
+				tt.args.userID,
 			).Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
 
 			handler := &GetChatSessionHandler{
@@ -90,6 +113,39 @@ func TestGetChatSessionHandler_GetChatSessionController(t *testing.T) {
 	}
 }
 
+func TestGetChatSessionHandler_GetChatSessionControllerHasAuthenticationError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest("GET", "/chat-sessions/32345678-0000-0000-0000-000000000000", nil)
+	mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+		"session_id": "32345678-0000-0000-0000-000000000000",
+	})
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &GetChatSessionHandler{
+		ChatSessionService: mockService,
+		logger:             logger,
+	}
+	sut := handler.GetChatSessionController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"Not Authorized"}
+`, string(actual))
+	assert.Equal(t, 401, mockResponse.StatusCode)
+}
+
 func TestGetChatSessionHandler_GetUserChatSessionsController(t *testing.T) {
 	logger := logger.NewLogger(context.Background())
 	mockCtrl := gomock.NewController(t)
@@ -97,14 +153,24 @@ func TestGetChatSessionHandler_GetUserChatSessionsController(t *testing.T) {
 
 	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
 
+	archived := true
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	archivedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	cursor := &domain.ChatSessionCursor{
+		UpdatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+	}
+
 	type args struct {
 		userID uuid.UUID
+		query  string
 	}
 
 	tests := []struct {
 		name                     string
 		args                     args
-		mockServiceResponseData  []*domain.ChatSession
+		expectedFilter           *domain.ChatSessionFilter
+		mockServiceResponseData  *domain.ChatSessionPage
 		mockServiceResponseError error
 		expected                 string
 		expectedStatusCode       int
@@ -114,30 +180,110 @@ func TestGetChatSessionHandler_GetUserChatSessionsController(t *testing.T) {
 			args: args{
 				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
 			},
-			mockServiceResponseData: []*domain.ChatSession{
-				&domain.ChatSession{
-					ID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
-					UserID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					Title:  "title1",
+			expectedFilter: &domain.ChatSessionFilter{},
+			mockServiceResponseData: &domain.ChatSessionPage{
+				Sessions: []*domain.ChatSessionSummary{
+					{
+						ID:           uuid.UUID{0x12, 0x34, 0x56, 0x78},
+						UserID:       uuid.UUID{0x42, 0x34, 0x56, 0x78},
+						Title:        "title1",
+						MessageCount: 2,
+						LastMessage: &domain.Message{
+							ID:      uuid.UUID{0x52, 0x34, 0x56, 0x78},
+							Sender:  "SYSTEM",
+							Content: "answer",
+						},
+					},
+					{
+						ID:     uuid.UUID{0x32, 0x34, 0x56, 0x78},
+						UserID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+						Title:  "title2",
+					},
 				},
-				&domain.ChatSession{
-					ID:     uuid.UUID{0x32, 0x34, 0x56, 0x78},
-					UserID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					Title:  "title2",
+			},
+			mockServiceResponseError: nil,
+			expected: `{"sessions":[{"id":"12345678-0000-0000-0000-000000000000","title":"title1","createdAt":"0001-01-01 00:00:00 +0000 UTC","updatedAt":"0001-01-01 00:00:00 +0000 UTC","messageCount":2,"lastMessage":{"id":"52345678-0000-0000-0000-000000000000","sender":"SYSTEM","content":"answer","created_at":"0001-01-01 00:00:00 +0000 UTC"}},{"id":"32345678-0000-0000-0000-000000000000","title":"title2","createdAt":"0001-01-01 00:00:00 +0000 UTC","updatedAt":"0001-01-01 00:00:00 +0000 UTC","messageCount":0}]}
+`,
+			expectedStatusCode: 200,
+		},
+		{
+			name: "filtered page with next page",
+			args: args{
+				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				query:  "?archived=true&from=2025-01-01&order=asc&limit=1&cursor=" + cursor.String(),
+			},
+			expectedFilter: &domain.ChatSessionFilter{
+				Archived:    &archived,
+				UpdatedFrom: &from,
+				Ascending:   true,
+				After:       cursor,
+				Limit:       1,
+			},
+			mockServiceResponseData: &domain.ChatSessionPage{
+				Sessions: []*domain.ChatSessionSummary{
+					{
+						ID:         uuid.UUID{0x12, 0x34, 0x56, 0x78},
+						UserID:     uuid.UUID{0x42, 0x34, 0x56, 0x78},
+						Title:      "title1",
+						UpdatedAt:  cursor.UpdatedAt,
+						ArchivedAt: &archivedAt,
+					},
 				},
+				NextCursor: cursor,
 			},
 			mockServiceResponseError: nil,
-			expected: `{"sessions":[{"id":"12345678-0000-0000-0000-000000000000","title":"title1","createdAt":"0001-01-01 00:00:00 +0000 UTC","updatedAt":"0001-01-01 00:00:00 +0000 UTC"},{"id":"32345678-0000-0000-0000-000000000000","title":"title2","createdAt":"0001-01-01 00:00:00 +0000 UTC","updatedAt":"0001-01-01 00:00:00 +0000 UTC"}]}
+			expected: `{"sessions":[{"id":"12345678-0000-0000-0000-000000000000","title":"title1","createdAt":"0001-01-01 00:00:00 +0000 UTC","updatedAt":"2025-01-03 00:00:00 +0000 UTC","archivedAt":"2025-01-02 00:00:00 +0000 UTC","messageCount":0}],"nextCursor":"` + cursor.String() + `"}
 `,
 			expectedStatusCode: 200,
 		},
+		{
+			name: "malformed cursor",
+			args: args{
+				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				query:  "?cursor=malformed",
+			},
+			expected: `{"errorMessage":"malformed cursor"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name: "limit out of range",
+			args: args{
+				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				query:  "?limit=101",
+			},
+			expected: `{"errorMessage":"limit must be between 1 and 100"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name: "from after to",
+			args: args{
+				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				query:  "?from=2025-02-01&to=2025-01-01",
+			},
+			expected: `{"errorMessage":"from date must be before to date"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name: "service error",
+			args: args{
+				userID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+			expectedFilter:           &domain.ChatSessionFilter{},
+			mockServiceResponseError: errors.New("db error"),
+			expected: `{"errorMessage":"error in getting user chat sessions"}
+`,
+			expectedStatusCode: 500,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRequest := httptest.NewRequest(
 				"GET",
-				"/user/"+tt.args.userID.String()+"/chat-sessions",
+				"/user/"+tt.args.userID.String()+"/chat-sessions"+tt.args.query,
 				nil,
 			)
 			vars := map[string]string{
@@ -146,10 +292,13 @@ func TestGetChatSessionHandler_GetUserChatSessionsController(t *testing.T) {
 			mockRequest = mux.SetURLVars(mockRequest, vars)
 			mockResponseRecorder := httptest.NewRecorder()
 
-			mockService.EXPECT().GetUserChatSessions(
-				gomock.Any(),
-				tt.args.userID,
-			).Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			if tt.expectedFilter != nil {
+				mockService.EXPECT().GetUserChatSessions(
+					gomock.Any(),
+					tt.args.userID,
+					tt.expectedFilter,
+				).Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
 
 			handler := &GetChatSessionHandler{
 				ChatSessionService: mockService,
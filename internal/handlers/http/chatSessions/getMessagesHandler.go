@@ -0,0 +1,128 @@
+package chatSessions
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type GetMessagesHandler struct {
+	MessageService services.MessageServiceInterface
+	logger         logger.LoggerInterface
+}
+
+func NewGetMessagesHandler(
+	service services.MessageServiceInterface,
+	logger logger.LoggerInterface,
+) *GetMessagesHandler {
+	return &GetMessagesHandler{
+		MessageService: service,
+		logger:         logger,
+	}
+}
+
+// @Summary		Lists messages of chat session
+// @Description	Lists a page of the messages of a chat session of the user, oldest first: the last ones, the ones before the message of before, or the ones after the message of after.
+// @Description	hasMore tells whether there are more messages past the page, older ones unless paging with after, so that older history is loaded with the id of the first message as before.
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id		path		string	true	"user id"
+// @Param			session_id	path		string	true	"chat session id"
+// @Param			before		query		string	false	"id of the message to list the messages before"
+// @Param			after		query		string	false	"id of the message to list the messages after"
+// @Param			limit		query		int		false	"messages in a page, 1 to 200, default 50"
+// @Success		200			{object}	MessagesResponse
+// @Failure		400			{object}	MessagesResponse	"Error in path or query parameters"
+// @Failure		401			{object}	MessagesResponse	"Authentication error"
+// @Failure		403			{object}	MessagesResponse	"Chat session of another user"
+// @Failure		404			{object}	MessagesResponse	"Chat session or cursor message not found"
+// @Failure		500			{object}	MessagesResponse	"Internal Server Error"
+// @Router			/users/{user_id}/chat-sessions/{session_id}/messages [get]
+func (handler *GetMessagesHandler) GetMessagesController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &MessagesResponse{}
+
+	sessionID, userID, errorMessage := chatSessionPathIDs(r)
+	if errorMessage != "" {
+		response.ErrorMessage = errorMessage
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	filter, err := messageFilterFromRequest(r)
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	page, err := handler.MessageService.GetChatSessionMessages(ctx, sessionID, userID, filter)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in getting messages",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
+		handler.logger.Error("Error in getting messages",
+			map[string]interface{}{
+				"errorMessage": userMismatchError.Error(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusForbidden, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in getting messages",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting messages"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = MessagesResponseFromModel(page)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *GetMessagesHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *MessagesResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in getting messages - json response"
+
+		handler.logger.Error("Error in getting messages - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
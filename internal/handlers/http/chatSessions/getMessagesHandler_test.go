@@ -0,0 +1,173 @@
+package chatSessions
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMessagesHandler_GetMessagesController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	sessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	messageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                     string
+		query                    string
+		expectedFilter           *domain.MessageFilter
+		mockServiceResponseData  *domain.MessagePage
+		mockServiceResponseError error
+		expected                 string
+		expectedStatusCode       int
+	}{
+		{
+			name:           "valid",
+			expectedFilter: &domain.MessageFilter{},
+			mockServiceResponseData: &domain.MessagePage{
+				Messages: []*domain.Message{
+					{
+						ID:            uuid.UUID{0x52, 0x34, 0x56, 0x78},
+						ChatSessionID: sessionID,
+						Sender:        "USER",
+						Content:       "question",
+					},
+					{
+						ID:            uuid.UUID{0x62, 0x34, 0x56, 0x78},
+						ChatSessionID: sessionID,
+						Sender:        "SYSTEM",
+						Content:       "answer",
+					},
+				},
+				HasMore: true,
+			},
+			expected: `{"messages":[{"id":"52345678-0000-0000-0000-000000000000","sender":"USER","content":"question","created_at":"0001-01-01 00:00:00 +0000 UTC"},{"id":"62345678-0000-0000-0000-000000000000","sender":"SYSTEM","content":"answer","created_at":"0001-01-01 00:00:00 +0000 UTC"}],"hasMore":true}
+`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:                    "before message",
+			query:                   "?before=" + messageID.String() + "&limit=10",
+			expectedFilter:          &domain.MessageFilter{Before: &messageID, Limit: 10},
+			mockServiceResponseData: &domain.MessagePage{},
+			expected: `{}
+`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:                    "after message",
+			query:                   "?after=" + messageID.String(),
+			expectedFilter:          &domain.MessageFilter{After: &messageID},
+			mockServiceResponseData: &domain.MessagePage{},
+			expected: `{}
+`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:  "before and after",
+			query: "?before=" + messageID.String() + "&after=" + messageID.String(),
+			expected: `{"errorMessage":"only one of before and after can be given"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "malformed before",
+			query: "?before=55",
+			expected: `{"errorMessage":"malformed before message uuid"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "limit out of range",
+			query: "?limit=0",
+			expected: `{"errorMessage":"limit must be between 1 and 200"}
+`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:           "not found",
+			query:          "?before=" + messageID.String(),
+			expectedFilter: &domain.MessageFilter{Before: &messageID},
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("messageID 12345678-0000-0000-0000-000000000000 not found in chatSession 32345678-0000-0000-0000-000000000000"),
+			},
+			expected: `{}
+`,
+			expectedStatusCode: 404,
+		},
+		{
+			name:                     "chat session of another user",
+			expectedFilter:           &domain.MessageFilter{},
+			mockServiceResponseError: customerrors.NewUserMismatchError(sessionID.String(), userID.String()),
+			expected: `{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000"}
+`,
+			expectedStatusCode: 403,
+		},
+		{
+			name:                     "service random error",
+			expectedFilter:           &domain.MessageFilter{},
+			mockServiceResponseError: errors.New("random error"),
+			expected: `{"errorMessage":"error in getting messages"}
+`,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest(
+				"GET",
+				"/users/"+userID.String()+"/chat-sessions/"+sessionID.String()+"/messages"+tt.query,
+				nil,
+			)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"user_id":    userID.String(),
+				"session_id": sessionID.String(),
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			if tt.expectedFilter != nil {
+				mockService.EXPECT().GetChatSessionMessages(
+					gomock.Any(),
+					sessionID,
+					userID,
+					tt.expectedFilter,
+				).Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			handler := &GetMessagesHandler{
+				MessageService: mockService,
+				logger:         logger,
+			}
+			sut := handler.GetMessagesController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
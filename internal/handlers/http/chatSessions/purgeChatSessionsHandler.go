@@ -0,0 +1,73 @@
+package chatSessions
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type PurgeChatSessionsHandler struct {
+	ChatSessionService services.ChatSessionServiceInterface
+	logger             logger.LoggerInterface
+}
+
+func NewPurgeChatSessionsHandler(
+	service services.ChatSessionServiceInterface,
+	logger logger.LoggerInterface,
+) *PurgeChatSessionsHandler {
+	return &PurgeChatSessionsHandler{
+		ChatSessionService: service,
+		logger:             logger,
+	}
+}
+
+// @Summary		Purges deleted chat sessions
+// @Description	Deletes for good the chat sessions deleted longer than CHAT_SESSION_DELETED_RETENTION ago, with their messages and the sources and tool calls of the messages
+// @Security		BearerAuth
+// @Success		200	{object}	PurgeChatSessionsResponse
+// @Failure		401	{object}	PurgeChatSessionsResponse	"Authentication error"
+// @Failure		403	{string}	string						"Not an admin"
+// @Failure		500	{object}	PurgeChatSessionsResponse	"Internal Server Error"
+// @Router			/admin/chat-sessions/purge [post]
+func (handler *PurgeChatSessionsHandler) PurgeChatSessionsController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &PurgeChatSessionsResponse{}
+
+	purged, err := handler.ChatSessionService.PurgeDeletedChatSessions(ctx)
+	if err != nil {
+		handler.logger.Error("Error in purging chat sessions",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in purging chat sessions"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response.Purged = purged
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *PurgeChatSessionsHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *PurgeChatSessionsResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in purging chat sessions - json response"
+
+		handler.logger.Error("Error in purging chat sessions - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
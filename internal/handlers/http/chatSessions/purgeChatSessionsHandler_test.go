@@ -0,0 +1,74 @@
+package chatSessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPurgeChatSessionsHandler_PurgeChatSessionsController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                     string
+		mockServiceReturned      int64
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name:                "valid",
+			mockServiceReturned: 3,
+			expected: json.RawMessage(`{"purged":3}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                     "service random error",
+			mockServiceResponseError: errors.New("random error"),
+			expected: json.RawMessage(`{"purged":0,"errorMessage":"error in purging chat sessions"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/admin/chat-sessions/purge", nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().PurgeDeletedChatSessions(gomock.Any()).
+				Return(tt.mockServiceReturned, tt.mockServiceResponseError)
+
+			handler := &PurgeChatSessionsHandler{
+				ChatSessionService: mockService,
+				logger:             logger,
+			}
+			sut := handler.PurgeChatSessionsController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
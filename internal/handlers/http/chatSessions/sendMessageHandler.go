@@ -2,6 +2,7 @@ package chatSessions
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
@@ -9,7 +10,10 @@ import (
 	"github.com/loukaspe/rag-golang/internal/repositories"
 	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type SendMessageHandler struct {
@@ -28,14 +32,17 @@ func NewSendMessageHandler(
 }
 
 // @Summary		Sends message to a given chat session and gets response
-// @Description	Sends message to a given chat session and gets response
+// @Description	Sends message to a given chat session and gets response. In agent mode the LLM calls tools before answering, and the calls are returned with the answer
 // @Security		BearerAuth
+// @Security		ApiKeyAuth
 // @Param			SendMessageRequest	body		SendMessageRequest	true	"request body"
 // @Param			user_id				path		int					true	"user id"
 // @Param			session_id			body		int					true	"session id"
 // @Success		201					{object}	SendMessageResponse
 // @Failure		400					{object}	SendMessageResponse	"Error in message payload"
 // @Failure		401					{object}	SendMessageResponse	"Authentication error"
+// @Failure		402					{object}	SendMessageResponse	"Monthly token quota exceeded"
+// @Failure		429					{object}	SendMessageResponse	"Rate limit or monthly message quota exceeded, retry after the Retry-After seconds"
 // @Failure		500					{object}	SendMessageResponse	"Internal Server Error"
 // @Router			/users/user_id/chat-sessions/session_id/messages [post]
 func (handler *SendMessageHandler) SendMessageController(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +114,19 @@ func (handler *SendMessageHandler) SendMessageController(w http.ResponseWriter,
 		return
 	}
 
+	getAnswerForMessage := handler.MessageService.GetAnswerForMessage
+	switch request.Mode {
+	case "", domain.AnswerModeRAG:
+	case domain.AnswerModeAgent:
+		getAnswerForMessage = handler.MessageService.GetAgentAnswerForMessage
+	default:
+		response.ErrorMessage = "unknown mode " + request.Mode
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
 	domainMessage := &domain.Message{
 		ChatSessionID: chatSessionID,
 		Content:       request.Content,
@@ -131,7 +151,8 @@ func (handler *SendMessageHandler) SendMessageController(w http.ResponseWriter,
 		return
 	}
 
-	if userMismatchError, ok := err.(customerrors.UserMismatchError); ok {
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
 		handler.logger.Error("Error in sending message",
 			map[string]interface{}{
 				"errorMessage": userMismatchError.Error(),
@@ -143,6 +164,28 @@ func (handler *SendMessageHandler) SendMessageController(w http.ResponseWriter,
 		return
 	}
 
+	// the token quota asks for a bigger plan, the message quota for waiting
+	// until it resets
+	var quotaExceededError *customerrors.QuotaExceededError
+	if errors.As(err, &quotaExceededError) {
+		handler.logger.Error("Error in sending message",
+			map[string]interface{}{
+				"errorMessage": quotaExceededError.Error(),
+			})
+
+		statusCode := http.StatusPaymentRequired
+		if quotaExceededError.Quota() == customerrors.QuotaMessages {
+			retryAfter := math.Ceil(time.Until(quotaExceededError.ResetsAt()).Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+			statusCode = http.StatusTooManyRequests
+		}
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, statusCode, response)
+
+		return
+	}
+
 	if err != nil {
 		handler.logger.Error("Error in sending message",
 			map[string]interface{}{
@@ -157,7 +200,7 @@ func (handler *SendMessageHandler) SendMessageController(w http.ResponseWriter,
 
 	domainMessage.ID = insertedUUID
 
-	replyMessage, err := handler.MessageService.GetAnswerForMessage(ctx, insertedUUID)
+	replyMessage, err := getAnswerForMessage(ctx, insertedUUID)
 	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
 		handler.logger.Error("Error in replying to message",
 			map[string]interface{}{
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -32,6 +33,8 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 	tests := []struct {
 		name                     string
 		args                     args
+		requestBody              string
+		mockAgentAnswer          bool
 		mockMessageInsertedID    uuid.UUID
 		mockReplyMessageInserted *domain.Message
 		expected                 []byte
@@ -43,6 +46,7 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 				userId:    uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				sessionId: uuid.UUID{0x32, 0x34, 0x56, 0x78},
 			},
+			requestBody:           `{"content":"Hello, this is a test message"}`,
 			mockMessageInsertedID: uuid.UUID{0x42, 0x34, 0x56, 0x88},
 			mockReplyMessageInserted: &domain.Message{
 				ID:            uuid.UUID{0x52, 0x34, 0x56, 0x88},
@@ -52,6 +56,31 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 				CreatedAt:     time.Time{},
 			},
 			expected: json.RawMessage(`{"userMessage":{"id":"42345688-0000-0000-0000-000000000000","sender":"USER","content":"Hello, this is a test message","created_at":"0001-01-01 00:00:00 +0000 UTC"},"systemMessage":{"id":"52345688-0000-0000-0000-000000000000","sender":"SYSTEM","content":"Reply","created_at":"0001-01-01 00:00:00 +0000 UTC"}}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name: "valid in agent mode",
+			args: args{
+				userId:    uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				sessionId: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+			},
+			requestBody:           `{"content":"Hello, this is a test message","mode":"agent"}`,
+			mockAgentAnswer:       true,
+			mockMessageInsertedID: uuid.UUID{0x42, 0x34, 0x56, 0x88},
+			mockReplyMessageInserted: &domain.Message{
+				ID:            uuid.UUID{0x52, 0x34, 0x56, 0x88},
+				ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				Sender:        "SYSTEM",
+				Content:       "Reply",
+				CreatedAt:     time.Time{},
+				ToolCalls: []*domain.ToolCall{
+					{Step: 1, Name: "calculate", Arguments: `{"operation":"divide","a":1,"b":0}`, Result: "error: division by zero", IsError: true},
+					{Step: 2, Name: "calculate", Arguments: `{"operation":"add","a":1,"b":2}`, Result: "3"},
+				},
+			},
+			expected: json.RawMessage(`{"userMessage":{"id":"42345688-0000-0000-0000-000000000000","sender":"USER","content":"Hello, this is a test message","created_at":"0001-01-01 00:00:00 +0000 UTC"},"systemMessage":{"id":"52345688-0000-0000-0000-000000000000","sender":"SYSTEM","content":"Reply","created_at":"0001-01-01 00:00:00 +0000 UTC",` +
+				`"toolCalls":[{"step":1,"name":"calculate","arguments":"{\"operation\":\"divide\",\"a\":1,\"b\":0}","result":"error: division by zero","isError":true},{"step":2,"name":"calculate","arguments":"{\"operation\":\"add\",\"a\":1,\"b\":2}","result":"3"}]}}
 `),
 			expectedStatusCode: 200,
 		},
@@ -63,7 +92,7 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 				"POST",
 				"/users/"+tt.args.userId.String()+"/chat-sessions"+"/"+tt.args.sessionId.String()+"/messages",
 				bytes.NewBuffer(
-					json.RawMessage(`{"content":"Hello, this is a test message"}`),
+					json.RawMessage(tt.requestBody),
 				),
 			)
 
@@ -86,10 +115,17 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 				},
 			).Return(tt.mockMessageInsertedID, nil)
 
-			mockMessageService.EXPECT().GetAnswerForMessage(
-				gomock.Any(),
-				tt.mockMessageInsertedID,
-			).Return(tt.mockReplyMessageInserted, nil)
+			if tt.mockAgentAnswer {
+				mockMessageService.EXPECT().GetAgentAnswerForMessage(
+					gomock.Any(),
+					tt.mockMessageInsertedID,
+				).Return(tt.mockReplyMessageInserted, nil)
+			} else {
+				mockMessageService.EXPECT().GetAnswerForMessage(
+					gomock.Any(),
+					tt.mockMessageInsertedID,
+				).Return(tt.mockReplyMessageInserted, nil)
+			}
 
 			handler := &SendMessageHandler{
 				MessageService: mockMessageService,
@@ -112,3 +148,172 @@ func TestSendMessageHandler_SendMessageController(t *testing.T) {
 		})
 	}
 }
+
+func TestSendMessageHandler_SendMessageControllerHasUserMismatchError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMessageService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest(
+		"POST",
+		"/users/12345678-0000-0000-0000-000000000000/chat-sessions/32345678-0000-0000-0000-000000000000/messages",
+		bytes.NewBuffer(
+			json.RawMessage(`{"content":"Hello, this is a test message"}`),
+		),
+	)
+
+	vars := map[string]string{
+		"user_id":    "12345678-0000-0000-0000-000000000000",
+		"session_id": "32345678-0000-0000-0000-000000000000",
+	}
+	mockRequest = mux.SetURLVars(mockRequest, vars)
+
+	mockRequest.Header.Set("Content-Type", "application/json")
+	mockResponseRecorder := httptest.NewRecorder()
+
+	mockMessageService.EXPECT().CreateMessage(
+		gomock.Any(),
+		uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		&domain.Message{
+			ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+			Sender:        "USER",
+			Content:       "Hello, this is a test message",
+		},
+	).Return(uuid.Nil, customerrors.NewUserMismatchError("32345678-0000-0000-0000-000000000000", "12345678-0000-0000-0000-000000000000"))
+
+	handler := &SendMessageHandler{
+		MessageService: mockMessageService,
+		logger:         logger,
+	}
+	sut := handler.SendMessageController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 12345678-0000-0000-0000-000000000000"}
+`, string(actual))
+	assert.Equal(t, 403, mockResponse.StatusCode)
+}
+
+func TestSendMessageHandler_SendMessageControllerHasQuotaExceededError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMessageService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	resetsAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name               string
+		mockServiceError   error
+		expected           string
+		expectedStatusCode int
+		expectedRetryAfter string
+	}{
+		{
+			name:               "token quota",
+			mockServiceError:   customerrors.NewQuotaExceededError(customerrors.QuotaTokens, 100000, resetsAt),
+			expected:           `{"errorMessage":"monthly token quota of 100000 exceeded, it resets at ` + resetsAt.Format(time.RFC3339) + `"}` + "\n",
+			expectedStatusCode: 402,
+		},
+		{
+			name:               "message quota",
+			mockServiceError:   customerrors.NewQuotaExceededError(customerrors.QuotaMessages, 500, resetsAt),
+			expected:           `{"errorMessage":"monthly message quota of 500 exceeded, it resets at ` + resetsAt.Format(time.RFC3339) + `"}` + "\n",
+			expectedStatusCode: 429,
+			expectedRetryAfter: "3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest(
+				"POST",
+				"/users/12345678-0000-0000-0000-000000000000/chat-sessions/32345678-0000-0000-0000-000000000000/messages",
+				bytes.NewBuffer(
+					json.RawMessage(`{"content":"Hello, this is a test message"}`),
+				),
+			)
+
+			vars := map[string]string{
+				"user_id":    "12345678-0000-0000-0000-000000000000",
+				"session_id": "32345678-0000-0000-0000-000000000000",
+			}
+			mockRequest = mux.SetURLVars(mockRequest, vars)
+
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockMessageService.EXPECT().CreateMessage(
+				gomock.Any(),
+				uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				gomock.Any(),
+			).Return(uuid.Nil, tt.mockServiceError)
+
+			handler := &SendMessageHandler{
+				MessageService: mockMessageService,
+				logger:         logger,
+			}
+			sut := handler.SendMessageController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, mockResponse.Header.Get("Retry-After"))
+		})
+	}
+}
+
+func TestSendMessageHandler_SendMessageControllerHasUnknownMode(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMessageService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest(
+		"POST",
+		"/users/12345678-0000-0000-0000-000000000000/chat-sessions/32345678-0000-0000-0000-000000000000/messages",
+		bytes.NewBuffer(
+			json.RawMessage(`{"content":"Hello, this is a test message","mode":"jedi"}`),
+		),
+	)
+	mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+		"user_id":    "12345678-0000-0000-0000-000000000000",
+		"session_id": "32345678-0000-0000-0000-000000000000",
+	})
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &SendMessageHandler{
+		MessageService: mockMessageService,
+		logger:         logger,
+	}
+	handler.SendMessageController(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"unknown mode jedi"}`+"\n", string(actual))
+	assert.Equal(t, 400, mockResponse.StatusCode)
+}
@@ -2,6 +2,7 @@ package chatSessions
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
@@ -27,13 +28,17 @@ func NewSubmitFeedbackHandler(
 }
 
 // @Summary		Submits a feedback to a message
-// @Description	Submits a feedback to a message
+// @Description	Submits a feedback to a SYSTEM message. The answer is rated with either a thumb (up/down) or a 1-5 rating,
+// @Description	optionally with a reason category, a corrected answer and a free text comment.
 // @Security		BearerAuth
+// @Security		ApiKeyAuth
 // @Param			SubmitFeedbackRequest	body		SubmitFeedbackRequest	true	"request body"
 // @Param			message_id				body		int						true	"message_id"
 // @Success		201						{object}	SubmitFeedbackResponse
 // @Failure		400						{object}	SubmitFeedbackResponse	"Error in message payload"
 // @Failure		401						{object}	SubmitFeedbackResponse	"Authentication error"
+// @Failure		403						{object}	SubmitFeedbackResponse	"Chat session of another user"
+// @Failure		404						{object}	SubmitFeedbackResponse	"Message not found"
 // @Failure		500						{object}	SubmitFeedbackResponse	"Internal Server Error"
 // @Router			/users/user_id/chat-sessions/session_id/messages/message_id/feedback [post]
 func (handler *SubmitFeedbackHandler) SubmitFeedbackController(w http.ResponseWriter, r *http.Request) {
@@ -128,16 +133,40 @@ func (handler *SubmitFeedbackHandler) SubmitFeedbackController(w http.ResponseWr
 		return
 	}
 
+	feedback := request.ToModel()
+
+	err = feedback.Validate()
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
 	err = handler.MessageService.UpdateMessageFeedback(
 		ctx,
 		&domain.Message{
 			ID:            messageID,
-			Feedback:      &request.Feedback,
+			Feedback:      feedback,
 			ChatSessionID: chatSessionID,
 		},
 		userID,
 	)
 
+	var feedbackNotAllowedError *customerrors.FeedbackNotAllowedError
+	if errors.As(err, &feedbackNotAllowedError) {
+		handler.logger.Error("Error in submitting feedback",
+			map[string]interface{}{
+				"errorMessage": feedbackNotAllowedError.Error(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
 	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
 		handler.logger.Error("Error in submitting feedback",
 			map[string]interface{}{
@@ -150,7 +179,8 @@ func (handler *SubmitFeedbackHandler) SubmitFeedbackController(w http.ResponseWr
 		return
 	}
 
-	if userMismatchError, ok := err.(customerrors.UserMismatchError); ok {
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
 		handler.logger.Error("Error in submitting feedback",
 			map[string]interface{}{
 				"errorMessage": userMismatchError.Error(),
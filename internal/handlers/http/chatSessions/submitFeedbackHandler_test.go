@@ -8,9 +8,11 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/loukaspe/rag-golang/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"io"
 	"net/http/httptest"
 	"testing"
 )
@@ -26,21 +28,41 @@ func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackController(t *testing
 		userID        uuid.UUID
 		chatSessionID uuid.UUID
 		messageID     uuid.UUID
-		feedback      string
+		body          string
 	}
 
 	tests := []struct {
 		name               string
 		args               args
+		expectedFeedback   *domain.MessageFeedback
 		expectedStatusCode int
 	}{
 		{
-			name: "valid",
+			name: "thumb with comment",
 			args: args{
 				messageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
 				userID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
-				feedback:      "abla",
+				body:          `{"thumb":"up","feedback":"abla"}`,
+			},
+			expectedFeedback: &domain.MessageFeedback{
+				Thumb:   "up",
+				Comment: "abla",
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name: "rating with reason and corrected answer",
+			args: args{
+				messageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				userID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				body:          `{"rating":2,"reason":"incomplete","correctedAnswer":"Sailboats are 88% slower than Electric cars."}`,
+			},
+			expectedFeedback: &domain.MessageFeedback{
+				Rating:          2,
+				Reason:          "incomplete",
+				CorrectedAnswer: "Sailboats are 88% slower than Electric cars.",
 			},
 			expectedStatusCode: 201,
 		},
@@ -50,9 +72,9 @@ func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackController(t *testing
 		t.Run(tt.name, func(t *testing.T) {
 			mockRequest := httptest.NewRequest(
 				"POST",
-				"/users/"+tt.args.messageID.String()+"/chat-sessions/"+tt.args.chatSessionID.String()+"/messages/"+tt.args.messageID.String(),
+				"/users/"+tt.args.userID.String()+"/chat-sessions/"+tt.args.chatSessionID.String()+"/messages/"+tt.args.messageID.String()+"/feedback",
 				bytes.NewBuffer(
-					json.RawMessage(`{"feedback":"abla"}`),
+					json.RawMessage(tt.args.body),
 				),
 			)
 
@@ -70,7 +92,7 @@ func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackController(t *testing
 				gomock.Any(),
 				&domain.Message{
 					ID:            tt.args.messageID,
-					Feedback:      &tt.args.feedback,
+					Feedback:      tt.expectedFeedback,
 					ChatSessionID: tt.args.chatSessionID,
 				},
 				tt.args.userID,
@@ -91,3 +113,207 @@ func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackController(t *testing
 		})
 	}
 }
+
+func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		body               string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name: "only a comment",
+			body: `{"feedback":"abla"}`,
+			expected: json.RawMessage(`{"errorMessage":"feedback needs a thumb or a rating"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "thumb and rating",
+			body: `{"thumb":"up","rating":5}`,
+			expected: json.RawMessage(`{"errorMessage":"feedback cannot have both a thumb and a rating"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "unknown thumb",
+			body: `{"thumb":"sideways"}`,
+			expected: json.RawMessage(`{"errorMessage":"thumb must be up or down"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "rating out of range",
+			body: `{"rating":6}`,
+			expected: json.RawMessage(`{"errorMessage":"rating must be between 1 and 5"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "negative rating",
+			body: `{"rating":-1}`,
+			expected: json.RawMessage(`{"errorMessage":"rating must be between 1 and 5"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "unknown reason",
+			body: `{"thumb":"down","reason":"rude"}`,
+			expected: json.RawMessage(`{"errorMessage":"reason must be one of wrong, incomplete, hallucinated, off-topic"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "malformed body",
+			body: `{"rating":"five"}`,
+			expected: json.RawMessage(`{"errorMessage":"malformed submitting feedback request"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest(
+				"POST",
+				"/users/42345678-0000-0000-0000-000000000000/chat-sessions/32345678-0000-0000-0000-000000000000/messages/12345678-0000-0000-0000-000000000000/feedback",
+				bytes.NewBuffer(
+					json.RawMessage(tt.body),
+				),
+			)
+
+			vars := map[string]string{
+				"user_id":    "42345678-0000-0000-0000-000000000000",
+				"session_id": "32345678-0000-0000-0000-000000000000",
+				"message_id": "12345678-0000-0000-0000-000000000000",
+			}
+			mockRequest = mux.SetURLVars(mockRequest, vars)
+
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &SubmitFeedbackHandler{
+				MessageService: mockService,
+				logger:         logger,
+			}
+			sut := handler.SubmitFeedbackController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestUpdateMessageFeedbackHandler_UpdateMessageFeedbackControllerHasServiceError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+
+	type args struct {
+		userID        uuid.UUID
+		chatSessionID uuid.UUID
+		messageID     uuid.UUID
+	}
+
+	tests := []struct {
+		name                     string
+		args                     args
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name: "feedback on a user message",
+			args: args{
+				messageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				userID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+			mockServiceResponseError: customerrors.NewFeedbackNotAllowedError("12345678-0000-0000-0000-000000000000", "USER"),
+			expected: json.RawMessage(`{"errorMessage":"message 12345678-0000-0000-0000-000000000000 is sent by USER, only SYSTEM messages accept feedback"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "chat session of another user",
+			args: args{
+				messageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				chatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				userID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+			mockServiceResponseError: customerrors.NewUserMismatchError("32345678-0000-0000-0000-000000000000", "42345678-0000-0000-0000-000000000000"),
+			expected: json.RawMessage(`{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000"}
+`),
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest(
+				"POST",
+				"/users/"+tt.args.userID.String()+"/chat-sessions/"+tt.args.chatSessionID.String()+"/messages/"+tt.args.messageID.String()+"/feedback",
+				bytes.NewBuffer(
+					json.RawMessage(`{"thumb":"down"}`),
+				),
+			)
+
+			vars := map[string]string{
+				"user_id":    tt.args.userID.String(),
+				"session_id": tt.args.chatSessionID.String(),
+				"message_id": tt.args.messageID.String(),
+			}
+			mockRequest = mux.SetURLVars(mockRequest, vars)
+
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().UpdateMessageFeedback(
+				gomock.Any(),
+				&domain.Message{
+					ID:            tt.args.messageID,
+					Feedback:      &domain.MessageFeedback{Thumb: "down"},
+					ChatSessionID: tt.args.chatSessionID,
+				},
+				tt.args.userID,
+			).Return(tt.mockServiceResponseError)
+
+			handler := &SubmitFeedbackHandler{
+				MessageService: mockService,
+				logger:         logger,
+			}
+			sut := handler.SubmitFeedbackController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
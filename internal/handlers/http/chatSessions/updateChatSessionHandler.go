@@ -0,0 +1,220 @@
+package chatSessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type UpdateChatSessionHandler struct {
+	ChatSessionService services.ChatSessionServiceInterface
+	logger             logger.LoggerInterface
+}
+
+func NewUpdateChatSessionHandler(
+	service services.ChatSessionServiceInterface,
+	logger logger.LoggerInterface,
+) *UpdateChatSessionHandler {
+	return &UpdateChatSessionHandler{
+		ChatSessionService: service,
+		logger:             logger,
+	}
+}
+
+// @Summary		Renames chat session
+// @Description	Sets the title of a chat session of the user, which is then not replaced by a generated one
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id						path		string						true	"user id"
+// @Param			session_id					path		string						true	"chat session id"
+// @Param			RenameChatSessionRequest	body		RenameChatSessionRequest	true	"request body"
+// @Success		200							{object}	ChatSessionResponse
+// @Failure		400							{object}	ChatSessionResponse	"Error in path parameters or payload"
+// @Failure		401							{object}	ChatSessionResponse	"Authentication error"
+// @Failure		403							{object}	ChatSessionResponse	"Chat session of another user"
+// @Failure		404							{object}	ChatSessionResponse	"Chat session not found"
+// @Failure		500							{object}	ChatSessionResponse	"Internal Server Error"
+// @Router			/users/{user_id}/chat-sessions/{session_id}/title [put]
+func (handler *UpdateChatSessionHandler) RenameChatSessionController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	request := &RenameChatSessionRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in updating chat session",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		handler.JsonResponse(w, http.StatusBadRequest, &ChatSessionResponse{ErrorMessage: "malformed rename chat session request"})
+
+		return
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" || utf8.RuneCountInString(title) > domain.MaxChatSessionTitleLength {
+		handler.JsonResponse(w, http.StatusBadRequest, &ChatSessionResponse{
+			ErrorMessage: "title must have 1 to " + strconv.Itoa(domain.MaxChatSessionTitleLength) + " characters",
+		})
+
+		return
+	}
+
+	handler.update(w, r, func(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error) {
+		return handler.ChatSessionService.RenameChatSession(ctx, sessionID, userID, title)
+	})
+}
+
+// @Summary		Archives chat session
+// @Description	Archives a chat session of the user. Archived sessions keep their messages and can still be read and continued
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id		path		string	true	"user id"
+// @Param			session_id	path		string	true	"chat session id"
+// @Success		200			{object}	ChatSessionResponse
+// @Failure		400			{object}	ChatSessionResponse	"Error in path parameters"
+// @Failure		401			{object}	ChatSessionResponse	"Authentication error"
+// @Failure		403			{object}	ChatSessionResponse	"Chat session of another user"
+// @Failure		404			{object}	ChatSessionResponse	"Chat session not found"
+// @Failure		500			{object}	ChatSessionResponse	"Internal Server Error"
+// @Router			/users/{user_id}/chat-sessions/{session_id}/archive [post]
+func (handler *UpdateChatSessionHandler) ArchiveChatSessionController(w http.ResponseWriter, r *http.Request) {
+	handler.update(w, r, handler.ChatSessionService.ArchiveChatSession)
+}
+
+// @Summary		Unarchives chat session
+// @Description	Unarchives an archived chat session of the user
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id		path		string	true	"user id"
+// @Param			session_id	path		string	true	"chat session id"
+// @Success		200			{object}	ChatSessionResponse
+// @Failure		400			{object}	ChatSessionResponse	"Error in path parameters"
+// @Failure		401			{object}	ChatSessionResponse	"Authentication error"
+// @Failure		403			{object}	ChatSessionResponse	"Chat session of another user"
+// @Failure		404			{object}	ChatSessionResponse	"Chat session not found"
+// @Failure		500			{object}	ChatSessionResponse	"Internal Server Error"
+// @Router			/users/{user_id}/chat-sessions/{session_id}/unarchive [post]
+func (handler *UpdateChatSessionHandler) UnarchiveChatSessionController(w http.ResponseWriter, r *http.Request) {
+	handler.update(w, r, handler.ChatSessionService.UnarchiveChatSession)
+}
+
+// update responds with the updated chat session, without its messages
+func (handler *UpdateChatSessionHandler) update(
+	w http.ResponseWriter,
+	r *http.Request,
+	updateFunc func(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (*domain.ChatSession, error),
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &ChatSessionResponse{}
+
+	sessionID, userID, errorMessage := chatSessionPathIDs(r)
+	if errorMessage != "" {
+		response.ErrorMessage = errorMessage
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	chatSession, err := updateFunc(ctx, sessionID, userID)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in updating chat session",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	var userMismatchError *customerrors.UserMismatchError
+	if errors.As(err, &userMismatchError) {
+		handler.logger.Error("Error in updating chat session",
+			map[string]interface{}{
+				"errorMessage": userMismatchError.Error(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusForbidden, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in updating chat session",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in updating chat session"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	chatSession.Messages = nil
+	response = ChatSessionResponseFromModel(chatSession)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+// chatSessionPathIDs reads the session_id and user_id of the path, or returns
+// what is wrong with them
+func chatSessionPathIDs(r *http.Request) (uuid.UUID, uuid.UUID, string) {
+	sessionIDAsString := mux.Vars(r)["session_id"]
+	if sessionIDAsString == "" {
+		return uuid.Nil, uuid.Nil, "missing session id"
+	}
+
+	sessionID, err := uuid.Parse(sessionIDAsString)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "malformed session uuid"
+	}
+
+	userIDAsString := mux.Vars(r)["user_id"]
+	if userIDAsString == "" {
+		return uuid.Nil, uuid.Nil, "missing user id"
+	}
+
+	userID, err := uuid.Parse(userIDAsString)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "malformed user uuid"
+	}
+
+	return sessionID, userID, ""
+}
+
+func (handler *UpdateChatSessionHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *ChatSessionResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in updating chat session - json response"
+
+		handler.logger.Error("Error in updating chat session - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
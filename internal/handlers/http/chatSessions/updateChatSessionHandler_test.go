@@ -0,0 +1,346 @@
+package chatSessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateChatSessionHandler_RenameChatSessionController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	sessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name                     string
+		body                     string
+		mockServiceCalled        bool
+		expectedTitle            string
+		mockServiceReturned      *domain.ChatSession
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name:              "valid",
+			body:              `{"title":"  Sailboats  "}`,
+			mockServiceCalled: true,
+			expectedTitle:     "Sailboats",
+			mockServiceReturned: &domain.ChatSession{
+				ID:        sessionID,
+				UserID:    userID,
+				Title:     "Sailboats",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Messages:  []*domain.Message{{Content: "how fast are sailboats?"}},
+			},
+			expected: json.RawMessage(`{"id":"32345678-0000-0000-0000-000000000000","title":"Sailboats","createdAt":"2025-01-02 03:04:05 +0000 UTC","updatedAt":"2025-01-02 03:04:05 +0000 UTC"}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name: "empty title",
+			body: `{"title":"  "}`,
+			expected: json.RawMessage(`{"errorMessage":"title must have 1 to 200 characters"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "too long title",
+			body: `{"title":"` + strings.Repeat("a", 201) + `"}`,
+			expected: json.RawMessage(`{"errorMessage":"title must have 1 to 200 characters"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "malformed body",
+			body: `{"title":`,
+			expected: json.RawMessage(`{"errorMessage":"malformed rename chat session request"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:                     "chat session of another user",
+			body:                     `{"title":"Sailboats"}`,
+			mockServiceCalled:        true,
+			expectedTitle:            "Sailboats",
+			mockServiceResponseError: customerrors.NewUserMismatchError(sessionID.String(), userID.String()),
+			expected: json.RawMessage(`{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000"}
+`),
+			expectedStatusCode: 403,
+		},
+		{
+			name:              "not found",
+			body:              `{"title":"Sailboats"}`,
+			mockServiceCalled: true,
+			expectedTitle:     "Sailboats",
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chatSessionID 32345678-0000-0000-0000-000000000000 not found"),
+			},
+			expected: json.RawMessage(`{}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:                     "service random error",
+			body:                     `{"title":"Sailboats"}`,
+			mockServiceCalled:        true,
+			expectedTitle:            "Sailboats",
+			mockServiceResponseError: errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in updating chat session"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("PUT", "/users/"+userID.String()+"/chat-sessions/"+sessionID.String()+"/title", strings.NewReader(tt.body))
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"user_id":    userID.String(),
+				"session_id": sessionID.String(),
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			if tt.mockServiceCalled {
+				mockService.EXPECT().RenameChatSession(
+					gomock.Any(),
+					sessionID,
+					userID,
+					tt.expectedTitle,
+				).Return(tt.mockServiceReturned, tt.mockServiceResponseError)
+			}
+
+			handler := &UpdateChatSessionHandler{
+				ChatSessionService: mockService,
+				logger:             logger,
+			}
+			sut := handler.RenameChatSessionController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestUpdateChatSessionHandler_ArchiveChatSessionController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	sessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	archivedAt := time.Date(2025, 2, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name                     string
+		mockServiceReturned      *domain.ChatSession
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name: "valid",
+			mockServiceReturned: &domain.ChatSession{
+				ID:         sessionID,
+				UserID:     userID,
+				Title:      "Sailboats",
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+				ArchivedAt: &archivedAt,
+			},
+			expected: json.RawMessage(`{"id":"32345678-0000-0000-0000-000000000000","title":"Sailboats","createdAt":"2025-01-02 03:04:05 +0000 UTC","updatedAt":"2025-01-02 03:04:05 +0000 UTC","archivedAt":"2025-02-02 03:04:05 +0000 UTC"}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                     "chat session of another user",
+			mockServiceResponseError: customerrors.NewUserMismatchError(sessionID.String(), userID.String()),
+			expected: json.RawMessage(`{"errorMessage":"chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000"}
+`),
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/users/"+userID.String()+"/chat-sessions/"+sessionID.String()+"/archive", nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"user_id":    userID.String(),
+				"session_id": sessionID.String(),
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().ArchiveChatSession(
+				gomock.Any(),
+				sessionID,
+				userID,
+			).Return(tt.mockServiceReturned, tt.mockServiceResponseError)
+
+			handler := &UpdateChatSessionHandler{
+				ChatSessionService: mockService,
+				logger:             logger,
+			}
+			sut := handler.ArchiveChatSessionController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestUpdateChatSessionHandler_UnarchiveChatSessionController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	sessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mockRequest := httptest.NewRequest("POST", "/users/"+userID.String()+"/chat-sessions/"+sessionID.String()+"/unarchive", nil)
+	mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+		"user_id":    userID.String(),
+		"session_id": sessionID.String(),
+	})
+	mockResponseRecorder := httptest.NewRecorder()
+
+	mockService.EXPECT().UnarchiveChatSession(
+		gomock.Any(),
+		sessionID,
+		userID,
+	).Return(&domain.ChatSession{
+		ID:        sessionID,
+		UserID:    userID,
+		Title:     "Sailboats",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}, nil)
+
+	handler := &UpdateChatSessionHandler{
+		ChatSessionService: mockService,
+		logger:             logger,
+	}
+	sut := handler.UnarchiveChatSessionController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"id":"32345678-0000-0000-0000-000000000000","title":"Sailboats","createdAt":"2025-01-02 03:04:05 +0000 UTC","updatedAt":"2025-01-02 03:04:05 +0000 UTC"}
+`, string(actual))
+	assert.Equal(t, 200, mockResponse.StatusCode)
+}
+
+func TestUpdateChatSessionHandler_ArchiveChatSessionControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		urlVars            map[string]string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:    "empty session id",
+			urlVars: map[string]string{"user_id": "42345678-0000-0000-0000-000000000000"},
+			expected: json.RawMessage(`{"errorMessage":"missing session id"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "session id not a uuid",
+			urlVars: map[string]string{"user_id": "42345678-0000-0000-0000-000000000000", "session_id": "55"},
+			expected: json.RawMessage(`{"errorMessage":"malformed session uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "user id not a uuid",
+			urlVars: map[string]string{"user_id": "55", "session_id": "32345678-0000-0000-0000-000000000000"},
+			expected: json.RawMessage(`{"errorMessage":"malformed user uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/users/user_id/chat-sessions/session_id/archive", nil)
+			mockRequest = mux.SetURLVars(mockRequest, tt.urlVars)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &UpdateChatSessionHandler{
+				ChatSessionService: mockService,
+				logger:             logger,
+			}
+			sut := handler.ArchiveChatSessionController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
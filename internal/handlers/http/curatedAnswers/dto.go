@@ -0,0 +1,58 @@
+package curatedAnswers
+
+import (
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type CuratedAnswersResponse struct {
+	CuratedAnswers []CuratedAnswerResponse `json:"curatedAnswers,omitempty"`
+	ErrorMessage   string                  `json:"errorMessage,omitempty"`
+}
+
+type CuratedAnswerResponse struct {
+	ID             string `json:"id,omitempty"`
+	MessageID      string `json:"messageId,omitempty"`
+	Question       string `json:"question,omitempty"`
+	OriginalAnswer string `json:"originalAnswer,omitempty"`
+	Answer         string `json:"answer,omitempty"`
+	Status         string `json:"status,omitempty" enums:"pending,approved,rejected"`
+	VectorID       string `json:"vectorId,omitempty"`
+	CreatedAt      string `json:"createdAt,omitempty"`
+	ReviewedAt     string `json:"reviewedAt,omitempty"`
+	ErrorMessage   string `json:"errorMessage,omitempty"`
+}
+
+func CuratedAnswerResponseFromModel(curatedAnswer *domain.CuratedAnswer) *CuratedAnswerResponse {
+	response := &CuratedAnswerResponse{
+		ID:             curatedAnswer.ID.String(),
+		Question:       curatedAnswer.Question,
+		OriginalAnswer: curatedAnswer.OriginalAnswer,
+		Answer:         curatedAnswer.Answer,
+		Status:         curatedAnswer.Status,
+		VectorID:       curatedAnswer.VectorID,
+		CreatedAt:      curatedAnswer.CreatedAt.Format(time.RFC3339),
+	}
+
+	if curatedAnswer.MessageID != uuid.Nil {
+		response.MessageID = curatedAnswer.MessageID.String()
+	}
+
+	if curatedAnswer.ReviewedAt != nil {
+		response.ReviewedAt = curatedAnswer.ReviewedAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func CuratedAnswersResponseFromModel(curatedAnswers []*domain.CuratedAnswer) *CuratedAnswersResponse {
+	responses := make([]CuratedAnswerResponse, len(curatedAnswers))
+	for i, curatedAnswer := range curatedAnswers {
+		responses[i] = *CuratedAnswerResponseFromModel(curatedAnswer)
+	}
+
+	return &CuratedAnswersResponse{
+		CuratedAnswers: responses,
+	}
+}
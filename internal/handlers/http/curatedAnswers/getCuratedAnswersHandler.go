@@ -0,0 +1,89 @@
+package curatedAnswers
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type GetCuratedAnswersHandler struct {
+	CuratedAnswerService services.CuratedAnswerServiceInterface
+	logger               logger.LoggerInterface
+}
+
+func NewGetCuratedAnswersHandler(
+	service services.CuratedAnswerServiceInterface,
+	logger logger.LoggerInterface,
+) *GetCuratedAnswersHandler {
+	return &GetCuratedAnswersHandler{
+		CuratedAnswerService: service,
+		logger:               logger,
+	}
+}
+
+// @Summary		Gets curated answers
+// @Description	Gets the answers corrected through feedback, optionally filtered by review status
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			status	query		string	false	"pending, approved or rejected"
+// @Success		200		{object}	CuratedAnswersResponse
+// @Failure		400		{object}	CuratedAnswersResponse	"Error in query parameters"
+// @Failure		401		{object}	CuratedAnswersResponse	"Authentication error"
+// @Failure		403		{string}	string					"Not a curator or API key without the kb:manage scope"
+// @Failure		500		{object}	CuratedAnswersResponse	"Internal Server Error"
+// @Router			/admin/curated-answers [get]
+func (handler *GetCuratedAnswersHandler) GetCuratedAnswersController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &CuratedAnswersResponse{}
+
+	status := r.URL.Query().Get("status")
+	if status != "" &&
+		status != domain.CuratedAnswerStatusPending &&
+		status != domain.CuratedAnswerStatusApproved &&
+		status != domain.CuratedAnswerStatusRejected {
+		response.ErrorMessage = "status must be one of pending, approved or rejected"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	curatedAnswers, err := handler.CuratedAnswerService.GetCuratedAnswers(ctx, status)
+	if err != nil {
+		handler.logger.Error("Error in getting curated answers",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting curated answers"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = CuratedAnswersResponseFromModel(curatedAnswers)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *GetCuratedAnswersHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *CuratedAnswersResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in getting curated answers - json response"
+
+		handler.logger.Error("Error in getting curated answers - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,142 @@
+package curatedAnswers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetCuratedAnswersHandler_GetCuratedAnswersController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockCuratedAnswerServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                       string
+		query                      string
+		expectedStatus             string
+		mockCuratedAnswersReturned []*domain.CuratedAnswer
+		expected                   []byte
+		expectedStatusCode         int
+	}{
+		{
+			name:           "pending",
+			query:          "?status=pending",
+			expectedStatus: "pending",
+			mockCuratedAnswersReturned: []*domain.CuratedAnswer{
+				{
+					ID:             uuid.UUID{0x42, 0x34, 0x56, 0x78},
+					MessageID:      uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Question:       "how fast are sailboats?",
+					OriginalAnswer: "Sailboats are 88% faster than Electric cars.",
+					Answer:         "Sailboats are 88% slower than Electric cars.",
+					Status:         "pending",
+					CreatedAt:      time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			expected: json.RawMessage(`{"curatedAnswers":[{"id":"42345678-0000-0000-0000-000000000000","messageId":"12345678-0000-0000-0000-000000000000","question":"how fast are sailboats?","originalAnswer":"Sailboats are 88% faster than Electric cars.","answer":"Sailboats are 88% slower than Electric cars.","status":"pending","createdAt":"2025-05-29T10:00:00Z"}]}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:           "approved of a purged chat session",
+			query:          "?status=approved",
+			expectedStatus: "approved",
+			mockCuratedAnswersReturned: []*domain.CuratedAnswer{
+				{
+					ID:             uuid.UUID{0x42, 0x34, 0x56, 0x78},
+					Question:       "how fast are sailboats?",
+					OriginalAnswer: "Sailboats are 88% faster than Electric cars.",
+					Answer:         "Sailboats are 88% slower than Electric cars.",
+					Status:         "approved",
+					VectorID:       "curated-42345678-0000-0000-0000-000000000000",
+					CreatedAt:      time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			expected: json.RawMessage(`{"curatedAnswers":[{"id":"42345678-0000-0000-0000-000000000000","question":"how fast are sailboats?","originalAnswer":"Sailboats are 88% faster than Electric cars.","answer":"Sailboats are 88% slower than Electric cars.","status":"approved","vectorId":"curated-42345678-0000-0000-0000-000000000000","createdAt":"2025-05-29T10:00:00Z"}]}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                       "all",
+			query:                      "",
+			expectedStatus:             "",
+			mockCuratedAnswersReturned: []*domain.CuratedAnswer{},
+			expected: json.RawMessage(`{}
+`),
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/admin/curated-answers"+tt.query, nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().GetCuratedAnswers(
+				gomock.Any(),
+				tt.expectedStatus,
+			).Return(tt.mockCuratedAnswersReturned, nil)
+
+			handler := &GetCuratedAnswersHandler{
+				CuratedAnswerService: mockService,
+				logger:               logger,
+			}
+			sut := handler.GetCuratedAnswersController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestGetCuratedAnswersHandler_GetCuratedAnswersControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockCuratedAnswerServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest("GET", "/admin/curated-answers?status=archived", nil)
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &GetCuratedAnswersHandler{
+		CuratedAnswerService: mockService,
+		logger:               logger,
+	}
+	sut := handler.GetCuratedAnswersController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"status must be one of pending, approved or rejected"}
+`, string(actual))
+	assert.Equal(t, 400, mockResponse.StatusCode)
+}
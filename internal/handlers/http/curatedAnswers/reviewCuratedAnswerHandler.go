@@ -0,0 +1,154 @@
+package curatedAnswers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type ReviewCuratedAnswerHandler struct {
+	CuratedAnswerService services.CuratedAnswerServiceInterface
+	logger               logger.LoggerInterface
+}
+
+func NewReviewCuratedAnswerHandler(
+	service services.CuratedAnswerServiceInterface,
+	logger logger.LoggerInterface,
+) *ReviewCuratedAnswerHandler {
+	return &ReviewCuratedAnswerHandler{
+		CuratedAnswerService: service,
+		logger:               logger,
+	}
+}
+
+// @Summary		Approves a curated answer
+// @Description	Ingests a pending curated answer in the vector store, tagged with its question, so that similar questions prefer it
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			curated_answer_id	path		string	true	"curated answer id"
+// @Success		200					{object}	CuratedAnswerResponse
+// @Failure		400					{object}	CuratedAnswerResponse	"Error in path parameters"
+// @Failure		401					{object}	CuratedAnswerResponse	"Authentication error"
+// @Failure		403					{string}	string					"Not a curator or API key without the kb:manage scope"
+// @Failure		404					{object}	CuratedAnswerResponse	"Curated answer not found"
+// @Failure		409					{object}	CuratedAnswerResponse	"Curated answer already reviewed"
+// @Failure		429					{string}	string					"Rate limit exceeded, retry after the Retry-After seconds"
+// @Failure		500					{object}	CuratedAnswerResponse	"Internal Server Error"
+// @Router			/admin/curated-answers/{curated_answer_id}/approve [post]
+func (handler *ReviewCuratedAnswerHandler) ApproveCuratedAnswerController(w http.ResponseWriter, r *http.Request) {
+	handler.review(w, r, handler.CuratedAnswerService.ApproveCuratedAnswer)
+}
+
+// @Summary		Rejects a curated answer
+// @Description	Rejects a pending curated answer, it is never used as context
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			curated_answer_id	path		string	true	"curated answer id"
+// @Success		200					{object}	CuratedAnswerResponse
+// @Failure		400					{object}	CuratedAnswerResponse	"Error in path parameters"
+// @Failure		401					{object}	CuratedAnswerResponse	"Authentication error"
+// @Failure		403					{string}	string					"Not a curator or API key without the kb:manage scope"
+// @Failure		404					{object}	CuratedAnswerResponse	"Curated answer not found"
+// @Failure		409					{object}	CuratedAnswerResponse	"Curated answer already reviewed"
+// @Failure		500					{object}	CuratedAnswerResponse	"Internal Server Error"
+// @Router			/admin/curated-answers/{curated_answer_id}/reject [post]
+func (handler *ReviewCuratedAnswerHandler) RejectCuratedAnswerController(w http.ResponseWriter, r *http.Request) {
+	handler.review(w, r, handler.CuratedAnswerService.RejectCuratedAnswer)
+}
+
+func (handler *ReviewCuratedAnswerHandler) review(
+	w http.ResponseWriter,
+	r *http.Request,
+	reviewFunc func(context.Context, uuid.UUID) (*domain.CuratedAnswer, error),
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &CuratedAnswerResponse{}
+
+	curatedAnswerIDAsString := mux.Vars(r)["curated_answer_id"]
+	if curatedAnswerIDAsString == "" {
+		response.ErrorMessage = "missing curated answer id"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	curatedAnswerID, err := uuid.Parse(curatedAnswerIDAsString)
+	if err != nil {
+		handler.logger.Error("Error in reviewing curated answer",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed curated answer uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	curatedAnswer, err := reviewFunc(ctx, curatedAnswerID)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in reviewing curated answer",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	var notPendingError *customerrors.CuratedAnswerNotPendingError
+	if errors.As(err, &notPendingError) {
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusConflict, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in reviewing curated answer",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in reviewing curated answer"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = CuratedAnswerResponseFromModel(curatedAnswer)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *ReviewCuratedAnswerHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *CuratedAnswerResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in reviewing curated answer - json response"
+
+		handler.logger.Error("Error in reviewing curated answer - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
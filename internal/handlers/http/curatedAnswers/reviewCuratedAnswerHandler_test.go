@@ -0,0 +1,175 @@
+package curatedAnswers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReviewCuratedAnswerHandler_ApproveCuratedAnswerController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockCuratedAnswerServiceInterface(mockCtrl)
+
+	reviewedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                     string
+		curatedAnswerID          uuid.UUID
+		mockServiceReturned      *domain.CuratedAnswer
+		mockServiceResponseError error
+		expected                 []byte
+		expectedStatusCode       int
+	}{
+		{
+			name:            "valid",
+			curatedAnswerID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			mockServiceReturned: &domain.CuratedAnswer{
+				ID:         uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				MessageID:  uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				Question:   "how fast are sailboats?",
+				Answer:     "Sailboats are 88% slower than Electric cars.",
+				Status:     "approved",
+				VectorID:   "curated-42345678-0000-0000-0000-000000000000",
+				CreatedAt:  time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				ReviewedAt: &reviewedAt,
+			},
+			expected: json.RawMessage(`{"id":"42345678-0000-0000-0000-000000000000","messageId":"12345678-0000-0000-0000-000000000000","question":"how fast are sailboats?","answer":"Sailboats are 88% slower than Electric cars.","status":"approved","vectorId":"curated-42345678-0000-0000-0000-000000000000","createdAt":"2025-05-29T10:00:00Z","reviewedAt":"2025-05-30T10:00:00Z"}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                     "already reviewed",
+			curatedAnswerID:          uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			mockServiceResponseError: customerrors.NewCuratedAnswerNotPendingError("42345678-0000-0000-0000-000000000000", "rejected"),
+			expected: json.RawMessage(`{"errorMessage":"curated answer 42345678-0000-0000-0000-000000000000 is already rejected"}
+`),
+			expectedStatusCode: 409,
+		},
+		{
+			name:            "not found",
+			curatedAnswerID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("curatedAnswerID 42345678-0000-0000-0000-000000000000 not found"),
+			},
+			expected: json.RawMessage(`{}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:                     "service random error",
+			curatedAnswerID:          uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			mockServiceResponseError: errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in reviewing curated answer"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/admin/curated-answers/"+tt.curatedAnswerID.String()+"/approve", nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"curated_answer_id": tt.curatedAnswerID.String(),
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().ApproveCuratedAnswer(
+				gomock.Any(),
+				tt.curatedAnswerID,
+			).Return(tt.mockServiceReturned, tt.mockServiceResponseError)
+
+			handler := &ReviewCuratedAnswerHandler{
+				CuratedAnswerService: mockService,
+				logger:               logger,
+			}
+			sut := handler.ApproveCuratedAnswerController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestReviewCuratedAnswerHandler_RejectCuratedAnswerControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockCuratedAnswerServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                    string
+		curatedAnswerIDAsString string
+		expected                []byte
+		expectedStatusCode      int
+	}{
+		{
+			name:                    "empty curated answer id",
+			curatedAnswerIDAsString: "",
+			expected: json.RawMessage(`{"errorMessage":"missing curated answer id"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:                    "curated answer id not a uuid",
+			curatedAnswerIDAsString: "55",
+			expected: json.RawMessage(`{"errorMessage":"malformed curated answer uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/admin/curated-answers/"+tt.curatedAnswerIDAsString+"/reject", nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{
+				"curated_answer_id": tt.curatedAnswerIDAsString,
+			})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &ReviewCuratedAnswerHandler{
+				CuratedAnswerService: mockService,
+				logger:               logger,
+			}
+			sut := handler.RejectCuratedAnswerController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
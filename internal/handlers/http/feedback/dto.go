@@ -0,0 +1,144 @@
+package feedback
+
+import (
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"net/http"
+	"time"
+)
+
+const defaultFeedbackPeriod = 30 * 24 * time.Hour
+
+type FeedbackAnalyticsResponse struct {
+	From         string                    `json:"from,omitempty"`
+	To           string                    `json:"to,omitempty"`
+	Interval     string                    `json:"interval,omitempty"`
+	Buckets      []FeedbackAnalyticsBucket `json:"buckets,omitempty"`
+	ErrorMessage string                    `json:"errorMessage,omitempty"`
+}
+
+type FeedbackAnalyticsBucket struct {
+	Period       string  `json:"period"`
+	Answers      int     `json:"answers"`
+	Feedback     int     `json:"feedback"`
+	FeedbackRate float64 `json:"feedbackRate"`
+}
+
+func FeedbackAnalyticsResponseFromModel(
+	from time.Time,
+	to time.Time,
+	interval string,
+	analytics []*domain.FeedbackAnalytics,
+) *FeedbackAnalyticsResponse {
+	buckets := make([]FeedbackAnalyticsBucket, len(analytics))
+	for i, bucket := range analytics {
+		buckets[i] = FeedbackAnalyticsBucket{
+			Period:   bucket.Period.Format(time.RFC3339),
+			Answers:  bucket.Answers,
+			Feedback: bucket.Feedback,
+		}
+
+		if bucket.Answers > 0 {
+			buckets[i].FeedbackRate = float64(bucket.Feedback) / float64(bucket.Answers)
+		}
+	}
+
+	return &FeedbackAnalyticsResponse{
+		From:     from.Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+		Interval: interval,
+		Buckets:  buckets,
+	}
+}
+
+// FeedbackExportRecord is one line of the feedback export. Its fields match
+// the golden question format of cmd/eval, so an export can be used as a golden
+// set directly.
+type FeedbackExportRecord struct {
+	ID              string                  `json:"id"`
+	ChatSessionID   string                  `json:"chat_session_id"`
+	Question        string                  `json:"question"`
+	Context         []FeedbackExportContext `json:"context"`
+	Answer          string                  `json:"answer"`
+	Thumb           string                  `json:"thumb,omitempty"`
+	Rating          int                     `json:"rating,omitempty"`
+	Reason          string                  `json:"reason,omitempty"`
+	CorrectedAnswer string                  `json:"corrected_answer,omitempty"`
+	Feedback        string                  `json:"feedback,omitempty"`
+	SubmittedAt     string                  `json:"submitted_at"`
+	CreatedAt       string                  `json:"created_at"`
+}
+
+type FeedbackExportContext struct {
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+}
+
+func FeedbackExportRecordFromModel(entry *domain.FeedbackEntry) *FeedbackExportRecord {
+	context := make([]FeedbackExportContext, len(entry.Context))
+	for i, chunk := range entry.Context {
+		context[i] = FeedbackExportContext{
+			ID:    chunk.ID,
+			Text:  chunk.Text,
+			Score: chunk.Score,
+		}
+	}
+
+	return &FeedbackExportRecord{
+		ID:              entry.MessageID.String(),
+		ChatSessionID:   entry.ChatSessionID.String(),
+		Question:        entry.Question,
+		Context:         context,
+		Answer:          entry.Answer,
+		Thumb:           entry.Feedback.Thumb,
+		Rating:          entry.Feedback.Rating,
+		Reason:          entry.Feedback.Reason,
+		CorrectedAnswer: entry.Feedback.CorrectedAnswer,
+		Feedback:        entry.Feedback.Comment,
+		SubmittedAt:     entry.Feedback.SubmittedAt.Format(time.RFC3339),
+		CreatedAt:       entry.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type FeedbackExportErrorResponse struct {
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// timeRangeFromRequest reads the optional "from" and "to" query parameters as
+// RFC 3339 timestamps or YYYY-MM-DD dates. "to" defaults to now and "from" to
+// 30 days before "to".
+func timeRangeFromRequest(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("malformed to date")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultFeedbackPeriod)
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("malformed from date")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from date must be before to date")
+	}
+
+	return from, to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
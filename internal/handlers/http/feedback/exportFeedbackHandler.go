@@ -0,0 +1,102 @@
+package feedback
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type ExportFeedbackHandler struct {
+	FeedbackService services.FeedbackServiceInterface
+	logger          logger.LoggerInterface
+}
+
+func NewExportFeedbackHandler(
+	service services.FeedbackServiceInterface,
+	logger logger.LoggerInterface,
+) *ExportFeedbackHandler {
+	return &ExportFeedbackHandler{
+		FeedbackService: service,
+		logger:          logger,
+	}
+}
+
+// @Summary		Exports feedback as JSONL
+// @Description	Exports every answer that received feedback as a (question, context, answer, feedback) JSON line.
+// @Description	The lines can be used as a golden set of cmd/eval.
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Produce		application/x-ndjson
+// @Param			from	query		string	false	"start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days before to"
+// @Param			to		query		string	false	"end date (RFC 3339 or YYYY-MM-DD), defaults to now"
+// @Success		200		{object}	FeedbackExportRecord
+// @Failure		400		{object}	FeedbackExportErrorResponse	"Error in query parameters"
+// @Failure		401		{object}	FeedbackExportErrorResponse	"Authentication error"
+// @Failure		403		{string}	string						"Not a curator or API key without the kb:manage scope"
+// @Failure		500		{object}	FeedbackExportErrorResponse	"Internal Server Error"
+// @Router			/admin/feedback/export [get]
+func (handler *ExportFeedbackHandler) ExportFeedbackController(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	response := &FeedbackExportErrorResponse{}
+
+	from, to, err := timeRangeFromRequest(r)
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	entries, err := handler.FeedbackService.GetFeedbackEntries(ctx, from, to)
+	if err != nil {
+		handler.logger.Error("Error in exporting feedback",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in exporting feedback"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="feedback.jsonl"`)
+	w.WriteHeader(http.StatusOK)
+
+	// json.Encoder terminates every value with a new line
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		err = encoder.Encode(FeedbackExportRecordFromModel(entry))
+		if err != nil {
+			handler.logger.Error("Error in exporting feedback - json response",
+				map[string]interface{}{
+					"errorMessage": err.Error(),
+				})
+
+			return
+		}
+	}
+}
+
+func (handler *ExportFeedbackHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *FeedbackExportErrorResponse,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in exporting feedback - json response"
+
+		handler.logger.Error("Error in exporting feedback - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,108 @@
+package feedback
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExportFeedbackHandler_ExportFeedbackController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockFeedbackServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedFrom        time.Time
+		expectedTo          time.Time
+		mockEntriesReturned []*domain.FeedbackEntry
+		expected            string
+		expectedStatusCode  int
+	}{
+		{
+			name:         "valid",
+			query:        "?from=2025-05-01&to=2025-06-01",
+			expectedFrom: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			mockEntriesReturned: []*domain.FeedbackEntry{
+				{
+					MessageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
+					Question:      "how fast are sailboats?",
+					Context: []*domain.RetrievedChunk{
+						{ID: "doc1-chunk-0", Text: "Sailboats are 88% slow than Electric cars.", Score: 0.5},
+					},
+					Answer: "Sailboats are 88% faster than Electric cars.",
+					Feedback: &domain.MessageFeedback{
+						Thumb:           "down",
+						Reason:          "wrong",
+						CorrectedAnswer: "Sailboats are 88% slower than Electric cars.",
+						SubmittedAt:     time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+					},
+					CreatedAt: time.Date(2025, 5, 29, 9, 59, 0, 0, time.UTC),
+				},
+				{
+					MessageID:     uuid.UUID{0x32, 0x34, 0x56, 0x78},
+					ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
+					Question:      "and scooters?",
+					Context:       []*domain.RetrievedChunk{},
+					Answer:        "The force is not strong enough for me to answer that question based on my context.",
+					Feedback: &domain.MessageFeedback{
+						Rating:      4,
+						Comment:     "ok",
+						SubmittedAt: time.Date(2025, 5, 29, 11, 0, 0, 0, time.UTC),
+					},
+					CreatedAt: time.Date(2025, 5, 29, 10, 59, 0, 0, time.UTC),
+				},
+			},
+			expected: `{"id":"12345678-0000-0000-0000-000000000000","chat_session_id":"22345678-0000-0000-0000-000000000000","question":"how fast are sailboats?","context":[{"id":"doc1-chunk-0","text":"Sailboats are 88% slow than Electric cars.","score":0.5}],"answer":"Sailboats are 88% faster than Electric cars.","thumb":"down","reason":"wrong","corrected_answer":"Sailboats are 88% slower than Electric cars.","submitted_at":"2025-05-29T10:00:00Z","created_at":"2025-05-29T09:59:00Z"}
+{"id":"32345678-0000-0000-0000-000000000000","chat_session_id":"22345678-0000-0000-0000-000000000000","question":"and scooters?","context":[],"answer":"The force is not strong enough for me to answer that question based on my context.","rating":4,"feedback":"ok","submitted_at":"2025-05-29T11:00:00Z","created_at":"2025-05-29T10:59:00Z"}
+`,
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/admin/feedback/export"+tt.query, nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().GetFeedbackEntries(
+				gomock.Any(),
+				tt.expectedFrom,
+				tt.expectedTo,
+			).Return(tt.mockEntriesReturned, nil)
+
+			handler := &ExportFeedbackHandler{
+				FeedbackService: mockService,
+				logger:          logger,
+			}
+			sut := handler.ExportFeedbackController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+			assert.Equal(t, "application/x-ndjson", mockResponse.Header.Get("Content-Type"))
+		})
+	}
+}
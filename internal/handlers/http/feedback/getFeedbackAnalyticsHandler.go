@@ -0,0 +1,103 @@
+package feedback
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type GetFeedbackAnalyticsHandler struct {
+	FeedbackService services.FeedbackServiceInterface
+	logger          logger.LoggerInterface
+}
+
+func NewGetFeedbackAnalyticsHandler(
+	service services.FeedbackServiceInterface,
+	logger logger.LoggerInterface,
+) *GetFeedbackAnalyticsHandler {
+	return &GetFeedbackAnalyticsHandler{
+		FeedbackService: service,
+		logger:          logger,
+	}
+}
+
+// @Summary		Gets feedback analytics
+// @Description	Counts the answers and the feedback they received, grouped by day, week or month
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			from		query		string	false	"start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days before to"
+// @Param			to			query		string	false	"end date (RFC 3339 or YYYY-MM-DD), defaults to now"
+// @Param			interval	query		string	false	"day, week or month, defaults to day"
+// @Success		200			{object}	FeedbackAnalyticsResponse
+// @Failure		400			{object}	FeedbackAnalyticsResponse	"Error in query parameters"
+// @Failure		401			{object}	FeedbackAnalyticsResponse	"Authentication error"
+// @Failure		403			{string}	string						"Not an admin or API key without the kb:manage scope"
+// @Failure		500			{object}	FeedbackAnalyticsResponse	"Internal Server Error"
+// @Router			/admin/feedback/analytics [get]
+func (handler *GetFeedbackAnalyticsHandler) GetFeedbackAnalyticsController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &FeedbackAnalyticsResponse{}
+
+	from, to, err := timeRangeFromRequest(r)
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = domain.FeedbackIntervalDay
+	}
+
+	if interval != domain.FeedbackIntervalDay &&
+		interval != domain.FeedbackIntervalWeek &&
+		interval != domain.FeedbackIntervalMonth {
+		response.ErrorMessage = "interval must be one of day, week or month"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	analytics, err := handler.FeedbackService.GetFeedbackAnalytics(ctx, from, to, interval)
+	if err != nil {
+		handler.logger.Error("Error in getting feedback analytics",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting feedback analytics"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = FeedbackAnalyticsResponseFromModel(from, to, interval, analytics)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *GetFeedbackAnalyticsHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *FeedbackAnalyticsResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in getting feedback analytics - json response"
+
+		handler.logger.Error("Error in getting feedback analytics - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,148 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetFeedbackAnalyticsHandler_GetFeedbackAnalyticsController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockFeedbackServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                  string
+		query                 string
+		expectedFrom          time.Time
+		expectedTo            time.Time
+		expectedInterval      string
+		mockAnalyticsReturned []*domain.FeedbackAnalytics
+		expected              []byte
+		expectedStatusCode    int
+	}{
+		{
+			name:             "valid",
+			query:            "?from=2025-05-01&to=2025-06-01T00:00:00Z&interval=week",
+			expectedFrom:     time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedInterval: "week",
+			mockAnalyticsReturned: []*domain.FeedbackAnalytics{
+				{
+					Period:   time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC),
+					Answers:  8,
+					Feedback: 2,
+				},
+			},
+			expected: json.RawMessage(`{"from":"2025-05-01T00:00:00Z","to":"2025-06-01T00:00:00Z","interval":"week","buckets":[{"period":"2025-05-26T00:00:00Z","answers":8,"feedback":2,"feedbackRate":0.25}]}
+`),
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/admin/feedback/analytics"+tt.query, nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().GetFeedbackAnalytics(
+				gomock.Any(),
+				tt.expectedFrom,
+				tt.expectedTo,
+				tt.expectedInterval,
+			).Return(tt.mockAnalyticsReturned, nil)
+
+			handler := &GetFeedbackAnalyticsHandler{
+				FeedbackService: mockService,
+				logger:          logger,
+			}
+			sut := handler.GetFeedbackAnalyticsController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestGetFeedbackAnalyticsHandler_GetFeedbackAnalyticsControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockFeedbackServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		query              string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:  "malformed from",
+			query: "?from=yesterday",
+			expected: json.RawMessage(`{"errorMessage":"malformed from date"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "from after to",
+			query: "?from=2025-06-01&to=2025-05-01",
+			expected: json.RawMessage(`{"errorMessage":"from date must be before to date"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "unknown interval",
+			query: "?interval=year",
+			expected: json.RawMessage(`{"errorMessage":"interval must be one of day, week or month"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/admin/feedback/analytics"+tt.query, nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &GetFeedbackAnalyticsHandler{
+				FeedbackService: mockService,
+				logger:          logger,
+			}
+			sut := handler.GetFeedbackAnalyticsController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type JwksHandler struct {
+	keySet *auth.KeySet
+	logger logger.LoggerInterface
+}
+
+// NewJwksHandler takes a nil key set when tokens are signed with a shared
+// secret, in which case there is nothing to publish
+func NewJwksHandler(keySet *auth.KeySet, logger logger.LoggerInterface) *JwksHandler {
+	return &JwksHandler{
+		keySet: keySet,
+		logger: logger,
+	}
+}
+
+// @Summary		Publishes the JWT verification keys
+// @Description	JSON Web Key Set with the public keys that verify the tokens of this service, selected by the kid header of a token. Empty when tokens are signed with a shared secret.
+// @Produce		json
+// @Success		200	{object}	auth.JWKS
+// @Router			/.well-known/jwks.json [get]
+func (handler *JwksHandler) JwksController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// verifiers may cache the keys, a rotation publishes the new key before
+	// it starts signing
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	jwks := auth.JWKS{Keys: []auth.JWK{}}
+	if handler.keySet != nil {
+		jwks = handler.keySet.JWKS()
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(jwks)
+	if err != nil {
+		handler.logger.Error("Error in publishing jwks - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJwksHandler_JwksController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keySet, err := auth.NewKeySet(map[string]*auth.SigningKey{
+		"ec-1": {ID: "ec-1", PrivateKey: privateKey, PublicKey: privateKey.Public(), Method: jwt.SigningMethodES256},
+	}, "ec-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		keySet           *auth.KeySet
+		expectedKeyCount int
+	}{
+		{
+			name:             "asymmetric keys",
+			keySet:           keySet,
+			expectedKeyCount: 1,
+		},
+		{
+			name:             "shared secret",
+			keySet:           nil,
+			expectedKeyCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := NewJwksHandler(tt.keySet, logger)
+			handler.JwksController(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			body, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			var actual auth.JWKS
+			err = json.Unmarshal(body, &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, 200, mockResponse.StatusCode)
+			assert.NotNil(t, actual.Keys)
+			assert.Len(t, actual.Keys, tt.expectedKeyCount)
+		})
+	}
+}
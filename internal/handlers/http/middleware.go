@@ -1,23 +1,66 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
+// APIKeyHeader carries the API key of requests that are not authenticated with
+// a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// AuthenticationMw accepts the tokens of the local auth mechanism, the tokens
+// of the identity provider when one is configured, and API keys. Any of them
+// can be nil to disable that kind of credential.
 type AuthenticationMw struct {
-	claimsDomain domain.JwtClaimsInterface
+	claimsDomain  domain.JwtClaimsInterface
+	tokenDenylist services.TokenDenylistServiceInterface
+	oidcAuth      services.OIDCAuthServiceInterface
+	apiKeys       services.APIKeyServiceInterface
 }
 type AuthenticationMechanismInterface interface {
 	AuthenticationMW(next http.Handler) http.Handler
 }
 
-func NewAuthenticationMw(claims domain.JwtClaimsInterface) *AuthenticationMw {
-	return &AuthenticationMw{claimsDomain: claims}
+func NewAuthenticationMw(
+	claims domain.JwtClaimsInterface,
+	tokenDenylist services.TokenDenylistServiceInterface,
+	oidcAuth services.OIDCAuthServiceInterface,
+	apiKeys services.APIKeyServiceInterface,
+) *AuthenticationMw {
+	return &AuthenticationMw{
+		claimsDomain:  claims,
+		tokenDenylist: tokenDenylist,
+		oidcAuth:      oidcAuth,
+		apiKeys:       apiKeys,
+	}
 }
 func (a *AuthenticationMw) AuthenticationMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get(APIKeyHeader); key != "" && a.apiKeys != nil {
+			claims, status, err := a.apiKeyClaims(r.Context(), key)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+
+			r = r.WithContext(auth.ContextWithClaims(r.Context(), claims))
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		authHeader := r.Header.Get("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer") {
 			http.Error(w, "Not Authorized", http.StatusUnauthorized)
@@ -26,12 +69,179 @@ func (a *AuthenticationMw) AuthenticationMW(next http.Handler) http.Handler {
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims, err := a.claimsDomain.GetClaimsFromToken(tokenString)
+		claims, status, err := a.claims(r.Context(), tokenString)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if tokenID, ok := claims["jti"].(string); ok {
+			revoked, err := a.tokenDenylist.IsTokenRevoked(r.Context(), tokenID)
+			if err != nil {
+				http.Error(w, "error in checking token revocation", http.StatusInternalServerError)
+				return
+			}
+
+			if revoked {
+				http.Error(w, "token is revoked", http.StatusUnauthorized)
+				return
+			}
+		}
+		r = r.WithContext(auth.ContextWithClaims(r.Context(), claims))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// claims picks the verifier by the unverified issuer of the token; the chosen
+// verifier then checks the issuer along with the signature
+func (a *AuthenticationMw) claims(ctx context.Context, tokenString string) (jwt.MapClaims, int, error) {
+	if a.oidcAuth != nil && auth.UnverifiedIssuer(tokenString) == a.oidcAuth.Issuer() {
+		claims, err := a.oidcAuth.Authenticate(ctx, tokenString)
+
+		var invalidToken *customerrors.InvalidTokenError
+		if errors.As(err, &invalidToken) {
+			return nil, http.StatusUnauthorized, err
+		}
+
+		var usernameTaken *customerrors.UsernameTakenError
+		if errors.As(err, &usernameTaken) {
+			return nil, http.StatusForbidden, err
+		}
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.New("error in authenticating token")
+		}
+
+		return claims, http.StatusOK, nil
+	}
+
+	if a.claimsDomain == nil {
+		return nil, http.StatusUnauthorized, errors.New("local tokens are disabled")
+	}
+
+	claims, err := a.claimsDomain.GetClaimsFromToken(tokenString)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	return claims, http.StatusOK, nil
+}
+
+func (a *AuthenticationMw) apiKeyClaims(ctx context.Context, key string) (jwt.MapClaims, int, error) {
+	apiKey, err := a.apiKeys.Authenticate(ctx, key)
+
+	var invalidAPIKey *customerrors.InvalidAPIKeyError
+	if errors.As(err, &invalidAPIKey) {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.New("error in authenticating api key")
+	}
+
+	return auth.APIKeyClaims(apiKey), http.StatusOK, nil
+}
+
+// RoleMW rejects requests of users without the role, or a higher one. It must
+// run after AuthenticationMW.
+func RoleMW(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userRole, err := auth.RoleFromContext(r.Context())
+			if err != nil {
+				http.Error(w, "Not Authorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !domain.HasRole(userRole, role) {
+				http.Error(w, "the "+role+" role is required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ScopeMW rejects requests authenticated with an API key that was not granted
+// the scope. It must run after AuthenticationMW.
+func ScopeMW(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.HasScope(r.Context(), scope) {
+				http.Error(w, "api key is missing the "+scope+" scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UserTokenMW rejects requests authenticated with an API key, for the
+// endpoints that only the user may call, like the ones managing API keys. It
+// must run after AuthenticationMW.
+func UserTokenMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth.IsAPIKeyFromContext(r.Context()) {
+			http.Error(w, "api keys cannot access this endpoint", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserPathMW rejects requests whose user_id path variable is not the
+// authenticated user, so that a caller cannot act as another user by changing
+// the path. It must run after AuthenticationMW.
+func UserPathMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticatedUserID, err := auth.UserIDFromContext(r.Context())
+		if err != nil {
+			http.Error(w, "Not Authorized", http.StatusUnauthorized)
+			return
+		}
+
+		pathUserID, err := uuid.Parse(mux.Vars(r)["user_id"])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusUnauthorized)
+			http.Error(w, "malformed user uuid", http.StatusBadRequest)
+			return
+		}
+
+		if pathUserID != authenticatedUserID {
+			http.Error(w, "user "+pathUserID.String()+" is not the authenticated user", http.StatusForbidden)
 			return
 		}
-		r = r.WithContext(a.claimsDomain.SetJWTClaimsContext(r.Context(), claims))
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RateLimitMW limits the requests of every API key, and of every user for the
+// requests authenticated with a token, to the limiter's rate. A nil limiter
+// disables the limit. It must run after AuthenticationMW.
+func RateLimitMW(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := auth.RateLimitKeyFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Not Authorized", http.StatusUnauthorized)
+				return
+			}
+
+			allowed, retryAfter := limiter.Allow(key)
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
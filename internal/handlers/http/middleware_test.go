@@ -0,0 +1,628 @@
+package http
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUserPathMW(t *testing.T) {
+	tests := []struct {
+		name               string
+		claims             jwt.MapClaims
+		pathUserID         string
+		expected           string
+		expectedStatusCode int
+	}{
+		{
+			name:               "authenticated user",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			pathUserID:         "12345678-0000-0000-0000-000000000000",
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "another user",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			pathUserID:         "42345678-0000-0000-0000-000000000000",
+			expected:           "user 42345678-0000-0000-0000-000000000000 is not the authenticated user\n",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "malformed path user id",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			pathUserID:         "luke",
+			expected:           "malformed user uuid\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "token subject is not a user id",
+			claims:             jwt.MapClaims{"sub": "luke"},
+			pathUserID:         "12345678-0000-0000-0000-000000000000",
+			expected:           "Not Authorized\n",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "no claims",
+			pathUserID:         "12345678-0000-0000-0000-000000000000",
+			expected:           "Not Authorized\n",
+			expectedStatusCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/users/"+tt.pathUserID+"/chat-sessions", nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": tt.pathUserID})
+			if tt.claims != nil {
+				mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), tt.claims))
+			}
+			mockResponseRecorder := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("next"))
+			})
+
+			UserPathMW(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestAuthenticationMw_AuthenticationMW(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDenylist := mock_services.NewMockTokenDenylistServiceInterface(mockCtrl)
+
+	jwtMechanism := auth.NewAuthMechanism("secret", "HS256", time.Minute)
+	token, err := jwtMechanism.CreateToken("12345678-0000-0000-0000-000000000000", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jwtMechanism.GetClaimsFromToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenID := claims["jti"].(string)
+
+	tests := []struct {
+		name                string
+		mockDenylistRevoked bool
+		mockDenylistError   error
+		expected            string
+		expectedStatusCode  int
+	}{
+		{
+			name:               "valid token",
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:                "revoked token",
+			mockDenylistRevoked: true,
+			expected:            "token is revoked\n",
+			expectedStatusCode:  401,
+		},
+		{
+			name:               "denylist error",
+			mockDenylistError:  errors.New("random error"),
+			expected:           "error in checking token revocation\n",
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/chat-sessions", nil)
+			mockRequest.Header.Set("Authorization", "Bearer "+token)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockDenylist.EXPECT().
+				IsTokenRevoked(gomock.Any(), tokenID).
+				Return(tt.mockDenylistRevoked, tt.mockDenylistError)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("next"))
+			})
+
+			NewAuthenticationMw(jwtMechanism, mockDenylist, nil, nil).AuthenticationMW(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestAuthenticationMw_AuthenticationMWWithOIDC(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDenylist := mock_services.NewMockTokenDenylistServiceInterface(mockCtrl)
+	mockOIDCAuth := mock_services.NewMockOIDCAuthServiceInterface(mockCtrl)
+
+	issuer := "https://issuer.example.com"
+	// the middleware only reads the issuer, the signature is the service's job
+	oidcToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": issuer}).SignedString([]byte("provider"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwtMechanism := auth.NewAuthMechanism("secret", "HS256", time.Minute)
+	localToken, err := jwtMechanism.CreateToken("12345678-0000-0000-0000-000000000000", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		claimsDomain       bool
+		token              string
+		mockOIDCCalled     bool
+		mockOIDCClaims     jwt.MapClaims
+		mockOIDCError      error
+		expected           string
+		expectedStatusCode int
+	}{
+		{
+			name:               "valid provider token",
+			token:              oidcToken,
+			mockOIDCCalled:     true,
+			mockOIDCClaims:     jwt.MapClaims{"iss": issuer, "sub": "12345678-0000-0000-0000-000000000000"},
+			expected:           "12345678-0000-0000-0000-000000000000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid provider token",
+			token:              oidcToken,
+			mockOIDCCalled:     true,
+			mockOIDCError:      customerrors.NewInvalidTokenError("token has an unexpected audience"),
+			expected:           "invalid token: token has an unexpected audience\n",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "provider username is a local account",
+			token:              oidcToken,
+			mockOIDCCalled:     true,
+			mockOIDCError:      customerrors.NewUsernameTakenError("luke"),
+			expected:           "username luke is already taken\n",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "provisioning error",
+			token:              oidcToken,
+			mockOIDCCalled:     true,
+			mockOIDCError:      errors.New("random error"),
+			expected:           "error in authenticating token\n",
+			expectedStatusCode: 500,
+		},
+		{
+			name:               "local token with local tokens disabled",
+			token:              localToken,
+			expected:           "local tokens are disabled\n",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "local token with local tokens enabled",
+			claimsDomain:       true,
+			token:              localToken,
+			expected:           "12345678-0000-0000-0000-000000000000",
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/chat-sessions", nil)
+			mockRequest.Header.Set("Authorization", "Bearer "+tt.token)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockOIDCAuth.EXPECT().Issuer().Return(issuer)
+			if tt.mockOIDCCalled {
+				mockOIDCAuth.EXPECT().
+					Authenticate(gomock.Any(), tt.token).
+					Return(tt.mockOIDCClaims, tt.mockOIDCError)
+			}
+			if tt.expectedStatusCode == 200 {
+				mockDenylist.EXPECT().
+					IsTokenRevoked(gomock.Any(), gomock.Any()).
+					Return(false, nil).
+					AnyTimes()
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ := auth.UserIDFromContext(r.Context())
+				w.Write([]byte(userID.String()))
+			})
+
+			var mw *AuthenticationMw
+			if tt.claimsDomain {
+				mw = NewAuthenticationMw(jwtMechanism, mockDenylist, mockOIDCAuth, nil)
+			} else {
+				mw = NewAuthenticationMw(nil, mockDenylist, mockOIDCAuth, nil)
+			}
+			mw.AuthenticationMW(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestAuthenticationMw_AuthenticationMWWithAPIKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDenylist := mock_services.NewMockTokenDenylistServiceInterface(mockCtrl)
+	mockAPIKeys := mock_services.NewMockAPIKeyServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                string
+		mockServiceResponse *domain.APIKey
+		mockServiceError    error
+		expected            string
+		expectedStatusCode  int
+	}{
+		{
+			name: "valid key",
+			mockServiceResponse: &domain.APIKey{
+				ID:       uuid.UUID{0x32, 0x34, 0x56, 0x78},
+				UserID:   uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				Scopes:   []string{domain.ScopeReadSessions},
+				UserRole: domain.RoleCurator,
+			},
+			expected:           "12345678-0000-0000-0000-000000000000 true curator",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "unknown or revoked key",
+			mockServiceError:   customerrors.NewInvalidAPIKeyError(),
+			expected:           "invalid or revoked api key\n",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "service error",
+			mockServiceError:   errors.New("random error"),
+			expected:           "error in authenticating api key\n",
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/chat-sessions", nil)
+			mockRequest.Header.Set(APIKeyHeader, "rag_abcd1234secret")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockAPIKeys.EXPECT().
+				Authenticate(gomock.Any(), "rag_abcd1234secret").
+				Return(tt.mockServiceResponse, tt.mockServiceError)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ := auth.UserIDFromContext(r.Context())
+				role, _ := auth.RoleFromContext(r.Context())
+				w.Write([]byte(userID.String() + " " + strconv.FormatBool(auth.IsAPIKeyFromContext(r.Context())) + " " + role))
+			})
+
+			NewAuthenticationMw(nil, mockDenylist, nil, mockAPIKeys).AuthenticationMW(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestScopeMW(t *testing.T) {
+	tests := []struct {
+		name               string
+		claims             jwt.MapClaims
+		expected           string
+		expectedStatusCode int
+	}{
+		{
+			name:               "user token",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name: "api key with the scope",
+			claims: jwt.MapClaims{
+				"sub":                  "12345678-0000-0000-0000-000000000000",
+				auth.APIKeyIDClaim:     "32345678-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeReadSessions, domain.ScopeSendMessages},
+			},
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name: "api key without the scope",
+			claims: jwt.MapClaims{
+				"sub":                  "12345678-0000-0000-0000-000000000000",
+				auth.APIKeyIDClaim:     "32345678-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeReadSessions},
+			},
+			expected:           "api key is missing the messages:send scope\n",
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/users/12345678-0000-0000-0000-000000000000/chat-sessions", nil)
+			mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), tt.claims))
+			mockResponseRecorder := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("next"))
+			})
+
+			ScopeMW(domain.ScopeSendMessages)(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestUserTokenMW(t *testing.T) {
+	tests := []struct {
+		name               string
+		claims             jwt.MapClaims
+		expected           string
+		expectedStatusCode int
+	}{
+		{
+			name:               "user token",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name: "api key",
+			claims: jwt.MapClaims{
+				"sub":                  "12345678-0000-0000-0000-000000000000",
+				auth.APIKeyIDClaim:     "32345678-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeManageKB},
+			},
+			expected:           "api keys cannot access this endpoint\n",
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/users/12345678-0000-0000-0000-000000000000/api-keys", nil)
+			mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), tt.claims))
+			mockResponseRecorder := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("next"))
+			})
+
+			UserTokenMW(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestRoleMW(t *testing.T) {
+	tests := []struct {
+		name               string
+		claims             jwt.MapClaims
+		expected           string
+		expectedStatusCode int
+	}{
+		{
+			name:               "required role",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000", "role": "curator"},
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "higher role",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000", "role": "admin"},
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "lower role",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000", "role": "user"},
+			expected:           "the curator role is required\n",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "token without role",
+			claims:             jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+			expected:           "the curator role is required\n",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "no claims",
+			expected:           "Not Authorized\n",
+			expectedStatusCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/admin/curated-answers", nil)
+			if tt.claims != nil {
+				mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), tt.claims))
+			}
+			mockResponseRecorder := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("next"))
+			})
+
+			RoleMW(domain.RoleCurator)(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestRateLimitMW(t *testing.T) {
+	userClaims := jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"}
+	apiKeyClaims := jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000", "api_key_id": "87654321-0000-0000-0000-000000000000"}
+	otherUserClaims := jwt.MapClaims{"sub": "22345678-0000-0000-0000-000000000000"}
+
+	// 1 request per minute and a burst of 2
+	limiter := ratelimit.NewLimiter(1, 2)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("next"))
+	})
+	handler := RateLimitMW(limiter)(next)
+
+	tests := []struct {
+		name               string
+		claims             jwt.MapClaims
+		expected           string
+		expectedStatusCode int
+		expectedRetryAfter string
+	}{
+		{
+			name:               "first request of the burst",
+			claims:             userClaims,
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "second request of the burst",
+			claims:             userClaims,
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "burst used up",
+			claims:             userClaims,
+			expected:           "rate limit exceeded\n",
+			expectedStatusCode: 429,
+			expectedRetryAfter: "60",
+		},
+		{
+			name:               "api key of the user has its own limit",
+			claims:             apiKeyClaims,
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "other user has its own limit",
+			claims:             otherUserClaims,
+			expected:           "next",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "no claims",
+			expected:           "Not Authorized\n",
+			expectedStatusCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/users/12345678-0000-0000-0000-000000000000/chat-sessions/1/messages", nil)
+			if tt.claims != nil {
+				mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), tt.claims))
+			}
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, mockResponse.Header.Get("Retry-After"))
+		})
+	}
+}
+
+func TestRateLimitMW_NilLimiter(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("next"))
+	})
+
+	mockRequest := httptest.NewRequest("GET", "/chat-sessions/1", nil)
+	mockResponseRecorder := httptest.NewRecorder()
+
+	RateLimitMW(nil)(next).ServeHTTP(mockResponseRecorder, mockRequest)
+
+	assert.Equal(t, 200, mockResponseRecorder.Code)
+	assert.Equal(t, "next", mockResponseRecorder.Body.String())
+}
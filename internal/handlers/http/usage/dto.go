@@ -0,0 +1,117 @@
+package usage
+
+import (
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultDailyUsagePeriod   = 30 * 24 * time.Hour
+	defaultMonthlyUsagePeriod = 365 * 24 * time.Hour
+)
+
+type UsageResponse struct {
+	From         string         `json:"from,omitempty"`
+	To           string         `json:"to,omitempty"`
+	Interval     string         `json:"interval,omitempty"`
+	Buckets      []UsageBucket  `json:"buckets,omitempty"`
+	Quota        *QuotaResponse `json:"quota,omitempty"`
+	ErrorMessage string         `json:"errorMessage,omitempty"`
+}
+
+type UsageBucket struct {
+	Period           string `json:"period"`
+	Messages         int64  `json:"messages"`
+	PromptTokens     int64  `json:"promptTokens"`
+	CompletionTokens int64  `json:"completionTokens"`
+	EmbeddingTokens  int64  `json:"embeddingTokens"`
+	TotalTokens      int64  `json:"totalTokens"`
+}
+
+// QuotaResponse is the usage of the current month against the quota. A
+// missing limit means there is no limit.
+type QuotaResponse struct {
+	MonthlyTokens   int64  `json:"monthlyTokens,omitempty"`
+	MonthlyMessages int64  `json:"monthlyMessages,omitempty"`
+	UsedTokens      int64  `json:"usedTokens"`
+	UsedMessages    int64  `json:"usedMessages"`
+	ResetsAt        string `json:"resetsAt"`
+}
+
+func UsageResponseFromModel(
+	from time.Time,
+	to time.Time,
+	interval string,
+	aggregates []*domain.UsageAggregate,
+	quotaStatus *domain.QuotaStatus,
+) *UsageResponse {
+	buckets := make([]UsageBucket, len(aggregates))
+	for i, aggregate := range aggregates {
+		buckets[i] = UsageBucket{
+			Period:           aggregate.Period.Format(time.RFC3339),
+			Messages:         aggregate.Messages,
+			PromptTokens:     aggregate.PromptTokens,
+			CompletionTokens: aggregate.CompletionTokens,
+			EmbeddingTokens:  aggregate.EmbeddingTokens,
+			TotalTokens:      aggregate.TotalTokens(),
+		}
+	}
+
+	return &UsageResponse{
+		From:     from.Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+		Interval: interval,
+		Buckets:  buckets,
+		Quota: &QuotaResponse{
+			MonthlyTokens:   quotaStatus.Quota.MonthlyTokens,
+			MonthlyMessages: quotaStatus.Quota.MonthlyMessages,
+			UsedTokens:      quotaStatus.UsedTokens,
+			UsedMessages:    quotaStatus.UsedMessages,
+			ResetsAt:        quotaStatus.ResetsAt.Format(time.RFC3339),
+		},
+	}
+}
+
+// timeRangeFromRequest reads the optional "from" and "to" query parameters as
+// RFC 3339 timestamps or YYYY-MM-DD dates. "to" defaults to now and "from" to
+// 30 days before "to" for daily usage, and a year before it for monthly usage.
+func timeRangeFromRequest(r *http.Request, interval string) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("malformed to date")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultDailyUsagePeriod)
+	if interval == domain.UsageIntervalMonth {
+		from = to.Add(-defaultMonthlyUsagePeriod)
+	}
+
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("malformed from date")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from date must be before to date")
+	}
+
+	return from, to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,126 @@
+package usage
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type GetUsageHandler struct {
+	UsageService services.UsageServiceInterface
+	logger       logger.LoggerInterface
+}
+
+func NewGetUsageHandler(
+	service services.UsageServiceInterface,
+	logger logger.LoggerInterface,
+) *GetUsageHandler {
+	return &GetUsageHandler{
+		UsageService: service,
+		logger:       logger,
+	}
+}
+
+// @Summary		Gets the token usage of a user
+// @Description	Sums the OpenAI tokens spent on answering the messages of the user per day or month, and reports the usage of the current month against the monthly quota
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			user_id		path		string	true	"user id"
+// @Param			from		query		string	false	"start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days (day) or a year (month) before to"
+// @Param			to			query		string	false	"end date (RFC 3339 or YYYY-MM-DD), defaults to now"
+// @Param			interval	query		string	false	"day or month, defaults to day"
+// @Success		200			{object}	UsageResponse
+// @Failure		400			{object}	UsageResponse	"Error in path or query parameters"
+// @Failure		401			{object}	UsageResponse	"Authentication error"
+// @Failure		403			{string}	string			"Not the authenticated user or API key without the sessions:read scope"
+// @Failure		500			{object}	UsageResponse	"Internal Server Error"
+// @Router			/users/{user_id}/usage [get]
+func (handler *GetUsageHandler) GetUsageController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &UsageResponse{}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed user uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = domain.UsageIntervalDay
+	}
+
+	if interval != domain.UsageIntervalDay && interval != domain.UsageIntervalMonth {
+		response.ErrorMessage = "interval must be one of day or month"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	from, to, err := timeRangeFromRequest(r, interval)
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	aggregates, err := handler.UsageService.GetUserUsage(ctx, userID, from, to, interval)
+	if err != nil {
+		handler.logger.Error("Error in getting usage",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting usage"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	quotaStatus, err := handler.UsageService.GetQuotaStatus(ctx, userID)
+	if err != nil {
+		handler.logger.Error("Error in getting usage",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in getting usage"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = UsageResponseFromModel(from, to, interval, aggregates, quotaStatus)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *GetUsageHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *UsageResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in getting usage - json response"
+
+		handler.logger.Error("Error in getting usage - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,217 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetUsageHandler_GetUsageController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUsageServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                   string
+		query                  string
+		expectedFrom           time.Time
+		expectedTo             time.Time
+		expectedInterval       string
+		mockAggregatesReturned []*domain.UsageAggregate
+		mockQuotaReturned      *domain.QuotaStatus
+		expected               []byte
+		expectedStatusCode     int
+	}{
+		{
+			name:             "daily",
+			query:            "?from=2025-05-01&to=2025-06-01T00:00:00Z",
+			expectedFrom:     time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedInterval: "day",
+			mockAggregatesReturned: []*domain.UsageAggregate{
+				{
+					Period:           time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC),
+					Messages:         2,
+					PromptTokens:     2400,
+					CompletionTokens: 300,
+					EmbeddingTokens:  24,
+				},
+			},
+			mockQuotaReturned: &domain.QuotaStatus{
+				Quota:        domain.UsageQuota{MonthlyTokens: 100000},
+				UsedTokens:   2724,
+				UsedMessages: 2,
+				ResetsAt:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expected: json.RawMessage(`{"from":"2025-05-01T00:00:00Z","to":"2025-06-01T00:00:00Z","interval":"day","buckets":[{"period":"2025-05-29T00:00:00Z","messages":2,"promptTokens":2400,"completionTokens":300,"embeddingTokens":24,"totalTokens":2724}],"quota":{"monthlyTokens":100000,"usedTokens":2724,"usedMessages":2,"resetsAt":"2025-06-01T00:00:00Z"}}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:                   "monthly without quota",
+			query:                  "?from=2025-01-01&to=2025-06-01&interval=month",
+			expectedFrom:           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:             time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedInterval:       "month",
+			mockAggregatesReturned: []*domain.UsageAggregate{},
+			mockQuotaReturned: &domain.QuotaStatus{
+				ResetsAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expected: json.RawMessage(`{"from":"2025-01-01T00:00:00Z","to":"2025-06-01T00:00:00Z","interval":"month","quota":{"usedTokens":0,"usedMessages":0,"resetsAt":"2025-06-01T00:00:00Z"}}
+`),
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/users/"+userID.String()+"/usage"+tt.query, nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": userID.String()})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().GetUserUsage(
+				gomock.Any(),
+				userID,
+				tt.expectedFrom,
+				tt.expectedTo,
+				tt.expectedInterval,
+			).Return(tt.mockAggregatesReturned, nil)
+			mockService.EXPECT().GetQuotaStatus(gomock.Any(), userID).Return(tt.mockQuotaReturned, nil)
+
+			handler := &GetUsageHandler{
+				UsageService: mockService,
+				logger:       logger,
+			}
+			sut := handler.GetUsageController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestGetUsageHandler_GetUsageControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUsageServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		userID             string
+		query              string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:   "malformed user id",
+			userID: "luke",
+			expected: json.RawMessage(`{"errorMessage":"malformed user uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:   "unknown interval",
+			userID: "12345678-0000-0000-0000-000000000000",
+			query:  "?interval=week",
+			expected: json.RawMessage(`{"errorMessage":"interval must be one of day or month"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:   "from after to",
+			userID: "12345678-0000-0000-0000-000000000000",
+			query:  "?from=2025-06-01&to=2025-05-01",
+			expected: json.RawMessage(`{"errorMessage":"from date must be before to date"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/users/"+tt.userID+"/usage"+tt.query, nil)
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": tt.userID})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &GetUsageHandler{
+				UsageService: mockService,
+				logger:       logger,
+			}
+			sut := handler.GetUsageController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, mockResponse.StatusCode)
+		})
+	}
+}
+
+func TestGetUsageHandler_GetUsageControllerHasInternalServerError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUsageServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	mockRequest := httptest.NewRequest("GET", "/users/"+userID.String()+"/usage", nil)
+	mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": userID.String()})
+	mockResponseRecorder := httptest.NewRecorder()
+
+	mockService.EXPECT().GetUserUsage(gomock.Any(), userID, gomock.Any(), gomock.Any(), "day").
+		Return(nil, errors.New("random error"))
+
+	handler := &GetUsageHandler{
+		UsageService: mockService,
+		logger:       logger,
+	}
+
+	handler.GetUsageController(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"error in getting usage"}
+`, string(actual))
+	assert.Equal(t, 500, mockResponse.StatusCode)
+}
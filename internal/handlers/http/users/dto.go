@@ -0,0 +1,91 @@
+package users
+
+import (
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+const (
+	passwordMinLength = 8
+	// bcrypt ignores everything after the 72nd byte
+	passwordMaxLength = 72
+)
+
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (request *CredentialsRequest) Validate() error {
+	if request.Username == "" || request.Password == "" {
+		return errors.New("empty username or password")
+	}
+
+	if len(request.Password) < passwordMinLength || len(request.Password) > passwordMaxLength {
+		return errors.New("password must be between 8 and 72 characters")
+	}
+
+	return nil
+}
+
+type UserResponse struct {
+	ID           string `json:"id,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Role         string `json:"role,omitempty" enums:"user,curator,admin"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+func UserResponseFromModel(user *domain.User) *UserResponse {
+	return &UserResponse{
+		ID:       user.ID.String(),
+		Username: user.Username,
+		Role:     user.Role,
+	}
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" enums:"user,curator,admin"`
+}
+
+func (request *UpdateUserRoleRequest) Validate() error {
+	if !domain.IsRole(request.Role) {
+		return errors.New("role must be one of user, curator, admin")
+	}
+
+	return nil
+}
+
+type UpdateUserRoleResponse struct {
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type LogoutRequest struct {
+	// RefreshToken is revoked together with the access token, if given
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type TokenResponse struct {
+	Token                 string `json:"token,omitempty"`
+	ExpiresAt             string `json:"expiresAt,omitempty"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	RefreshTokenExpiresAt string `json:"refreshTokenExpiresAt,omitempty"`
+	ErrorMessage          string `json:"errorMessage,omitempty"`
+}
+
+func TokenResponseFromModel(tokenPair *domain.TokenPair) *TokenResponse {
+	return &TokenResponse{
+		Token:                 tokenPair.AccessToken,
+		ExpiresAt:             tokenPair.AccessTokenExpiresAt.Format(time.RFC3339),
+		RefreshToken:          tokenPair.RefreshToken,
+		RefreshTokenExpiresAt: tokenPair.RefreshTokenExpiresAt.Format(time.RFC3339),
+	}
+}
+
+type LogoutResponse struct {
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
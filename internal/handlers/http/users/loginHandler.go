@@ -0,0 +1,107 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type LoginHandler struct {
+	UserService services.UserServiceInterface
+	logger      logger.LoggerInterface
+}
+
+func NewLoginHandler(
+	service services.UserServiceInterface,
+	logger logger.LoggerInterface,
+) *LoginHandler {
+	return &LoginHandler{
+		UserService: service,
+		logger:      logger,
+	}
+}
+
+// @Summary		Logs a user in
+// @Description	Verifies the credentials against the stored bcrypt hash and returns a short-lived JWT whose subject is the user id, and a refresh token. Also served on /token.
+// @Param			request	body		CredentialsRequest	true	"username and password"
+// @Success		200		{object}	TokenResponse
+// @Failure		400		{object}	TokenResponse	"Error in request body"
+// @Failure		401		{object}	TokenResponse	"Invalid username or password"
+// @Failure		500		{object}	TokenResponse	"Internal Server Error"
+// @Router			/login [post]
+func (handler *LoginHandler) LoginController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &TokenResponse{}
+	request := &CredentialsRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in logging in",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed auth request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	if request.Username == "" || request.Password == "" {
+		response.ErrorMessage = "empty username or password"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	tokenPair, err := handler.UserService.Login(ctx, request.Username, request.Password)
+
+	var invalidCredentialsError *customerrors.InvalidCredentialsError
+	if errors.As(err, &invalidCredentialsError) {
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in logging in",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error during creation of the token"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = TokenResponseFromModel(tokenPair)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *LoginHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *TokenResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in logging in - json response"
+
+		handler.logger.Error("Error in logging in - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,153 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoginHandler_LoginController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                string
+		mockServiceResponse *domain.TokenPair
+		mockServiceError    error
+		expected            []byte
+		expectedStatusCode  int
+	}{
+		{
+			name:                "valid",
+			mockServiceResponse: &domain.TokenPair{
+				AccessToken:           "header.payload.signature",
+				AccessTokenExpiresAt:  time.Date(2025, 5, 29, 10, 15, 0, 0, time.UTC),
+				RefreshToken:          "refresh-token",
+				RefreshTokenExpiresAt: time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC),
+			},
+			expected: json.RawMessage(`{"token":"header.payload.signature","expiresAt":"2025-05-29T10:15:00Z","refreshToken":"refresh-token","refreshTokenExpiresAt":"2025-06-28T10:00:00Z"}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:             "invalid credentials",
+			mockServiceError: customerrors.NewInvalidCredentialsError(),
+			expected: json.RawMessage(`{"errorMessage":"invalid username or password"}
+`),
+			expectedStatusCode: 401,
+		},
+		{
+			name:             "service error",
+			mockServiceError: errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error during creation of the token"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest(
+				"POST",
+				"/login",
+				bytes.NewBuffer(json.RawMessage(`{"username":"obi-wan","password":"hellothere"}`)),
+			)
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().
+				Login(gomock.Any(), "obi-wan", "hellothere").
+				Return(tt.mockServiceResponse, tt.mockServiceError)
+
+			handler := &LoginHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.LoginController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestLoginHandler_LoginControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		body               string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name: "empty username",
+			body: `{"password":"hellothere"}`,
+			expected: json.RawMessage(`{"errorMessage":"empty username or password"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "malformed body",
+			body: `{"username":`,
+			expected: json.RawMessage(`{"errorMessage":"malformed auth request"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/login", bytes.NewBuffer(json.RawMessage(tt.body)))
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &LoginHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.LoginController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
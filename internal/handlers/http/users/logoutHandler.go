@@ -0,0 +1,120 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"io"
+	"net/http"
+)
+
+type LogoutHandler struct {
+	UserService services.UserServiceInterface
+	logger      logger.LoggerInterface
+}
+
+func NewLogoutHandler(
+	service services.UserServiceInterface,
+	logger logger.LoggerInterface,
+) *LogoutHandler {
+	return &LogoutHandler{
+		UserService: service,
+		logger:      logger,
+	}
+}
+
+// @Summary		Logs a user out
+// @Description	Revokes the access token of the request and, if given, the refresh token
+// @Security		BearerAuth
+// @Param			request	body	LogoutRequest	false	"refresh token to revoke"
+// @Success		204
+// @Failure		400	{object}	LogoutResponse	"Error in request body"
+// @Failure		401	{object}	LogoutResponse	"Authentication error or refresh token of another user"
+// @Failure		500	{object}	LogoutResponse	"Internal Server Error"
+// @Router			/logout [post]
+func (handler *LogoutHandler) LogoutController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &LogoutResponse{}
+	request := &LogoutRequest{}
+
+	// the body is optional, logging out without it only revokes the access token
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		handler.logger.Error("Error in logging out",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed logout request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		response.ErrorMessage = "Not Authorized"
+
+		handler.JsonResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	tokenID, expiresAt, err := auth.TokenIDFromContext(ctx)
+	if err != nil {
+		response.ErrorMessage = "Not Authorized"
+
+		handler.JsonResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	err = handler.UserService.Logout(ctx, userID, request.RefreshToken, tokenID, expiresAt)
+
+	var invalidRefreshTokenError *customerrors.InvalidRefreshTokenError
+	if errors.As(err, &invalidRefreshTokenError) {
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in logging out",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in logging out"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *LogoutHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *LogoutResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in logging out - json response"
+
+		handler.logger.Error("Error in logging out - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,148 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLogoutHandler_LogoutController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	claims := jwt.MapClaims{
+		"sub": "12345678-0000-0000-0000-000000000000",
+		"jti": "a1b2c3",
+		"exp": float64(1748513700),
+	}
+
+	tests := []struct {
+		name                 string
+		body                 string
+		expectedRefreshToken string
+		mockServiceError     error
+		expected             []byte
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "with refresh token",
+			body:                 `{"refreshToken":"refresh-token"}`,
+			expectedRefreshToken: "refresh-token",
+			expected:             []byte{},
+			expectedStatusCode:   204,
+		},
+		{
+			name:                 "without body",
+			body:                 ``,
+			expectedRefreshToken: "",
+			expected:             []byte{},
+			expectedStatusCode:   204,
+		},
+		{
+			name:                 "refresh token of another user",
+			body:                 `{"refreshToken":"refresh-token"}`,
+			expectedRefreshToken: "refresh-token",
+			mockServiceError:     customerrors.NewInvalidRefreshTokenError(),
+			expected: json.RawMessage(`{"errorMessage":"invalid or expired refresh token"}
+`),
+			expectedStatusCode: 401,
+		},
+		{
+			name:                 "service error",
+			body:                 ``,
+			expectedRefreshToken: "",
+			mockServiceError:     errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in logging out"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/logout", bytes.NewBuffer([]byte(tt.body)))
+			mockRequest = mockRequest.WithContext(auth.ContextWithClaims(mockRequest.Context(), claims))
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().
+				Logout(
+					gomock.Any(),
+					uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					tt.expectedRefreshToken,
+					"a1b2c3",
+					time.Unix(1748513700, 0),
+				).
+				Return(tt.mockServiceError)
+
+			handler := &LogoutHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.LogoutController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestLogoutHandler_LogoutControllerHasAuthenticationError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest("POST", "/logout", nil)
+	mockRequest = mockRequest.WithContext(auth.ContextWithClaims(
+		mockRequest.Context(),
+		jwt.MapClaims{"sub": "12345678-0000-0000-0000-000000000000"},
+	))
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &LogoutHandler{
+		UserService: mockService,
+		logger:      logger,
+	}
+	sut := handler.LogoutController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"Not Authorized"}
+`, string(actual))
+	assert.Equal(t, 401, mockResponse.StatusCode)
+}
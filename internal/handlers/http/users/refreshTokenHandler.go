@@ -0,0 +1,107 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type RefreshTokenHandler struct {
+	UserService services.UserServiceInterface
+	logger      logger.LoggerInterface
+}
+
+func NewRefreshTokenHandler(
+	service services.UserServiceInterface,
+	logger logger.LoggerInterface,
+) *RefreshTokenHandler {
+	return &RefreshTokenHandler{
+		UserService: service,
+		logger:      logger,
+	}
+}
+
+// @Summary		Refreshes the tokens
+// @Description	Exchanges a refresh token for a new access token and a new refresh token. The given refresh token is revoked, and reusing a revoked one revokes all refresh tokens of the user.
+// @Param			request	body		RefreshTokenRequest	true	"refresh token"
+// @Success		200		{object}	TokenResponse
+// @Failure		400		{object}	TokenResponse	"Error in request body"
+// @Failure		401		{object}	TokenResponse	"Invalid, revoked or expired refresh token"
+// @Failure		500		{object}	TokenResponse	"Internal Server Error"
+// @Router			/token/refresh [post]
+func (handler *RefreshTokenHandler) RefreshTokenController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &TokenResponse{}
+	request := &RefreshTokenRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in refreshing token",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed refresh token request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	if request.RefreshToken == "" {
+		response.ErrorMessage = "empty refresh token"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	tokenPair, err := handler.UserService.Refresh(ctx, request.RefreshToken)
+
+	var invalidRefreshTokenError *customerrors.InvalidRefreshTokenError
+	if errors.As(err, &invalidRefreshTokenError) {
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusUnauthorized, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in refreshing token",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in refreshing token"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = TokenResponseFromModel(tokenPair)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *RefreshTokenHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *TokenResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in refreshing token - json response"
+
+		handler.logger.Error("Error in refreshing token - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
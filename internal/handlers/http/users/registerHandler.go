@@ -0,0 +1,108 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type RegisterHandler struct {
+	UserService services.UserServiceInterface
+	logger      logger.LoggerInterface
+}
+
+func NewRegisterHandler(
+	service services.UserServiceInterface,
+	logger logger.LoggerInterface,
+) *RegisterHandler {
+	return &RegisterHandler{
+		UserService: service,
+		logger:      logger,
+	}
+}
+
+// @Summary		Registers a user
+// @Description	Creates a user account, the password is stored as a bcrypt hash
+// @Param			request	body		CredentialsRequest	true	"username and password (8 to 72 characters)"
+// @Success		201		{object}	UserResponse
+// @Failure		400		{object}	UserResponse	"Error in request body"
+// @Failure		409		{object}	UserResponse	"Username already taken"
+// @Failure		500		{object}	UserResponse	"Internal Server Error"
+// @Router			/register [post]
+func (handler *RegisterHandler) RegisterController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &UserResponse{}
+	request := &CredentialsRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in registering user",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed registration request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	user, err := handler.UserService.Register(ctx, request.Username, request.Password)
+
+	var usernameTakenError *customerrors.UsernameTakenError
+	if errors.As(err, &usernameTakenError) {
+		response.ErrorMessage = err.Error()
+		handler.JsonResponse(w, http.StatusConflict, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in registering user",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in registering user"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = UserResponseFromModel(user)
+	handler.JsonResponse(w, http.StatusCreated, response)
+}
+
+func (handler *RegisterHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *UserResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in registering user - json response"
+
+		handler.logger.Error("Error in registering user - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,159 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterHandler_RegisterController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name                string
+		body                string
+		mockServiceResponse *domain.User
+		mockServiceError    error
+		expected            []byte
+		expectedStatusCode  int
+	}{
+		{
+			name: "valid",
+			body: `{"username":"obi-wan","password":"hellothere"}`,
+			mockServiceResponse: &domain.User{
+				ID:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				Username: "obi-wan",
+				Password: "$2a$10$hashedpassword",
+			},
+			expected: json.RawMessage(`{"id":"12345678-0000-0000-0000-000000000000","username":"obi-wan"}
+`),
+			expectedStatusCode: 201,
+		},
+		{
+			name:             "username taken",
+			body:             `{"username":"obi-wan","password":"hellothere"}`,
+			mockServiceError: customerrors.NewUsernameTakenError("obi-wan"),
+			expected: json.RawMessage(`{"errorMessage":"username obi-wan is already taken"}
+`),
+			expectedStatusCode: 409,
+		},
+		{
+			name:             "service error",
+			body:             `{"username":"obi-wan","password":"hellothere"}`,
+			mockServiceError: errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in registering user"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/register", bytes.NewBuffer(json.RawMessage(tt.body)))
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().
+				Register(gomock.Any(), "obi-wan", "hellothere").
+				Return(tt.mockServiceResponse, tt.mockServiceError)
+
+			handler := &RegisterHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.RegisterController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestRegisterHandler_RegisterControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		body               string
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name: "empty password",
+			body: `{"username":"obi-wan"}`,
+			expected: json.RawMessage(`{"errorMessage":"empty username or password"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "short password",
+			body: `{"username":"obi-wan","password":"hello"}`,
+			expected: json.RawMessage(`{"errorMessage":"password must be between 8 and 72 characters"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name: "malformed body",
+			body: `{"username":42}`,
+			expected: json.RawMessage(`{"errorMessage":"malformed registration request"}
+`),
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("POST", "/register", bytes.NewBuffer(json.RawMessage(tt.body)))
+			mockRequest.Header.Set("Content-Type", "application/json")
+			mockResponseRecorder := httptest.NewRecorder()
+
+			handler := &RegisterHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.RegisterController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
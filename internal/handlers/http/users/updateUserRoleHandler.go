@@ -0,0 +1,131 @@
+package users
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+)
+
+type UpdateUserRoleHandler struct {
+	UserService services.UserServiceInterface
+	logger      logger.LoggerInterface
+}
+
+func NewUpdateUserRoleHandler(
+	service services.UserServiceInterface,
+	logger logger.LoggerInterface,
+) *UpdateUserRoleHandler {
+	return &UpdateUserRoleHandler{
+		UserService: service,
+		logger:      logger,
+	}
+}
+
+// @Summary		Changes the role of a user
+// @Description	Sets the role of a user. Tokens carry the role, so it applies to the tokens the user gets from then on. Admins only.
+// @Security		BearerAuth
+// @Param			user_id	path	string					true	"user id"
+// @Param			request	body	UpdateUserRoleRequest	true	"new role"
+// @Success		204
+// @Failure		400	{object}	UpdateUserRoleResponse	"Error in request"
+// @Failure		401	{object}	UpdateUserRoleResponse	"Authentication error"
+// @Failure		403	{object}	UpdateUserRoleResponse	"Not an admin or authenticated with an API key"
+// @Failure		404	{object}	UpdateUserRoleResponse	"User not found"
+// @Failure		500	{object}	UpdateUserRoleResponse	"Internal Server Error"
+// @Router			/admin/users/{user_id}/role [put]
+func (handler *UpdateUserRoleHandler) UpdateUserRoleController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &UpdateUserRoleResponse{}
+	request := &UpdateUserRoleRequest{}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		response.ErrorMessage = "malformed user uuid"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Error("Error in updating user role",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "malformed role request"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		response.ErrorMessage = err.Error()
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	err = handler.UserService.UpdateUserRole(ctx, userID, request.Role)
+
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		handler.logger.Error("Error in updating user role",
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		response.ErrorMessage = resourceNotFound.Unwrap().Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in updating user role",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in updating user role"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	handler.logger.Info("Updated user role",
+		map[string]interface{}{
+			"userID": userID.String(),
+			"role":   request.Role,
+		})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *UpdateUserRoleHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *UpdateUserRoleResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in updating user role - json response"
+
+		handler.logger.Error("Error in updating user role - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
@@ -0,0 +1,118 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateUserRoleHandler_UpdateUserRoleController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockUserServiceInterface(mockCtrl)
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name               string
+		userID             string
+		body               string
+		mockServiceCalled  bool
+		mockServiceError   error
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:               "valid",
+			userID:             userID.String(),
+			body:               `{"role":"curator"}`,
+			mockServiceCalled:  true,
+			expected:           []byte{},
+			expectedStatusCode: 204,
+		},
+		{
+			name:   "malformed user id",
+			userID: "luke",
+			body:   `{"role":"curator"}`,
+			expected: json.RawMessage(`{"errorMessage":"malformed user uuid"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:   "unknown role",
+			userID: userID.String(),
+			body:   `{"role":"jedi"}`,
+			expected: json.RawMessage(`{"errorMessage":"role must be one of user, curator, admin"}
+`),
+			expectedStatusCode: 400,
+		},
+		{
+			name:              "unknown user",
+			userID:            userID.String(),
+			body:              `{"role":"curator"}`,
+			mockServiceCalled: true,
+			mockServiceError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("userID 12345678-0000-0000-0000-000000000000 not found"),
+			},
+			expected: json.RawMessage(`{"errorMessage":"userID 12345678-0000-0000-0000-000000000000 not found"}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:              "service error",
+			userID:            userID.String(),
+			body:              `{"role":"curator"}`,
+			mockServiceCalled: true,
+			mockServiceError:  errors.New("random error"),
+			expected: json.RawMessage(`{"errorMessage":"error in updating user role"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("PUT", "/admin/users/"+tt.userID+"/role", bytes.NewBuffer([]byte(tt.body)))
+			mockRequest = mux.SetURLVars(mockRequest, map[string]string{"user_id": tt.userID})
+			mockResponseRecorder := httptest.NewRecorder()
+
+			if tt.mockServiceCalled {
+				mockService.EXPECT().
+					UpdateUserRole(gomock.Any(), userID, "curator").
+					Return(tt.mockServiceError)
+			}
+
+			handler := &UpdateUserRoleHandler{
+				UserService: mockService,
+				logger:      logger,
+			}
+			sut := handler.UpdateUserRoleController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
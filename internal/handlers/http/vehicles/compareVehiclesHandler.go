@@ -0,0 +1,103 @@
+package vehicles
+
+import (
+	"encoding/json"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"net/http"
+	"strings"
+)
+
+type CompareVehiclesHandler struct {
+	VehicleComparisonService services.VehicleComparisonServiceInterface
+	logger                   logger.LoggerInterface
+}
+
+func NewCompareVehiclesHandler(
+	service services.VehicleComparisonServiceInterface,
+	logger logger.LoggerInterface,
+) *CompareVehiclesHandler {
+	return &CompareVehiclesHandler{
+		VehicleComparisonService: service,
+		logger:                   logger,
+	}
+}
+
+// @Summary		Compares vehicles
+// @Description	Answers comparisons of the vehicles document exactly, from the statements parsed at ingestion. Every matching statement is returned, restated from the side of the subject: ratio is the attribute of the subject over the one of the object and percent how much more, or less when negative, it is
+// @Security		BearerAuth
+// @Security		ApiKeyAuth
+// @Param			subject		query		string	true	"Vehicle to compare, like Sailboats"
+// @Param			attribute	query		string	false	"Attribute to compare by, like slow"
+// @Param			object		query		string	false	"Vehicle to compare with, like Electric cars"
+// @Success		200			{object}	VehicleComparisonsResponse
+// @Failure		400			{object}	VehicleComparisonsResponse	"Error in query parameters"
+// @Failure		401			{object}	VehicleComparisonsResponse	"Authentication error"
+// @Failure		403			{string}	string						"API key without the messages:send scope"
+// @Failure		404			{object}	VehicleComparisonsResponse	"No matching comparisons"
+// @Failure		500			{object}	VehicleComparisonsResponse	"Internal Server Error"
+// @Router			/vehicles/comparisons [get]
+func (handler *CompareVehiclesHandler) CompareVehiclesController(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+
+	response := &VehicleComparisonsResponse{}
+
+	query := r.URL.Query()
+	filter := &domain.VehicleComparisonFilter{
+		Subject:   strings.TrimSpace(query.Get("subject")),
+		Attribute: strings.TrimSpace(query.Get("attribute")),
+		Object:    strings.TrimSpace(query.Get("object")),
+	}
+	if filter.Subject == "" {
+		response.ErrorMessage = "missing subject"
+
+		handler.JsonResponse(w, http.StatusBadRequest, response)
+
+		return
+	}
+
+	facts, err := handler.VehicleComparisonService.CompareVehicles(ctx, filter)
+	if notFoundError, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		response.ErrorMessage = notFoundError.Unwrap().Error()
+		handler.JsonResponse(w, http.StatusNotFound, response)
+
+		return
+	}
+
+	if err != nil {
+		handler.logger.Error("Error in comparing vehicles",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		response.ErrorMessage = "error in comparing vehicles"
+		handler.JsonResponse(w, http.StatusInternalServerError, response)
+
+		return
+	}
+
+	response = VehicleComparisonsResponseFromModel(facts)
+	handler.JsonResponse(w, http.StatusOK, response)
+}
+
+func (handler *CompareVehiclesHandler) JsonResponse(
+	w http.ResponseWriter,
+	statusCode int,
+	response *VehicleComparisonsResponse,
+) {
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response.ErrorMessage = "error in comparing vehicles - json response"
+
+		handler.logger.Error("Error in comparing vehicles - json response",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+	}
+}
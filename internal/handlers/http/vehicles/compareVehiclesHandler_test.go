@@ -0,0 +1,136 @@
+package vehicles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVehiclesHandler_CompareVehiclesController(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockVehicleComparisonServiceInterface(mockCtrl)
+
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilter     *domain.VehicleComparisonFilter
+		mockFactsReturned  []*domain.VehicleComparisonFact
+		mockErrorReturned  error
+		expected           []byte
+		expectedStatusCode int
+	}{
+		{
+			name:           "valid",
+			query:          "?subject=Electric+cars&attribute=slow&object=Sailboats",
+			expectedFilter: &domain.VehicleComparisonFilter{Subject: "Electric cars", Attribute: "slow", Object: "Sailboats"},
+			mockFactsReturned: []*domain.VehicleComparisonFact{
+				{
+					Subject:   "Electric cars",
+					Attribute: "slow",
+					Object:    "Sailboats",
+					Ratio:     0.5319,
+					Percent:   -46.81,
+					Inverse:   true,
+					Statement: "Sailboats are 88% slow than Electric cars.",
+					Line:      3,
+				},
+			},
+			expected: json.RawMessage(`{"comparisons":[{"subject":"Electric cars","attribute":"slow","object":"Sailboats","ratio":0.5319,"percent":-46.81,"inverse":true,"statement":"Sailboats are 88% slow than Electric cars.","line":3}]}
+`),
+			expectedStatusCode: 200,
+		},
+		{
+			name:           "not found",
+			query:          "?subject=Bicycles",
+			expectedFilter: &domain.VehicleComparisonFilter{Subject: "Bicycles"},
+			mockErrorReturned: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("no comparisons of Bicycles found"),
+			},
+			expected: json.RawMessage(`{"errorMessage":"no comparisons of Bicycles found"}
+`),
+			expectedStatusCode: 404,
+		},
+		{
+			name:              "service error",
+			query:             "?subject=Sailboats",
+			expectedFilter:    &domain.VehicleComparisonFilter{Subject: "Sailboats"},
+			mockErrorReturned: errors.New("connection refused"),
+			expected: json.RawMessage(`{"errorMessage":"error in comparing vehicles"}
+`),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequest := httptest.NewRequest("GET", "/vehicles/comparisons"+tt.query, nil)
+			mockResponseRecorder := httptest.NewRecorder()
+
+			mockService.EXPECT().CompareVehicles(
+				gomock.Any(),
+				tt.expectedFilter,
+			).Return(tt.mockFactsReturned, tt.mockErrorReturned)
+
+			handler := &CompareVehiclesHandler{
+				VehicleComparisonService: mockService,
+				logger:                   logger,
+			}
+			sut := handler.CompareVehiclesController
+
+			sut(mockResponseRecorder, mockRequest)
+
+			mockResponse := mockResponseRecorder.Result()
+			actual, err := io.ReadAll(mockResponse.Body)
+			if err != nil {
+				t.Errorf("error with response reading: %v", err)
+				return
+			}
+			actualStatusCode := mockResponse.StatusCode
+
+			assert.Equal(t, string(tt.expected), string(actual))
+			assert.Equal(t, tt.expectedStatusCode, actualStatusCode)
+		})
+	}
+}
+
+func TestCompareVehiclesHandler_CompareVehiclesControllerHasBadRequestError(t *testing.T) {
+	logger := logger.NewLogger(context.Background())
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_services.NewMockVehicleComparisonServiceInterface(mockCtrl)
+
+	mockRequest := httptest.NewRequest("GET", "/vehicles/comparisons?attribute=slow", nil)
+	mockResponseRecorder := httptest.NewRecorder()
+
+	handler := &CompareVehiclesHandler{
+		VehicleComparisonService: mockService,
+		logger:                   logger,
+	}
+	sut := handler.CompareVehiclesController
+
+	sut(mockResponseRecorder, mockRequest)
+
+	mockResponse := mockResponseRecorder.Result()
+	actual, err := io.ReadAll(mockResponse.Body)
+	if err != nil {
+		t.Errorf("error with response reading: %v", err)
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"missing subject"}
+`, string(actual))
+	assert.Equal(t, 400, mockResponse.StatusCode)
+}
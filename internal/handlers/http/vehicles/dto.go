@@ -0,0 +1,41 @@
+package vehicles
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type VehicleComparisonsResponse struct {
+	Comparisons  []VehicleComparisonResponse `json:"comparisons,omitempty"`
+	ErrorMessage string                      `json:"errorMessage,omitempty"`
+}
+
+type VehicleComparisonResponse struct {
+	Subject   string  `json:"subject"`
+	Attribute string  `json:"attribute"`
+	Object    string  `json:"object"`
+	Ratio     float64 `json:"ratio"`
+	Percent   float64 `json:"percent"`
+	Inverse   bool    `json:"inverse"`
+	Statement string  `json:"statement"`
+	Line      int     `json:"line"`
+}
+
+func VehicleComparisonsResponseFromModel(facts []*domain.VehicleComparisonFact) *VehicleComparisonsResponse {
+	responses := make([]VehicleComparisonResponse, len(facts))
+	for i, fact := range facts {
+		responses[i] = VehicleComparisonResponse{
+			Subject:   fact.Subject,
+			Attribute: fact.Attribute,
+			Object:    fact.Object,
+			Ratio:     fact.Ratio,
+			Percent:   fact.Percent,
+			Inverse:   fact.Inverse,
+			Statement: fact.Statement,
+			Line:      fact.Line,
+		}
+	}
+
+	return &VehicleComparisonsResponse{
+		Comparisons: responses,
+	}
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"strings"
+	"time"
+)
+
+// APIKey keeps its scopes space separated, like the scope claim of OAuth
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Name       string     `gorm:"type:text;not null"`
+	Prefix     string     `gorm:"type:text;not null"`
+	KeyHash    string     `gorm:"type:text;not null;uniqueIndex"`
+	Scopes     string     `gorm:"type:text;not null"`
+	LastUsedAt *time.Time `gorm:"null"`
+	RevokedAt  *time.Time `gorm:"null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func (apiKey *APIKey) toDomain() *domain.APIKey {
+	return &domain.APIKey{
+		ID:         apiKey.ID,
+		UserID:     apiKey.UserID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		KeyHash:    apiKey.KeyHash,
+		Scopes:     strings.Fields(apiKey.Scopes),
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (repo *APIKeyRepository) CreateAPIKey(
+	ctx context.Context,
+	apiKey *domain.APIKey,
+) (*domain.APIKey, error) {
+	var err error
+
+	modelAPIKey := APIKey{
+		UserID:  apiKey.UserID,
+		Name:    apiKey.Name,
+		Prefix:  apiKey.Prefix,
+		KeyHash: apiKey.KeyHash,
+		Scopes:  strings.Join(apiKey.Scopes, " "),
+	}
+
+	err = repo.db.WithContext(ctx).Create(&modelAPIKey).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return modelAPIKey.toDomain(), nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestAPIKeyRepository_CreateAPIKey(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &APIKeyRepository{
+		db: gormDb,
+	}
+
+	apiKey := &domain.APIKey{
+		UserID:  uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		Name:    "batch job",
+		Prefix:  "rag_abcd1234",
+		KeyHash: domain.HashAPIKey("rag_abcd1234secret"),
+		Scopes:  []string{domain.ScopeReadSessions, domain.ScopeSendMessages},
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "api_keys" ("user_id","name","prefix","key_hash","scopes","last_used_at","revoked_at","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+		WithArgs(
+			apiKey.UserID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "sessions:read messages:send",
+			nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.UUID{0x32, 0x34, 0x56, 0x78}))
+	mockDb.ExpectCommit()
+
+	actual, err := repo.CreateAPIKey(context.Background(), apiKey)
+	if err != nil {
+		t.Errorf("CreateAPIKey() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, uuid.UUID{0x32, 0x34, 0x56, 0x78}, actual.ID)
+	assert.Equal(t, apiKey.Scopes, actual.Scopes)
+	assert.False(t, actual.CreatedAt.IsZero())
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
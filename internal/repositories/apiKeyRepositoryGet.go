@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func (repo *APIKeyRepository) GetAPIKeyByHash(
+	ctx context.Context,
+	keyHash string,
+) (*domain.APIKey, error) {
+	var err error
+	var modelAPIKey *APIKey
+
+	err = repo.db.WithContext(ctx).
+		Model(APIKey{}).
+		Where("key_hash = ?", keyHash).
+		Take(&modelAPIKey).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.APIKey{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("api key not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.APIKey{}, err
+	}
+
+	return modelAPIKey.toDomain(), nil
+}
+
+// GetUserAPIKeys returns the API keys of a user, revoked ones included, newest
+// first
+func (repo *APIKeyRepository) GetUserAPIKeys(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*domain.APIKey, error) {
+	var err error
+	var modelAPIKeys []*APIKey
+
+	err = repo.db.WithContext(ctx).
+		Model(APIKey{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&modelAPIKeys).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys := make([]*domain.APIKey, 0, len(modelAPIKeys))
+	for _, modelAPIKey := range modelAPIKeys {
+		apiKeys = append(apiKeys, modelAPIKey.toDomain())
+	}
+
+	return apiKeys, nil
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepository_GetAPIKeyByHash(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &APIKeyRepository{
+		db: gormDb,
+	}
+
+	lastUsedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+	expected := &domain.APIKey{
+		ID:         uuid.UUID{0x32, 0x34, 0x56, 0x78},
+		UserID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		Name:       "batch job",
+		Prefix:     "rag_abcd1234",
+		KeyHash:    domain.HashAPIKey("rag_abcd1234secret"),
+		Scopes:     []string{domain.ScopeReadSessions, domain.ScopeSendMessages},
+		LastUsedAt: &lastUsedAt,
+		CreatedAt:  time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1 LIMIT $2`)).
+		WithArgs(expected.KeyHash, 1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "last_used_at", "revoked_at", "created_at"}).
+				AddRow(
+					expected.ID, expected.UserID, expected.Name, expected.Prefix, expected.KeyHash,
+					"sessions:read messages:send", lastUsedAt, nil, expected.CreatedAt,
+				),
+		)
+
+	actual, err := repo.GetAPIKeyByHash(context.Background(), expected.KeyHash)
+	if err != nil {
+		t.Errorf("GetAPIKeyByHash() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, expected, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_GetAPIKeyByHashHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &APIKeyRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1 LIMIT $2`)).
+		WithArgs("unknown", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err = repo.GetAPIKeyByHash(context.Background(), "unknown")
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestAPIKeyRepository_GetUserAPIKeys(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &APIKeyRepository{
+		db: gormDb,
+	}
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	revokedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+	expected := []*domain.APIKey{
+		{
+			ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			UserID:    userID,
+			Name:      "kb sync",
+			Prefix:    "rag_efgh5678",
+			KeyHash:   domain.HashAPIKey("rag_efgh5678secret"),
+			Scopes:    []string{domain.ScopeManageKB},
+			CreatedAt: time.Date(2025, 5, 30, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+			UserID:    userID,
+			Name:      "batch job",
+			Prefix:    "rag_abcd1234",
+			KeyHash:   domain.HashAPIKey("rag_abcd1234secret"),
+			Scopes:    []string{domain.ScopeReadSessions},
+			RevokedAt: &revokedAt,
+			CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE user_id = $1 ORDER BY created_at DESC`)).
+		WithArgs(userID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "last_used_at", "revoked_at", "created_at"}).
+				AddRow(
+					expected[0].ID, userID, expected[0].Name, expected[0].Prefix, expected[0].KeyHash,
+					"kb:manage", nil, nil, expected[0].CreatedAt,
+				).
+				AddRow(
+					expected[1].ID, userID, expected[1].Name, expected[1].Prefix, expected[1].KeyHash,
+					"sessions:read", nil, revokedAt, expected[1].CreatedAt,
+				),
+		)
+
+	actual, err := repo.GetUserAPIKeys(context.Background(), userID)
+	if err != nil {
+		t.Errorf("GetUserAPIKeys() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, expected, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
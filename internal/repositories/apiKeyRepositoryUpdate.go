@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"time"
+)
+
+// RevokeAPIKey revokes an active API key of the user. Keys of other users are
+// reported as not found, so that their IDs cannot be probed.
+func (repo *APIKeyRepository) RevokeAPIKey(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+) error {
+	result := repo.db.WithContext(ctx).
+		Model(&APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("active api key " + id.String() + " not found"),
+		}
+	}
+
+	return nil
+}
+
+func (repo *APIKeyRepository) UpdateAPIKeyLastUsed(
+	ctx context.Context,
+	id uuid.UUID,
+	lastUsedAt time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error
+}
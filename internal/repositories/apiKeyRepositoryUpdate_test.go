@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name             string
+		mockRowsAffected int64
+		expectedError    error
+	}{
+		{
+			name:             "active key of the user",
+			mockRowsAffected: 1,
+		},
+		{
+			name:             "revoked or another user's key",
+			mockRowsAffected: 0,
+			expectedError:    customerrors.ResourceNotFoundErrorWrapper{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &APIKeyRepository{
+				db: gormDb,
+			}
+
+			id := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+			userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "revoked_at"=$1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`)).
+				WithArgs(sqlmock.AnyArg(), id, userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.mockRowsAffected))
+			mockDb.ExpectCommit()
+
+			err := repo.RevokeAPIKey(context.Background(), id, userID)
+
+			if tt.expectedError != nil {
+				assert.IsType(t, tt.expectedError, err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_UpdateAPIKeyLastUsed(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &APIKeyRepository{
+		db: gormDb,
+	}
+
+	id := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	lastUsedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "last_used_at"=$1 WHERE id = $2`)).
+		WithArgs(lastUsedAt, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	err = repo.UpdateAPIKeyLastUsed(context.Background(), id, lastUsedAt)
+
+	assert.Nil(t, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
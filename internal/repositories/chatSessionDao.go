@@ -2,26 +2,117 @@ package repositories
 
 import (
 	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
 	"time"
 )
 
 const SYSTEM_SENDER = "SYSTEM"
 const USER_SENDER = "USER"
 
+// ChatSession is soft deleted: gorm leaves the deleted sessions out of every
+// query, until PurgeChatSessions deletes them with their messages. The sessions
+// of a user are listed by updated_at, through idx_chat_sessions_user_updated.
 type ChatSession struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
-	Title     string    `gorm:"type:text"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
-	Messages  []Message
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index;index:idx_chat_sessions_user_updated,priority:1"`
+	Title      string         `gorm:"type:text"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime;index:idx_chat_sessions_user_updated,priority:2"`
+	ArchivedAt *time.Time     `gorm:"null"`
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	Messages   []Message      `gorm:"constraint:OnDelete:CASCADE"`
 }
 
+// Message is paged through by created_at and id, through
+// idx_messages_session_created
 type Message struct {
 	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	ChatSessionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	ChatSessionID uuid.UUID `gorm:"type:uuid;not null;index;index:idx_messages_session_created,priority:1"`
 	Sender        string    `gorm:"type:text;not null"`
 	Content       string    `gorm:"type:text;not null"`
-	CreatedAt     time.Time `gorm:"not null"`
-	Feedback      *string   `gorm:"type:text;null"`
+	CreatedAt     time.Time `gorm:"not null;index:idx_messages_session_created,priority:2"`
+	// Feedback is the free text comment of the feedback
+	Feedback                *string           `gorm:"type:text;null"`
+	FeedbackThumb           *string           `gorm:"type:text;null"`
+	FeedbackRating          *int              `gorm:"type:smallint;null"`
+	FeedbackReason          *string           `gorm:"type:text;null"`
+	FeedbackCorrectedAnswer *string           `gorm:"type:text;null"`
+	FeedbackSubmittedAt     *time.Time        `gorm:"null"`
+	Sources                 []MessageSource   `gorm:"constraint:OnDelete:CASCADE"`
+	ToolCalls               []MessageToolCall `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// domainFeedback maps the feedback columns of the message, nil when no
+// feedback was submitted.
+func (message *Message) domainFeedback() *domain.MessageFeedback {
+	if message.FeedbackSubmittedAt == nil {
+		return nil
+	}
+
+	feedback := &domain.MessageFeedback{
+		SubmittedAt: *message.FeedbackSubmittedAt,
+	}
+
+	if message.FeedbackThumb != nil {
+		feedback.Thumb = *message.FeedbackThumb
+	}
+	if message.FeedbackRating != nil {
+		feedback.Rating = *message.FeedbackRating
+	}
+	if message.FeedbackReason != nil {
+		feedback.Reason = *message.FeedbackReason
+	}
+	if message.FeedbackCorrectedAnswer != nil {
+		feedback.CorrectedAnswer = *message.FeedbackCorrectedAnswer
+	}
+	if message.Feedback != nil {
+		feedback.Comment = *message.Feedback
+	}
+
+	return feedback
+}
+
+// MessageSource is a knowledge base chunk that was retrieved and used as
+// context for a SYSTEM message.
+type MessageSource struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Rank      int       `gorm:"not null"`
+	ChunkID   string    `gorm:"type:text;not null"`
+	Content   string    `gorm:"type:text;not null"`
+	Score     float32   `gorm:"not null"`
+}
+
+// MessageToolCall is a tool call of the LLM while it was generating a SYSTEM
+// message in agent mode.
+type MessageToolCall struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Rank      int       `gorm:"not null"`
+	Step      int       `gorm:"not null"`
+	Name      string    `gorm:"type:text;not null"`
+	Arguments string    `gorm:"type:text;not null"`
+	Result    string    `gorm:"type:text;not null"`
+	IsError   bool      `gorm:"not null"`
+}
+
+// domainToolCalls maps the tool calls of the message, ordered by rank
+func (message *Message) domainToolCalls() []*domain.ToolCall {
+	if len(message.ToolCalls) == 0 {
+		return nil
+	}
+
+	toolCalls := make([]*domain.ToolCall, len(message.ToolCalls))
+	for i, toolCall := range message.ToolCalls {
+		toolCalls[i] = &domain.ToolCall{
+			Step:      toolCall.Step,
+			Name:      toolCall.Name,
+			Arguments: toolCall.Arguments,
+			Result:    toolCall.Result,
+			IsError:   toolCall.IsError,
+		}
+	}
+
+	return toolCalls
 }
@@ -43,7 +43,7 @@ func TestChatRepository_CreateChatSession(t *testing.T) {
 					Messages: nil,
 				},
 			},
-			mockSqlChatQueryExpected:   `INSERT INTO "chat_sessions" ("user_id","title","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`,
+			mockSqlChatQueryExpected:   `INSERT INTO "chat_sessions" ("user_id","title","created_at","updated_at","archived_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
 			mockInsertedChatIdReturned: uuid.UUID{0x12, 0x34, 0x56, 0x78},
 			expectedChatUid:            uuid.UUID{0x12, 0x34, 0x56, 0x78},
 		},
@@ -59,7 +59,7 @@ func TestChatRepository_CreateChatSession(t *testing.T) {
 
 			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlChatQueryExpected)).
 				WithArgs(
-					tt.args.chat.UserID, tt.args.chat.Title, sqlmock.AnyArg(), sqlmock.AnyArg(),
+					tt.args.chat.UserID, tt.args.chat.Title, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil,
 				).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedChatIdReturned))
 			mockDb.ExpectCommit()
@@ -104,7 +104,7 @@ func TestChatRepository_CreateChatSessionWithError(t *testing.T) {
 					UserID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				},
 			},
-			mockSqlChatQueryExpected: `INSERT INTO "chat_sessions" ("user_id","title","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`,
+			mockSqlChatQueryExpected: `INSERT INTO "chat_sessions" ("user_id","title","created_at","updated_at","archived_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
 			expectedErrorMessage:     "random error",
 		},
 	}
@@ -118,7 +118,7 @@ func TestChatRepository_CreateChatSessionWithError(t *testing.T) {
 			mockDb.ExpectBegin()
 			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlChatQueryExpected)).
 				WithArgs(
-					tt.args.chat.UserID, tt.args.chat.Title, sqlmock.AnyArg(), sqlmock.AnyArg(),
+					tt.args.chat.UserID, tt.args.chat.Title, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil,
 				).
 				WillReturnError(errors.New(tt.expectedErrorMessage))
 			mockDb.ExpectRollback()
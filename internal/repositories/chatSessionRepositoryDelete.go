@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// DeleteChatSession soft deletes the chat session, its messages are left for
+// PurgeChatSessions
+func (repo *ChatSessionRepository) DeleteChatSession(
+	ctx context.Context,
+	uuid uuid.UUID,
+) error {
+	result := repo.db.WithContext(ctx).
+		Where("id = ?", uuid).
+		Delete(&ChatSession{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + uuid.String() + " not found"),
+		}
+	}
+
+	return nil
+}
+
+// PurgeChatSessions deletes the chat sessions that were soft deleted before
+// deletedBefore, together with their messages and the sources and tool calls
+// of the messages, and returns how many sessions were purged. The curated
+// answers of the messages are deleted too unless they were approved: those are
+// in the knowledge base and only lose their message. The token usages are
+// kept, they have no foreign keys to what they accounted for.
+func (repo *ChatSessionRepository) PurgeChatSessions(
+	ctx context.Context,
+	deletedBefore time.Time,
+) (int64, error) {
+	var purged int64
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sessionIDs := tx.Unscoped().Model(&ChatSession{}).
+			Select("id").
+			Where("deleted_at < ?", deletedBefore)
+		messageIDs := tx.Model(&Message{}).
+			Select("id").
+			Where("chat_session_id IN (?)", sessionIDs)
+
+		err := tx.Where("message_id IN (?)", messageIDs).Delete(&MessageSource{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("message_id IN (?)", messageIDs).Delete(&MessageToolCall{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("message_id IN (?) AND status <> ?", messageIDs, domain.CuratedAnswerStatusApproved).
+			Delete(&CuratedAnswer{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&CuratedAnswer{}).
+			Where("message_id IN (?)", messageIDs).
+			Update("message_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("chat_session_id IN (?)", sessionIDs).Delete(&Message{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(&ChatSession{})
+		purged = result.RowsAffected
+
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
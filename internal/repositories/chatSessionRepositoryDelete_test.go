@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestChatRepository_DeleteChatSession(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		uuid uuid.UUID
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		mockSqlChatQueryExpected string
+		mockRowsAffected         int64
+		expectedError            error
+	}{
+		{
+			name: "valid",
+			args: args{
+				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+			},
+			mockSqlChatQueryExpected: `UPDATE "chat_sessions" SET "deleted_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`,
+			mockRowsAffected:         1,
+		},
+		{
+			name: "chat session not found",
+			args: args{
+				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+			},
+			mockSqlChatQueryExpected: `UPDATE "chat_sessions" SET "deleted_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`,
+			mockRowsAffected:         0,
+			expectedError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chatSessionID 12345678-0000-0000-0000-000000000000 not found"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ChatSessionRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlChatQueryExpected)).
+				WithArgs(sqlmock.AnyArg(), tt.args.uuid).
+				WillReturnResult(sqlmock.NewResult(0, tt.mockRowsAffected))
+			mockDb.ExpectCommit()
+
+			actual := repo.DeleteChatSession(context.Background(), tt.args.uuid)
+
+			assert.Equal(t, tt.expectedError, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestChatRepository_PurgeChatSessions(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	deletedBefore := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	repo := &ChatSessionRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "message_sources" WHERE message_id IN (SELECT "id" FROM "messages" WHERE chat_session_id IN (SELECT "id" FROM "chat_sessions" WHERE deleted_at < $1))`)).
+		WithArgs(deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 6))
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "message_tool_calls" WHERE message_id IN (SELECT "id" FROM "messages" WHERE chat_session_id IN (SELECT "id" FROM "chat_sessions" WHERE deleted_at < $1))`)).
+		WithArgs(deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "curated_answers" WHERE message_id IN (SELECT "id" FROM "messages" WHERE chat_session_id IN (SELECT "id" FROM "chat_sessions" WHERE deleted_at < $1)) AND status <> $2`)).
+		WithArgs(deletedBefore, domain.CuratedAnswerStatusApproved).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "curated_answers" SET "message_id"=$1,"updated_at"=$2 WHERE message_id IN (SELECT "id" FROM "messages" WHERE chat_session_id IN (SELECT "id" FROM "chat_sessions" WHERE deleted_at < $3))`)).
+		WithArgs(nil, sqlmock.AnyArg(), deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "messages" WHERE chat_session_id IN (SELECT "id" FROM "chat_sessions" WHERE deleted_at < $1)`)).
+		WithArgs(deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "chat_sessions" WHERE deleted_at < $1`)).
+		WithArgs(deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDb.ExpectCommit()
+
+	actual, err := repo.PurgeChatSessions(context.Background(), deletedBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestChatRepository_PurgeChatSessionsWithError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	deletedBefore := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	repo := &ChatSessionRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`DELETE FROM "message_sources"`)).
+		WithArgs(deletedBefore).
+		WillReturnError(errors.New("random error"))
+	mockDb.ExpectRollback()
+
+	actual, err := repo.PurgeChatSessions(context.Background(), deletedBefore)
+
+	assert.Equal(t, errors.New("random error"), err)
+	assert.Equal(t, int64(0), actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"gorm.io/gorm"
+	"time"
 )
 
 func (repo *ChatSessionRepository) GetChatSession(
@@ -16,8 +17,14 @@ func (repo *ChatSessionRepository) GetChatSession(
 	var err error
 	var modelChatSession *ChatSession
 
+	// the tool calls are only needed to inspect a single session
 	err = repo.db.WithContext(ctx).
-		Preload("Messages").
+		Preload("Messages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		}).
+		Preload("Messages.ToolCalls", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank")
+		}).
 		Model(ChatSession{}).
 		Where("id = ?", uuid).
 		Take(&modelChatSession).Error
@@ -40,68 +47,138 @@ func (repo *ChatSessionRepository) GetChatSession(
 			Sender:        msg.Sender,
 			Content:       msg.Content,
 			CreatedAt:     msg.CreatedAt,
+			Feedback:      msg.domainFeedback(),
+			ToolCalls:     msg.domainToolCalls(),
 		}
 	}
 
 	return &domain.ChatSession{
-		ID:        modelChatSession.ID,
-		Title:     modelChatSession.Title,
-		UserID:    modelChatSession.UserID,
-		CreatedAt: modelChatSession.CreatedAt,
-		UpdatedAt: modelChatSession.UpdatedAt,
-		Messages:  messages,
+		ID:         modelChatSession.ID,
+		Title:      modelChatSession.Title,
+		UserID:     modelChatSession.UserID,
+		CreatedAt:  modelChatSession.CreatedAt,
+		UpdatedAt:  modelChatSession.UpdatedAt,
+		ArchivedAt: modelChatSession.ArchivedAt,
+		Messages:   messages,
 	}, err
 }
 
-func (repo *ChatSessionRepository) GetUserChatSessions(
+// GetChatSessionUserID returns the user of the chat session, to check who owns
+// it without loading its messages
+func (repo *ChatSessionRepository) GetChatSessionUserID(
 	ctx context.Context,
-	uuid uuid.UUID,
-) ([]*domain.ChatSession, error) {
-	var err error
-	var modelChatSessions []*ChatSession
+	chatSessionID uuid.UUID,
+) (uuid.UUID, error) {
+	var modelChatSession *ChatSession
 
-	err = repo.db.WithContext(ctx).
-		Preload("Messages").
+	err := repo.db.WithContext(ctx).
 		Model(ChatSession{}).
-		Where("user_id = ?", uuid).
-		Find(&modelChatSessions).Error
+		Select("user_id").
+		Where("id = ?", chatSessionID).
+		Take(&modelChatSession).Error
 
 	if err == gorm.ErrRecordNotFound {
-		return []*domain.ChatSession{}, customerrors.ResourceNotFoundErrorWrapper{
-			OriginalError: errors.New("user uuid " + uuid.String() + " not found"),
+		return uuid.Nil, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + chatSessionID.String() + " not found"),
 		}
 	}
 
 	if err != nil {
-		return []*domain.ChatSession{}, err
+		return uuid.Nil, err
 	}
 
-	chatSessions := make([]*domain.ChatSession, 0, len(modelChatSessions))
-	for _, modelChatSession := range modelChatSessions {
-
-		messages := make([]*domain.Message, len(modelChatSession.Messages))
-		for i, msg := range modelChatSession.Messages {
-			messages[i] = &domain.Message{
-				ID:            msg.ID,
-				ChatSessionID: msg.ChatSessionID,
-				Sender:        msg.Sender,
-				Content:       msg.Content,
-				CreatedAt:     msg.CreatedAt,
-			}
+	return modelChatSession.UserID, nil
+}
+
+// chatSessionSummary is a row of GetUserChatSessionSummaries, the last
+// message columns are null for a session without messages
+type chatSessionSummary struct {
+	ID                   uuid.UUID
+	UserID               uuid.UUID
+	Title                string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	ArchivedAt           *time.Time
+	MessageCount         int
+	LastMessageID        *uuid.UUID
+	LastMessageSender    *string
+	LastMessageContent   *string
+	LastMessageCreatedAt *time.Time
+}
+
+// GetUserChatSessionSummaries lists the chat sessions of the user without
+// loading their messages, counting them and joining only the last one.
+func (repo *ChatSessionRepository) GetUserChatSessionSummaries(
+	ctx context.Context,
+	userID uuid.UUID,
+	filter *domain.ChatSessionFilter,
+) ([]*domain.ChatSessionSummary, error) {
+	query := repo.db.WithContext(ctx).
+		Model(&ChatSession{}).
+		Select(`chat_sessions.id, chat_sessions.user_id, chat_sessions.title, chat_sessions.created_at, `+
+			`chat_sessions.updated_at, chat_sessions.archived_at, `+
+			`(SELECT COUNT(*) FROM messages WHERE messages.chat_session_id = chat_sessions.id) AS message_count, `+
+			`last_message.id AS last_message_id, last_message.sender AS last_message_sender, `+
+			`LEFT(last_message.content, ?) AS last_message_content, last_message.created_at AS last_message_created_at`,
+			domain.ChatSessionPreviewLength,
+		).
+		Joins(`LEFT JOIN LATERAL (SELECT id, sender, content, created_at FROM messages `+
+			`WHERE messages.chat_session_id = chat_sessions.id ORDER BY created_at DESC, id DESC LIMIT 1) AS last_message ON true`).
+		Where("chat_sessions.user_id = ?", userID)
+
+	if filter.Archived != nil && *filter.Archived {
+		query = query.Where("chat_sessions.archived_at IS NOT NULL")
+	}
+	if filter.Archived != nil && !*filter.Archived {
+		query = query.Where("chat_sessions.archived_at IS NULL")
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where("chat_sessions.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		query = query.Where("chat_sessions.updated_at < ?", *filter.UpdatedTo)
+	}
+
+	// the id breaks the ties of updated_at, so that a cursor is a stable position
+	order := "chat_sessions.updated_at DESC, chat_sessions.id DESC"
+	if filter.Ascending {
+		order = "chat_sessions.updated_at, chat_sessions.id"
+	}
+	if filter.After != nil && filter.Ascending {
+		query = query.Where("(chat_sessions.updated_at, chat_sessions.id) > (?, ?)", filter.After.UpdatedAt, filter.After.ID)
+	}
+	if filter.After != nil && !filter.Ascending {
+		query = query.Where("(chat_sessions.updated_at, chat_sessions.id) < (?, ?)", filter.After.UpdatedAt, filter.After.ID)
+	}
+
+	var rows []*chatSessionSummary
+	err := query.Order(order).Limit(filter.Limit).Scan(&rows).Error
+	if err != nil {
+		return []*domain.ChatSessionSummary{}, err
+	}
+
+	summaries := make([]*domain.ChatSessionSummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = &domain.ChatSessionSummary{
+			ID:           row.ID,
+			UserID:       row.UserID,
+			Title:        row.Title,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+			ArchivedAt:   row.ArchivedAt,
+			MessageCount: row.MessageCount,
 		}
 
-		chatSessions = append(
-			chatSessions,
-			&domain.ChatSession{
-				ID:        modelChatSession.ID,
-				Title:     modelChatSession.Title,
-				UserID:    modelChatSession.UserID,
-				CreatedAt: modelChatSession.CreatedAt,
-				UpdatedAt: modelChatSession.UpdatedAt,
-				Messages:  messages,
-			},
-		)
+		if row.LastMessageID != nil {
+			summaries[i].LastMessage = &domain.Message{
+				ID:            *row.LastMessageID,
+				ChatSessionID: row.ID,
+				Sender:        *row.LastMessageSender,
+				Content:       *row.LastMessageContent,
+				CreatedAt:     *row.LastMessageCreatedAt,
+			}
+		}
 	}
 
-	return chatSessions, nil
+	return summaries, nil
 }
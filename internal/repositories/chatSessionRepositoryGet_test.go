@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
@@ -28,21 +29,24 @@ func TestChatRepository_GetChatSession(t *testing.T) {
 		uuid uuid.UUID
 	}
 	tests := []struct {
-		name                         string
-		args                         args
-		mockSqlChatQueryExpected     string
-		mockSqlMessagesQueryExpected string
-		mockChatReturned             *ChatSession
-		mockMessagesReturned         []*Message
-		expected                     *domain.ChatSession
+		name                          string
+		args                          args
+		mockSqlChatQueryExpected      string
+		mockSqlMessagesQueryExpected  string
+		mockSqlToolCallsQueryExpected string
+		mockChatReturned              *ChatSession
+		mockMessagesReturned          []*Message
+		mockToolCallReturned          *MessageToolCall
+		expected                      *domain.ChatSession
 	}{
 		{
 			name: "valid",
 			args: args{
 				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
 			},
-			mockSqlChatQueryExpected:     `SELECT * FROM "chat_sessions" WHERE id = $1 LIMIT $2`,
-			mockSqlMessagesQueryExpected: `SELECT * FROM "messages" WHERE "messages"."chat_session_id" = $1`,
+			mockSqlChatQueryExpected:      `SELECT * FROM "chat_sessions" WHERE id = $1 AND "chat_sessions"."deleted_at" IS NULL LIMIT $2`,
+			mockSqlMessagesQueryExpected:  `SELECT * FROM "messages" WHERE "messages"."chat_session_id" = $1 ORDER BY created_at, id`,
+			mockSqlToolCallsQueryExpected: `SELECT * FROM "message_tool_calls" WHERE "message_tool_calls"."message_id" IN ($1,$2) ORDER BY rank`,
 			mockChatReturned: &ChatSession{
 				ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
@@ -66,6 +70,15 @@ func TestChatRepository_GetChatSession(t *testing.T) {
 					CreatedAt:     time.Time{},
 				},
 			},
+			mockToolCallReturned: &MessageToolCall{
+				ID:        uuid.UUID{0x72, 0x34, 0x56, 0x78},
+				MessageID: uuid.UUID{0x02, 0x34, 0x56, 0x68},
+				Rank:      1,
+				Step:      1,
+				Name:      "search_knowledge_base",
+				Arguments: `{"query":"force"}`,
+				Result:    `{"hits":[]}`,
+			},
 			expected: &domain.ChatSession{
 				ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
@@ -79,6 +92,14 @@ func TestChatRepository_GetChatSession(t *testing.T) {
 						Sender:        "SYSTEM",
 						Content:       "MAY THE FORCE BE WITH YOU",
 						CreatedAt:     time.Time{},
+						ToolCalls: []*domain.ToolCall{
+							{
+								Step:      1,
+								Name:      "search_knowledge_base",
+								Arguments: `{"query":"force"}`,
+								Result:    `{"hits":[]}`,
+							},
+						},
 					},
 					{
 						ID:            uuid.UUID{0x052, 0x34, 0x56, 0x58},
@@ -121,6 +142,17 @@ func TestChatRepository_GetChatSession(t *testing.T) {
 					),
 				)
 
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlToolCallsQueryExpected)).
+				WithArgs(tt.mockMessagesReturned[0].ID, tt.mockMessagesReturned[1].ID).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "message_id", "rank", "step", "name", "arguments", "result", "is_error"},
+					).AddRow(
+						tt.mockToolCallReturned.ID, tt.mockToolCallReturned.MessageID, tt.mockToolCallReturned.Rank, tt.mockToolCallReturned.Step,
+						tt.mockToolCallReturned.Name, tt.mockToolCallReturned.Arguments, tt.mockToolCallReturned.Result, tt.mockToolCallReturned.IsError,
+					),
+				)
+
 			actual, err := repo.GetChatSession(context.Background(), tt.args.uuid)
 			if err != nil {
 				t.Errorf("GetChatSession() error = %v", err)
@@ -160,7 +192,7 @@ func TestChatRepository_GetChatSessionWithError(t *testing.T) {
 			args: args{
 				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
 			},
-			mockSqlChatQueryExpected: `SELECT * FROM "chat_sessions" WHERE id = $1 LIMIT $2`,
+			mockSqlChatQueryExpected: `SELECT * FROM "chat_sessions" WHERE id = $1 AND "chat_sessions"."deleted_at" IS NULL LIMIT $2`,
 			mockSqlErrorReturned:     errors.New("random error"),
 			expectedError:            errors.New("random error"),
 		},
@@ -169,7 +201,7 @@ func TestChatRepository_GetChatSessionWithError(t *testing.T) {
 			args: args{
 				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
 			},
-			mockSqlChatQueryExpected: `SELECT * FROM "chat_sessions" WHERE id = $1 LIMIT $2`,
+			mockSqlChatQueryExpected: `SELECT * FROM "chat_sessions" WHERE id = $1 AND "chat_sessions"."deleted_at" IS NULL LIMIT $2`,
 			mockSqlErrorReturned:     gorm.ErrRecordNotFound,
 			expectedError: customerrors.ResourceNotFoundErrorWrapper{
 				OriginalError: errors.New("chatSessionID 12345678-0000-0000-0000-000000000000 not found"),
@@ -197,7 +229,61 @@ func TestChatRepository_GetChatSessionWithError(t *testing.T) {
 	}
 }
 
-func TestChatRepository_GetUserChatSessions(t *testing.T) {
+func TestChatRepository_GetChatSessionUserID(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	chatSessionID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	userID := uuid.UUID{0x22, 0x34, 0x56, 0x88}
+
+	tests := []struct {
+		name          string
+		mockRows      *sqlmock.Rows
+		expected      uuid.UUID
+		expectedError error
+	}{
+		{
+			name:     "valid",
+			mockRows: sqlmock.NewRows([]string{"user_id"}).AddRow(userID),
+			expected: userID,
+		},
+		{
+			name:     "not found",
+			mockRows: sqlmock.NewRows([]string{"user_id"}),
+			expected: uuid.Nil,
+			expectedError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chatSessionID " + chatSessionID.String() + " not found"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ChatSessionRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT "user_id" FROM "chat_sessions" WHERE id = $1 AND "chat_sessions"."deleted_at" IS NULL LIMIT $2`)).
+				WithArgs(chatSessionID, 1).
+				WillReturnRows(tt.mockRows)
+
+			actual, err := repo.GetChatSessionUserID(context.Background(), chatSessionID)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestChatRepository_GetUserChatSessionSummaries(t *testing.T) {
 	db, mockDb, err := sqlmock.New()
 	if err != nil {
 		t.Error(err.Error())
@@ -206,119 +292,125 @@ func TestChatRepository_GetUserChatSessions(t *testing.T) {
 
 	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
 
+	userID := uuid.UUID{0x22, 0x34, 0x56, 0x88}
+	archived := true
+	updatedFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedTo := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	archivedAt := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	cursor := &domain.ChatSessionCursor{
+		UpdatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
+	}
+
+	summarySelect := `SELECT chat_sessions.id, chat_sessions.user_id, chat_sessions.title, chat_sessions.created_at, ` +
+		`chat_sessions.updated_at, chat_sessions.archived_at, ` +
+		`(SELECT COUNT(*) FROM messages WHERE messages.chat_session_id = chat_sessions.id) AS message_count, ` +
+		`last_message.id AS last_message_id, last_message.sender AS last_message_sender, ` +
+		`LEFT(last_message.content, $1) AS last_message_content, last_message.created_at AS last_message_created_at ` +
+		`FROM "chat_sessions" LEFT JOIN LATERAL (SELECT id, sender, content, created_at FROM messages ` +
+		`WHERE messages.chat_session_id = chat_sessions.id ORDER BY created_at DESC, id DESC LIMIT 1) AS last_message ON true `
+
+	columns := []string{
+		"id", "user_id", "title", "created_at", "updated_at", "archived_at", "message_count",
+		"last_message_id", "last_message_sender", "last_message_content", "last_message_created_at",
+	}
+
 	type args struct {
-		uuid uuid.UUID
+		userID uuid.UUID
+		filter *domain.ChatSessionFilter
 	}
 	tests := []struct {
-		name                         string
-		args                         args
-		mockSqlChatQueryExpected     string
-		mockSqlMessagesQueryExpected string
-		mockChatsReturned            []*ChatSession
-		mockMessagesReturned         []*Message
-		expected                     []*domain.ChatSession
+		name                     string
+		args                     args
+		mockSqlQueryExpected     string
+		mockSqlQueryArgsExpected []driver.Value
+		mockRowsReturned         *sqlmock.Rows
+		expected                 []*domain.ChatSessionSummary
 	}{
 		{
-			name: "valid",
+			name: "newest first",
 			args: args{
-				uuid: uuid.UUID{0x22, 0x34, 0x56, 0x88},
+				userID: userID,
+				filter: &domain.ChatSessionFilter{Limit: 3},
 			},
-			mockSqlChatQueryExpected:     `SELECT * FROM "chat_sessions" WHERE user_id = $1`,
-			mockSqlMessagesQueryExpected: `SELECT * FROM "messages" WHERE "messages"."chat_session_id" IN ($1,$2)`,
-			mockChatsReturned: []*ChatSession{
-				&ChatSession{
-					ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
-					UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
-					Title:     "mockTitle",
-					CreatedAt: time.Time{},
-					UpdatedAt: time.Time{},
-				},
-				&ChatSession{
-					ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
-					Title:     "mockTitle",
-					CreatedAt: time.Time{},
-					UpdatedAt: time.Time{},
-				},
-			},
-			mockMessagesReturned: []*Message{
-
+			mockSqlQueryExpected: summarySelect +
+				`WHERE chat_sessions.user_id = $2 AND "chat_sessions"."deleted_at" IS NULL ` +
+				`ORDER BY chat_sessions.updated_at DESC, chat_sessions.id DESC LIMIT $3`,
+			mockSqlQueryArgsExpected: []driver.Value{domain.ChatSessionPreviewLength, userID, 3},
+			mockRowsReturned: sqlmock.NewRows(columns).
+				AddRow(
+					uuid.UUID{0x32, 0x34, 0x56, 0x78}, userID, "mockTitle", updatedFrom, updatedTo, nil, 2,
+					uuid.UUID{0x02, 0x34, 0x56, 0x68}, "SYSTEM", "MAY THE FORCE BE WITH YOU", updatedTo,
+				).
+				AddRow(
+					uuid.UUID{0x42, 0x34, 0x56, 0x78}, userID, "", updatedFrom, updatedFrom, nil, 0,
+					nil, nil, nil, nil,
+				),
+			expected: []*domain.ChatSessionSummary{
 				{
-					ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
-					ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
-					Sender:        "SYSTEM",
-					Content:       "MAY THE FORCE BE WITH YOU",
-					CreatedAt:     time.Time{},
-				},
-				{
-					ID:            uuid.UUID{0x052, 0x34, 0x56, 0x58},
-					ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
-					Sender:        "USER",
-					Content:       "2MAY THE FORCE BE WITH YOU2",
-					CreatedAt:     time.Time{},
-				},
-
-				{
-					ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
-					ChatSessionID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					Sender:        "SYSTEM",
-					Content:       "3MAY THE FORCE BE WITH YOU3",
-					CreatedAt:     time.Time{},
+					ID:           uuid.UUID{0x32, 0x34, 0x56, 0x78},
+					UserID:       userID,
+					Title:        "mockTitle",
+					CreatedAt:    updatedFrom,
+					UpdatedAt:    updatedTo,
+					MessageCount: 2,
+					LastMessage: &domain.Message{
+						ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
+						ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+						Sender:        "SYSTEM",
+						Content:       "MAY THE FORCE BE WITH YOU",
+						CreatedAt:     updatedTo,
+					},
 				},
 				{
-					ID:            uuid.UUID{0x052, 0x34, 0x56, 0x58},
-					ChatSessionID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					Sender:        "USER",
-					Content:       "4MAY THE FORCE BE WITH YOU4",
-					CreatedAt:     time.Time{},
+					ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+					UserID:    userID,
+					CreatedAt: updatedFrom,
+					UpdatedAt: updatedFrom,
 				},
 			},
-			expected: []*domain.ChatSession{
-				&domain.ChatSession{
-					ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
-					UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
-					Title:     "mockTitle",
-					CreatedAt: time.Time{},
-					UpdatedAt: time.Time{},
-					Messages: []*domain.Message{
-						{
-							ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
-							ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
-							Sender:        "SYSTEM",
-							Content:       "MAY THE FORCE BE WITH YOU",
-							CreatedAt:     time.Time{},
-						},
-						{
-
-							ID:            uuid.UUID{0x052, 0x34, 0x56, 0x58},
-							ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
-							Sender:        "USER",
-							Content:       "2MAY THE FORCE BE WITH YOU2",
-							CreatedAt:     time.Time{},
-						},
-					},
+		},
+		{
+			name: "archived in date range after cursor oldest first",
+			args: args{
+				userID: userID,
+				filter: &domain.ChatSessionFilter{
+					Archived:    &archived,
+					UpdatedFrom: &updatedFrom,
+					UpdatedTo:   &updatedTo,
+					Ascending:   true,
+					After:       cursor,
+					Limit:       2,
 				},
-				&domain.ChatSession{
-					ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
-					UserID:    uuid.UUID{0x22, 0x34, 0x56, 0x88},
-					Title:     "mockTitle",
-					CreatedAt: time.Time{},
-					UpdatedAt: time.Time{},
-					Messages: []*domain.Message{
-						{
-							ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
-							ChatSessionID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-							Sender:        "SYSTEM",
-							Content:       "3MAY THE FORCE BE WITH YOU3",
-							CreatedAt:     time.Time{},
-						},
-						{
-							ID:            uuid.UUID{0x052, 0x34, 0x56, 0x58},
-							ChatSessionID: uuid.UUID{0x42, 0x34, 0x56, 0x78},
-							Sender:        "USER",
-							Content:       "4MAY THE FORCE BE WITH YOU4",
-							CreatedAt:     time.Time{},
-						},
+			},
+			mockSqlQueryExpected: summarySelect +
+				`WHERE chat_sessions.user_id = $2 AND chat_sessions.archived_at IS NOT NULL ` +
+				`AND chat_sessions.updated_at >= $3 AND chat_sessions.updated_at < $4 ` +
+				`AND (chat_sessions.updated_at, chat_sessions.id) > ($5, $6) AND "chat_sessions"."deleted_at" IS NULL ` +
+				`ORDER BY chat_sessions.updated_at, chat_sessions.id LIMIT $7`,
+			mockSqlQueryArgsExpected: []driver.Value{
+				domain.ChatSessionPreviewLength, userID, updatedFrom, updatedTo, cursor.UpdatedAt, cursor.ID, 2,
+			},
+			mockRowsReturned: sqlmock.NewRows(columns).
+				AddRow(
+					uuid.UUID{0x32, 0x34, 0x56, 0x78}, userID, "mockTitle", updatedFrom, updatedTo, archivedAt, 1,
+					uuid.UUID{0x02, 0x34, 0x56, 0x68}, "USER", "MAY THE FORCE BE WITH YOU", updatedTo,
+				),
+			expected: []*domain.ChatSessionSummary{
+				{
+					ID:           uuid.UUID{0x32, 0x34, 0x56, 0x78},
+					UserID:       userID,
+					Title:        "mockTitle",
+					CreatedAt:    updatedFrom,
+					UpdatedAt:    updatedTo,
+					ArchivedAt:   &archivedAt,
+					MessageCount: 1,
+					LastMessage: &domain.Message{
+						ID:            uuid.UUID{0x02, 0x34, 0x56, 0x68},
+						ChatSessionID: uuid.UUID{0x32, 0x34, 0x56, 0x78},
+						Sender:        "USER",
+						Content:       "MAY THE FORCE BE WITH YOU",
+						CreatedAt:     updatedTo,
 					},
 				},
 			},
@@ -330,41 +422,13 @@ func TestChatRepository_GetUserChatSessions(t *testing.T) {
 				db: gormDb,
 			}
 
-			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlChatQueryExpected)).
-				WithArgs(tt.args.uuid).
-				WillReturnRows(
-					sqlmock.NewRows(
-						[]string{"id", "title", "user_id", "created_at", "updated_at"},
-					).AddRow(
-						tt.mockChatsReturned[0].ID, tt.mockChatsReturned[0].Title, tt.mockChatsReturned[0].UserID, tt.mockChatsReturned[0].CreatedAt, tt.mockChatsReturned[0].UpdatedAt,
-					).AddRow(
-						tt.mockChatsReturned[1].ID, tt.mockChatsReturned[1].Title, tt.mockChatsReturned[1].UserID, tt.mockChatsReturned[1].CreatedAt, tt.mockChatsReturned[1].UpdatedAt,
-					),
-				)
-
-			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessagesQueryExpected)).
-				WithArgs(tt.mockChatsReturned[0].ID, tt.mockChatsReturned[1].ID).
-				WillReturnRows(
-					sqlmock.NewRows(
-						[]string{"id", "chat_session_id", "sender", "created_at", "content"},
-					).AddRow(
-						tt.mockMessagesReturned[0].ID, tt.mockMessagesReturned[0].ChatSessionID,
-						tt.mockMessagesReturned[0].Sender, tt.mockMessagesReturned[0].CreatedAt, tt.mockMessagesReturned[0].Content,
-					).AddRow(
-						tt.mockMessagesReturned[1].ID, tt.mockMessagesReturned[1].ChatSessionID,
-						tt.mockMessagesReturned[1].Sender, tt.mockMessagesReturned[1].CreatedAt, tt.mockMessagesReturned[1].Content,
-					).AddRow(
-						tt.mockMessagesReturned[2].ID, tt.mockMessagesReturned[2].ChatSessionID,
-						tt.mockMessagesReturned[2].Sender, tt.mockMessagesReturned[2].CreatedAt, tt.mockMessagesReturned[2].Content,
-					).AddRow(
-						tt.mockMessagesReturned[3].ID, tt.mockMessagesReturned[3].ChatSessionID,
-						tt.mockMessagesReturned[3].Sender, tt.mockMessagesReturned[3].CreatedAt, tt.mockMessagesReturned[3].Content,
-					),
-				)
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.mockSqlQueryArgsExpected...).
+				WillReturnRows(tt.mockRowsReturned)
 
-			actual, err := repo.GetUserChatSessions(context.Background(), tt.args.uuid)
+			actual, err := repo.GetUserChatSessionSummaries(context.Background(), tt.args.userID, tt.args.filter)
 			if err != nil {
-				t.Errorf("GetChatSession() error = %v", err)
+				t.Errorf("GetUserChatSessionSummaries() error = %v", err)
 				return
 			}
 
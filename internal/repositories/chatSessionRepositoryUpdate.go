@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"gorm.io/gorm"
+	"time"
 )
 
 func (repo *ChatSessionRepository) UpdateChatSessionTitle(
@@ -25,3 +26,27 @@ func (repo *ChatSessionRepository) UpdateChatSessionTitle(
 
 	return err
 }
+
+// UpdateChatSessionArchivedAt archives the chat session at archivedAt, or
+// unarchives it for nil. Archiving is not an update of the conversation, so
+// updated_at is left as it is.
+func (repo *ChatSessionRepository) UpdateChatSessionArchivedAt(
+	ctx context.Context,
+	uuid uuid.UUID,
+	archivedAt *time.Time,
+) error {
+	result := repo.db.WithContext(ctx).Model(&ChatSession{}).
+		Where("id = ?", uuid).
+		UpdateColumn("archived_at", archivedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("chatSessionID " + uuid.String() + " not found"),
+		}
+	}
+
+	return nil
+}
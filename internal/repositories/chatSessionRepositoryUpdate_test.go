@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestChatRepository_UpdateChatSessionTitle(t *testing.T) {
@@ -140,3 +141,84 @@ func TestChatRepository_UpdateChatSessionTitleWithError(t *testing.T) {
 		})
 	}
 }
+
+func TestChatRepository_UpdateChatSessionArchivedAt(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	archivedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		uuid       uuid.UUID
+		archivedAt *time.Time
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		mockSqlChatQueryExpected string
+		mockRowsAffected         int64
+		expectedError            error
+	}{
+		{
+			name: "archive",
+			args: args{
+				uuid:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				archivedAt: &archivedAt,
+			},
+			mockSqlChatQueryExpected: `UPDATE "chat_sessions" SET "archived_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`,
+			mockRowsAffected:         1,
+		},
+		{
+			name: "unarchive",
+			args: args{
+				uuid:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				archivedAt: nil,
+			},
+			mockSqlChatQueryExpected: `UPDATE "chat_sessions" SET "archived_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`,
+			mockRowsAffected:         1,
+		},
+		{
+			name: "chat session not found",
+			args: args{
+				uuid:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				archivedAt: &archivedAt,
+			},
+			mockSqlChatQueryExpected: `UPDATE "chat_sessions" SET "archived_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`,
+			mockRowsAffected:         0,
+			expectedError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chatSessionID 12345678-0000-0000-0000-000000000000 not found"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ChatSessionRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlChatQueryExpected)).
+				WithArgs(tt.args.archivedAt, tt.args.uuid).
+				WillReturnResult(sqlmock.NewResult(0, tt.mockRowsAffected))
+			mockDb.ExpectCommit()
+
+			actual := repo.UpdateChatSessionArchivedAt(
+				context.Background(),
+				tt.args.uuid,
+				tt.args.archivedAt,
+			)
+
+			assert.Equal(t, tt.expectedError, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type CuratedAnswer struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	MessageID      *uuid.UUID `gorm:"type:uuid;null;uniqueIndex"`
+	Question       string     `gorm:"type:text;not null"`
+	OriginalAnswer string     `gorm:"type:text;not null"`
+	Answer         string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:text;not null;index"`
+	VectorID       *string    `gorm:"type:text;null"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+	ReviewedAt     *time.Time `gorm:"null"`
+}
+
+func (curatedAnswer *CuratedAnswer) toDomain() *domain.CuratedAnswer {
+	domainCuratedAnswer := &domain.CuratedAnswer{
+		ID:             curatedAnswer.ID,
+		Question:       curatedAnswer.Question,
+		OriginalAnswer: curatedAnswer.OriginalAnswer,
+		Answer:         curatedAnswer.Answer,
+		Status:         curatedAnswer.Status,
+		CreatedAt:      curatedAnswer.CreatedAt,
+		ReviewedAt:     curatedAnswer.ReviewedAt,
+	}
+
+	if curatedAnswer.MessageID != nil {
+		domainCuratedAnswer.MessageID = *curatedAnswer.MessageID
+	}
+
+	if curatedAnswer.VectorID != nil {
+		domainCuratedAnswer.VectorID = *curatedAnswer.VectorID
+	}
+
+	return domainCuratedAnswer
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CuratedAnswerRepository struct {
+	db *gorm.DB
+}
+
+func NewCuratedAnswerRepository(db *gorm.DB) *CuratedAnswerRepository {
+	return &CuratedAnswerRepository{db: db}
+}
+
+// CreateCuratedAnswer stores a pending curated answer. A message has at most
+// one curated answer: a new correction replaces the previous one while it is
+// still pending, and is ignored once it has been reviewed.
+func (repo *CuratedAnswerRepository) CreateCuratedAnswer(
+	ctx context.Context,
+	curatedAnswer *domain.CuratedAnswer,
+) (uuid.UUID, error) {
+	var err error
+
+	modelCuratedAnswer := CuratedAnswer{
+		MessageID:      &curatedAnswer.MessageID,
+		Question:       curatedAnswer.Question,
+		OriginalAnswer: curatedAnswer.OriginalAnswer,
+		Answer:         curatedAnswer.Answer,
+		Status:         domain.CuratedAnswerStatusPending,
+	}
+
+	err = repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"question", "original_answer", "answer", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "curated_answers", Name: "status"}, Value: domain.CuratedAnswerStatusPending},
+			}},
+		}).
+		Create(&modelCuratedAnswer).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return modelCuratedAnswer.ID, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestCuratedAnswerRepository_CreateCuratedAnswer(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		curatedAnswer *domain.CuratedAnswer
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		mockSqlQueryExpected     string
+		mockInsertedIdReturned   uuid.UUID
+		expectedCuratedAnswerUid uuid.UUID
+	}{
+		{
+			name: "valid",
+			args: args{
+				curatedAnswer: &domain.CuratedAnswer{
+					MessageID:      uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Question:       "how fast are sailboats?",
+					OriginalAnswer: "Sailboats are 88% faster than Electric cars.",
+					Answer:         "Sailboats are 88% slower than Electric cars.",
+				},
+			},
+			mockSqlQueryExpected:     `INSERT INTO "curated_answers" ("message_id","question","original_answer","answer","status","vector_id","created_at","updated_at","reviewed_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) ON CONFLICT ("message_id") DO UPDATE SET "question"="excluded"."question","original_answer"="excluded"."original_answer","answer"="excluded"."answer","updated_at"="excluded"."updated_at" WHERE "curated_answers"."status" = $10  RETURNING "id"`,
+			mockInsertedIdReturned:   uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			expectedCuratedAnswerUid: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &CuratedAnswerRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(
+					tt.args.curatedAnswer.MessageID, tt.args.curatedAnswer.Question, tt.args.curatedAnswer.OriginalAnswer,
+					tt.args.curatedAnswer.Answer, domain.CuratedAnswerStatusPending, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), nil,
+					domain.CuratedAnswerStatusPending,
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedIdReturned))
+			mockDb.ExpectCommit()
+
+			actual, err := repo.CreateCuratedAnswer(context.Background(), tt.args.curatedAnswer)
+			if err != nil {
+				t.Errorf("CreateCuratedAnswer() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expectedCuratedAnswerUid, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func (repo *CuratedAnswerRepository) GetCuratedAnswer(
+	ctx context.Context,
+	uuid uuid.UUID,
+) (*domain.CuratedAnswer, error) {
+	var err error
+	var modelCuratedAnswer *CuratedAnswer
+
+	err = repo.db.WithContext(ctx).
+		Model(CuratedAnswer{}).
+		Where("id = ?", uuid).
+		Take(&modelCuratedAnswer).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.CuratedAnswer{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("curatedAnswerID " + uuid.String() + " not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.CuratedAnswer{}, err
+	}
+
+	return modelCuratedAnswer.toDomain(), nil
+}
+
+// GetCuratedAnswers returns the curated answers with the given status, or all
+// of them when the status is empty, oldest first.
+func (repo *CuratedAnswerRepository) GetCuratedAnswers(
+	ctx context.Context,
+	status string,
+) ([]*domain.CuratedAnswer, error) {
+	var modelCuratedAnswers []*CuratedAnswer
+
+	query := repo.db.WithContext(ctx).Model(CuratedAnswer{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("created_at").Find(&modelCuratedAnswers).Error
+	if err != nil {
+		return []*domain.CuratedAnswer{}, err
+	}
+
+	curatedAnswers := make([]*domain.CuratedAnswer, len(modelCuratedAnswers))
+	for i, modelCuratedAnswer := range modelCuratedAnswers {
+		curatedAnswers[i] = modelCuratedAnswer.toDomain()
+	}
+
+	return curatedAnswers, nil
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCuratedAnswerRepository_GetCuratedAnswer(t *testing.T) {
+	vectorID := "curated-42345678-0000-0000-0000-000000000000"
+	messageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	reviewedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		uuid uuid.UUID
+	}
+	tests := []struct {
+		name                      string
+		args                      args
+		mockSqlQueryExpected      string
+		mockCuratedAnswerReturned *CuratedAnswer
+		expected                  *domain.CuratedAnswer
+	}{
+		{
+			name: "valid",
+			args: args{
+				uuid: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+			mockSqlQueryExpected: `SELECT * FROM "curated_answers" WHERE id = $1 LIMIT $2`,
+			mockCuratedAnswerReturned: &CuratedAnswer{
+				ID:             uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				MessageID:      &messageID,
+				Question:       "how fast are sailboats?",
+				OriginalAnswer: "Sailboats are 88% faster than Electric cars.",
+				Answer:         "Sailboats are 88% slower than Electric cars.",
+				Status:         "approved",
+				VectorID:       &vectorID,
+				CreatedAt:      time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				ReviewedAt:     &reviewedAt,
+			},
+			expected: &domain.CuratedAnswer{
+				ID:             uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				MessageID:      uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				Question:       "how fast are sailboats?",
+				OriginalAnswer: "Sailboats are 88% faster than Electric cars.",
+				Answer:         "Sailboats are 88% slower than Electric cars.",
+				Status:         "approved",
+				VectorID:       vectorID,
+				CreatedAt:      time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				ReviewedAt:     &reviewedAt,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &CuratedAnswerRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.args.uuid, 1).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "message_id", "question", "original_answer", "answer", "status", "vector_id", "created_at", "reviewed_at"},
+					).AddRow(
+						tt.mockCuratedAnswerReturned.ID, tt.mockCuratedAnswerReturned.MessageID, tt.mockCuratedAnswerReturned.Question,
+						tt.mockCuratedAnswerReturned.OriginalAnswer, tt.mockCuratedAnswerReturned.Answer, tt.mockCuratedAnswerReturned.Status,
+						tt.mockCuratedAnswerReturned.VectorID, tt.mockCuratedAnswerReturned.CreatedAt, tt.mockCuratedAnswerReturned.ReviewedAt,
+					))
+
+			actual, err := repo.GetCuratedAnswer(context.Background(), tt.args.uuid)
+			if err != nil {
+				t.Errorf("GetCuratedAnswer() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestCuratedAnswerRepository_GetCuratedAnswerHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &CuratedAnswerRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "curated_answers" WHERE id = $1 LIMIT $2`)).
+		WithArgs(uuid.UUID{0x42, 0x34, 0x56, 0x78}, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err = repo.GetCuratedAnswer(context.Background(), uuid.UUID{0x42, 0x34, 0x56, 0x78})
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestCuratedAnswerRepository_GetCuratedAnswers(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name                 string
+		status               string
+		mockSqlQueryExpected string
+		mockQueryArgs        []driver.Value
+		expected             []*domain.CuratedAnswer
+	}{
+		{
+			name:                 "pending",
+			status:               "pending",
+			mockSqlQueryExpected: `SELECT * FROM "curated_answers" WHERE status = $1 ORDER BY created_at`,
+			mockQueryArgs:        []driver.Value{"pending"},
+			expected: []*domain.CuratedAnswer{
+				{
+					ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+					MessageID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Question:  "how fast are sailboats?",
+					Answer:    "Sailboats are 88% slower than Electric cars.",
+					Status:    "pending",
+					CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:                 "all",
+			status:               "",
+			mockSqlQueryExpected: `SELECT * FROM "curated_answers" ORDER BY created_at`,
+			mockQueryArgs:        []driver.Value{},
+			expected: []*domain.CuratedAnswer{
+				{
+					ID:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+					MessageID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Question:  "how fast are sailboats?",
+					Answer:    "Sailboats are 88% slower than Electric cars.",
+					Status:    "rejected",
+					CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &CuratedAnswerRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.mockQueryArgs...).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "message_id", "question", "answer", "status", "created_at"},
+					).AddRow(
+						tt.expected[0].ID, tt.expected[0].MessageID, tt.expected[0].Question,
+						tt.expected[0].Answer, tt.expected[0].Status, tt.expected[0].CreatedAt,
+					))
+
+			actual, err := repo.GetCuratedAnswers(context.Background(), tt.status)
+			if err != nil {
+				t.Errorf("GetCuratedAnswers() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"time"
+)
+
+// UpdateCuratedAnswerStatus records the review of a pending curated answer and
+// the ID of its chunk in the vector store, if it was ingested.
+func (repo *CuratedAnswerRepository) UpdateCuratedAnswerStatus(
+	ctx context.Context,
+	uuid uuid.UUID,
+	status string,
+	vectorID string,
+) error {
+	result := repo.db.WithContext(ctx).
+		Model(&CuratedAnswer{}).
+		Where("id = ?", uuid).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"vector_id":   nullableString(vectorID),
+			"reviewed_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("curatedAnswerID " + uuid.String() + " not found"),
+		}
+	}
+
+	return nil
+}
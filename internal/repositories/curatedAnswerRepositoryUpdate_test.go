@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestCuratedAnswerRepository_UpdateCuratedAnswerStatus(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		uuid     uuid.UUID
+		status   string
+		vectorID string
+	}
+	tests := []struct {
+		name                 string
+		args                 args
+		mockSqlQueryExpected string
+		expectedQueryArgs    []driver.Value
+	}{
+		{
+			name: "approved",
+			args: args{
+				uuid:     uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				status:   "approved",
+				vectorID: "curated-42345678-0000-0000-0000-000000000000",
+			},
+			mockSqlQueryExpected: `UPDATE "curated_answers" SET "reviewed_at"=$1,"status"=$2,"vector_id"=$3,"updated_at"=$4 WHERE id = $5`,
+			expectedQueryArgs: []driver.Value{
+				sqlmock.AnyArg(), "approved", "curated-42345678-0000-0000-0000-000000000000", sqlmock.AnyArg(), uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+		},
+		{
+			name: "rejected",
+			args: args{
+				uuid:   uuid.UUID{0x42, 0x34, 0x56, 0x78},
+				status: "rejected",
+			},
+			mockSqlQueryExpected: `UPDATE "curated_answers" SET "reviewed_at"=$1,"status"=$2,"vector_id"=$3,"updated_at"=$4 WHERE id = $5`,
+			expectedQueryArgs: []driver.Value{
+				sqlmock.AnyArg(), "rejected", nil, sqlmock.AnyArg(), uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &CuratedAnswerRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.expectedQueryArgs...).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDb.ExpectCommit()
+
+			err := repo.UpdateCuratedAnswerStatus(context.Background(), tt.args.uuid, tt.args.status, tt.args.vectorID)
+
+			assert.Nil(t, err)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestCuratedAnswerRepository_UpdateCuratedAnswerStatusHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &CuratedAnswerRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "curated_answers" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDb.ExpectCommit()
+
+	err = repo.UpdateCuratedAnswerStatus(context.Background(), uuid.UUID{0x42, 0x34, 0x56, 0x78}, "rejected", "")
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
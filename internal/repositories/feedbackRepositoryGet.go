@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type FeedbackRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedbackRepository(db *gorm.DB) *FeedbackRepository {
+	return &FeedbackRepository{db: db}
+}
+
+type feedbackAnalyticsRow struct {
+	Period   time.Time
+	Answers  int
+	Feedback int
+}
+
+type feedbackQuestionRow struct {
+	MessageID uuid.UUID
+	Question  string
+}
+
+// liveChatSessionsJoin leaves out the messages of soft deleted chat sessions
+const liveChatSessionsJoin = "JOIN chat_sessions ON chat_sessions.id = messages.chat_session_id AND chat_sessions.deleted_at IS NULL"
+
+// GetFeedbackAnalytics counts the SYSTEM answers created in [from, to) and how
+// many of them received feedback, grouped by the interval (day, week or month).
+// The answers of deleted chat sessions are not counted.
+func (repo *FeedbackRepository) GetFeedbackAnalytics(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	interval string,
+) ([]*domain.FeedbackAnalytics, error) {
+	var rows []*feedbackAnalyticsRow
+
+	err := repo.db.WithContext(ctx).
+		Model(&Message{}).
+		Select("date_trunc(?, messages.created_at) AS period, COUNT(*) AS answers, COUNT(messages.feedback_submitted_at) AS feedback", interval).
+		Joins(liveChatSessionsJoin).
+		Where("messages.sender = ? AND messages.created_at >= ? AND messages.created_at < ?", SYSTEM_SENDER, from, to).
+		Group("period").
+		Order("period").
+		Scan(&rows).Error
+	if err != nil {
+		return []*domain.FeedbackAnalytics{}, err
+	}
+
+	analytics := make([]*domain.FeedbackAnalytics, len(rows))
+	for i, row := range rows {
+		analytics[i] = &domain.FeedbackAnalytics{
+			Period:   row.Period,
+			Answers:  row.Answers,
+			Feedback: row.Feedback,
+		}
+	}
+
+	return analytics, nil
+}
+
+// GetFeedbackEntries returns the SYSTEM answers created in [from, to) that
+// received feedback, with their sources and the USER message they answered.
+// The answers of deleted chat sessions are left out.
+func (repo *FeedbackRepository) GetFeedbackEntries(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*domain.FeedbackEntry, error) {
+	var modelMessages []*Message
+
+	err := repo.db.WithContext(ctx).
+		Preload("Sources", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank")
+		}).
+		Joins(liveChatSessionsJoin).
+		Where("messages.sender = ? AND messages.feedback_submitted_at IS NOT NULL AND messages.created_at >= ? AND messages.created_at < ?", SYSTEM_SENDER, from, to).
+		Order("messages.created_at").
+		Find(&modelMessages).Error
+	if err != nil {
+		return []*domain.FeedbackEntry{}, err
+	}
+
+	if len(modelMessages) == 0 {
+		return []*domain.FeedbackEntry{}, nil
+	}
+
+	messageIDs := make([]uuid.UUID, len(modelMessages))
+	for i, modelMessage := range modelMessages {
+		messageIDs[i] = modelMessage.ID
+	}
+
+	// the question of an answer is the latest USER message of the session
+	// that was sent before it
+	var questionRows []*feedbackQuestionRow
+	err = repo.db.WithContext(ctx).Raw(
+		`SELECT DISTINCT ON (answers.id) answers.id AS message_id, questions.content AS question
+		FROM messages answers
+		JOIN messages questions ON questions.chat_session_id = answers.chat_session_id
+			AND questions.sender = ? AND questions.created_at <= answers.created_at
+		WHERE answers.id IN ?
+		ORDER BY answers.id, questions.created_at DESC`,
+		USER_SENDER,
+		messageIDs,
+	).Scan(&questionRows).Error
+	if err != nil {
+		return []*domain.FeedbackEntry{}, err
+	}
+
+	questions := make(map[uuid.UUID]string, len(questionRows))
+	for _, questionRow := range questionRows {
+		questions[questionRow.MessageID] = questionRow.Question
+	}
+
+	entries := make([]*domain.FeedbackEntry, len(modelMessages))
+	for i, modelMessage := range modelMessages {
+		sources := make([]*domain.RetrievedChunk, len(modelMessage.Sources))
+		for j, source := range modelMessage.Sources {
+			sources[j] = &domain.RetrievedChunk{
+				ID:    source.ChunkID,
+				Text:  source.Content,
+				Score: source.Score,
+			}
+		}
+
+		entries[i] = &domain.FeedbackEntry{
+			MessageID:     modelMessage.ID,
+			ChatSessionID: modelMessage.ChatSessionID,
+			Question:      questions[modelMessage.ID],
+			Context:       sources,
+			Answer:        modelMessage.Content,
+			Feedback:      modelMessage.domainFeedback(),
+			CreatedAt:     modelMessage.CreatedAt,
+		}
+	}
+
+	return entries, nil
+}
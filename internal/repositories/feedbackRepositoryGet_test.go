@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFeedbackRepository_GetFeedbackAnalytics(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		from     time.Time
+		to       time.Time
+		interval string
+	}
+	tests := []struct {
+		name                 string
+		args                 args
+		mockSqlQueryExpected string
+		expected             []*domain.FeedbackAnalytics
+	}{
+		{
+			name: "valid",
+			args: args{
+				from:     time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+				to:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				interval: "day",
+			},
+			mockSqlQueryExpected: `SELECT date_trunc($1, messages.created_at) AS period, COUNT(*) AS answers, COUNT(messages.feedback_submitted_at) AS feedback FROM "messages" JOIN chat_sessions ON chat_sessions.id = messages.chat_session_id AND chat_sessions.deleted_at IS NULL WHERE messages.sender = $2 AND messages.created_at >= $3 AND messages.created_at < $4 GROUP BY "period" ORDER BY period`,
+			expected: []*domain.FeedbackAnalytics{
+				{
+					Period:   time.Date(2025, 5, 28, 0, 0, 0, 0, time.UTC),
+					Answers:  10,
+					Feedback: 2,
+				},
+				{
+					Period:   time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC),
+					Answers:  4,
+					Feedback: 0,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FeedbackRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.args.interval, SYSTEM_SENDER, tt.args.from, tt.args.to).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"period", "answers", "feedback"},
+					).AddRow(
+						tt.expected[0].Period, tt.expected[0].Answers, tt.expected[0].Feedback,
+					).AddRow(
+						tt.expected[1].Period, tt.expected[1].Answers, tt.expected[1].Feedback,
+					),
+				)
+
+			actual, err := repo.GetFeedbackAnalytics(context.Background(), tt.args.from, tt.args.to, tt.args.interval)
+			if err != nil {
+				t.Errorf("GetFeedbackAnalytics() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestFeedbackRepository_GetFeedbackEntries(t *testing.T) {
+	thumb := "down"
+	reason := "wrong"
+	comment := "wrong numbers"
+	submittedAt := time.Date(2025, 5, 29, 10, 5, 0, 0, time.UTC)
+
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		from time.Time
+		to   time.Time
+	}
+	tests := []struct {
+		name                          string
+		args                          args
+		mockSqlMessagesQueryExpected  string
+		mockSqlSourcesQueryExpected   string
+		mockSqlQuestionsQueryExpected string
+		mockMessageReturned           *Message
+		mockSourcesReturned           []*MessageSource
+		expected                      []*domain.FeedbackEntry
+	}{
+		{
+			name: "valid",
+			args: args{
+				from: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+				to:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+			mockSqlMessagesQueryExpected:  `SELECT "messages"."id","messages"."chat_session_id","messages"."sender","messages"."content","messages"."created_at","messages"."feedback","messages"."feedback_thumb","messages"."feedback_rating","messages"."feedback_reason","messages"."feedback_corrected_answer","messages"."feedback_submitted_at" FROM "messages" JOIN chat_sessions ON chat_sessions.id = messages.chat_session_id AND chat_sessions.deleted_at IS NULL WHERE messages.sender = $1 AND messages.feedback_submitted_at IS NOT NULL AND messages.created_at >= $2 AND messages.created_at < $3 ORDER BY messages.created_at`,
+			mockSqlSourcesQueryExpected:   `SELECT * FROM "message_sources" WHERE "message_sources"."message_id" = $1 ORDER BY rank`,
+			mockSqlQuestionsQueryExpected: `SELECT DISTINCT ON (answers.id) answers.id AS message_id, questions.content AS question`,
+			mockMessageReturned: &Message{
+				ID:                  uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				ChatSessionID:       uuid.UUID{0x22, 0x34, 0x56, 0x78},
+				Sender:              "SYSTEM",
+				Content:             "Sailboats are 88% faster than Electric cars.",
+				CreatedAt:           time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				Feedback:            &comment,
+				FeedbackThumb:       &thumb,
+				FeedbackReason:      &reason,
+				FeedbackSubmittedAt: &submittedAt,
+			},
+			mockSourcesReturned: []*MessageSource{
+				{
+					ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+					MessageID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Rank:      1,
+					ChunkID:   "doc1-chunk-0",
+					Content:   "Sailboats are 88% slow than Electric cars.",
+					Score:     0.8,
+				},
+			},
+			expected: []*domain.FeedbackEntry{
+				{
+					MessageID:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
+					Question:      "how fast are sailboats?",
+					Context: []*domain.RetrievedChunk{
+						{
+							ID:    "doc1-chunk-0",
+							Text:  "Sailboats are 88% slow than Electric cars.",
+							Score: 0.8,
+						},
+					},
+					Answer: "Sailboats are 88% faster than Electric cars.",
+					Feedback: &domain.MessageFeedback{
+						Thumb:       thumb,
+						Reason:      reason,
+						Comment:     comment,
+						SubmittedAt: submittedAt,
+					},
+					CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FeedbackRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessagesQueryExpected)).
+				WithArgs(SYSTEM_SENDER, tt.args.from, tt.args.to).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "chat_session_id", "sender", "content", "created_at", "feedback", "feedback_thumb", "feedback_reason", "feedback_submitted_at"},
+					).AddRow(
+						tt.mockMessageReturned.ID, tt.mockMessageReturned.ChatSessionID, tt.mockMessageReturned.Sender,
+						tt.mockMessageReturned.Content, tt.mockMessageReturned.CreatedAt, tt.mockMessageReturned.Feedback,
+						tt.mockMessageReturned.FeedbackThumb, tt.mockMessageReturned.FeedbackReason, tt.mockMessageReturned.FeedbackSubmittedAt,
+					),
+				)
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlSourcesQueryExpected)).
+				WithArgs(tt.mockMessageReturned.ID).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "message_id", "rank", "chunk_id", "content", "score"},
+					).AddRow(
+						tt.mockSourcesReturned[0].ID, tt.mockSourcesReturned[0].MessageID, tt.mockSourcesReturned[0].Rank,
+						tt.mockSourcesReturned[0].ChunkID, tt.mockSourcesReturned[0].Content, tt.mockSourcesReturned[0].Score,
+					),
+				)
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQuestionsQueryExpected)).
+				WithArgs(USER_SENDER, tt.mockMessageReturned.ID).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"message_id", "question"},
+					).AddRow(
+						tt.mockMessageReturned.ID, tt.expected[0].Question,
+					),
+				)
+
+			actual, err := repo.GetFeedbackEntries(context.Background(), tt.args.from, tt.args.to)
+			if err != nil {
+				t.Errorf("GetFeedbackEntries() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -28,7 +28,37 @@ func (repo *MessageRepository) CreateMessage(
 		CreatedAt:     chat.CreatedAt,
 	}
 
-	err = repo.db.WithContext(ctx).Create(&modelChat).Error
+	for i, source := range chat.Sources {
+		modelChat.Sources = append(modelChat.Sources, MessageSource{
+			Rank:    i + 1,
+			ChunkID: source.ID,
+			Content: source.Text,
+			Score:   source.Score,
+		})
+	}
+
+	for i, toolCall := range chat.ToolCalls {
+		modelChat.ToolCalls = append(modelChat.ToolCalls, MessageToolCall{
+			Rank:      i + 1,
+			Step:      toolCall.Step,
+			Name:      toolCall.Name,
+			Arguments: toolCall.Arguments,
+			Result:    toolCall.Result,
+			IsError:   toolCall.IsError,
+		})
+	}
+
+	err = repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&modelChat).Error
+		if err != nil {
+			return err
+		}
+
+		// a new message updates its chat session, that the sessions are listed by
+		return tx.Model(&ChatSession{}).
+			Where("id = ?", modelChat.ChatSessionID).
+			UpdateColumn("updated_at", modelChat.CreatedAt).Error
+	})
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -43,7 +43,7 @@ func TestChatRepository_CreateMessage(t *testing.T) {
 					Feedback:      nil,
 				},
 			},
-			mockSqlMessageQueryExpected:   `INSERT INTO "messages" ("chat_session_id","sender","content","created_at","feedback") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+			mockSqlMessageQueryExpected:   `INSERT INTO "messages" ("chat_session_id","sender","content","created_at","feedback","feedback_thumb","feedback_rating","feedback_reason","feedback_corrected_answer","feedback_submitted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`,
 			mockInsertedMessageIdReturned: uuid.UUID{0x42, 0x34, 0x56, 0x78},
 			expectedMessageUid:            uuid.UUID{0x42, 0x34, 0x56, 0x78},
 		},
@@ -59,9 +59,173 @@ func TestChatRepository_CreateMessage(t *testing.T) {
 
 			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessageQueryExpected)).
 				WithArgs(
-					tt.args.message.ChatSessionID, tt.args.message.Sender, tt.args.message.Content, sqlmock.AnyArg(), tt.args.message.Feedback,
+					tt.args.message.ChatSessionID, tt.args.message.Sender, tt.args.message.Content, sqlmock.AnyArg(), nil, nil, nil, nil, nil, nil,
 				).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedMessageIdReturned))
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "chat_sessions" SET "updated_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`)).
+				WithArgs(sqlmock.AnyArg(), tt.args.message.ChatSessionID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDb.ExpectCommit()
+
+			actual, err := repo.CreateMessage(context.Background(), tt.args.message)
+			if err != nil {
+				t.Errorf("CreateMessage() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expectedMessageUid, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestChatRepository_CreateMessageWithSources(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		message *domain.Message
+	}
+	tests := []struct {
+		name                          string
+		args                          args
+		mockSqlMessageQueryExpected   string
+		mockSqlSourcesQueryExpected   string
+		mockInsertedMessageIdReturned uuid.UUID
+		expectedMessageUid            uuid.UUID
+	}{
+		{
+			name: "valid",
+			args: args{
+				message: &domain.Message{
+					ChatSessionID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Sender:        "SYSTEM",
+					Content:       "ablaabla",
+					CreatedAt:     time.Time{},
+					Sources: []*domain.RetrievedChunk{
+						{ID: "doc1-chunk-3", Text: "Sailboats are 88% slow than Electric cars.", Score: 0.8},
+						{ID: "doc1-chunk-5", Text: "Sedans are 101% affordable than Pickup trucks.", Score: 0.5},
+					},
+				},
+			},
+			mockSqlMessageQueryExpected:   `INSERT INTO "messages" ("chat_session_id","sender","content","created_at","feedback","feedback_thumb","feedback_rating","feedback_reason","feedback_corrected_answer","feedback_submitted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`,
+			mockSqlSourcesQueryExpected:   `INSERT INTO "message_sources" ("message_id","rank","chunk_id","content","score") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) ON CONFLICT ("id") DO UPDATE SET "message_id"="excluded"."message_id" RETURNING "id"`,
+			mockInsertedMessageIdReturned: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			expectedMessageUid:            uuid.UUID{0x42, 0x34, 0x56, 0x78},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MessageRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessageQueryExpected)).
+				WithArgs(
+					tt.args.message.ChatSessionID, tt.args.message.Sender, tt.args.message.Content, sqlmock.AnyArg(), nil, nil, nil, nil, nil, nil,
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedMessageIdReturned))
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlSourcesQueryExpected)).
+				WithArgs(
+					tt.mockInsertedMessageIdReturned, 1, tt.args.message.Sources[0].ID, tt.args.message.Sources[0].Text, tt.args.message.Sources[0].Score,
+					tt.mockInsertedMessageIdReturned, 2, tt.args.message.Sources[1].ID, tt.args.message.Sources[1].Text, tt.args.message.Sources[1].Score,
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "chat_sessions" SET "updated_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`)).
+				WithArgs(sqlmock.AnyArg(), tt.args.message.ChatSessionID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mockDb.ExpectCommit()
+
+			actual, err := repo.CreateMessage(context.Background(), tt.args.message)
+			if err != nil {
+				t.Errorf("CreateMessage() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expectedMessageUid, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestChatRepository_CreateMessageWithToolCalls(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		message *domain.Message
+	}
+	tests := []struct {
+		name                          string
+		args                          args
+		mockSqlMessageQueryExpected   string
+		mockSqlToolCallsQueryExpected string
+		mockInsertedMessageIdReturned uuid.UUID
+		expectedMessageUid            uuid.UUID
+	}{
+		{
+			name: "valid",
+			args: args{
+				message: &domain.Message{
+					ChatSessionID: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					Sender:        "SYSTEM",
+					Content:       "ablaabla",
+					CreatedAt:     time.Time{},
+					ToolCalls: []*domain.ToolCall{
+						{Step: 1, Name: "search_knowledge_base", Arguments: `{"query":"sailboats"}`, Result: `{"hits":[]}`},
+						{Step: 2, Name: "calculate", Arguments: `{"operation":"divide","a":1,"b":0}`, Result: "division by zero", IsError: true},
+					},
+				},
+			},
+			mockSqlMessageQueryExpected:   `INSERT INTO "messages" ("chat_session_id","sender","content","created_at","feedback","feedback_thumb","feedback_rating","feedback_reason","feedback_corrected_answer","feedback_submitted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`,
+			mockSqlToolCallsQueryExpected: `INSERT INTO "message_tool_calls" ("message_id","rank","step","name","arguments","result","is_error") VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14) ON CONFLICT ("id") DO UPDATE SET "message_id"="excluded"."message_id" RETURNING "id"`,
+			mockInsertedMessageIdReturned: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			expectedMessageUid:            uuid.UUID{0x42, 0x34, 0x56, 0x78},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MessageRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessageQueryExpected)).
+				WithArgs(
+					tt.args.message.ChatSessionID, tt.args.message.Sender, tt.args.message.Content, sqlmock.AnyArg(), nil, nil, nil, nil, nil, nil,
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedMessageIdReturned))
+
+			toolCalls := tt.args.message.ToolCalls
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlToolCallsQueryExpected)).
+				WithArgs(
+					tt.mockInsertedMessageIdReturned, 1, toolCalls[0].Step, toolCalls[0].Name, toolCalls[0].Arguments, toolCalls[0].Result, toolCalls[0].IsError,
+					tt.mockInsertedMessageIdReturned, 2, toolCalls[1].Step, toolCalls[1].Name, toolCalls[1].Arguments, toolCalls[1].Result, toolCalls[1].IsError,
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "chat_sessions" SET "updated_at"=$1 WHERE id = $2 AND "chat_sessions"."deleted_at" IS NULL`)).
+				WithArgs(sqlmock.AnyArg(), tt.args.message.ChatSessionID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
 			mockDb.ExpectCommit()
 
 			actual, err := repo.CreateMessage(context.Background(), tt.args.message)
@@ -37,5 +37,85 @@ func (repo *MessageRepository) GetMessage(
 		Sender:        modelMessage.Sender,
 		Content:       modelMessage.Content,
 		CreatedAt:     modelMessage.CreatedAt,
+		Feedback:      modelMessage.domainFeedback(),
 	}, err
 }
+
+// GetChatSessionMessages returns a page of the messages of the chat session,
+// oldest first. Messages are ordered by created_at and then by id, so that the
+// ones created at the same time keep their order across pages.
+func (repo *MessageRepository) GetChatSessionMessages(
+	ctx context.Context,
+	chatSessionID uuid.UUID,
+	filter *domain.MessageFilter,
+) ([]*domain.Message, error) {
+	query := repo.db.WithContext(ctx).
+		Preload("ToolCalls", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank")
+		}).
+		Model(Message{}).
+		Where("chat_session_id = ?", chatSessionID)
+
+	cursorID := filter.Before
+	if filter.After != nil {
+		cursorID = filter.After
+	}
+
+	if cursorID != nil {
+		var cursor *Message
+
+		err := repo.db.WithContext(ctx).
+			Model(Message{}).
+			Select("id", "created_at").
+			Where("id = ? AND chat_session_id = ?", *cursorID, chatSessionID).
+			Take(&cursor).Error
+		if err == gorm.ErrRecordNotFound {
+			return []*domain.Message{}, customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("messageID " + cursorID.String() + " not found in chatSession " + chatSessionID.String()),
+			}
+		}
+		if err != nil {
+			return []*domain.Message{}, err
+		}
+
+		if filter.After != nil {
+			query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+		} else {
+			query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+	}
+
+	// the page before a message, or the last one, is the newest messages of
+	// what is left, that are put back oldest first below
+	if filter.After != nil {
+		query = query.Order("created_at, id")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	var modelMessages []*Message
+	err := query.Limit(filter.Limit).Find(&modelMessages).Error
+	if err != nil {
+		return []*domain.Message{}, err
+	}
+
+	messages := make([]*domain.Message, len(modelMessages))
+	for i, modelMessage := range modelMessages {
+		position := i
+		if filter.After == nil {
+			position = len(modelMessages) - 1 - i
+		}
+
+		messages[position] = &domain.Message{
+			ID:            modelMessage.ID,
+			ChatSessionID: modelMessage.ChatSessionID,
+			Sender:        modelMessage.Sender,
+			Content:       modelMessage.Content,
+			CreatedAt:     modelMessage.CreatedAt,
+			Feedback:      modelMessage.domainFeedback(),
+			ToolCalls:     modelMessage.domainToolCalls(),
+		}
+	}
+
+	return messages, nil
+}
@@ -2,17 +2,25 @@ package repositories
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestChatRepository_GetMessage(t *testing.T) {
+	comment := "ablabla"
+	rating := 2
+	submittedAt := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
+
 	db, mockDb, err := sqlmock.New()
 	if err != nil {
 		t.Error(err.Error())
@@ -38,16 +46,24 @@ func TestChatRepository_GetMessage(t *testing.T) {
 			},
 			mockSqlMessageQueryExpected: `SELECT * FROM "messages" WHERE id = $1 LIMIT $2`,
 			mockMessageReturned: &Message{
-				ID:            uuid.UUID{0x12, 0x34, 0x56, 0x78},
-				ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
-				Sender:        "user1",
-				Content:       "Hello, world!",
+				ID:                  uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				ChatSessionID:       uuid.UUID{0x22, 0x34, 0x56, 0x78},
+				Sender:              "user1",
+				Content:             "Hello, world!",
+				Feedback:            &comment,
+				FeedbackRating:      &rating,
+				FeedbackSubmittedAt: &submittedAt,
 			},
 			expected: &domain.Message{
 				ID:            uuid.UUID{0x12, 0x34, 0x56, 0x78},
 				ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
 				Sender:        "user1",
 				Content:       "Hello, world!",
+				Feedback: &domain.MessageFeedback{
+					Rating:      rating,
+					Comment:     comment,
+					SubmittedAt: submittedAt,
+				},
 			},
 		},
 	}
@@ -61,10 +77,11 @@ func TestChatRepository_GetMessage(t *testing.T) {
 				WithArgs(tt.args.uuid, 1).
 				WillReturnRows(
 					sqlmock.NewRows(
-						[]string{"id", "chat_session_id", "sender", "created_at", "content"},
+						[]string{"id", "chat_session_id", "sender", "created_at", "content", "feedback", "feedback_rating", "feedback_submitted_at"},
 					).AddRow(
 						tt.mockMessageReturned.ID, tt.mockMessageReturned.ChatSessionID,
 						tt.mockMessageReturned.Sender, tt.mockMessageReturned.CreatedAt, tt.mockMessageReturned.Content,
+						tt.mockMessageReturned.Feedback, tt.mockMessageReturned.FeedbackRating, tt.mockMessageReturned.FeedbackSubmittedAt,
 					))
 
 			actual, err := repo.GetMessage(context.Background(), tt.args.uuid)
@@ -81,3 +98,138 @@ func TestChatRepository_GetMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestChatRepository_GetChatSessionMessages(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	chatSessionID := uuid.UUID{0x22, 0x34, 0x56, 0x78}
+	cursorID := uuid.UUID{0x02, 0x34, 0x56, 0x78}
+	cursorCreatedAt := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
+	olderCreatedAt := time.Date(2025, 5, 29, 10, 1, 0, 0, time.UTC)
+	newerCreatedAt := time.Date(2025, 5, 29, 10, 2, 0, 0, time.UTC)
+
+	type args struct {
+		filter *domain.MessageFilter
+	}
+	tests := []struct {
+		name                         string
+		args                         args
+		mockSqlCursorQueryExpected   string
+		mockCursorFound              bool
+		mockSqlMessagesQueryExpected string
+		mockSqlMessagesArgsExpected  []driver.Value
+		mockMessagesReturned         []*Message
+		expected                     []*domain.Message
+		expectedError                error
+	}{
+		{
+			name: "last messages",
+			args: args{
+				filter: &domain.MessageFilter{Limit: 3},
+			},
+			mockSqlMessagesQueryExpected: `SELECT * FROM "messages" WHERE chat_session_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`,
+			mockSqlMessagesArgsExpected:  []driver.Value{chatSessionID, 3},
+			mockMessagesReturned: []*Message{
+				{ID: uuid.UUID{0x42, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "SYSTEM", Content: "answer", CreatedAt: newerCreatedAt},
+				{ID: uuid.UUID{0x32, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "USER", Content: "question", CreatedAt: olderCreatedAt},
+			},
+			expected: []*domain.Message{
+				{ID: uuid.UUID{0x32, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "USER", Content: "question", CreatedAt: olderCreatedAt},
+				{ID: uuid.UUID{0x42, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "SYSTEM", Content: "answer", CreatedAt: newerCreatedAt},
+			},
+		},
+		{
+			name: "messages after cursor",
+			args: args{
+				filter: &domain.MessageFilter{After: &cursorID, Limit: 2},
+			},
+			mockSqlCursorQueryExpected:   `SELECT "id","created_at" FROM "messages" WHERE id = $1 AND chat_session_id = $2 LIMIT $3`,
+			mockCursorFound:              true,
+			mockSqlMessagesQueryExpected: `SELECT * FROM "messages" WHERE chat_session_id = $1 AND (created_at, id) > ($2, $3) ORDER BY created_at, id LIMIT $4`,
+			mockSqlMessagesArgsExpected:  []driver.Value{chatSessionID, cursorCreatedAt, cursorID, 2},
+			mockMessagesReturned: []*Message{
+				{ID: uuid.UUID{0x32, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "USER", Content: "question", CreatedAt: olderCreatedAt},
+				{ID: uuid.UUID{0x42, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "SYSTEM", Content: "answer", CreatedAt: newerCreatedAt},
+			},
+			expected: []*domain.Message{
+				{ID: uuid.UUID{0x32, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "USER", Content: "question", CreatedAt: olderCreatedAt},
+				{ID: uuid.UUID{0x42, 0x34, 0x56, 0x78}, ChatSessionID: chatSessionID, Sender: "SYSTEM", Content: "answer", CreatedAt: newerCreatedAt},
+			},
+		},
+		{
+			name: "messages before cursor",
+			args: args{
+				filter: &domain.MessageFilter{Before: &cursorID, Limit: 2},
+			},
+			mockSqlCursorQueryExpected:   `SELECT "id","created_at" FROM "messages" WHERE id = $1 AND chat_session_id = $2 LIMIT $3`,
+			mockCursorFound:              true,
+			mockSqlMessagesQueryExpected: `SELECT * FROM "messages" WHERE chat_session_id = $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4`,
+			mockSqlMessagesArgsExpected:  []driver.Value{chatSessionID, cursorCreatedAt, cursorID, 2},
+			mockMessagesReturned:         []*Message{},
+			expected:                     []*domain.Message{},
+		},
+		{
+			name: "cursor not found",
+			args: args{
+				filter: &domain.MessageFilter{Before: &cursorID, Limit: 2},
+			},
+			mockSqlCursorQueryExpected: `SELECT "id","created_at" FROM "messages" WHERE id = $1 AND chat_session_id = $2 LIMIT $3`,
+			expected:                   []*domain.Message{},
+			expectedError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("messageID " + cursorID.String() + " not found in chatSession " + chatSessionID.String()),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MessageRepository{
+				db: gormDb,
+			}
+
+			if tt.mockSqlCursorQueryExpected != "" {
+				cursorRows := sqlmock.NewRows([]string{"id", "created_at"})
+				if tt.mockCursorFound {
+					cursorRows.AddRow(cursorID, cursorCreatedAt)
+				}
+
+				mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlCursorQueryExpected)).
+					WithArgs(cursorID, chatSessionID, 1).
+					WillReturnRows(cursorRows)
+			}
+
+			if tt.mockSqlMessagesQueryExpected != "" {
+				messageRows := sqlmock.NewRows([]string{"id", "chat_session_id", "sender", "content", "created_at"})
+				messageIDs := make([]driver.Value, len(tt.mockMessagesReturned))
+				for i, message := range tt.mockMessagesReturned {
+					messageRows.AddRow(message.ID, message.ChatSessionID, message.Sender, message.Content, message.CreatedAt)
+					messageIDs[i] = message.ID
+				}
+
+				mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlMessagesQueryExpected)).
+					WithArgs(tt.mockSqlMessagesArgsExpected...).
+					WillReturnRows(messageRows)
+
+				if len(tt.mockMessagesReturned) > 0 {
+					mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "message_tool_calls" WHERE "message_tool_calls"."message_id" IN ($1,$2) ORDER BY rank`)).
+						WithArgs(messageIDs...).
+						WillReturnRows(sqlmock.NewRows([]string{"id", "message_id"}))
+				}
+			}
+
+			actual, err := repo.GetChatSessionMessages(context.Background(), chatSessionID, tt.args.filter)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
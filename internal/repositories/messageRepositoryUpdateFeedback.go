@@ -4,27 +4,54 @@ import (
 	"context"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
 	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
-	"gorm.io/gorm"
 )
 
+// UpdateMessageFeedback stores the feedback of a SYSTEM message. USER
+// messages are not updated and are reported as not found.
 func (repo *MessageRepository) UpdateMessageFeedback(
 	ctx context.Context,
 	uuid uuid.UUID,
-	feedback string,
+	feedback *domain.MessageFeedback,
 ) error {
-	var err error
-
-	err = repo.db.WithContext(ctx).
+	result := repo.db.WithContext(ctx).
 		Model(Message{}).
-		Where("id = ?", uuid).
-		Update("feedback", feedback).Error
+		Where("id = ? AND sender = ?", uuid, SYSTEM_SENDER).
+		Updates(map[string]interface{}{
+			"feedback":                  nullableString(feedback.Comment),
+			"feedback_thumb":            nullableString(feedback.Thumb),
+			"feedback_rating":           nullableInt(feedback.Rating),
+			"feedback_reason":           nullableString(feedback.Reason),
+			"feedback_corrected_answer": nullableString(feedback.CorrectedAnswer),
+			"feedback_submitted_at":     feedback.SubmittedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
 
-	if err == gorm.ErrRecordNotFound {
+	if result.RowsAffected == 0 {
 		return customerrors.ResourceNotFoundErrorWrapper{
-			OriginalError: errors.New("messageID " + uuid.String() + " not found"),
+			OriginalError: errors.New("SYSTEM messageID " + uuid.String() + " not found"),
 		}
 	}
 
-	return err
+	return nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func nullableInt(value int) *int {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
 }
@@ -2,18 +2,21 @@ package repositories
 
 import (
 	"context"
+	"database/sql/driver"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestChatRepository_UpdateMessageFeedback(t *testing.T) {
-	feedback := "ablabla"
+	submittedAt := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
 
 	db, mockDb, err := sqlmock.New()
 	if err != nil {
@@ -25,27 +28,44 @@ func TestChatRepository_UpdateMessageFeedback(t *testing.T) {
 
 	type args struct {
 		uuid     uuid.UUID
-		feedback string
+		feedback *domain.MessageFeedback
 	}
 	tests := []struct {
 		name                        string
 		args                        args
 		mockSqlMessageQueryExpected string
-		expected                    *domain.Message
+		expectedQueryArgs           []driver.Value
 	}{
 		{
-			name: "valid",
+			name: "thumb with reason",
 			args: args{
-				uuid:     uuid.UUID{0x12, 0x34, 0x56, 0x78},
-				feedback: feedback,
+				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				feedback: &domain.MessageFeedback{
+					Thumb:       "down",
+					Reason:      "hallucinated",
+					Comment:     "ablabla",
+					SubmittedAt: submittedAt,
+				},
 			},
-			mockSqlMessageQueryExpected: `UPDATE "messages" SET "feedback"=$1 WHERE id = $2`,
-			expected: &domain.Message{
-				ID:            uuid.UUID{0x12, 0x34, 0x56, 0x78},
-				ChatSessionID: uuid.UUID{0x22, 0x34, 0x56, 0x78},
-				Sender:        "user1",
-				Content:       "Hello, world!",
-				Feedback:      &feedback,
+			mockSqlMessageQueryExpected: `UPDATE "messages" SET "feedback"=$1,"feedback_corrected_answer"=$2,"feedback_rating"=$3,"feedback_reason"=$4,"feedback_submitted_at"=$5,"feedback_thumb"=$6 WHERE id = $7 AND sender = $8`,
+			expectedQueryArgs: []driver.Value{
+				"ablabla", nil, nil, "hallucinated", submittedAt, "down", uuid.UUID{0x12, 0x34, 0x56, 0x78}, SYSTEM_SENDER,
+			},
+		},
+		{
+			name: "rating with corrected answer",
+			args: args{
+				uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				feedback: &domain.MessageFeedback{
+					Rating:          2,
+					Reason:          "incomplete",
+					CorrectedAnswer: "Sailboats are 88% slower than Electric cars.",
+					SubmittedAt:     submittedAt,
+				},
+			},
+			mockSqlMessageQueryExpected: `UPDATE "messages" SET "feedback"=$1,"feedback_corrected_answer"=$2,"feedback_rating"=$3,"feedback_reason"=$4,"feedback_submitted_at"=$5,"feedback_thumb"=$6 WHERE id = $7 AND sender = $8`,
+			expectedQueryArgs: []driver.Value{
+				nil, "Sailboats are 88% slower than Electric cars.", 2, "incomplete", submittedAt, nil, uuid.UUID{0x12, 0x34, 0x56, 0x78}, SYSTEM_SENDER,
 			},
 		},
 	}
@@ -57,7 +77,7 @@ func TestChatRepository_UpdateMessageFeedback(t *testing.T) {
 
 			mockDb.ExpectBegin()
 			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlMessageQueryExpected)).
-				WithArgs(tt.args.feedback, tt.args.uuid).
+				WithArgs(tt.expectedQueryArgs...).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 			mockDb.ExpectCommit()
 
@@ -75,3 +95,50 @@ func TestChatRepository_UpdateMessageFeedback(t *testing.T) {
 		})
 	}
 }
+
+func TestChatRepository_UpdateMessageFeedbackHasNotFoundErrorForUserMessage(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name                        string
+		uuid                        uuid.UUID
+		feedback                    *domain.MessageFeedback
+		mockSqlMessageQueryExpected string
+	}{
+		{
+			name: "user message or missing message",
+			uuid: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+			feedback: &domain.MessageFeedback{
+				Thumb:       "up",
+				SubmittedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+			},
+			mockSqlMessageQueryExpected: `UPDATE "messages" SET "feedback"=$1,"feedback_corrected_answer"=$2,"feedback_rating"=$3,"feedback_reason"=$4,"feedback_submitted_at"=$5,"feedback_thumb"=$6 WHERE id = $7 AND sender = $8`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MessageRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlMessageQueryExpected)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mockDb.ExpectCommit()
+
+			err := repo.UpdateMessageFeedback(context.Background(), tt.uuid, tt.feedback)
+
+			assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time `gorm:"null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func (refreshToken *RefreshToken) toDomain() *domain.RefreshToken {
+	return &domain.RefreshToken{
+		ID:        refreshToken.ID,
+		UserID:    refreshToken.UserID,
+		TokenHash: refreshToken.TokenHash,
+		ExpiresAt: refreshToken.ExpiresAt,
+		RevokedAt: refreshToken.RevokedAt,
+		CreatedAt: refreshToken.CreatedAt,
+	}
+}
+
+// RevokedToken is an access token that was revoked before its expiration. It
+// only matters until then, so it can be deleted afterwards.
+type RevokedToken struct {
+	TokenID   string    `gorm:"type:text;primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
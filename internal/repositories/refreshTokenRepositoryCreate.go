@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (repo *RefreshTokenRepository) CreateRefreshToken(
+	ctx context.Context,
+	refreshToken *domain.RefreshToken,
+) (uuid.UUID, error) {
+	var err error
+
+	modelRefreshToken := RefreshToken{
+		UserID:    refreshToken.UserID,
+		TokenHash: refreshToken.TokenHash,
+		ExpiresAt: refreshToken.ExpiresAt,
+	}
+
+	err = repo.db.WithContext(ctx).Create(&modelRefreshToken).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return modelRefreshToken.ID, nil
+}
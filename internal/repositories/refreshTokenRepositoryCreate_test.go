@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRepository_CreateRefreshToken(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		refreshToken *domain.RefreshToken
+	}
+	tests := []struct {
+		name                    string
+		args                    args
+		mockSqlQueryExpected    string
+		mockInsertedIdReturned  uuid.UUID
+		expectedRefreshTokenUid uuid.UUID
+	}{
+		{
+			name: "valid",
+			args: args{
+				refreshToken: &domain.RefreshToken{
+					UserID:    uuid.UUID{0x12, 0x34, 0x56, 0x78},
+					TokenHash: domain.HashRefreshToken("refresh-token"),
+					ExpiresAt: time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			mockSqlQueryExpected:    `INSERT INTO "refresh_tokens" ("user_id","token_hash","expires_at","revoked_at","created_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+			mockInsertedIdReturned:  uuid.UUID{0x22, 0x34, 0x56, 0x78},
+			expectedRefreshTokenUid: uuid.UUID{0x22, 0x34, 0x56, 0x78},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RefreshTokenRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(
+					tt.args.refreshToken.UserID, tt.args.refreshToken.TokenHash, tt.args.refreshToken.ExpiresAt,
+					nil, sqlmock.AnyArg(),
+				).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedIdReturned))
+			mockDb.ExpectCommit()
+
+			actual, err := repo.CreateRefreshToken(context.Background(), tt.args.refreshToken)
+			if err != nil {
+				t.Errorf("CreateRefreshToken() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expectedRefreshTokenUid, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
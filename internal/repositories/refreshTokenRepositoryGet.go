@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func (repo *RefreshTokenRepository) GetRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*domain.RefreshToken, error) {
+	var err error
+	var modelRefreshToken *RefreshToken
+
+	err = repo.db.WithContext(ctx).
+		Model(RefreshToken{}).
+		Where("token_hash = ?", tokenHash).
+		Take(&modelRefreshToken).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.RefreshToken{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("refresh token not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.RefreshToken{}, err
+	}
+
+	return modelRefreshToken.toDomain(), nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRepository_GetRefreshTokenByHash(t *testing.T) {
+	revokedAt := time.Date(2025, 5, 30, 10, 0, 0, 0, time.UTC)
+
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name                 string
+		tokenHash            string
+		mockSqlQueryExpected string
+		expected             *domain.RefreshToken
+	}{
+		{
+			name:                 "active",
+			tokenHash:            domain.HashRefreshToken("refresh-token"),
+			mockSqlQueryExpected: `SELECT * FROM "refresh_tokens" WHERE token_hash = $1 LIMIT $2`,
+			expected: &domain.RefreshToken{
+				ID:        uuid.UUID{0x22, 0x34, 0x56, 0x78},
+				UserID:    uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				TokenHash: domain.HashRefreshToken("refresh-token"),
+				ExpiresAt: time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC),
+				CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:                 "revoked",
+			tokenHash:            domain.HashRefreshToken("refresh-token"),
+			mockSqlQueryExpected: `SELECT * FROM "refresh_tokens" WHERE token_hash = $1 LIMIT $2`,
+			expected: &domain.RefreshToken{
+				ID:        uuid.UUID{0x22, 0x34, 0x56, 0x78},
+				UserID:    uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				TokenHash: domain.HashRefreshToken("refresh-token"),
+				ExpiresAt: time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC),
+				RevokedAt: &revokedAt,
+				CreatedAt: time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RefreshTokenRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.tokenHash, 1).
+				WillReturnRows(
+					sqlmock.NewRows(
+						[]string{"id", "user_id", "token_hash", "expires_at", "revoked_at", "created_at"},
+					).AddRow(
+						tt.expected.ID, tt.expected.UserID, tt.expected.TokenHash,
+						tt.expected.ExpiresAt, tt.expected.RevokedAt, tt.expected.CreatedAt,
+					))
+
+			actual, err := repo.GetRefreshTokenByHash(context.Background(), tt.tokenHash)
+			if err != nil {
+				t.Errorf("GetRefreshTokenByHash() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRepository_GetRefreshTokenByHashHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &RefreshTokenRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "refresh_tokens" WHERE token_hash = $1 LIMIT $2`)).
+		WithArgs(domain.HashRefreshToken("unknown"), 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err = repo.GetRefreshTokenByHash(context.Background(), domain.HashRefreshToken("unknown"))
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"time"
+)
+
+// RevokeRefreshToken revokes a refresh token that is still active. Only one of
+// two concurrent revocations of the same token succeeds, the other one gets a
+// not found error, so a token cannot be rotated twice.
+func (repo *RefreshTokenRepository) RevokeRefreshToken(
+	ctx context.Context,
+	uuid uuid.UUID,
+) error {
+	result := repo.db.WithContext(ctx).
+		Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", uuid).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("active refresh token " + uuid.String() + " not found"),
+		}
+	}
+
+	return nil
+}
+
+func (repo *RefreshTokenRepository) RevokeUserRefreshTokens(
+	ctx context.Context,
+	userID uuid.UUID,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestRefreshTokenRepository_RevokeRefreshToken(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name                 string
+		uuid                 uuid.UUID
+		mockRowsAffected     int64
+		mockSqlQueryExpected string
+		expectedError        error
+	}{
+		{
+			name:                 "active",
+			uuid:                 uuid.UUID{0x22, 0x34, 0x56, 0x78},
+			mockRowsAffected:     1,
+			mockSqlQueryExpected: `UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE id = $2 AND revoked_at IS NULL`,
+		},
+		{
+			name:                 "already revoked",
+			uuid:                 uuid.UUID{0x22, 0x34, 0x56, 0x78},
+			mockRowsAffected:     0,
+			mockSqlQueryExpected: `UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE id = $2 AND revoked_at IS NULL`,
+			expectedError:        customerrors.ResourceNotFoundErrorWrapper{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RefreshTokenRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(sqlmock.AnyArg(), tt.uuid).
+				WillReturnResult(sqlmock.NewResult(0, tt.mockRowsAffected))
+			mockDb.ExpectCommit()
+
+			err := repo.RevokeRefreshToken(context.Background(), tt.uuid)
+
+			if tt.expectedError != nil {
+				assert.IsType(t, tt.expectedError, err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRepository_RevokeUserRefreshTokens(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &RefreshTokenRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE user_id = $2 AND revoked_at IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), uuid.UUID{0x12, 0x34, 0x56, 0x78}).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mockDb.ExpectCommit()
+
+	err = repo.RevokeUserRefreshTokens(context.Background(), uuid.UUID{0x12, 0x34, 0x56, 0x78})
+
+	assert.Nil(t, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
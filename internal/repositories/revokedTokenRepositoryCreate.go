@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+// RevokeToken adds an access token to the denylist. Revoking it twice is a
+// no-op.
+func (repo *RevokedTokenRepository) RevokeToken(
+	ctx context.Context,
+	tokenID string,
+	expiresAt time.Time,
+) error {
+	modelRevokedToken := RevokedToken{
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+	}
+
+	return repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&modelRevokedToken).Error
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRevokedTokenRepository_RevokeToken(t *testing.T) {
+	expiresAt := time.Date(2025, 5, 29, 10, 15, 0, 0, time.UTC)
+
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &RevokedTokenRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectExec(regexp.QuoteMeta(`INSERT INTO "revoked_tokens" ("token_id","expires_at","created_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)).
+		WithArgs("a1b2c3", expiresAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
+
+	err = repo.RevokeToken(context.Background(), "a1b2c3", expiresAt)
+
+	assert.Nil(t, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
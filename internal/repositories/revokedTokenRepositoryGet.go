@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+)
+
+func (repo *RevokedTokenRepository) IsTokenRevoked(
+	ctx context.Context,
+	tokenID string,
+) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+		Model(&RevokedToken{}).
+		Where("token_id = ?", tokenID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestRevokedTokenRepository_IsTokenRevoked(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name      string
+		mockCount int
+		expected  bool
+	}{
+		{
+			name:      "revoked",
+			mockCount: 1,
+			expected:  true,
+		},
+		{
+			name:      "not revoked",
+			mockCount: 0,
+			expected:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RevokedTokenRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "revoked_tokens" WHERE token_id = $1`)).
+				WithArgs("a1b2c3").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.mockCount))
+
+			actual, err := repo.IsTokenRevoked(context.Background(), "a1b2c3")
+			if err != nil {
+				t.Errorf("IsTokenRevoked() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TokenUsage has no foreign keys to the chat session and the message, so that
+// the usage is still accounted for after they are deleted
+type TokenUsage struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID           uuid.UUID `gorm:"type:uuid;not null;index:idx_token_usages_user_id_created_at"`
+	ChatSessionID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	MessageID        uuid.UUID `gorm:"type:uuid;not null"`
+	PromptTokens     int64     `gorm:"not null"`
+	CompletionTokens int64     `gorm:"not null"`
+	EmbeddingTokens  int64     `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"not null;index:idx_token_usages_user_id_created_at"`
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type UsageRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageRepository(db *gorm.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+func (repo *UsageRepository) CreateTokenUsage(
+	ctx context.Context,
+	usage *domain.TokenUsage,
+) (uuid.UUID, error) {
+	modelTokenUsage := TokenUsage{
+		UserID:           usage.UserID,
+		ChatSessionID:    usage.ChatSessionID,
+		MessageID:        usage.MessageID,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		EmbeddingTokens:  usage.EmbeddingTokens,
+		CreatedAt:        usage.CreatedAt,
+	}
+
+	err := repo.db.WithContext(ctx).Create(&modelTokenUsage).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return modelTokenUsage.ID, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestUsageRepository_CreateTokenUsage(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	usage := &domain.TokenUsage{
+		UserID:           uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		ChatSessionID:    uuid.UUID{0x22, 0x34, 0x56, 0x78},
+		MessageID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+		PromptTokens:     1200,
+		CompletionTokens: 150,
+		EmbeddingTokens:  12,
+		CreatedAt:        time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC),
+	}
+
+	repo := &UsageRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "token_usages" ("user_id","chat_session_id","message_id","prompt_tokens","completion_tokens","embedding_tokens","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(usage.UserID, usage.ChatSessionID, usage.MessageID, int64(1200), int64(150), int64(12), usage.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.UUID{0x42, 0x34, 0x56, 0x78}))
+	mockDb.ExpectCommit()
+
+	actual, err := repo.CreateTokenUsage(context.Background(), usage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID{0x42, 0x34, 0x56, 0x78}, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type usageAggregateRow struct {
+	Period           time.Time
+	Messages         int64
+	PromptTokens     int64
+	CompletionTokens int64
+	EmbeddingTokens  int64
+}
+
+func (row *usageAggregateRow) toDomain() *domain.UsageAggregate {
+	return &domain.UsageAggregate{
+		Period:           row.Period,
+		Messages:         row.Messages,
+		PromptTokens:     row.PromptTokens,
+		CompletionTokens: row.CompletionTokens,
+		EmbeddingTokens:  row.EmbeddingTokens,
+	}
+}
+
+// usageSumsSelect counts the answered messages, leaving out the searches of
+// the knowledge base that have no message
+const usageSumsSelect = "COUNT(*) FILTER (WHERE message_id <> '00000000-0000-0000-0000-000000000000') AS messages, COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, " +
+	"COALESCE(SUM(completion_tokens), 0) AS completion_tokens, COALESCE(SUM(embedding_tokens), 0) AS embedding_tokens"
+
+// GetUserUsage sums the token usage of the user in [from, to), grouped by the
+// interval (day or month). Periods are truncated in UTC, like the monthly
+// quotas.
+func (repo *UsageRepository) GetUserUsage(
+	ctx context.Context,
+	userID uuid.UUID,
+	from time.Time,
+	to time.Time,
+	interval string,
+) ([]*domain.UsageAggregate, error) {
+	var rows []*usageAggregateRow
+
+	err := repo.db.WithContext(ctx).
+		Model(&TokenUsage{}).
+		Select("date_trunc(?, created_at AT TIME ZONE 'UTC') AS period, "+usageSumsSelect, interval).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Group("period").
+		Order("period").
+		Scan(&rows).Error
+	if err != nil {
+		return []*domain.UsageAggregate{}, err
+	}
+
+	aggregates := make([]*domain.UsageAggregate, len(rows))
+	for i, row := range rows {
+		aggregates[i] = row.toDomain()
+		aggregates[i].Period = row.Period.UTC()
+	}
+
+	return aggregates, nil
+}
+
+// GetUserUsageTotal sums the token usage of the user in [from, to)
+func (repo *UsageRepository) GetUserUsageTotal(
+	ctx context.Context,
+	userID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) (*domain.UsageAggregate, error) {
+	var row usageAggregateRow
+
+	err := repo.db.WithContext(ctx).
+		Model(&TokenUsage{}).
+		Select(usageSumsSelect).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate := row.toDomain()
+	aggregate.Period = from
+
+	return aggregate, nil
+}
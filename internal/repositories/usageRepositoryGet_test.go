@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestUsageRepository_GetUserUsage(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	expected := []*domain.UsageAggregate{
+		{
+			Period:           time.Date(2025, 5, 28, 0, 0, 0, 0, time.UTC),
+			Messages:         3,
+			PromptTokens:     3600,
+			CompletionTokens: 450,
+			EmbeddingTokens:  36,
+		},
+		{
+			Period:           time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC),
+			Messages:         1,
+			PromptTokens:     1200,
+			CompletionTokens: 150,
+			EmbeddingTokens:  12,
+		},
+	}
+
+	repo := &UsageRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT date_trunc($1, created_at AT TIME ZONE 'UTC') AS period, COUNT(*) FILTER (WHERE message_id <> '00000000-0000-0000-0000-000000000000') AS messages, COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(completion_tokens), 0) AS completion_tokens, COALESCE(SUM(embedding_tokens), 0) AS embedding_tokens FROM "token_usages" WHERE user_id = $2 AND created_at >= $3 AND created_at < $4 GROUP BY "period" ORDER BY period`)).
+		WithArgs("day", userID, from, to).
+		WillReturnRows(
+			sqlmock.NewRows(
+				[]string{"period", "messages", "prompt_tokens", "completion_tokens", "embedding_tokens"},
+			).AddRow(
+				expected[0].Period, expected[0].Messages, expected[0].PromptTokens, expected[0].CompletionTokens, expected[0].EmbeddingTokens,
+			).AddRow(
+				expected[1].Period, expected[1].Messages, expected[1].PromptTokens, expected[1].CompletionTokens, expected[1].EmbeddingTokens,
+			),
+		)
+
+	actual, err := repo.GetUserUsage(context.Background(), userID, from, to, "day")
+	if err != nil {
+		t.Errorf("GetUserUsage() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, expected, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestUsageRepository_GetUserUsageTotal(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := &UsageRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FILTER (WHERE message_id <> '00000000-0000-0000-0000-000000000000') AS messages, COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(completion_tokens), 0) AS completion_tokens, COALESCE(SUM(embedding_tokens), 0) AS embedding_tokens FROM "token_usages" WHERE user_id = $1 AND created_at >= $2 AND created_at < $3`)).
+		WithArgs(userID, from, to).
+		WillReturnRows(
+			sqlmock.NewRows(
+				[]string{"messages", "prompt_tokens", "completion_tokens", "embedding_tokens"},
+			).AddRow(4, 4800, 600, 48),
+		)
+
+	actual, err := repo.GetUserUsageTotal(context.Background(), userID, from, to)
+	if err != nil {
+		t.Errorf("GetUserUsageTotal() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, &domain.UsageAggregate{
+		Period:           from,
+		Messages:         4,
+		PromptTokens:     4800,
+		CompletionTokens: 600,
+		EmbeddingTokens:  48,
+	}, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
@@ -2,14 +2,34 @@ package repositories
 
 import (
 	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"time"
 )
 
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
-	Username     string    `gorm:"not null;"`
-	Password     string    `gorm:"not null;"`
-	ChatSessions []ChatSession
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	Username      string    `gorm:"not null;uniqueIndex"`
+	Password      string    `gorm:"not null;"`
+	ExternalID    *string   `gorm:"uniqueIndex"`
+	Role          string    `gorm:"type:text;not null"`
+	ChatSessions  []ChatSession
+	RefreshTokens []RefreshToken `gorm:"constraint:OnDelete:CASCADE"`
+	APIKeys       []APIKey       `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (user *User) toDomain() *domain.User {
+	domainUser := &domain.User{
+		ID:       user.ID,
+		Username: user.Username,
+		Password: user.Password,
+		Role:     user.Role,
+	}
+
+	if user.ExternalID != nil {
+		domainUser.ExternalID = *user.ExternalID
+	}
+
+	return domainUser
 }
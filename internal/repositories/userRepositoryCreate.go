@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const uniqueViolationCode = "23505"
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// CreateUser stores a user whose password is already hashed. The unique indexes
+// on the username and the external ID decide which of two concurrent
+// registrations wins.
+func (repo *UserRepository) CreateUser(
+	ctx context.Context,
+	user *domain.User,
+) (uuid.UUID, error) {
+	var err error
+
+	modelUser := User{
+		Username: user.Username,
+		Password: user.Password,
+		Role:     user.Role,
+	}
+
+	if user.ExternalID != "" {
+		modelUser.ExternalID = &user.ExternalID
+	}
+
+	err = repo.db.WithContext(ctx).Create(&modelUser).Error
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return uuid.Nil, customerrors.NewUsernameTakenError(user.Username)
+	}
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return modelUser.ID, nil
+}
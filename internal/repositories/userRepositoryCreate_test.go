@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestUserRepository_CreateUser(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		user *domain.User
+	}
+	tests := []struct {
+		name                   string
+		args                   args
+		mockSqlQueryExpected   string
+		mockExternalIDArg      driver.Value
+		mockInsertedIdReturned uuid.UUID
+		expectedUserUid        uuid.UUID
+	}{
+		{
+			name: "valid",
+			args: args{
+				user: &domain.User{
+					Username: "obi-wan",
+					Password: "$2a$10$hashedpassword",
+					Role:     "user",
+				},
+			},
+			mockSqlQueryExpected:   `INSERT INTO "users" ("created_at","updated_at","username","password","external_id","role") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+			mockExternalIDArg:      nil,
+			mockInsertedIdReturned: uuid.UUID{0x12, 0x34, 0x56, 0x78},
+			expectedUserUid:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		},
+		{
+			name: "provisioned through oidc",
+			args: args{
+				user: &domain.User{
+					Username:   "luke",
+					ExternalID: "https://issuer.example.com#luke",
+					Role:       "user",
+				},
+			},
+			mockSqlQueryExpected:   `INSERT INTO "users" ("created_at","updated_at","username","password","external_id","role") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+			mockExternalIDArg:      "https://issuer.example.com#luke",
+			mockInsertedIdReturned: uuid.UUID{0x42, 0x34, 0x56, 0x78},
+			expectedUserUid:        uuid.UUID{0x42, 0x34, 0x56, 0x78},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &UserRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), tt.args.user.Username, tt.args.user.Password, tt.mockExternalIDArg, tt.args.user.Role).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.mockInsertedIdReturned))
+			mockDb.ExpectCommit()
+
+			actual, err := repo.CreateUser(context.Background(), tt.args.user)
+			if err != nil {
+				t.Errorf("CreateUser() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expectedUserUid, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestUserRepository_CreateUserHasUsernameTakenError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &UserRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("created_at","updated_at","username","password","external_id","role") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mockDb.ExpectRollback()
+
+	_, err = repo.CreateUser(context.Background(), &domain.User{
+		Username: "obi-wan",
+		Password: "$2a$10$hashedpassword",
+	})
+
+	assert.Equal(t, customerrors.NewUsernameTakenError("obi-wan"), err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
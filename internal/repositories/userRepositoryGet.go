@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func (repo *UserRepository) GetUser(
+	ctx context.Context,
+	uuid uuid.UUID,
+) (*domain.User, error) {
+	var err error
+	var modelUser *User
+
+	err = repo.db.WithContext(ctx).
+		Model(User{}).
+		Where("id = ?", uuid).
+		Take(&modelUser).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.User{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("userID " + uuid.String() + " not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.User{}, err
+	}
+
+	return modelUser.toDomain(), nil
+}
+
+func (repo *UserRepository) GetUserByUsername(
+	ctx context.Context,
+	username string,
+) (*domain.User, error) {
+	var err error
+	var modelUser *User
+
+	err = repo.db.WithContext(ctx).
+		Model(User{}).
+		Where("username = ?", username).
+		Take(&modelUser).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.User{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("username " + username + " not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.User{}, err
+	}
+
+	return modelUser.toDomain(), nil
+}
+
+func (repo *UserRepository) GetUserByExternalID(
+	ctx context.Context,
+	externalID string,
+) (*domain.User, error) {
+	var err error
+	var modelUser *User
+
+	err = repo.db.WithContext(ctx).
+		Model(User{}).
+		Where("external_id = ?", externalID).
+		Take(&modelUser).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return &domain.User{}, customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("externalID " + externalID + " not found"),
+		}
+	}
+
+	if err != nil {
+		return &domain.User{}, err
+	}
+
+	return modelUser.toDomain(), nil
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestUserRepository_GetUser(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &UserRepository{
+		db: gormDb,
+	}
+
+	expected := &domain.User{
+		ID:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		Username: "obi-wan",
+		Password: "$2a$10$hashedpassword",
+		Role:     "curator",
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 LIMIT $2`)).
+		WithArgs(expected.ID, 1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "username", "password", "role"}).
+				AddRow(expected.ID, expected.Username, expected.Password, expected.Role),
+		)
+
+	actual, err := repo.GetUser(context.Background(), expected.ID)
+	if err != nil {
+		t.Errorf("GetUser() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, expected, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestUserRepository_GetUserByUsername(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name                 string
+		username             string
+		mockSqlQueryExpected string
+		expected             *domain.User
+	}{
+		{
+			name:                 "valid",
+			username:             "obi-wan",
+			mockSqlQueryExpected: `SELECT * FROM "users" WHERE username = $1 LIMIT $2`,
+			expected: &domain.User{
+				ID:       uuid.UUID{0x12, 0x34, 0x56, 0x78},
+				Username: "obi-wan",
+				Password: "$2a$10$hashedpassword",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &UserRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.username, 1).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"id", "username", "password"}).
+						AddRow(tt.expected.ID, tt.expected.Username, tt.expected.Password),
+				)
+
+			actual, err := repo.GetUserByUsername(context.Background(), tt.username)
+			if err != nil {
+				t.Errorf("GetUserByUsername() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUserByUsernameHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &UserRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE username = $1 LIMIT $2`)).
+		WithArgs("obi-wan", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err = repo.GetUserByUsername(context.Background(), "obi-wan")
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestUserRepository_GetUserByExternalID(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &UserRepository{
+		db: gormDb,
+	}
+
+	expected := &domain.User{
+		ID:         uuid.UUID{0x12, 0x34, 0x56, 0x78},
+		Username:   "luke",
+		ExternalID: "https://issuer.example.com#luke",
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE external_id = $1 LIMIT $2`)).
+		WithArgs(expected.ExternalID, 1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "username", "password", "external_id"}).
+				AddRow(expected.ID, expected.Username, "", expected.ExternalID),
+		)
+
+	actual, err := repo.GetUserByExternalID(context.Background(), expected.ExternalID)
+	if err != nil {
+		t.Errorf("GetUserByExternalID() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, expected, actual)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
+func TestUserRepository_GetUserByExternalIDHasNotFoundError(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	repo := &UserRepository{
+		db: gormDb,
+	}
+
+	mockDb.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE external_id = $1 LIMIT $2`)).
+		WithArgs("https://issuer.example.com#luke", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err = repo.GetUserByExternalID(context.Background(), "https://issuer.example.com#luke")
+
+	assert.IsType(t, customerrors.ResourceNotFoundErrorWrapper{}, err)
+
+	if err = mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
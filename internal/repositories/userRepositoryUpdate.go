@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+)
+
+func (repo *UserRepository) UpdateUserRole(
+	ctx context.Context,
+	userID uuid.UUID,
+	role string,
+) error {
+	result := repo.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", userID).
+		Update("role", role)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customerrors.ResourceNotFoundErrorWrapper{
+			OriginalError: errors.New("userID " + userID.String() + " not found"),
+		}
+	}
+
+	return nil
+}
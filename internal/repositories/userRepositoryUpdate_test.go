@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestUserRepository_UpdateUserRole(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	tests := []struct {
+		name             string
+		mockRowsAffected int64
+		expectedError    error
+	}{
+		{
+			name:             "existing user",
+			mockRowsAffected: 1,
+		},
+		{
+			name:             "unknown user",
+			mockRowsAffected: 0,
+			expectedError:    customerrors.ResourceNotFoundErrorWrapper{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &UserRepository{
+				db: gormDb,
+			}
+
+			userID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "role"=$1,"updated_at"=$2 WHERE id = $3`)).
+				WithArgs("curator", sqlmock.AnyArg(), userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.mockRowsAffected))
+			mockDb.ExpectCommit()
+
+			err := repo.UpdateUserRole(context.Background(), userID, "curator")
+
+			if tt.expectedError != nil {
+				assert.IsType(t, tt.expectedError, err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+type VehicleComparison struct {
+	ID        uint    `gorm:"primaryKey"`
+	Line      int     `gorm:"not null"`
+	Subject   string  `gorm:"type:text;not null;index"`
+	Percent   float64 `gorm:"not null"`
+	Attribute string  `gorm:"type:text;not null;index"`
+	Object    string  `gorm:"type:text;not null;index"`
+}
+
+func (comparison *VehicleComparison) toDomain() *domain.VehicleComparison {
+	return &domain.VehicleComparison{
+		Line:      comparison.Line,
+		Subject:   comparison.Subject,
+		Percent:   comparison.Percent,
+		Attribute: comparison.Attribute,
+		Object:    comparison.Object,
+	}
+}
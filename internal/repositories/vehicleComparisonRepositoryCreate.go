@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// vehicleComparisonsBatchSize keeps the parameters of each insert well below
+// the limit of postgres
+const vehicleComparisonsBatchSize = 500
+
+type VehicleComparisonRepository struct {
+	db *gorm.DB
+}
+
+func NewVehicleComparisonRepository(db *gorm.DB) *VehicleComparisonRepository {
+	return &VehicleComparisonRepository{db: db}
+}
+
+// ReplaceVehicleComparisons replaces all the stored comparisons with the given
+// ones, so that re-ingesting the vehicles document does not duplicate them.
+func (repo *VehicleComparisonRepository) ReplaceVehicleComparisons(
+	ctx context.Context,
+	comparisons []*domain.VehicleComparison,
+) error {
+	modelComparisons := make([]*VehicleComparison, len(comparisons))
+	for i, comparison := range comparisons {
+		modelComparisons[i] = &VehicleComparison{
+			Line:      comparison.Line,
+			Subject:   comparison.Subject,
+			Percent:   comparison.Percent,
+			Attribute: comparison.Attribute,
+			Object:    comparison.Object,
+		}
+	}
+
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("1 = 1").Delete(&VehicleComparison{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(modelComparisons) == 0 {
+			return nil
+		}
+
+		return tx.CreateInBatches(modelComparisons, vehicleComparisonsBatchSize).Error
+	})
+}
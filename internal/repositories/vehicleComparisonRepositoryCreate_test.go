@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestVehicleComparisonRepository_ReplaceVehicleComparisons(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		comparisons []*domain.VehicleComparison
+	}
+	tests := []struct {
+		name                  string
+		args                  args
+		mockSqlDeleteExpected string
+		mockSqlInsertExpected string
+		expectInsert          bool
+	}{
+		{
+			name: "valid",
+			args: args{
+				comparisons: []*domain.VehicleComparison{
+					{Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+					{Line: 4, Subject: "Electric cars", Percent: 152, Attribute: "quick to accelerate", Object: "Hybrid vehicles"},
+				},
+			},
+			mockSqlDeleteExpected: `DELETE FROM "vehicle_comparisons" WHERE 1 = 1`,
+			mockSqlInsertExpected: `INSERT INTO "vehicle_comparisons" ("line","subject","percent","attribute","object") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) RETURNING "id"`,
+			expectInsert:          true,
+		},
+		{
+			name: "no comparisons",
+			args: args{
+				comparisons: []*domain.VehicleComparison{},
+			},
+			mockSqlDeleteExpected: `DELETE FROM "vehicle_comparisons" WHERE 1 = 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &VehicleComparisonRepository{
+				db: gormDb,
+			}
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec(regexp.QuoteMeta(tt.mockSqlDeleteExpected)).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+			if tt.expectInsert {
+				mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlInsertExpected)).
+					WithArgs(
+						3, "Sailboats", 88.0, "slow", "Electric cars",
+						4, "Electric cars", 152.0, "quick to accelerate", "Hybrid vehicles",
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+			}
+			mockDb.ExpectCommit()
+
+			err := repo.ReplaceVehicleComparisons(context.Background(), tt.args.comparisons)
+			if err != nil {
+				t.Errorf("ReplaceVehicleComparisons() error = %v", err)
+			}
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+)
+
+// GetVehicleComparisons returns the comparisons of the filter's subject in
+// either direction, so both "Subject ... than Object" and "Object ... than
+// Subject", in the order of the document. Names are matched case-insensitively.
+func (repo *VehicleComparisonRepository) GetVehicleComparisons(
+	ctx context.Context,
+	filter *domain.VehicleComparisonFilter,
+) ([]*domain.VehicleComparison, error) {
+	var modelComparisons []*VehicleComparison
+
+	query := repo.db.WithContext(ctx).Model(VehicleComparison{})
+	if filter.Object == "" {
+		query = query.Where(
+			"LOWER(subject) = LOWER(?) OR LOWER(object) = LOWER(?)",
+			filter.Subject, filter.Subject,
+		)
+	} else {
+		query = query.Where(
+			"(LOWER(subject) = LOWER(?) AND LOWER(object) = LOWER(?)) OR (LOWER(subject) = LOWER(?) AND LOWER(object) = LOWER(?))",
+			filter.Subject, filter.Object, filter.Object, filter.Subject,
+		)
+	}
+	if filter.Attribute != "" {
+		query = query.Where("LOWER(attribute) = LOWER(?)", filter.Attribute)
+	}
+
+	err := query.Order("line").Find(&modelComparisons).Error
+	if err != nil {
+		return []*domain.VehicleComparison{}, err
+	}
+
+	comparisons := make([]*domain.VehicleComparison, len(modelComparisons))
+	for i, modelComparison := range modelComparisons {
+		comparisons[i] = modelComparison.toDomain()
+	}
+
+	return comparisons, nil
+}
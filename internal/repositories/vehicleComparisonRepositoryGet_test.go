@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestVehicleComparisonRepository_GetVehicleComparisons(t *testing.T) {
+	db, mockDb, err := sqlmock.New()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	defer db.Close()
+
+	gormDb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+
+	type args struct {
+		filter *domain.VehicleComparisonFilter
+	}
+	tests := []struct {
+		name                    string
+		args                    args
+		mockSqlQueryExpected    string
+		mockSqlArgsExpected     []driver.Value
+		mockComparisonsReturned []*VehicleComparison
+		expected                []*domain.VehicleComparison
+	}{
+		{
+			name: "subject only",
+			args: args{
+				filter: &domain.VehicleComparisonFilter{Subject: "sailboats"},
+			},
+			mockSqlQueryExpected: `SELECT * FROM "vehicle_comparisons" WHERE LOWER(subject) = LOWER($1) OR LOWER(object) = LOWER($2) ORDER BY line`,
+			mockSqlArgsExpected:  []driver.Value{"sailboats", "sailboats"},
+			mockComparisonsReturned: []*VehicleComparison{
+				{ID: 1, Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+				{ID: 2, Line: 7, Subject: "Scooters", Percent: 12, Attribute: "fast", Object: "Sailboats"},
+			},
+			expected: []*domain.VehicleComparison{
+				{Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+				{Line: 7, Subject: "Scooters", Percent: 12, Attribute: "fast", Object: "Sailboats"},
+			},
+		},
+		{
+			name: "subject and attribute",
+			args: args{
+				filter: &domain.VehicleComparisonFilter{Subject: "Sailboats", Attribute: "Slow"},
+			},
+			mockSqlQueryExpected: `SELECT * FROM "vehicle_comparisons" WHERE (LOWER(subject) = LOWER($1) OR LOWER(object) = LOWER($2)) AND LOWER(attribute) = LOWER($3) ORDER BY line`,
+			mockSqlArgsExpected:  []driver.Value{"Sailboats", "Sailboats", "Slow"},
+			mockComparisonsReturned: []*VehicleComparison{
+				{ID: 1, Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+			},
+			expected: []*domain.VehicleComparison{
+				{Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+			},
+		},
+		{
+			name: "subject, attribute and object",
+			args: args{
+				filter: &domain.VehicleComparisonFilter{Subject: "Sailboats", Attribute: "slow", Object: "Electric cars"},
+			},
+			mockSqlQueryExpected: `SELECT * FROM "vehicle_comparisons" WHERE ((LOWER(subject) = LOWER($1) AND LOWER(object) = LOWER($2)) OR (LOWER(subject) = LOWER($3) AND LOWER(object) = LOWER($4))) AND LOWER(attribute) = LOWER($5) ORDER BY line`,
+			mockSqlArgsExpected:  []driver.Value{"Sailboats", "Electric cars", "Electric cars", "Sailboats", "slow"},
+			mockComparisonsReturned: []*VehicleComparison{
+				{ID: 1, Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+			},
+			expected: []*domain.VehicleComparison{
+				{Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &VehicleComparisonRepository{
+				db: gormDb,
+			}
+
+			rows := sqlmock.NewRows([]string{"id", "line", "subject", "percent", "attribute", "object"})
+			for _, comparison := range tt.mockComparisonsReturned {
+				rows.AddRow(
+					comparison.ID, comparison.Line, comparison.Subject,
+					comparison.Percent, comparison.Attribute, comparison.Object,
+				)
+			}
+
+			mockDb.ExpectQuery(regexp.QuoteMeta(tt.mockSqlQueryExpected)).
+				WithArgs(tt.mockSqlArgsExpected...).
+				WillReturnRows(rows)
+
+			actual, err := repo.GetVehicleComparisons(context.Background(), tt.args.filter)
+			if err != nil {
+				t.Errorf("GetVehicleComparisons() error = %v", err)
+			}
+
+			assert.Equal(t, tt.expected, actual)
+
+			if err = mockDb.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expections: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/apiKeyService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/apiKeyService.go -destination=../mocks/mock_internal/core/services/apiKeyService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIKeyServiceInterface is a mock of APIKeyServiceInterface interface.
+type MockAPIKeyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyServiceInterfaceMockRecorder
+}
+
+// MockAPIKeyServiceInterfaceMockRecorder is the mock recorder for MockAPIKeyServiceInterface.
+type MockAPIKeyServiceInterfaceMockRecorder struct {
+	mock *MockAPIKeyServiceInterface
+}
+
+// NewMockAPIKeyServiceInterface creates a new mock instance.
+func NewMockAPIKeyServiceInterface(ctrl *gomock.Controller) *MockAPIKeyServiceInterface {
+	mock := &MockAPIKeyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyServiceInterface) EXPECT() *MockAPIKeyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeyServiceInterface) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeyServiceInterfaceMockRecorder) Authenticate(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeyServiceInterface)(nil).Authenticate), ctx, key)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyServiceInterface) CreateAPIKey(ctx context.Context, userID uuid.UUID, name string, scopes []string) (*domain.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, userID, name, scopes)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyServiceInterfaceMockRecorder) CreateAPIKey(ctx, userID, name, scopes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyServiceInterface)(nil).CreateAPIKey), ctx, userID, name, scopes)
+}
+
+// GetUserAPIKeys mocks base method.
+func (m *MockAPIKeyServiceInterface) GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAPIKeys", ctx, userID)
+	ret0, _ := ret[0].([]*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAPIKeys indicates an expected call of GetUserAPIKeys.
+func (mr *MockAPIKeyServiceInterfaceMockRecorder) GetUserAPIKeys(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAPIKeys", reflect.TypeOf((*MockAPIKeyServiceInterface)(nil).GetUserAPIKeys), ctx, userID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyServiceInterface) RevokeAPIKey(ctx context.Context, userID, apiKeyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, userID, apiKeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyServiceInterfaceMockRecorder) RevokeAPIKey(ctx, userID, apiKeyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyServiceInterface)(nil).RevokeAPIKey), ctx, userID, apiKeyID)
+}
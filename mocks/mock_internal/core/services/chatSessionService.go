@@ -41,6 +41,21 @@ func (m *MockChatSessionServiceInterface) EXPECT() *MockChatSessionServiceInterf
 	return m.recorder
 }
 
+// ArchiveChatSession mocks base method.
+func (m *MockChatSessionServiceInterface) ArchiveChatSession(ctx context.Context, sessionID, userID uuid.UUID) (*domain.ChatSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveChatSession", ctx, sessionID, userID)
+	ret0, _ := ret[0].(*domain.ChatSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveChatSession indicates an expected call of ArchiveChatSession.
+func (mr *MockChatSessionServiceInterfaceMockRecorder) ArchiveChatSession(ctx, sessionID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).ArchiveChatSession), ctx, sessionID, userID)
+}
+
 // CreateChatSession mocks base method.
 func (m *MockChatSessionServiceInterface) CreateChatSession(arg0 context.Context, arg1 *domain.ChatSession) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -56,34 +71,93 @@ func (mr *MockChatSessionServiceInterfaceMockRecorder) CreateChatSession(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).CreateChatSession), arg0, arg1)
 }
 
+// DeleteChatSession mocks base method.
+func (m *MockChatSessionServiceInterface) DeleteChatSession(ctx context.Context, sessionID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChatSession", ctx, sessionID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChatSession indicates an expected call of DeleteChatSession.
+func (mr *MockChatSessionServiceInterfaceMockRecorder) DeleteChatSession(ctx, sessionID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).DeleteChatSession), ctx, sessionID, userID)
+}
+
 // GetChatSession mocks base method.
-func (m *MockChatSessionServiceInterface) GetChatSession(arg0 context.Context, arg1 uuid.UUID) (*domain.ChatSession, error) {
+func (m *MockChatSessionServiceInterface) GetChatSession(ctx context.Context, sessionID, userID uuid.UUID) (*domain.ChatSession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChatSession", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetChatSession", ctx, sessionID, userID)
 	ret0, _ := ret[0].(*domain.ChatSession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetChatSession indicates an expected call of GetChatSession.
-func (mr *MockChatSessionServiceInterfaceMockRecorder) GetChatSession(arg0, arg1 any) *gomock.Call {
+func (mr *MockChatSessionServiceInterfaceMockRecorder) GetChatSession(ctx, sessionID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).GetChatSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).GetChatSession), ctx, sessionID, userID)
 }
 
 // GetUserChatSessions mocks base method.
-func (m *MockChatSessionServiceInterface) GetUserChatSessions(arg0 context.Context, arg1 uuid.UUID) ([]*domain.ChatSession, error) {
+func (m *MockChatSessionServiceInterface) GetUserChatSessions(ctx context.Context, userID uuid.UUID, filter *domain.ChatSessionFilter) (*domain.ChatSessionPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserChatSessions", arg0, arg1)
-	ret0, _ := ret[0].([]*domain.ChatSession)
+	ret := m.ctrl.Call(m, "GetUserChatSessions", ctx, userID, filter)
+	ret0, _ := ret[0].(*domain.ChatSessionPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserChatSessions indicates an expected call of GetUserChatSessions.
-func (mr *MockChatSessionServiceInterfaceMockRecorder) GetUserChatSessions(arg0, arg1 any) *gomock.Call {
+func (mr *MockChatSessionServiceInterfaceMockRecorder) GetUserChatSessions(ctx, userID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChatSessions", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).GetUserChatSessions), ctx, userID, filter)
+}
+
+// PurgeDeletedChatSessions mocks base method.
+func (m *MockChatSessionServiceInterface) PurgeDeletedChatSessions(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedChatSessions", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedChatSessions indicates an expected call of PurgeDeletedChatSessions.
+func (mr *MockChatSessionServiceInterfaceMockRecorder) PurgeDeletedChatSessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedChatSessions", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).PurgeDeletedChatSessions), ctx)
+}
+
+// RenameChatSession mocks base method.
+func (m *MockChatSessionServiceInterface) RenameChatSession(ctx context.Context, sessionID, userID uuid.UUID, title string) (*domain.ChatSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameChatSession", ctx, sessionID, userID, title)
+	ret0, _ := ret[0].(*domain.ChatSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameChatSession indicates an expected call of RenameChatSession.
+func (mr *MockChatSessionServiceInterfaceMockRecorder) RenameChatSession(ctx, sessionID, userID, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).RenameChatSession), ctx, sessionID, userID, title)
+}
+
+// UnarchiveChatSession mocks base method.
+func (m *MockChatSessionServiceInterface) UnarchiveChatSession(ctx context.Context, sessionID, userID uuid.UUID) (*domain.ChatSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnarchiveChatSession", ctx, sessionID, userID)
+	ret0, _ := ret[0].(*domain.ChatSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveChatSession indicates an expected call of UnarchiveChatSession.
+func (mr *MockChatSessionServiceInterfaceMockRecorder) UnarchiveChatSession(ctx, sessionID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChatSessions", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).GetUserChatSessions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveChatSession", reflect.TypeOf((*MockChatSessionServiceInterface)(nil).UnarchiveChatSession), ctx, sessionID, userID)
 }
 
 // UpdateChatSessionTitle mocks base method.
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/curatedAnswerService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/curatedAnswerService.go -destination=../mocks/mock_internal/core/services/curatedAnswerService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCuratedAnswerServiceInterface is a mock of CuratedAnswerServiceInterface interface.
+type MockCuratedAnswerServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCuratedAnswerServiceInterfaceMockRecorder
+}
+
+// MockCuratedAnswerServiceInterfaceMockRecorder is the mock recorder for MockCuratedAnswerServiceInterface.
+type MockCuratedAnswerServiceInterfaceMockRecorder struct {
+	mock *MockCuratedAnswerServiceInterface
+}
+
+// NewMockCuratedAnswerServiceInterface creates a new mock instance.
+func NewMockCuratedAnswerServiceInterface(ctrl *gomock.Controller) *MockCuratedAnswerServiceInterface {
+	mock := &MockCuratedAnswerServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCuratedAnswerServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCuratedAnswerServiceInterface) EXPECT() *MockCuratedAnswerServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ApproveCuratedAnswer mocks base method.
+func (m *MockCuratedAnswerServiceInterface) ApproveCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveCuratedAnswer", ctx, id)
+	ret0, _ := ret[0].(*domain.CuratedAnswer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveCuratedAnswer indicates an expected call of ApproveCuratedAnswer.
+func (mr *MockCuratedAnswerServiceInterfaceMockRecorder) ApproveCuratedAnswer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveCuratedAnswer", reflect.TypeOf((*MockCuratedAnswerServiceInterface)(nil).ApproveCuratedAnswer), ctx, id)
+}
+
+// GetCuratedAnswers mocks base method.
+func (m *MockCuratedAnswerServiceInterface) GetCuratedAnswers(ctx context.Context, status string) ([]*domain.CuratedAnswer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCuratedAnswers", ctx, status)
+	ret0, _ := ret[0].([]*domain.CuratedAnswer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCuratedAnswers indicates an expected call of GetCuratedAnswers.
+func (mr *MockCuratedAnswerServiceInterfaceMockRecorder) GetCuratedAnswers(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCuratedAnswers", reflect.TypeOf((*MockCuratedAnswerServiceInterface)(nil).GetCuratedAnswers), ctx, status)
+}
+
+// RejectCuratedAnswer mocks base method.
+func (m *MockCuratedAnswerServiceInterface) RejectCuratedAnswer(ctx context.Context, id uuid.UUID) (*domain.CuratedAnswer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectCuratedAnswer", ctx, id)
+	ret0, _ := ret[0].(*domain.CuratedAnswer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectCuratedAnswer indicates an expected call of RejectCuratedAnswer.
+func (mr *MockCuratedAnswerServiceInterfaceMockRecorder) RejectCuratedAnswer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectCuratedAnswer", reflect.TypeOf((*MockCuratedAnswerServiceInterface)(nil).RejectCuratedAnswer), ctx, id)
+}
+
+// MockChunkStore is a mock of ChunkStore interface.
+type MockChunkStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockChunkStoreMockRecorder
+}
+
+// MockChunkStoreMockRecorder is the mock recorder for MockChunkStore.
+type MockChunkStoreMockRecorder struct {
+	mock *MockChunkStore
+}
+
+// NewMockChunkStore creates a new mock instance.
+func NewMockChunkStore(ctrl *gomock.Controller) *MockChunkStore {
+	mock := &MockChunkStore{ctrl: ctrl}
+	mock.recorder = &MockChunkStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChunkStore) EXPECT() *MockChunkStoreMockRecorder {
+	return m.recorder
+}
+
+// UpsertChunk mocks base method.
+func (m *MockChunkStore) UpsertChunk(ctx context.Context, id string, embedding *domain.Embeddings, metadata map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertChunk", ctx, id, embedding, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertChunk indicates an expected call of UpsertChunk.
+func (mr *MockChunkStoreMockRecorder) UpsertChunk(ctx, id, embedding, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertChunk", reflect.TypeOf((*MockChunkStore)(nil).UpsertChunk), ctx, id, embedding, metadata)
+}
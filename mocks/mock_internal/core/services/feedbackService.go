@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/feedbackService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/feedbackService.go -destination=../mocks/mock_internal/core/services/feedbackService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedbackServiceInterface is a mock of FeedbackServiceInterface interface.
+type MockFeedbackServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedbackServiceInterfaceMockRecorder
+}
+
+// MockFeedbackServiceInterfaceMockRecorder is the mock recorder for MockFeedbackServiceInterface.
+type MockFeedbackServiceInterfaceMockRecorder struct {
+	mock *MockFeedbackServiceInterface
+}
+
+// NewMockFeedbackServiceInterface creates a new mock instance.
+func NewMockFeedbackServiceInterface(ctrl *gomock.Controller) *MockFeedbackServiceInterface {
+	mock := &MockFeedbackServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockFeedbackServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedbackServiceInterface) EXPECT() *MockFeedbackServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetFeedbackAnalytics mocks base method.
+func (m *MockFeedbackServiceInterface) GetFeedbackAnalytics(ctx context.Context, from, to time.Time, interval string) ([]*domain.FeedbackAnalytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedbackAnalytics", ctx, from, to, interval)
+	ret0, _ := ret[0].([]*domain.FeedbackAnalytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedbackAnalytics indicates an expected call of GetFeedbackAnalytics.
+func (mr *MockFeedbackServiceInterfaceMockRecorder) GetFeedbackAnalytics(ctx, from, to, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedbackAnalytics", reflect.TypeOf((*MockFeedbackServiceInterface)(nil).GetFeedbackAnalytics), ctx, from, to, interval)
+}
+
+// GetFeedbackEntries mocks base method.
+func (m *MockFeedbackServiceInterface) GetFeedbackEntries(ctx context.Context, from, to time.Time) ([]*domain.FeedbackEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedbackEntries", ctx, from, to)
+	ret0, _ := ret[0].([]*domain.FeedbackEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedbackEntries indicates an expected call of GetFeedbackEntries.
+func (mr *MockFeedbackServiceInterfaceMockRecorder) GetFeedbackEntries(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedbackEntries", reflect.TypeOf((*MockFeedbackServiceInterface)(nil).GetFeedbackEntries), ctx, from, to)
+}
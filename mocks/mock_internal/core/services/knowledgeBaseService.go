@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/knowledgeBaseService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/knowledgeBaseService.go -destination=../mocks/mock_internal/core/services/knowledgeBaseService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKnowledgeBaseServiceInterface is a mock of KnowledgeBaseServiceInterface interface.
+type MockKnowledgeBaseServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKnowledgeBaseServiceInterfaceMockRecorder
+}
+
+// MockKnowledgeBaseServiceInterfaceMockRecorder is the mock recorder for MockKnowledgeBaseServiceInterface.
+type MockKnowledgeBaseServiceInterfaceMockRecorder struct {
+	mock *MockKnowledgeBaseServiceInterface
+}
+
+// NewMockKnowledgeBaseServiceInterface creates a new mock instance.
+func NewMockKnowledgeBaseServiceInterface(ctrl *gomock.Controller) *MockKnowledgeBaseServiceInterface {
+	mock := &MockKnowledgeBaseServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockKnowledgeBaseServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKnowledgeBaseServiceInterface) EXPECT() *MockKnowledgeBaseServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetDocumentContent mocks base method.
+func (m *MockKnowledgeBaseServiceInterface) GetDocumentContent(ctx context.Context, documentType string) (*domain.Document, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentContent", ctx, documentType)
+	ret0, _ := ret[0].(*domain.Document)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDocumentContent indicates an expected call of GetDocumentContent.
+func (mr *MockKnowledgeBaseServiceInterfaceMockRecorder) GetDocumentContent(ctx, documentType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentContent", reflect.TypeOf((*MockKnowledgeBaseServiceInterface)(nil).GetDocumentContent), ctx, documentType)
+}
+
+// GetDocuments mocks base method.
+func (m *MockKnowledgeBaseServiceInterface) GetDocuments() []*domain.Document {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocuments")
+	ret0, _ := ret[0].([]*domain.Document)
+	return ret0
+}
+
+// GetDocuments indicates an expected call of GetDocuments.
+func (mr *MockKnowledgeBaseServiceInterfaceMockRecorder) GetDocuments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocuments", reflect.TypeOf((*MockKnowledgeBaseServiceInterface)(nil).GetDocuments))
+}
+
+// Search mocks base method.
+func (m *MockKnowledgeBaseServiceInterface) Search(ctx context.Context, userID uuid.UUID, query string, options domain.SearchOptions) ([]*domain.RetrievedChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userID, query, options)
+	ret0, _ := ret[0].([]*domain.RetrievedChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockKnowledgeBaseServiceInterfaceMockRecorder) Search(ctx, userID, query, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockKnowledgeBaseServiceInterface)(nil).Search), ctx, userID, query, options)
+}
@@ -15,6 +15,7 @@ import (
 
 	uuid "github.com/google/uuid"
 	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	openai "github.com/openai/openai-go"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -56,6 +57,21 @@ func (mr *MockMessageServiceInterfaceMockRecorder) CreateMessage(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessageServiceInterface)(nil).CreateMessage), arg0, arg1, arg2)
 }
 
+// GetAgentAnswerForMessage mocks base method.
+func (m *MockMessageServiceInterface) GetAgentAnswerForMessage(arg0 context.Context, arg1 uuid.UUID) (*domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgentAnswerForMessage", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentAnswerForMessage indicates an expected call of GetAgentAnswerForMessage.
+func (mr *MockMessageServiceInterfaceMockRecorder) GetAgentAnswerForMessage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentAnswerForMessage", reflect.TypeOf((*MockMessageServiceInterface)(nil).GetAgentAnswerForMessage), arg0, arg1)
+}
+
 // GetAnswerForMessage mocks base method.
 func (m *MockMessageServiceInterface) GetAnswerForMessage(arg0 context.Context, arg1 uuid.UUID) (*domain.Message, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +87,36 @@ func (mr *MockMessageServiceInterfaceMockRecorder) GetAnswerForMessage(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerForMessage", reflect.TypeOf((*MockMessageServiceInterface)(nil).GetAnswerForMessage), arg0, arg1)
 }
 
+// GetChatSessionMessages mocks base method.
+func (m *MockMessageServiceInterface) GetChatSessionMessages(ctx context.Context, chatSessionID, userID uuid.UUID, filter *domain.MessageFilter) (*domain.MessagePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatSessionMessages", ctx, chatSessionID, userID, filter)
+	ret0, _ := ret[0].(*domain.MessagePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatSessionMessages indicates an expected call of GetChatSessionMessages.
+func (mr *MockMessageServiceInterfaceMockRecorder) GetChatSessionMessages(ctx, chatSessionID, userID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSessionMessages", reflect.TypeOf((*MockMessageServiceInterface)(nil).GetChatSessionMessages), ctx, chatSessionID, userID, filter)
+}
+
+// RetrieveContext mocks base method.
+func (m *MockMessageServiceInterface) RetrieveContext(ctx context.Context, query string) ([]*domain.RetrievedChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveContext", ctx, query)
+	ret0, _ := ret[0].([]*domain.RetrievedChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveContext indicates an expected call of RetrieveContext.
+func (mr *MockMessageServiceInterfaceMockRecorder) RetrieveContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveContext", reflect.TypeOf((*MockMessageServiceInterface)(nil).RetrieveContext), ctx, query)
+}
+
 // UpdateMessageFeedback mocks base method.
 func (m *MockMessageServiceInterface) UpdateMessageFeedback(ctx context.Context, message *domain.Message, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -146,11 +192,26 @@ func (m *MockVectorDB) EXPECT() *MockVectorDBMockRecorder {
 	return m.recorder
 }
 
+// Search mocks base method.
+func (m *MockVectorDB) Search(ctx context.Context, embeddings []float32, options domain.SearchOptions) ([]*domain.RetrievedChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, embeddings, options)
+	ret0, _ := ret[0].([]*domain.RetrievedChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockVectorDBMockRecorder) Search(ctx, embeddings, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockVectorDB)(nil).Search), ctx, embeddings, options)
+}
+
 // SemanticSearch mocks base method.
-func (m *MockVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]string, error) {
+func (m *MockVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]*domain.RetrievedChunk, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SemanticSearch", ctx, embeddings)
-	ret0, _ := ret[0].([]string)
+	ret0, _ := ret[0].([]*domain.RetrievedChunk)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -160,3 +221,41 @@ func (mr *MockVectorDBMockRecorder) SemanticSearch(ctx, embeddings any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SemanticSearch", reflect.TypeOf((*MockVectorDB)(nil).SemanticSearch), ctx, embeddings)
 }
+
+// MockChatProvider is a mock of ChatProvider interface.
+type MockChatProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatProviderMockRecorder
+}
+
+// MockChatProviderMockRecorder is the mock recorder for MockChatProvider.
+type MockChatProviderMockRecorder struct {
+	mock *MockChatProvider
+}
+
+// NewMockChatProvider creates a new mock instance.
+func NewMockChatProvider(ctrl *gomock.Controller) *MockChatProvider {
+	mock := &MockChatProvider{ctrl: ctrl}
+	mock.recorder = &MockChatProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatProvider) EXPECT() *MockChatProviderMockRecorder {
+	return m.recorder
+}
+
+// NewChatCompletion mocks base method.
+func (m *MockChatProvider) NewChatCompletion(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewChatCompletion", ctx, params)
+	ret0, _ := ret[0].(*openai.ChatCompletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewChatCompletion indicates an expected call of NewChatCompletion.
+func (mr *MockChatProviderMockRecorder) NewChatCompletion(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewChatCompletion", reflect.TypeOf((*MockChatProvider)(nil).NewChatCompletion), ctx, params)
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/oidcAuthService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/oidcAuthService.go -destination=../mocks/mock_internal/core/services/oidcAuthService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOIDCAuthServiceInterface is a mock of OIDCAuthServiceInterface interface.
+type MockOIDCAuthServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCAuthServiceInterfaceMockRecorder
+}
+
+// MockOIDCAuthServiceInterfaceMockRecorder is the mock recorder for MockOIDCAuthServiceInterface.
+type MockOIDCAuthServiceInterfaceMockRecorder struct {
+	mock *MockOIDCAuthServiceInterface
+}
+
+// NewMockOIDCAuthServiceInterface creates a new mock instance.
+func NewMockOIDCAuthServiceInterface(ctrl *gomock.Controller) *MockOIDCAuthServiceInterface {
+	mock := &MockOIDCAuthServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockOIDCAuthServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCAuthServiceInterface) EXPECT() *MockOIDCAuthServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockOIDCAuthServiceInterface) Authenticate(ctx context.Context, token string) (jwt.MapClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(jwt.MapClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockOIDCAuthServiceInterfaceMockRecorder) Authenticate(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockOIDCAuthServiceInterface)(nil).Authenticate), ctx, token)
+}
+
+// Issuer mocks base method.
+func (m *MockOIDCAuthServiceInterface) Issuer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer.
+func (mr *MockOIDCAuthServiceInterfaceMockRecorder) Issuer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*MockOIDCAuthServiceInterface)(nil).Issuer))
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/tokenDenylistService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/tokenDenylistService.go -destination=../mocks/mock_internal/core/services/tokenDenylistService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenDenylistServiceInterface is a mock of TokenDenylistServiceInterface interface.
+type MockTokenDenylistServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenDenylistServiceInterfaceMockRecorder
+}
+
+// MockTokenDenylistServiceInterfaceMockRecorder is the mock recorder for MockTokenDenylistServiceInterface.
+type MockTokenDenylistServiceInterfaceMockRecorder struct {
+	mock *MockTokenDenylistServiceInterface
+}
+
+// NewMockTokenDenylistServiceInterface creates a new mock instance.
+func NewMockTokenDenylistServiceInterface(ctrl *gomock.Controller) *MockTokenDenylistServiceInterface {
+	mock := &MockTokenDenylistServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTokenDenylistServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenDenylistServiceInterface) EXPECT() *MockTokenDenylistServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockTokenDenylistServiceInterface) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, tokenID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockTokenDenylistServiceInterfaceMockRecorder) IsTokenRevoked(ctx, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockTokenDenylistServiceInterface)(nil).IsTokenRevoked), ctx, tokenID)
+}
+
+// RevokeToken mocks base method.
+func (m *MockTokenDenylistServiceInterface) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, tokenID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockTokenDenylistServiceInterfaceMockRecorder) RevokeToken(ctx, tokenID, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockTokenDenylistServiceInterface)(nil).RevokeToken), ctx, tokenID, expiresAt)
+}
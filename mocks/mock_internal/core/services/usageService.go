@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/usageService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/usageService.go -destination=../mocks/mock_internal/core/services/usageService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsageServiceInterface is a mock of UsageServiceInterface interface.
+type MockUsageServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageServiceInterfaceMockRecorder
+}
+
+// MockUsageServiceInterfaceMockRecorder is the mock recorder for MockUsageServiceInterface.
+type MockUsageServiceInterfaceMockRecorder struct {
+	mock *MockUsageServiceInterface
+}
+
+// NewMockUsageServiceInterface creates a new mock instance.
+func NewMockUsageServiceInterface(ctrl *gomock.Controller) *MockUsageServiceInterface {
+	mock := &MockUsageServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockUsageServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageServiceInterface) EXPECT() *MockUsageServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CheckQuota mocks base method.
+func (m *MockUsageServiceInterface) CheckQuota(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckQuota", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckQuota indicates an expected call of CheckQuota.
+func (mr *MockUsageServiceInterfaceMockRecorder) CheckQuota(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckQuota", reflect.TypeOf((*MockUsageServiceInterface)(nil).CheckQuota), ctx, userID)
+}
+
+// GetQuotaStatus mocks base method.
+func (m *MockUsageServiceInterface) GetQuotaStatus(ctx context.Context, userID uuid.UUID) (*domain.QuotaStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaStatus", ctx, userID)
+	ret0, _ := ret[0].(*domain.QuotaStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaStatus indicates an expected call of GetQuotaStatus.
+func (mr *MockUsageServiceInterfaceMockRecorder) GetQuotaStatus(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaStatus", reflect.TypeOf((*MockUsageServiceInterface)(nil).GetQuotaStatus), ctx, userID)
+}
+
+// GetUserUsage mocks base method.
+func (m *MockUsageServiceInterface) GetUserUsage(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]*domain.UsageAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserUsage", ctx, userID, from, to, interval)
+	ret0, _ := ret[0].([]*domain.UsageAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserUsage indicates an expected call of GetUserUsage.
+func (mr *MockUsageServiceInterfaceMockRecorder) GetUserUsage(ctx, userID, from, to, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserUsage", reflect.TypeOf((*MockUsageServiceInterface)(nil).GetUserUsage), ctx, userID, from, to, interval)
+}
+
+// RecordUsage mocks base method.
+func (m *MockUsageServiceInterface) RecordUsage(ctx context.Context, usage *domain.TokenUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordUsage", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordUsage indicates an expected call of RecordUsage.
+func (mr *MockUsageServiceInterfaceMockRecorder) RecordUsage(ctx, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordUsage", reflect.TypeOf((*MockUsageServiceInterface)(nil).RecordUsage), ctx, usage)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../internal/core/services/vehicleComparisonService.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../internal/core/services/vehicleComparisonService.go -destination=../mocks/mock_internal/core/services/vehicleComparisonService.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/loukaspe/rag-golang/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVehicleComparisonServiceInterface is a mock of VehicleComparisonServiceInterface interface.
+type MockVehicleComparisonServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockVehicleComparisonServiceInterfaceMockRecorder
+}
+
+// MockVehicleComparisonServiceInterfaceMockRecorder is the mock recorder for MockVehicleComparisonServiceInterface.
+type MockVehicleComparisonServiceInterfaceMockRecorder struct {
+	mock *MockVehicleComparisonServiceInterface
+}
+
+// NewMockVehicleComparisonServiceInterface creates a new mock instance.
+func NewMockVehicleComparisonServiceInterface(ctrl *gomock.Controller) *MockVehicleComparisonServiceInterface {
+	mock := &MockVehicleComparisonServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockVehicleComparisonServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVehicleComparisonServiceInterface) EXPECT() *MockVehicleComparisonServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CompareVehicles mocks base method.
+func (m *MockVehicleComparisonServiceInterface) CompareVehicles(ctx context.Context, filter *domain.VehicleComparisonFilter) ([]*domain.VehicleComparisonFact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareVehicles", ctx, filter)
+	ret0, _ := ret[0].([]*domain.VehicleComparisonFact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareVehicles indicates an expected call of CompareVehicles.
+func (mr *MockVehicleComparisonServiceInterfaceMockRecorder) CompareVehicles(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareVehicles", reflect.TypeOf((*MockVehicleComparisonServiceInterface)(nil).CompareVehicles), ctx, filter)
+}
+
+// IngestVehicleComparisons mocks base method.
+func (m *MockVehicleComparisonServiceInterface) IngestVehicleComparisons(ctx context.Context, text string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IngestVehicleComparisons", ctx, text)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IngestVehicleComparisons indicates an expected call of IngestVehicleComparisons.
+func (mr *MockVehicleComparisonServiceInterfaceMockRecorder) IngestVehicleComparisons(ctx, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IngestVehicleComparisons", reflect.TypeOf((*MockVehicleComparisonServiceInterface)(nil).IngestVehicleComparisons), ctx, text)
+}
@@ -4,38 +4,71 @@ import (
 	"context"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"time"
 )
 
-type claimskey int
-
-var claimsKey claimskey
-
 type AuthMechanism struct {
-	secret        []byte
-	signingMethod string
+	secret         []byte
+	signingMethod  string
+	accessTokenTTL time.Duration
+	// keySet replaces the secret when tokens are signed with asymmetric keys
+	keySet *KeySet
 }
 
 func NewAuthMechanism(
 	secret string,
 	signingMethod string,
+	accessTokenTTL time.Duration,
+) *AuthMechanism {
+	return &AuthMechanism{
+		secret:         []byte(secret),
+		signingMethod:  signingMethod,
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+// NewAsymmetricAuthMechanism signs tokens with the signing key of the key set
+// and verifies them with the key named by their kid header
+func NewAsymmetricAuthMechanism(
+	keySet *KeySet,
+	accessTokenTTL time.Duration,
 ) *AuthMechanism {
 	return &AuthMechanism{
-		secret:        []byte(secret),
-		signingMethod: signingMethod,
+		signingMethod:  keySet.SigningKey().Method.Alg(),
+		accessTokenTTL: accessTokenTTL,
+		keySet:         keySet,
 	}
 }
-func (j *AuthMechanism) CreateToken(sub string, userInfo interface{}) (string, error) {
+
+// KeySet is nil when tokens are signed with a shared secret
+func (j *AuthMechanism) KeySet() *KeySet {
+	return j.keySet
+}
+
+func (j *AuthMechanism) CreateToken(sub string, role string, userInfo interface{}) (string, error) {
 	token := jwt.New(jwt.GetSigningMethod(j.signingMethod))
-	expiration := time.Now().Add(time.Hour)
+	var signingKey interface{} = j.secret
+	if j.keySet != nil {
+		key := j.keySet.SigningKey()
+		token.Header["kid"] = key.ID
+		signingKey = key.PrivateKey
+	}
+
+	now := time.Now()
 	token.Claims = &domain.JwtClaims{
-		&jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiration),
+		RegisteredClaims: &jwt.RegisteredClaims{
+			// the ID lets a single access token be revoked before it expires
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.accessTokenTTL)),
 			Subject:   sub,
-		}, userInfo,
+		},
+		Role:     role,
+		UserInfo: userInfo,
 	}
-	val, err := token.SignedString(j.secret)
+	val, err := token.SignedString(signingKey)
 	if err != nil {
 
 		return "", err
@@ -43,12 +76,7 @@ func (j *AuthMechanism) CreateToken(sub string, userInfo interface{}) (string, e
 	return val, nil
 }
 func (j *AuthMechanism) GetClaimsFromToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.secret, nil
-	})
+	token, err := jwt.Parse(tokenString, j.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +85,28 @@ func (j *AuthMechanism) GetClaimsFromToken(tokenString string) (jwt.MapClaims, e
 	}
 	return nil, err
 }
+func (j *AuthMechanism) verificationKey(token *jwt.Token) (interface{}, error) {
+	if j.keySet == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return j.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keySet.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+
+	// the algorithm is pinned by the key, never taken from the token alone
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
 func (j *AuthMechanism) SetJWTClaimsContext(ctx context.Context, claims jwt.MapClaims) context.Context {
-	return context.WithValue(ctx, claimsKey, claims)
+	return ContextWithClaims(ctx, claims)
 }
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"slices"
+	"time"
+)
+
+type claimskey int
+
+var claimsKey claimskey
+
+var ErrNoAuthenticatedUser = errors.New("no authenticated user in context")
+
+const RoleClaim = "role"
+
+// The claims of a request authenticated with an API key, next to its owner as
+// the subject. Requests authenticated with a token carry no scopes, they are
+// not restricted.
+const (
+	APIKeyIDClaim     = "api_key_id"
+	APIKeyScopesClaim = "api_key_scopes"
+)
+
+// APIKeyClaims are the claims of a request authenticated with the API key,
+// which acts with the current role of its owner
+func APIKeyClaims(apiKey *domain.APIKey) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":             apiKey.UserID.String(),
+		RoleClaim:         apiKey.UserRole,
+		APIKeyIDClaim:     apiKey.ID.String(),
+		APIKeyScopesClaim: apiKey.Scopes,
+	}
+}
+
+func ContextWithClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(jwt.MapClaims)
+	return claims, ok
+}
+
+// UserIDFromContext returns the ID of the authenticated user, which is the
+// subject of the token that AuthenticationMW stored in the context
+func UserIDFromContext(ctx context.Context) (uuid.UUID, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrNoAuthenticatedUser
+	}
+
+	subject, _ := claims["sub"].(string)
+
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return uuid.Nil, errors.New("token subject is not a user id")
+	}
+
+	return userID, nil
+}
+
+// TokenIDFromContext returns the ID and the expiration of the authenticated
+// access token, which is what revoking it needs
+func TokenIDFromContext(ctx context.Context) (string, time.Time, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return "", time.Time{}, ErrNoAuthenticatedUser
+	}
+
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return "", time.Time{}, errors.New("token has no id")
+	}
+
+	expiresAt, _ := claims["exp"].(float64)
+
+	return tokenID, time.Unix(int64(expiresAt), 0), nil
+}
+
+// RoleFromContext returns the role of the authenticated user, the role claim
+// of its token
+func RoleFromContext(ctx context.Context) (string, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return "", ErrNoAuthenticatedUser
+	}
+
+	role, _ := claims[RoleClaim].(string)
+
+	return role, nil
+}
+
+// IsAPIKeyFromContext tells whether the request was authenticated with an API
+// key rather than a token
+func IsAPIKeyFromContext(ctx context.Context) bool {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	_, ok = claims[APIKeyIDClaim]
+
+	return ok
+}
+
+// HasScope tells whether the authenticated request may use the scope. Only
+// API keys are limited to the scopes they were granted.
+func HasScope(ctx context.Context, scope string) bool {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	if _, ok = claims[APIKeyIDClaim]; !ok {
+		return true
+	}
+
+	scopes, _ := claims[APIKeyScopesClaim].([]string)
+
+	return slices.Contains(scopes, scope)
+}
+
+// RateLimitKeyFromContext returns the key the authenticated request is rate
+// limited by. Every API key has its own limit, so that a busy integration does
+// not use up the limit of its owner.
+func RateLimitKeyFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	if apiKeyID, ok := claims[APIKeyIDClaim].(string); ok && apiKeyID != "" {
+		return "api_key:" + apiKeyID, true
+	}
+
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		return "user:" + sub, true
+	}
+
+	return "", false
+}
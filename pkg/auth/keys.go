@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SigningKey is an asymmetric key identified by its kid. Keys loaded from a
+// public key only verify tokens, which is how retired keys are kept around
+// until the tokens they signed expire.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// KeySet holds the keys that verify tokens and the one that signs new ones.
+// Rotating keys means adding a new key, making it the signing one, and
+// removing the old one once the tokens it signed have expired.
+type KeySet struct {
+	signingKeyID string
+	keys         map[string]*SigningKey
+}
+
+// LoadKeySet reads PEM files given as a map of kid to path. The signing key
+// must be a private key; the others can be private or public.
+func LoadKeySet(paths map[string]string, signingKeyID string) (*KeySet, error) {
+	keys := make(map[string]*SigningKey, len(paths))
+
+	for kid, path := range paths {
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key %s: %w", kid, err)
+		}
+
+		key, err := ParseSigningKey(kid, pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing key %s: %w", kid, err)
+		}
+
+		keys[kid] = key
+	}
+
+	return NewKeySet(keys, signingKeyID)
+}
+
+func NewKeySet(keys map[string]*SigningKey, signingKeyID string) (*KeySet, error) {
+	signingKey, ok := keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not in the key set", signingKeyID)
+	}
+
+	if signingKey.PrivateKey == nil {
+		return nil, fmt.Errorf("signing key %s has no private key", signingKeyID)
+	}
+
+	return &KeySet{
+		signingKeyID: signingKeyID,
+		keys:         keys,
+	}, nil
+}
+
+// ParseKeyPaths parses the "kid=path,kid=path" format of the JWT_SIGNING_KEYS env
+func ParseKeyPaths(value string) (map[string]string, error) {
+	paths := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(pair, "=")
+		if !found || kid == "" || path == "" {
+			return nil, fmt.Errorf("malformed key %q, expected kid=path", pair)
+		}
+
+		paths[strings.TrimSpace(kid)] = strings.TrimSpace(path)
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+
+	return paths, nil
+}
+
+// ParseSigningKey parses a PEM encoded RSA or EC key, private (PKCS#1, SEC 1
+// or PKCS#8) or public (PKIX). The signing method follows from the key type:
+// RS256 for RSA and ES256/ES384/ES512 for the P-256/P-384/P-521 curves.
+func ParseSigningKey(kid string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key := &SigningKey{ID: kid}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = privateKey
+	case "EC PRIVATE KEY":
+		privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = privateKey
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		key.PrivateKey = signer
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.PublicKey = publicKey
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+
+	if key.PrivateKey != nil {
+		key.PublicKey = key.PrivateKey.Public()
+	}
+
+	method, err := signingMethodForKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key.Method = method
+
+	return key, nil
+}
+
+func signingMethodForKey(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, errors.New("unsupported elliptic curve")
+	default:
+		return nil, errors.New("only RSA and EC keys are supported")
+	}
+}
+
+func (keySet *KeySet) SigningKey() *SigningKey {
+	return keySet.keys[keySet.signingKeyID]
+}
+
+func (keySet *KeySet) Key(kid string) (*SigningKey, bool) {
+	key, ok := keySet.keys[kid]
+	return key, ok
+}
+
+// Keys returns the keys sorted by kid, so that the JWKS is stable
+func (keySet *KeySet) Keys() []*SigningKey {
+	keys := make([]*SigningKey, 0, len(keySet.keys))
+	for _, key := range keySet.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys
+}
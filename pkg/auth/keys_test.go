@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func generateRSAKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))
+}
+
+func generateECKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, writePEM(t, "PRIVATE KEY", der)
+}
+
+func TestLoadKeySet_SignsAndVerifies(t *testing.T) {
+	_, rsaPath := generateRSAKey(t)
+	_, ecPath := generateECKey(t)
+
+	tests := []struct {
+		name           string
+		paths          map[string]string
+		signingKeyID   string
+		expectedMethod string
+	}{
+		{
+			name:           "RS256",
+			paths:          map[string]string{"rsa-1": rsaPath},
+			signingKeyID:   "rsa-1",
+			expectedMethod: "RS256",
+		},
+		{
+			name:           "ES256",
+			paths:          map[string]string{"ec-1": ecPath},
+			signingKeyID:   "ec-1",
+			expectedMethod: "ES256",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keySet, err := LoadKeySet(tt.paths, tt.signingKeyID)
+			if err != nil {
+				t.Fatalf("LoadKeySet() error = %v", err)
+			}
+
+			mechanism := NewAsymmetricAuthMechanism(keySet, time.Minute)
+
+			token, err := mechanism.CreateToken("12345678-0000-0000-0000-000000000000", "user", nil)
+			if err != nil {
+				t.Fatalf("CreateToken() error = %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedMethod, parsed.Header["alg"])
+			assert.Equal(t, tt.signingKeyID, parsed.Header["kid"])
+
+			claims, err := mechanism.GetClaimsFromToken(token)
+			if err != nil {
+				t.Fatalf("GetClaimsFromToken() error = %v", err)
+			}
+			assert.Equal(t, "12345678-0000-0000-0000-000000000000", claims["sub"])
+			assert.Equal(t, "user", claims[RoleClaim])
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldPrivateKey, oldPath := generateRSAKey(t)
+	_, newPath := generateECKey(t)
+
+	oldKeySet, err := LoadKeySet(map[string]string{"old": oldPath}, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldToken, err := NewAsymmetricAuthMechanism(oldKeySet, time.Minute).CreateToken("user", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the old key is retired to its public part, the new one signs
+	publicKeyDer, err := x509.MarshalPKIXPublicKey(&oldPrivateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retiredPath := writePEM(t, "PUBLIC KEY", publicKeyDer)
+
+	rotatedKeySet, err := LoadKeySet(map[string]string{"old": retiredPath, "new": newPath}, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotatedMechanism := NewAsymmetricAuthMechanism(rotatedKeySet, time.Minute)
+
+	_, err = rotatedMechanism.GetClaimsFromToken(oldToken)
+	assert.Nil(t, err, "tokens of the retired key still verify")
+
+	newToken, err := rotatedMechanism.CreateToken("user", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "new", parsed.Header["kid"])
+
+	_, err = LoadKeySet(map[string]string{"old": retiredPath}, "old")
+	assert.EqualError(t, err, "signing key old has no private key")
+}
+
+func TestAuthMechanism_GetClaimsFromTokenRejectsForeignTokens(t *testing.T) {
+	_, rsaPath := generateRSAKey(t)
+	otherPrivateKey, _ := generateRSAKey(t)
+
+	keySet, err := LoadKeySet(map[string]string{"rsa-1": rsaPath}, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mechanism := NewAsymmetricAuthMechanism(keySet, time.Minute)
+
+	claims := jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Minute).Unix()}
+
+	unknownKid := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	unknownKid.Header["kid"] = "rsa-2"
+	unknownKidToken, _ := unknownKid.SignedString(otherPrivateKey)
+
+	wrongKey := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	wrongKey.Header["kid"] = "rsa-1"
+	wrongKeyToken, _ := wrongKey.SignedString(otherPrivateKey)
+
+	// an HMAC token keyed with the public key must not pass as RS256
+	publicKeyDer, _ := x509.MarshalPKIXPublicKey(keySet.SigningKey().PublicKey)
+	algConfusion := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	algConfusion.Header["kid"] = "rsa-1"
+	algConfusionToken, _ := algConfusion.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDer}))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "unknown kid", token: unknownKidToken},
+		{name: "signed by another key", token: wrongKeyToken},
+		{name: "algorithm confusion", token: algConfusionToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mechanism.GetClaimsFromToken(tt.token)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaPrivateKey, rsaPath := generateRSAKey(t)
+	ecPrivateKey, ecPath := generateECKey(t)
+
+	keySet, err := LoadKeySet(map[string]string{"rsa-1": rsaPath, "ec-1": ecPath}, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := keySet.JWKS()
+
+	if !assert.Len(t, jwks.Keys, 2) {
+		return
+	}
+
+	ecJWK := jwks.Keys[0]
+	assert.Equal(t, "ec-1", ecJWK.KeyID)
+	assert.Equal(t, "EC", ecJWK.KeyType)
+	assert.Equal(t, "ES256", ecJWK.Algorithm)
+	assert.Equal(t, "P-256", ecJWK.Curve)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(ecPrivateKey.X.FillBytes(make([]byte, 32))), ecJWK.X)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(ecPrivateKey.Y.FillBytes(make([]byte, 32))), ecJWK.Y)
+
+	rsaJWK := jwks.Keys[1]
+	assert.Equal(t, "rsa-1", rsaJWK.KeyID)
+	assert.Equal(t, "RSA", rsaJWK.KeyType)
+	assert.Equal(t, "RS256", rsaJWK.Algorithm)
+	assert.Equal(t, "sig", rsaJWK.Use)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+
+	modulus, err := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, new(big.Int).SetBytes(modulus).Cmp(rsaPrivateKey.N))
+}
+
+func TestParseKeyPaths(t *testing.T) {
+	paths, err := ParseKeyPaths("2025-05=/keys/old.pem, 2025-06=/keys/new.pem")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"2025-05": "/keys/old.pem", "2025-06": "/keys/new.pem"}, paths)
+
+	_, err = ParseKeyPaths("/keys/old.pem")
+	assert.EqualError(t, err, `malformed key "/keys/old.pem", expected kid=path`)
+
+	_, err = ParseKeyPaths("")
+	assert.EqualError(t, err, "no signing keys configured")
+}
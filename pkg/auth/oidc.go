@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// minJWKSRefreshInterval limits how often a token with an unknown kid can make
+// the verifier fetch the JWKS again, so that such tokens cannot flood the
+// identity provider
+const minJWKSRefreshInterval = time.Minute
+
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JwksURI string `json:"jwks_uri"`
+}
+
+// OIDCVerifier verifies bearer tokens issued by an OpenID Connect provider.
+// The JWKS of the provider is cached for cacheTTL and fetched again earlier
+// when a token carries an unknown kid, which is how provider key rotations are
+// picked up.
+type OIDCVerifier struct {
+	issuer     string
+	audience   string
+	jwksURI    string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewOIDCVerifier reads the discovery document of the issuer and fetches its
+// JWKS once, so that a misconfigured issuer fails at startup
+func NewOIDCVerifier(
+	ctx context.Context,
+	issuer string,
+	audience string,
+	httpClient *http.Client,
+	cacheTTL time.Duration,
+) (*OIDCVerifier, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	var discovery discoveryDocument
+	err := getJSON(ctx, httpClient, issuer+discoveryPath, &discovery)
+	if err != nil {
+		return nil, fmt.Errorf("reading discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery document is for issuer %s, expected %s", discovery.Issuer, issuer)
+	}
+
+	if discovery.JwksURI == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+
+	verifier := &OIDCVerifier{
+		issuer:     discovery.Issuer,
+		audience:   audience,
+		jwksURI:    discovery.JwksURI,
+		httpClient: httpClient,
+		cacheTTL:   cacheTTL,
+	}
+
+	err = verifier.refreshKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+func (verifier *OIDCVerifier) Issuer() string {
+	return verifier.issuer
+}
+
+// Verify checks the signature, the issuer, the audience and the expiration of
+// a token and returns its claims
+func (verifier *OIDCVerifier) Verify(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(oidcSigningMethods))
+
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return verifier.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if !claims.VerifyIssuer(verifier.issuer, true) {
+		return nil, errors.New("token has an unexpected issuer")
+	}
+
+	if !claims.VerifyAudience(verifier.audience, true) {
+		return nil, errors.New("token has an unexpected audience")
+	}
+
+	// jwt only checks exp when it is present, provider tokens must have it
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("token has no expiration")
+	}
+
+	return claims, nil
+}
+
+func (verifier *OIDCVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	verifier.mu.RLock()
+	key, ok := verifier.keys[kid]
+	fetchedAt := verifier.fetchedAt
+	verifier.mu.RUnlock()
+
+	sinceFetch := time.Since(fetchedAt)
+	if ok && sinceFetch < verifier.cacheTTL {
+		return key, nil
+	}
+
+	if ok || sinceFetch >= minJWKSRefreshInterval {
+		err := verifier.refreshKeys(ctx)
+		if err != nil {
+			// a provider outage should not lock out tokens of known keys
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+
+		verifier.mu.RLock()
+		key, ok = verifier.keys[kid]
+		verifier.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+
+	return key, nil
+}
+
+func (verifier *OIDCVerifier) refreshKeys(ctx context.Context) error {
+	var jwks JWKS
+	err := getJSON(ctx, verifier.httpClient, verifier.jwksURI, &jwks)
+	if err != nil {
+		return fmt.Errorf("fetching jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			// keys of unsupported types are skipped, not fatal
+			continue
+		}
+
+		keys[jwk.KeyID] = publicKey
+	}
+
+	verifier.mu.Lock()
+	verifier.keys = keys
+	verifier.fetchedAt = time.Now()
+	verifier.mu.Unlock()
+
+	return nil
+}
+
+// UnverifiedIssuer reads the iss claim of a token without verifying it, to
+// pick the verifier of the token
+func UnverifiedIssuer(tokenString string) string {
+	claims := jwt.MapClaims{}
+
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return ""
+	}
+
+	issuer, _ := claims["iss"].(string)
+
+	return issuer
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, url string, target interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(target)
+}
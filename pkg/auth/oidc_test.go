@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubIssuer is an OpenID Connect provider serving discovery and a JWKS of
+// keys that tests can rotate
+type stubIssuer struct {
+	server      *httptest.Server
+	jwksFetches atomic.Int32
+
+	mu     sync.Mutex
+	issuer string
+	keySet *KeySet
+}
+
+func newStubIssuer(t *testing.T, keySet *KeySet) *stubIssuer {
+	t.Helper()
+
+	stub := &stubIssuer{keySet: keySet}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:  stub.issuer,
+			JwksURI: stub.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		stub.jwksFetches.Add(1)
+		json.NewEncoder(w).Encode(stub.keySet.JWKS())
+	})
+
+	stub.server = httptest.NewServer(mux)
+	stub.issuer = stub.server.URL
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (stub *stubIssuer) rotate(keySet *KeySet) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	stub.keySet = keySet
+}
+
+func newTestKeySet(t *testing.T, kid string) *KeySet {
+	t.Helper()
+
+	_, path := generateRSAKey(t)
+
+	keySet, err := LoadKeySet(map[string]string{kid: path}, kid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keySet
+}
+
+func signTestToken(t *testing.T, keySet *KeySet, claims jwt.MapClaims) string {
+	t.Helper()
+
+	key := keySet.SigningKey()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenString
+}
+
+func TestOIDCVerifier_Verify(t *testing.T) {
+	keySet := newTestKeySet(t, "provider-1")
+	stub := newStubIssuer(t, keySet)
+
+	verifier, err := NewOIDCVerifier(context.Background(), stub.issuer, "rag-golang", http.DefaultClient, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Minute).Unix()
+
+	tests := []struct {
+		name          string
+		claims        jwt.MapClaims
+		keySet        *KeySet
+		expectedError string
+	}{
+		{
+			name:   "valid",
+			claims: jwt.MapClaims{"iss": stub.issuer, "aud": "rag-golang", "sub": "luke", "exp": expiresAt},
+			keySet: keySet,
+		},
+		{
+			name:   "valid with audience list",
+			claims: jwt.MapClaims{"iss": stub.issuer, "aud": []string{"other", "rag-golang"}, "sub": "luke", "exp": expiresAt},
+			keySet: keySet,
+		},
+		{
+			name:          "wrong audience",
+			claims:        jwt.MapClaims{"iss": stub.issuer, "aud": "other", "sub": "luke", "exp": expiresAt},
+			keySet:        keySet,
+			expectedError: "token has an unexpected audience",
+		},
+		{
+			name:          "no audience",
+			claims:        jwt.MapClaims{"iss": stub.issuer, "sub": "luke", "exp": expiresAt},
+			keySet:        keySet,
+			expectedError: "token has an unexpected audience",
+		},
+		{
+			name:          "wrong issuer",
+			claims:        jwt.MapClaims{"iss": "https://evil.example.com", "aud": "rag-golang", "sub": "luke", "exp": expiresAt},
+			keySet:        keySet,
+			expectedError: "token has an unexpected issuer",
+		},
+		{
+			name:          "expired",
+			claims:        jwt.MapClaims{"iss": stub.issuer, "aud": "rag-golang", "sub": "luke", "exp": time.Now().Add(-time.Minute).Unix()},
+			keySet:        keySet,
+			expectedError: "Token is expired",
+		},
+		{
+			name:          "no expiration",
+			claims:        jwt.MapClaims{"iss": stub.issuer, "aud": "rag-golang", "sub": "luke"},
+			keySet:        keySet,
+			expectedError: "token has no expiration",
+		},
+		{
+			name:          "signed by a key with the same kid but not the provider's",
+			claims:        jwt.MapClaims{"iss": stub.issuer, "aud": "rag-golang", "sub": "luke", "exp": expiresAt},
+			keySet:        newTestKeySet(t, "provider-1"),
+			expectedError: "crypto/rsa: verification error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), signTestToken(t, tt.keySet, tt.claims))
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "luke", claims["sub"])
+		})
+	}
+}
+
+func TestOIDCVerifier_VerifyRejectsSymmetricTokens(t *testing.T) {
+	stub := newStubIssuer(t, newTestKeySet(t, "provider-1"))
+
+	verifier, err := NewOIDCVerifier(context.Background(), stub.issuer, "rag-golang", http.DefaultClient, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": stub.issuer,
+		"aud": "rag-golang",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = "provider-1"
+
+	tokenString, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = verifier.Verify(context.Background(), tokenString)
+
+	assert.EqualError(t, err, "signing method HS256 is invalid")
+}
+
+func TestOIDCVerifier_VerifyRefetchesKeysOnRotation(t *testing.T) {
+	stub := newStubIssuer(t, newTestKeySet(t, "provider-1"))
+
+	verifier, err := NewOIDCVerifier(context.Background(), stub.issuer, "rag-golang", http.DefaultClient, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedKeySet := newTestKeySet(t, "provider-2")
+	stub.rotate(rotatedKeySet)
+
+	token := signTestToken(t, rotatedKeySet, jwt.MapClaims{
+		"iss": stub.issuer,
+		"aud": "rag-golang",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+
+	// right after a fetch an unknown kid does not reach the provider
+	_, err = verifier.Verify(context.Background(), token)
+	assert.EqualError(t, err, `unknown key id: "provider-2"`)
+	assert.Equal(t, int32(1), stub.jwksFetches.Load())
+
+	verifier.mu.Lock()
+	verifier.fetchedAt = time.Now().Add(-2 * minJWKSRefreshInterval)
+	verifier.mu.Unlock()
+
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), stub.jwksFetches.Load())
+
+	// the refetched keys are cached
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), stub.jwksFetches.Load())
+}
+
+func TestNewOIDCVerifier_RejectsDiscoveryOfAnotherIssuer(t *testing.T) {
+	stub := newStubIssuer(t, newTestKeySet(t, "provider-1"))
+	stub.issuer = "https://evil.example.com"
+
+	_, err := NewOIDCVerifier(context.Background(), stub.server.URL, "rag-golang", http.DefaultClient, time.Hour)
+
+	assert.EqualError(t, err, "discovery document is for issuer https://evil.example.com, expected "+stub.server.URL)
+}
+
+func TestUnverifiedIssuer(t *testing.T) {
+	token := signTestToken(t, newTestKeySet(t, "provider-1"), jwt.MapClaims{"iss": "https://issuer.example.com"})
+
+	assert.Equal(t, "https://issuer.example.com", UnverifiedIssuer(token))
+	assert.Equal(t, "", UnverifiedIssuer("not a token"))
+}
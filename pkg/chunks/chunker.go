@@ -1,16 +1,15 @@
 package chunks
 
 import (
-	"github.com/pkoukk/tiktoken-go"
 	"strings"
 )
 
 type Chunker struct {
-	Encoder           *tiktoken.Tiktoken
+	Encoder           Encoder
 	MaxTokensPerChunk int
 }
 
-func NewChunker(encoder *tiktoken.Tiktoken, maxTokens int) (*Chunker, error) {
+func NewChunker(encoder Encoder, maxTokens int) (*Chunker, error) {
 	return &Chunker{Encoder: encoder, MaxTokensPerChunk: maxTokens}, nil
 }
 
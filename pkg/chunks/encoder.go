@@ -0,0 +1,21 @@
+package chunks
+
+import "strings"
+
+// Encoder is the tokenizer used to count tokens per chunk. *tiktoken.Tiktoken
+// satisfies it.
+type Encoder interface {
+	Encode(text string, allowedSpecial []string, disallowedSpecial []string) []int
+}
+
+// WordEncoder counts every whitespace separated word as one token. It is used
+// when the tiktoken BPE files cannot be downloaded, e.g. when running offline.
+type WordEncoder struct{}
+
+func NewWordEncoder() *WordEncoder {
+	return &WordEncoder{}
+}
+
+func (e *WordEncoder) Encode(text string, allowedSpecial []string, disallowedSpecial []string) []int {
+	return make([]int, len(strings.Fields(text)))
+}
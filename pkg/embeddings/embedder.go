@@ -2,22 +2,23 @@ package embeddings
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"github.com/openai/openai-go"
-	"math"
-	"time"
 )
 
-const maxEmbeddingRetries = 5
+// EmbeddingsClient creates embeddings with OpenAI, retrying and limiting the
+// requests as llm.Client does
+type EmbeddingsClient interface {
+	NewEmbedding(ctx context.Context, params openai.EmbeddingNewParams) (*openai.CreateEmbeddingResponse, error)
+}
 
 type EmbeddingService struct {
-	OpenAIClient   *openai.Client
+	OpenAIClient   EmbeddingsClient
 	EmbeddingModel openai.EmbeddingModel
 }
 
-func NewEmbeddingService(client *openai.Client, embeddingModel openai.EmbeddingModel) *EmbeddingService {
+func NewEmbeddingService(client EmbeddingsClient, embeddingModel openai.EmbeddingModel) *EmbeddingService {
 	return &EmbeddingService{
 		OpenAIClient:   client,
 		EmbeddingModel: embeddingModel,
@@ -25,42 +26,25 @@ func NewEmbeddingService(client *openai.Client, embeddingModel openai.EmbeddingM
 }
 
 func (s *EmbeddingService) Embed(ctx context.Context, inputs []string) ([]*domain.Embeddings, error) {
-	var resp *openai.CreateEmbeddingResponse
-	var err error
 	domainEmbeddings := make([]*domain.Embeddings, 0)
 
 	for _, input := range inputs {
-		for attempt := 0; attempt < maxEmbeddingRetries; attempt++ {
-			resp, err = s.OpenAIClient.Embeddings.New(
-				ctx,
-				openai.EmbeddingNewParams{
-					Model: s.EmbeddingModel,
-					Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String(input)},
-				},
-			)
-
-			if err == nil {
-				break
-			}
-
-			var apiErr *openai.Error
-			if errors.As(err, &apiErr) && apiErr.StatusCode == 429 {
-				wait := time.Duration(math.Pow(2, float64(attempt))) * time.Second
-				time.Sleep(wait)
-				continue
-			}
-
-			return nil, fmt.Errorf("failed embedding: %w", err)
-		}
-
+		resp, err := s.OpenAIClient.NewEmbedding(
+			ctx,
+			openai.EmbeddingNewParams{
+				Model: s.EmbeddingModel,
+				Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String(input)},
+			},
+		)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed embedding: %w", err)
 		}
 
 		for _, d := range resp.Data {
 			domainEmbeddings = append(domainEmbeddings, &domain.Embeddings{
 				Embeddings: d.Embedding,
 				Text:       input,
+				Tokens:     resp.Usage.TotalTokens,
 			})
 		}
 	}
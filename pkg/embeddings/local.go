@@ -0,0 +1,88 @@
+package embeddings
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const defaultLocalEmbeddingDimensions = 512
+
+// LocalEmbeddingService is a deterministic, offline embedder based on feature
+// hashing of lower-cased word unigrams and bigrams. Its vectors are not
+// comparable with OpenAI ones, so both corpus and queries must be embedded
+// with it.
+type LocalEmbeddingService struct {
+	dimensions int
+}
+
+func NewLocalEmbeddingService(dimensions int) *LocalEmbeddingService {
+	if dimensions <= 0 {
+		dimensions = defaultLocalEmbeddingDimensions
+	}
+
+	return &LocalEmbeddingService{
+		dimensions: dimensions,
+	}
+}
+
+func (s *LocalEmbeddingService) Embed(ctx context.Context, inputs []string) ([]*domain.Embeddings, error) {
+	domainEmbeddings := make([]*domain.Embeddings, 0, len(inputs))
+
+	for _, input := range inputs {
+		domainEmbeddings = append(domainEmbeddings, &domain.Embeddings{
+			Embeddings: s.embed(input),
+			Text:       input,
+		})
+	}
+
+	return domainEmbeddings, nil
+}
+
+func (s *LocalEmbeddingService) embed(input string) []float64 {
+	vector := make([]float64, s.dimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for i, word := range words {
+		s.addFeature(vector, word)
+		if i > 0 {
+			s.addFeature(vector, words[i-1]+" "+word)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+
+	if norm == 0 {
+		return vector
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+
+	return vector
+}
+
+func (s *LocalEmbeddingService) addFeature(vector []float64, feature string) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	// the top bit decides the sign so that collisions cancel out instead of
+	// piling up
+	if sum>>63 == 1 {
+		vector[sum%uint64(s.dimensions)] -= 1
+	} else {
+		vector[sum%uint64(s.dimensions)] += 1
+	}
+}
@@ -1,5 +1,10 @@
 package customerrors
 
+import (
+	"strconv"
+	"time"
+)
+
 type ResourceNotFoundErrorWrapper struct {
 	OriginalError error
 }
@@ -29,3 +34,132 @@ func NewUserMismatchError(chatSessionID, userID string) *UserMismatchError {
 func (err UserMismatchError) Error() string {
 	return "chatSession " + err.chatSessionID + " does not belong to user " + err.userID
 }
+
+type FeedbackNotAllowedError struct {
+	messageID string
+	sender    string
+}
+
+func NewFeedbackNotAllowedError(messageID, sender string) *FeedbackNotAllowedError {
+	return &FeedbackNotAllowedError{
+		messageID: messageID,
+		sender:    sender,
+	}
+}
+
+func (err FeedbackNotAllowedError) Error() string {
+	return "message " + err.messageID + " is sent by " + err.sender + ", only SYSTEM messages accept feedback"
+}
+
+type CuratedAnswerNotPendingError struct {
+	curatedAnswerID string
+	status          string
+}
+
+func NewCuratedAnswerNotPendingError(curatedAnswerID, status string) *CuratedAnswerNotPendingError {
+	return &CuratedAnswerNotPendingError{
+		curatedAnswerID: curatedAnswerID,
+		status:          status,
+	}
+}
+
+func (err CuratedAnswerNotPendingError) Error() string {
+	return "curated answer " + err.curatedAnswerID + " is already " + err.status
+}
+
+type UsernameTakenError struct {
+	username string
+}
+
+func NewUsernameTakenError(username string) *UsernameTakenError {
+	return &UsernameTakenError{
+		username: username,
+	}
+}
+
+func (err UsernameTakenError) Error() string {
+	return "username " + err.username + " is already taken"
+}
+
+// InvalidCredentialsError does not tell whether the username or the password
+// was wrong, so that it cannot be used to enumerate users
+type InvalidCredentialsError struct{}
+
+func NewInvalidCredentialsError() *InvalidCredentialsError {
+	return &InvalidCredentialsError{}
+}
+
+func (err InvalidCredentialsError) Error() string {
+	return "invalid username or password"
+}
+
+type InvalidRefreshTokenError struct{}
+
+func NewInvalidRefreshTokenError() *InvalidRefreshTokenError {
+	return &InvalidRefreshTokenError{}
+}
+
+func (err InvalidRefreshTokenError) Error() string {
+	return "invalid or expired refresh token"
+}
+
+// InvalidTokenError is returned when a bearer token of the identity provider
+// fails verification
+type InvalidTokenError struct {
+	reason string
+}
+
+func NewInvalidTokenError(reason string) *InvalidTokenError {
+	return &InvalidTokenError{
+		reason: reason,
+	}
+}
+
+func (err InvalidTokenError) Error() string {
+	return "invalid token: " + err.reason
+}
+
+type InvalidAPIKeyError struct{}
+
+func NewInvalidAPIKeyError() *InvalidAPIKeyError {
+	return &InvalidAPIKeyError{}
+}
+
+func (err InvalidAPIKeyError) Error() string {
+	return "invalid or revoked api key"
+}
+
+const (
+	QuotaTokens   = "token"
+	QuotaMessages = "message"
+)
+
+// QuotaExceededError is returned when a user has used up a monthly quota. The
+// token quota is about spending, the message quota about the request volume,
+// so the handlers answer them with 402 and 429 respectively.
+type QuotaExceededError struct {
+	quota    string
+	limit    int64
+	resetsAt time.Time
+}
+
+func NewQuotaExceededError(quota string, limit int64, resetsAt time.Time) *QuotaExceededError {
+	return &QuotaExceededError{
+		quota:    quota,
+		limit:    limit,
+		resetsAt: resetsAt,
+	}
+}
+
+func (err QuotaExceededError) Error() string {
+	return "monthly " + err.quota + " quota of " + strconv.FormatInt(err.limit, 10) +
+		" exceeded, it resets at " + err.resetsAt.Format(time.RFC3339)
+}
+
+func (err QuotaExceededError) Quota() string {
+	return err.quota
+}
+
+func (err QuotaExceededError) ResetsAt() time.Time {
+	return err.resetsAt
+}
@@ -0,0 +1,144 @@
+package evaluation
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// MetricDiff is the change of a metric between the baseline and the current
+// report. QuestionID is empty for the averaged metrics.
+type MetricDiff struct {
+	QuestionID string  `json:"question_id,omitempty"`
+	Metric     string  `json:"metric"`
+	Baseline   float64 `json:"baseline"`
+	Current    float64 `json:"current"`
+	Delta      float64 `json:"delta"`
+	Regression bool    `json:"regression"`
+}
+
+type Comparison struct {
+	Tolerance float64       `json:"tolerance"`
+	Summary   []*MetricDiff `json:"summary"`
+	Questions []*MetricDiff `json:"questions"`
+}
+
+// Compare diffs the current report against a baseline. A metric regresses when
+// it drops by more than the tolerance. Questions and metrics that are missing
+// from either report are skipped.
+func Compare(baseline, current *Report, tolerance float64) *Comparison {
+	comparison := &Comparison{
+		Tolerance: tolerance,
+		Summary:   diffMetrics("", baseline.Metrics(), current.Metrics(), tolerance),
+	}
+
+	baselineQuestions := make(map[string]*QuestionResult, len(baseline.Questions))
+	for _, question := range baseline.Questions {
+		baselineQuestions[question.ID] = question
+	}
+
+	for _, question := range current.Questions {
+		baselineQuestion, ok := baselineQuestions[question.ID]
+		if !ok {
+			continue
+		}
+
+		comparison.Questions = append(
+			comparison.Questions,
+			diffMetrics(question.ID, baselineQuestion.Metrics(), question.Metrics(), tolerance)...,
+		)
+	}
+
+	return comparison
+}
+
+func diffMetrics(questionID string, baseline, current map[string]float64, tolerance float64) []*MetricDiff {
+	var diffs []*MetricDiff
+
+	for _, metric := range metricNames {
+		baselineValue, ok := baseline[metric]
+		if !ok {
+			continue
+		}
+
+		currentValue, ok := current[metric]
+		if !ok {
+			continue
+		}
+
+		delta := currentValue - baselineValue
+		diffs = append(diffs, &MetricDiff{
+			QuestionID: questionID,
+			Metric:     metric,
+			Baseline:   baselineValue,
+			Current:    currentValue,
+			Delta:      delta,
+			Regression: delta < -tolerance,
+		})
+	}
+
+	return diffs
+}
+
+func (c *Comparison) Regressions() []*MetricDiff {
+	var regressions []*MetricDiff
+
+	for _, diff := range append(c.Summary, c.Questions...) {
+		if diff.Regression {
+			regressions = append(regressions, diff)
+		}
+	}
+
+	return regressions
+}
+
+// WriteTable prints the averaged metrics against the baseline followed by
+// every question metric that regressed.
+func (c *Comparison) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "METRIC\tBASELINE\tCURRENT\tDELTA\t")
+	for _, diff := range c.Summary {
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%+.3f\t%s\n",
+			diff.Metric, diff.Baseline, diff.Current, diff.Delta, regressionMarker(diff))
+	}
+
+	var questionRegressions []*MetricDiff
+	for _, diff := range c.Questions {
+		if diff.Regression {
+			questionRegressions = append(questionRegressions, diff)
+		}
+	}
+
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	if len(questionRegressions) > 0 {
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "\nQUESTION\tMETRIC\tBASELINE\tCURRENT\tDELTA")
+		for _, diff := range questionRegressions {
+			fmt.Fprintf(tw, "%s\t%s\t%.3f\t%.3f\t%+.3f\n",
+				diff.QuestionID, diff.Metric, diff.Baseline, diff.Current, diff.Delta)
+		}
+
+		err = tw.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n%d regressions (tolerance %.3f)\n", len(c.Regressions()), c.Tolerance)
+
+	return err
+}
+
+func regressionMarker(diff *MetricDiff) string {
+	if diff.Regression {
+		return "REGRESSION"
+	}
+
+	return ""
+}
@@ -0,0 +1,55 @@
+package evaluation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := NewReport(ReportConfig{K: 3}, []*QuestionResult{
+		{
+			ID:        "q1",
+			Retrieval: &RetrievalScore{RecallAtK: 1, ReciprocalRank: 1, NDCGAtK: 1},
+			Answer:    &AnswerScore{Faithfulness: 1, Relevance: 1},
+		},
+		{
+			ID:        "q2",
+			Retrieval: &RetrievalScore{RecallAtK: 0.5, ReciprocalRank: 0.5, NDCGAtK: 0.5},
+			Answer:    &AnswerScore{Faithfulness: 0.8, Relevance: 0.8},
+		},
+	})
+
+	current := NewReport(ReportConfig{K: 3}, []*QuestionResult{
+		{
+			ID:        "q1",
+			Retrieval: &RetrievalScore{RecallAtK: 1, ReciprocalRank: 1, NDCGAtK: 1},
+			Answer:    &AnswerScore{Faithfulness: 0.5, Relevance: 1},
+		},
+		{
+			ID:        "q2",
+			Retrieval: &RetrievalScore{RecallAtK: 0.52, ReciprocalRank: 0.5, NDCGAtK: 0.5},
+			Answer:    &AnswerScore{Faithfulness: 0.8, Relevance: 0.8},
+		},
+		{
+			ID:        "q3",
+			Retrieval: &RetrievalScore{},
+		},
+	})
+
+	comparison := Compare(baseline, current, 0.05)
+
+	var regressions []string
+	for _, diff := range comparison.Regressions() {
+		regressions = append(regressions, diff.QuestionID+"/"+diff.Metric)
+	}
+
+	// the new question drags the retrieval averages down, q1 loses faithfulness
+	assert.Equal(t, []string{
+		"/" + MetricRecallAtK,
+		"/" + MetricMRR,
+		"/" + MetricNDCGAtK,
+		"/" + MetricFaithfulness,
+		"q1/" + MetricFaithfulness,
+	}, regressions)
+	assert.Len(t, comparison.Questions, 10)
+}
@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GoldenQuestion is a question with the chunks and/or keywords that the
+// retrieval is expected to bring back for it.
+//
+// Context, Answer and Feedback are filled by the feedback export of the API
+// (GET /admin/feedback/export): when a question has no expectations, the IDs
+// of its exported context are used as the expected chunks.
+type GoldenQuestion struct {
+	ID               string           `json:"id" yaml:"id"`
+	Question         string           `json:"question" yaml:"question"`
+	ExpectedChunks   []string         `json:"expected_chunks,omitempty" yaml:"expected_chunks,omitempty"`
+	ExpectedKeywords []string         `json:"expected_keywords,omitempty" yaml:"expected_keywords,omitempty"`
+	Context          []*GoldenContext `json:"context,omitempty" yaml:"context,omitempty"`
+	Answer           string           `json:"answer,omitempty" yaml:"answer,omitempty"`
+	Feedback         string           `json:"feedback,omitempty" yaml:"feedback,omitempty"`
+}
+
+type GoldenContext struct {
+	ID    string  `json:"id" yaml:"id"`
+	Text  string  `json:"text,omitempty" yaml:"text,omitempty"`
+	Score float32 `json:"score,omitempty" yaml:"score,omitempty"`
+}
+
+// LoadGoldenSet reads a golden question set. Files ending in .yaml or .yml are
+// parsed as a YAML list, everything else as JSONL with one question per line.
+func LoadGoldenSet(path string) ([]*GoldenQuestion, error) {
+	var questions []*GoldenQuestion
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		questions, err = loadYAML(path)
+	default:
+		questions, err = loadJSONL(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, question := range questions {
+		if question.Question == "" {
+			return nil, fmt.Errorf("golden question %d has no question", i+1)
+		}
+
+		if question.ID == "" {
+			question.ID = fmt.Sprintf("q%d", i+1)
+		}
+
+		if len(question.ExpectedChunks) == 0 && len(question.ExpectedKeywords) == 0 {
+			for _, context := range question.Context {
+				question.ExpectedChunks = append(question.ExpectedChunks, context.ID)
+			}
+		}
+	}
+
+	return questions, nil
+}
+
+func loadYAML(path string) ([]*GoldenQuestion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []*GoldenQuestion
+	err = yaml.Unmarshal(content, &questions)
+	if err != nil {
+		return nil, fmt.Errorf("malformed golden set %s: %w", path, err)
+	}
+
+	return questions, nil
+}
+
+func loadJSONL(path string) ([]*GoldenQuestion, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var questions []*GoldenQuestion
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		question := &GoldenQuestion{}
+		err = json.Unmarshal([]byte(text), question)
+		if err != nil {
+			return nil, fmt.Errorf("malformed golden set %s line %d: %w", path, line, err)
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, scanner.Err()
+}
@@ -0,0 +1,109 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/openai/openai-go"
+	"strings"
+)
+
+// AnswerScore holds the grading of an answer by the judge. Scores are in [0, 1].
+type AnswerScore struct {
+	Answer       string  `json:"answer"`
+	Faithfulness float64 `json:"faithfulness"`
+	Relevance    float64 `json:"relevance"`
+	Reasoning    string  `json:"reasoning,omitempty"`
+}
+
+// Judge grades answers with an LLM: faithfulness measures whether every claim
+// of the answer is supported by the retrieved context and relevance whether
+// the answer addresses the question.
+type Judge struct {
+	chatProvider services.ChatProvider
+	model        openai.ChatModel
+}
+
+func NewJudge(chatProvider services.ChatProvider, model openai.ChatModel) *Judge {
+	return &Judge{
+		chatProvider: chatProvider,
+		model:        model,
+	}
+}
+
+const judgeSystemPrompt = `You are a strict grader of a retrieval-augmented question answering system.
+Given a question, the context that was retrieved for it and the answer that was produced, grade:
+- faithfulness: 1 when every claim of the answer is supported by the context, 0 when none is
+- relevance: 1 when the answer fully addresses the question, 0 when it does not address it at all
+Use intermediate values for partial cases. A refusal to answer is faithful, and relevant only when the context does
+not contain the answer.
+Reply only with a JSON object: {"faithfulness": <0..1>, "relevance": <0..1>, "reasoning": "<one sentence>"}`
+
+func (j *Judge) Grade(ctx context.Context, question string, contexts []string, answer string) (*AnswerScore, error) {
+	prompt := fmt.Sprintf(`Question:
+		%s
+
+		Context:
+		%s
+
+		Answer:
+		%s`,
+		question,
+		strings.Join(contexts, "\n"),
+		answer,
+	)
+
+	chatCompletion, err := j.chatProvider.NewChatCompletion(ctx, openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(judgeSystemPrompt),
+			openai.UserMessage(prompt),
+		},
+		Model:       j.model,
+		Temperature: openai.Float(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(chatCompletion.Choices) == 0 || chatCompletion.Choices[0].Message.Content == "" {
+		return nil, errors.New("received empty response from judge")
+	}
+
+	score := &AnswerScore{}
+	err = json.Unmarshal([]byte(extractJSONObject(chatCompletion.Choices[0].Message.Content)), score)
+	if err != nil {
+		return nil, fmt.Errorf("malformed judge response: %w", err)
+	}
+
+	score.Answer = answer
+	score.Faithfulness = clamp(score.Faithfulness)
+	score.Relevance = clamp(score.Relevance)
+
+	return score, nil
+}
+
+// extractJSONObject strips anything around the outermost JSON object, e.g.
+// markdown code fences that models tend to add.
+func extractJSONObject(content string) string {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end < start {
+		return content
+	}
+
+	return content[start : end+1]
+}
+
+func clamp(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+
+	if value > 1 {
+		return 1
+	}
+
+	return value
+}
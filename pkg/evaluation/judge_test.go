@@ -0,0 +1,94 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type stubChatProvider struct {
+	content string
+	err     error
+}
+
+func (p *stubChatProvider) NewChatCompletion(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Content: p.content}},
+		},
+	}, nil
+}
+
+func TestJudge_Grade(t *testing.T) {
+	tests := []struct {
+		name          string
+		chatProvider  *stubChatProvider
+		expected      *AnswerScore
+		expectedError bool
+	}{
+		{
+			name: "valid",
+			chatProvider: &stubChatProvider{
+				content: `{"faithfulness": 0.5, "relevance": 1, "reasoning": "half of the claims are supported"}`,
+			},
+			expected: &AnswerScore{
+				Answer:       "Sailboats are 88% slower.",
+				Faithfulness: 0.5,
+				Relevance:    1,
+				Reasoning:    "half of the claims are supported",
+			},
+		},
+		{
+			name: "code fences and out of range scores",
+			chatProvider: &stubChatProvider{
+				content: "```json\n{\"faithfulness\": 1.4, \"relevance\": -2}\n```",
+			},
+			expected: &AnswerScore{
+				Answer:       "Sailboats are 88% slower.",
+				Faithfulness: 1,
+				Relevance:    0,
+			},
+		},
+		{
+			name: "malformed response",
+			chatProvider: &stubChatProvider{
+				content: "looks good to me",
+			},
+			expectedError: true,
+		},
+		{
+			name: "provider error",
+			chatProvider: &stubChatProvider{
+				err: errors.New("random error"),
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge := NewJudge(tt.chatProvider, openai.ChatModelGPT4_1Mini)
+
+			actual, err := judge.Grade(
+				context.Background(),
+				"How slow are sailboats compared to electric cars?",
+				[]string{"Sailboats are 88% slow than Electric cars."},
+				"Sailboats are 88% slower.",
+			)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
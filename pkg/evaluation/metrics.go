@@ -0,0 +1,112 @@
+package evaluation
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"math"
+	"strings"
+)
+
+// RetrievalScore holds the retrieval metrics of a single golden question.
+type RetrievalScore struct {
+	RetrievedChunks []string `json:"retrieved_chunks"`
+	RecallAtK       float64  `json:"recall_at_k"`
+	ReciprocalRank  float64  `json:"reciprocal_rank"`
+	NDCGAtK         float64  `json:"ndcg_at_k"`
+}
+
+// ScoreRetrieval computes recall@k, reciprocal rank and nDCG@k of the retrieved
+// chunks against the expectations of the question.
+//
+// A retrieved chunk is relevant when its ID is one of the expected chunks or
+// its text contains one of the expected keywords (case-insensitive). Recall is
+// the fraction of expected chunks and keywords found in the top k. Relevance is
+// binary for nDCG, and the ideal ranking has as many relevant chunks as the
+// larger of the expected chunks and the relevant chunks actually retrieved.
+func ScoreRetrieval(question *GoldenQuestion, retrievedChunks []*domain.RetrievedChunk, k int) *RetrievalScore {
+	if k > 0 && len(retrievedChunks) > k {
+		retrievedChunks = retrievedChunks[:k]
+	}
+
+	score := &RetrievalScore{
+		RetrievedChunks: make([]string, len(retrievedChunks)),
+	}
+
+	expectedChunks := make(map[string]bool, len(question.ExpectedChunks))
+	for _, id := range question.ExpectedChunks {
+		expectedChunks[id] = false
+	}
+
+	expectedKeywords := make(map[string]bool, len(question.ExpectedKeywords))
+	for _, keyword := range question.ExpectedKeywords {
+		expectedKeywords[strings.ToLower(keyword)] = false
+	}
+
+	var dcg float64
+	relevantRetrieved := 0
+
+	for i, retrievedChunk := range retrievedChunks {
+		score.RetrievedChunks[i] = retrievedChunk.ID
+
+		relevant := false
+
+		if _, ok := expectedChunks[retrievedChunk.ID]; ok {
+			expectedChunks[retrievedChunk.ID] = true
+			relevant = true
+		}
+
+		text := strings.ToLower(retrievedChunk.Text)
+		for keyword := range expectedKeywords {
+			if strings.Contains(text, keyword) {
+				expectedKeywords[keyword] = true
+				relevant = true
+			}
+		}
+
+		if !relevant {
+			continue
+		}
+
+		relevantRetrieved++
+		dcg += 1 / math.Log2(float64(i+2))
+
+		if score.ReciprocalRank == 0 {
+			score.ReciprocalRank = 1 / float64(i+1)
+		}
+	}
+
+	expected := len(expectedChunks) + len(expectedKeywords)
+	if expected > 0 {
+		found := 0
+		for _, ok := range expectedChunks {
+			if ok {
+				found++
+			}
+		}
+		for _, ok := range expectedKeywords {
+			if ok {
+				found++
+			}
+		}
+
+		score.RecallAtK = float64(found) / float64(expected)
+	}
+
+	idealRelevant := len(expectedChunks)
+	if relevantRetrieved > idealRelevant {
+		idealRelevant = relevantRetrieved
+	}
+	if k > 0 && idealRelevant > k {
+		idealRelevant = k
+	}
+
+	var idcg float64
+	for i := 0; i < idealRelevant; i++ {
+		idcg += 1 / math.Log2(float64(i+2))
+	}
+
+	if idcg > 0 {
+		score.NDCGAtK = dcg / idcg
+	}
+
+	return score
+}
@@ -0,0 +1,74 @@
+package evaluation
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScoreRetrieval(t *testing.T) {
+	retrievedChunks := []*domain.RetrievedChunk{
+		{ID: "doc1-chunk-3", Text: "Sedans are 26% environmentally friendly than Speedboats."},
+		{ID: "doc1-chunk-0", Text: "Sailboats are 88% slow than Electric cars."},
+		{ID: "doc1-chunk-7", Text: "Private jets are 18% versatile than Road bikes."},
+	}
+
+	tests := []struct {
+		name     string
+		question *GoldenQuestion
+		k        int
+		expected *RetrievalScore
+	}{
+		{
+			name: "expected chunk at second rank",
+			question: &GoldenQuestion{
+				ExpectedChunks: []string{"doc1-chunk-0"},
+			},
+			k: 3,
+			expected: &RetrievalScore{
+				RetrievedChunks: []string{"doc1-chunk-3", "doc1-chunk-0", "doc1-chunk-7"},
+				RecallAtK:       1,
+				ReciprocalRank:  0.5,
+				NDCGAtK:         0.6309297535714575,
+			},
+		},
+		{
+			name: "keywords are case insensitive",
+			question: &GoldenQuestion{
+				ExpectedKeywords: []string{"sedans", "private JETS"},
+			},
+			k: 3,
+			expected: &RetrievalScore{
+				RetrievedChunks: []string{"doc1-chunk-3", "doc1-chunk-0", "doc1-chunk-7"},
+				RecallAtK:       1,
+				ReciprocalRank:  1,
+				NDCGAtK:         0.9197207891481876,
+			},
+		},
+		{
+			name: "expectations outside of top k",
+			question: &GoldenQuestion{
+				ExpectedChunks:   []string{"doc1-chunk-7"},
+				ExpectedKeywords: []string{"sailboats"},
+			},
+			k: 1,
+			expected: &RetrievalScore{
+				RetrievedChunks: []string{"doc1-chunk-3"},
+				RecallAtK:       0,
+				ReciprocalRank:  0,
+				NDCGAtK:         0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ScoreRetrieval(tt.question, retrievedChunks, tt.k)
+
+			assert.Equal(t, tt.expected.RetrievedChunks, actual.RetrievedChunks)
+			assert.InDelta(t, tt.expected.RecallAtK, actual.RecallAtK, 1e-9)
+			assert.InDelta(t, tt.expected.ReciprocalRank, actual.ReciprocalRank, 1e-9)
+			assert.InDelta(t, tt.expected.NDCGAtK, actual.NDCGAtK, 1e-9)
+		})
+	}
+}
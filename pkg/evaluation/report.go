@@ -0,0 +1,194 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	MetricRecallAtK    = "recall_at_k"
+	MetricMRR          = "mrr"
+	MetricNDCGAtK      = "ndcg_at_k"
+	MetricFaithfulness = "faithfulness"
+	MetricRelevance    = "relevance"
+)
+
+// metricNames keeps the order in which metrics are printed and compared.
+var metricNames = []string{MetricRecallAtK, MetricMRR, MetricNDCGAtK, MetricFaithfulness, MetricRelevance}
+
+type ReportConfig struct {
+	K                 int      `json:"k"`
+	Threshold         float32  `json:"threshold"`
+	MaxTokensPerChunk int      `json:"max_tokens_per_chunk"`
+	Embedder          string   `json:"embedder"`
+	Corpus            []string `json:"corpus"`
+	JudgeModel        string   `json:"judge_model,omitempty"`
+}
+
+type QuestionResult struct {
+	ID        string          `json:"id"`
+	Question  string          `json:"question"`
+	Retrieval *RetrievalScore `json:"retrieval"`
+	Answer    *AnswerScore    `json:"answer,omitempty"`
+}
+
+// Metrics returns the metrics of the question by name. Answer metrics are only
+// present when the answer was graded.
+func (q *QuestionResult) Metrics() map[string]float64 {
+	metrics := map[string]float64{
+		MetricRecallAtK: q.Retrieval.RecallAtK,
+		MetricMRR:       q.Retrieval.ReciprocalRank,
+		MetricNDCGAtK:   q.Retrieval.NDCGAtK,
+	}
+
+	if q.Answer != nil {
+		metrics[MetricFaithfulness] = q.Answer.Faithfulness
+		metrics[MetricRelevance] = q.Answer.Relevance
+	}
+
+	return metrics
+}
+
+type RetrievalSummary struct {
+	RecallAtK float64 `json:"recall_at_k"`
+	MRR       float64 `json:"mrr"`
+	NDCGAtK   float64 `json:"ndcg_at_k"`
+}
+
+type AnswerSummary struct {
+	Faithfulness float64 `json:"faithfulness"`
+	Relevance    float64 `json:"relevance"`
+}
+
+type Report struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Config      ReportConfig      `json:"config"`
+	Retrieval   RetrievalSummary  `json:"retrieval"`
+	Answers     *AnswerSummary    `json:"answers,omitempty"`
+	Questions   []*QuestionResult `json:"questions"`
+	Comparison  *Comparison       `json:"comparison,omitempty"`
+}
+
+func NewReport(config ReportConfig, results []*QuestionResult) *Report {
+	report := &Report{
+		GeneratedAt: time.Now().UTC(),
+		Config:      config,
+		Questions:   results,
+	}
+
+	if len(results) == 0 {
+		return report
+	}
+
+	graded := 0
+	answers := &AnswerSummary{}
+
+	for _, result := range results {
+		report.Retrieval.RecallAtK += result.Retrieval.RecallAtK
+		report.Retrieval.MRR += result.Retrieval.ReciprocalRank
+		report.Retrieval.NDCGAtK += result.Retrieval.NDCGAtK
+
+		if result.Answer != nil {
+			graded++
+			answers.Faithfulness += result.Answer.Faithfulness
+			answers.Relevance += result.Answer.Relevance
+		}
+	}
+
+	total := float64(len(results))
+	report.Retrieval.RecallAtK /= total
+	report.Retrieval.MRR /= total
+	report.Retrieval.NDCGAtK /= total
+
+	if graded > 0 {
+		answers.Faithfulness /= float64(graded)
+		answers.Relevance /= float64(graded)
+		report.Answers = answers
+	}
+
+	return report
+}
+
+// Metrics returns the averaged metrics of the report by name.
+func (r *Report) Metrics() map[string]float64 {
+	metrics := map[string]float64{
+		MetricRecallAtK: r.Retrieval.RecallAtK,
+		MetricMRR:       r.Retrieval.MRR,
+		MetricNDCGAtK:   r.Retrieval.NDCGAtK,
+	}
+
+	if r.Answers != nil {
+		metrics[MetricFaithfulness] = r.Answers.Faithfulness
+		metrics[MetricRelevance] = r.Answers.Relevance
+	}
+
+	return metrics
+}
+
+// WriteTable prints one row per question followed by the averages.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.Answers != nil {
+		fmt.Fprintf(tw, "ID\tRECALL@%d\tRR\tNDCG@%d\tFAITHFUL\tRELEVANT\tQUESTION\n", r.Config.K, r.Config.K)
+	} else {
+		fmt.Fprintf(tw, "ID\tRECALL@%d\tRR\tNDCG@%d\tQUESTION\n", r.Config.K, r.Config.K)
+	}
+
+	for _, result := range r.Questions {
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%.3f\t",
+			result.ID,
+			result.Retrieval.RecallAtK,
+			result.Retrieval.ReciprocalRank,
+			result.Retrieval.NDCGAtK,
+		)
+		if r.Answers != nil {
+			if result.Answer != nil {
+				fmt.Fprintf(tw, "%.3f\t%.3f\t", result.Answer.Faithfulness, result.Answer.Relevance)
+			} else {
+				fmt.Fprint(tw, "-\t-\t")
+			}
+		}
+		fmt.Fprintf(tw, "%s\n", result.Question)
+	}
+
+	fmt.Fprintf(tw, "MEAN\t%.3f\t%.3f\t%.3f\t",
+		r.Retrieval.RecallAtK,
+		r.Retrieval.MRR,
+		r.Retrieval.NDCGAtK,
+	)
+	if r.Answers != nil {
+		fmt.Fprintf(tw, "%.3f\t%.3f\t", r.Answers.Faithfulness, r.Answers.Relevance)
+	}
+	fmt.Fprintf(tw, "%d questions\n", len(r.Questions))
+
+	return tw.Flush()
+}
+
+func (r *Report) WriteJSON(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
+
+func LoadReport(path string) (*Report, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	err = json.Unmarshal(content, report)
+	if err != nil {
+		return nil, fmt.Errorf("malformed report %s: %w", path, err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling OpenAI while the circuit breaker
+// is open
+var ErrCircuitOpen = errors.New("openai circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker opens after threshold consecutive failures and rejects calls
+// for cooldown. After that a single trial call is let through: its success
+// closes the breaker, its failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (breaker *circuitBreaker) allow() error {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.state {
+	case circuitOpen:
+		if breaker.now().Sub(breaker.openedAt) < breaker.cooldown {
+			return ErrCircuitOpen
+		}
+		breaker.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// the trial call is still running
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+func (breaker *circuitBreaker) success() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.state = circuitClosed
+	breaker.failures = 0
+}
+
+func (breaker *circuitBreaker) failure() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.failures++
+	if breaker.state == circuitHalfOpen || breaker.failures >= breaker.threshold {
+		breaker.state = circuitOpen
+		breaker.openedAt = breaker.now()
+	}
+}
+
+// abandon is called when the caller gave up on the call, which says nothing
+// about OpenAI. A trial call is then given to the next caller.
+func (breaker *circuitBreaker) abandon() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if breaker.state == circuitHalfOpen {
+		breaker.state = circuitOpen
+	}
+}
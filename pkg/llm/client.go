@@ -0,0 +1,220 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ClientConfig struct {
+	// MaxConcurrency is the number of requests to OpenAI in flight at once,
+	// for the whole process
+	MaxConcurrency int
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseBackoff doubles on every retry up to MaxBackoff, and the actual wait
+	// is a random duration up to it
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// RequestTimeout limits every attempt, a timed out attempt is retried
+	RequestTimeout time.Duration
+	// FailureThreshold consecutive failures open the circuit breaker for
+	// OpenDuration
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		MaxConcurrency:   8,
+		MaxRetries:       4,
+		BaseBackoff:      500 * time.Millisecond,
+		MaxBackoff:       30 * time.Second,
+		RequestTimeout:   60 * time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// Client is the single way to OpenAI, shared by the embeddings and the chat
+// completions so that they respect the same concurrency limit and circuit
+// breaker. Attempts failing with 429, 5xx or a timeout are retried with
+// exponential backoff and jitter, or after the Retry-After of the response
+// when it has one.
+type Client struct {
+	openAI    openai.Client
+	config    ClientConfig
+	semaphore chan struct{}
+	breaker   *circuitBreaker
+	sleep     func(ctx context.Context, duration time.Duration) error
+}
+
+// NewClient creates the OpenAI client with the options, its own retries
+// disabled in favour of the ones of the wrapper
+func NewClient(config ClientConfig, opts ...option.RequestOption) *Client {
+	opts = append(opts, option.WithMaxRetries(0))
+
+	return &Client{
+		openAI:    openai.NewClient(opts...),
+		config:    config,
+		semaphore: make(chan struct{}, max(config.MaxConcurrency, 1)),
+		breaker:   newCircuitBreaker(max(config.FailureThreshold, 1), config.OpenDuration),
+		sleep:     sleepContext,
+	}
+}
+
+func (c *Client) NewChatCompletion(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	var chatCompletion *openai.ChatCompletion
+
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		chatCompletion, err = c.openAI.Chat.Completions.New(ctx, params)
+		return err
+	})
+
+	return chatCompletion, err
+}
+
+func (c *Client) NewEmbedding(ctx context.Context, params openai.EmbeddingNewParams) (*openai.CreateEmbeddingResponse, error) {
+	var embedding *openai.CreateEmbeddingResponse
+
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		embedding, err = c.openAI.Embeddings.New(ctx, params)
+		return err
+	})
+
+	return embedding, err
+}
+
+func (c *Client) do(ctx context.Context, call func(ctx context.Context) error) error {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		err = c.attempt(ctx, call)
+		if err == nil || errors.Is(err, ErrCircuitOpen) || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		if attempt == c.config.MaxRetries {
+			return err
+		}
+
+		wait, ok := retryAfter(err)
+		if !ok {
+			wait = c.backoff(attempt)
+		}
+
+		if sleepErr := c.sleep(ctx, wait); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// attempt holds a slot of the semaphore only while the request runs, not
+// while waiting to retry
+func (c *Client) attempt(ctx context.Context, call func(ctx context.Context) error) error {
+	err := c.breaker.allow()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case c.semaphore <- struct{}{}:
+	case <-ctx.Done():
+		c.breaker.abandon()
+		return ctx.Err()
+	}
+	defer func() { <-c.semaphore }()
+
+	attemptCtx := ctx
+	if c.config.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, c.config.RequestTimeout)
+		defer cancel()
+	}
+
+	err = call(attemptCtx)
+
+	// client errors, like a bad request, say nothing about the health of OpenAI
+	switch {
+	case ctx.Err() != nil:
+		c.breaker.abandon()
+	case err != nil && isRetryable(err):
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+
+	return err
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.config.MaxBackoff
+	if attempt < 32 {
+		ceiling = min(c.config.BaseBackoff<<attempt, c.config.MaxBackoff)
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func isRetryable(err error) bool {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryAfter reads the Retry-After-Ms header that OpenAI sends, or the
+// standard Retry-After in seconds
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return 0, false
+	}
+
+	if milliseconds, parseErr := strconv.ParseFloat(apiErr.Response.Header.Get("Retry-After-Ms"), 64); parseErr == nil && milliseconds >= 0 {
+		return time.Duration(milliseconds * float64(time.Millisecond)), true
+	}
+
+	if seconds, parseErr := strconv.ParseFloat(apiErr.Response.Header.Get("Retry-After"), 64); parseErr == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+
+	if retryAt, parseErr := http.ParseTime(apiErr.Response.Header.Get("Retry-After")); parseErr == nil {
+		return max(time.Until(retryAt), 0), true
+	}
+
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,342 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeResponse is what the fake OpenAI server answers to a request
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	delay   time.Duration
+}
+
+// fakeOpenAI answers the scripted responses in order, and a successful chat
+// completion or embedding once they run out
+type fakeOpenAI struct {
+	server   *httptest.Server
+	requests atomic.Int32
+
+	mu        sync.Mutex
+	responses []fakeResponse
+	inFlight  int
+	maxFlight int
+}
+
+func newFakeOpenAI(t *testing.T, responses ...fakeResponse) *fakeOpenAI {
+	t.Helper()
+
+	fake := &fakeOpenAI{responses: responses}
+
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.requests.Add(1)
+		// the request context is only cancelled once the body has been read
+		io.Copy(io.Discard, r.Body)
+
+		fake.mu.Lock()
+		fake.inFlight++
+		fake.maxFlight = max(fake.maxFlight, fake.inFlight)
+		response := fakeResponse{status: http.StatusOK}
+		if len(fake.responses) > 0 {
+			response = fake.responses[0]
+			fake.responses = fake.responses[1:]
+		}
+		fake.mu.Unlock()
+
+		defer func() {
+			fake.mu.Lock()
+			fake.inFlight--
+			fake.mu.Unlock()
+		}()
+
+		select {
+		case <-time.After(response.delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		for name, value := range response.headers {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.status)
+
+		if response.status != http.StatusOK {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{"message": http.StatusText(response.status), "type": "error"},
+			})
+			return
+		}
+
+		if r.URL.Path == "/embeddings" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"model":  "text-embedding-3-small",
+				"data":   []map[string]interface{}{{"object": "embedding", "index": 0, "embedding": []float64{0.1, 0.2}}},
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     "chatcmpl-1",
+			"object": "chat.completion",
+			"model":  "gpt-4.1-nano",
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       map[string]string{"role": "assistant", "content": "May the force be with you"},
+			}},
+		})
+	}))
+	t.Cleanup(fake.server.Close)
+
+	return fake
+}
+
+// newTestClient records the waits between retries instead of sleeping
+func newTestClient(fake *fakeOpenAI, config ClientConfig) (*Client, *[]time.Duration) {
+	client := NewClient(config, option.WithAPIKey("test"), option.WithBaseURL(fake.server.URL))
+
+	var mu sync.Mutex
+	waits := &[]time.Duration{}
+	client.sleep = func(ctx context.Context, duration time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		*waits = append(*waits, duration)
+		return nil
+	}
+
+	return client, waits
+}
+
+func testClientConfig() ClientConfig {
+	return ClientConfig{
+		MaxConcurrency:   4,
+		MaxRetries:       3,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		RequestTimeout:   time.Second,
+		FailureThreshold: 10,
+		OpenDuration:     time.Minute,
+	}
+}
+
+func chatParams() openai.ChatCompletionNewParams {
+	return openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{openai.UserMessage("Who is Luke?")},
+		Model:    openai.ChatModelGPT4_1Nano,
+	}
+}
+
+func TestClient_NewChatCompletion(t *testing.T) {
+	tests := []struct {
+		name             string
+		responses        []fakeResponse
+		expectedRequests int32
+		expectedError    bool
+		expectedWaits    []time.Duration
+	}{
+		{
+			name:             "success at once",
+			expectedRequests: 1,
+			expectedWaits:    []time.Duration{},
+		},
+		{
+			name: "retries rate limits and server errors",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests},
+				{status: http.StatusInternalServerError},
+				{status: http.StatusServiceUnavailable},
+			},
+			expectedRequests: 4,
+		},
+		{
+			name: "respects Retry-After",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "2"}},
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After-Ms": "150"}},
+			},
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{2 * time.Second, 150 * time.Millisecond},
+		},
+		{
+			name: "retries timeouts",
+			responses: []fakeResponse{
+				{status: http.StatusOK, delay: 5 * time.Second},
+			},
+			expectedRequests: 2,
+		},
+		{
+			name: "does not retry client errors",
+			responses: []fakeResponse{
+				{status: http.StatusBadRequest},
+			},
+			expectedRequests: 1,
+			expectedError:    true,
+			expectedWaits:    []time.Duration{},
+		},
+		{
+			name: "gives up after the retries",
+			responses: []fakeResponse{
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+			},
+			expectedRequests: 4,
+			expectedError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeOpenAI(t, tt.responses...)
+			config := testClientConfig()
+			config.RequestTimeout = 200 * time.Millisecond
+			client, waits := newTestClient(fake, config)
+
+			chatCompletion, err := client.NewChatCompletion(context.Background(), chatParams())
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "May the force be with you", chatCompletion.Choices[0].Message.Content)
+			}
+
+			assert.Equal(t, tt.expectedRequests, fake.requests.Load())
+
+			if tt.expectedWaits != nil {
+				assert.Equal(t, tt.expectedWaits, *waits)
+				return
+			}
+
+			// backoff waits are jittered up to the doubling ceiling
+			for i, wait := range *waits {
+				assert.LessOrEqual(t, wait, min(config.BaseBackoff<<i, config.MaxBackoff))
+			}
+		})
+	}
+}
+
+func TestClient_NewEmbedding(t *testing.T) {
+	fake := newFakeOpenAI(t, fakeResponse{status: http.StatusTooManyRequests})
+	client, _ := newTestClient(fake, testClientConfig())
+
+	embedding, err := client.NewEmbedding(context.Background(), openai.EmbeddingNewParams{
+		Model: openai.EmbeddingModelTextEmbedding3Small,
+		Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String("Who is Luke?")},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.1, 0.2}, embedding.Data[0].Embedding)
+	assert.Equal(t, int32(2), fake.requests.Load())
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	fake := newFakeOpenAI(t,
+		fakeResponse{status: http.StatusInternalServerError},
+		fakeResponse{status: http.StatusInternalServerError},
+		fakeResponse{status: http.StatusInternalServerError},
+	)
+
+	config := testClientConfig()
+	config.MaxRetries = 0
+	config.FailureThreshold = 2
+	client, _ := newTestClient(fake, config)
+
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := client.NewChatCompletion(context.Background(), chatParams())
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+
+	// open: OpenAI is not called
+	_, err := client.NewChatCompletion(context.Background(), chatParams())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), fake.requests.Load())
+
+	// a failed trial opens it again
+	now = now.Add(config.OpenDuration)
+	_, err = client.NewChatCompletion(context.Background(), chatParams())
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(3), fake.requests.Load())
+
+	_, err = client.NewChatCompletion(context.Background(), chatParams())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// a successful trial closes it
+	now = now.Add(config.OpenDuration)
+	_, err = client.NewChatCompletion(context.Background(), chatParams())
+	assert.NoError(t, err)
+
+	_, err = client.NewChatCompletion(context.Background(), chatParams())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), fake.requests.Load())
+}
+
+func TestClient_LimitsConcurrency(t *testing.T) {
+	responses := make([]fakeResponse, 0, 10)
+	for i := 0; i < 10; i++ {
+		responses = append(responses, fakeResponse{status: http.StatusOK, delay: 20 * time.Millisecond})
+	}
+	fake := newFakeOpenAI(t, responses...)
+
+	config := testClientConfig()
+	config.MaxConcurrency = 2
+	client, _ := newTestClient(fake, config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := client.NewChatCompletion(context.Background(), chatParams())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), fake.requests.Load())
+	assert.Equal(t, 2, fake.maxFlight)
+}
+
+func TestClient_StopsRetryingWhenTheContextIsDone(t *testing.T) {
+	fake := newFakeOpenAI(t,
+		fakeResponse{status: http.StatusTooManyRequests},
+		fakeResponse{status: http.StatusTooManyRequests},
+	)
+
+	client := NewClient(testClientConfig(), option.WithAPIKey("test"), option.WithBaseURL(fake.server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client.config.BaseBackoff = time.Hour
+	client.config.MaxBackoff = time.Hour
+
+	_, err := client.NewChatCompletion(ctx, chatParams())
+
+	var apiErr *openai.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, int32(1), fake.requests.Load())
+}
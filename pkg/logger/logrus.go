@@ -3,6 +3,7 @@ package logger
 import (
 	"context"
 	"github.com/sirupsen/logrus"
+	"io"
 	"os"
 )
 
@@ -12,8 +13,14 @@ type LogrusLogger struct {
 }
 
 func NewLogger(ctx context.Context) *LogrusLogger {
+	return NewLoggerWithOutput(ctx, os.Stdout)
+}
+
+// NewLoggerWithOutput logs to the writer, e.g. to stderr when stdout is taken
+// by the MCP stdio transport
+func NewLoggerWithOutput(ctx context.Context, out io.Writer) *LogrusLogger {
 	logger := logrus.New()
-	logger.Out = os.Stdout
+	logger.Out = out
 
 	return &LogrusLogger{logger: logger, ctx: ctx}
 }
@@ -0,0 +1,77 @@
+package mcpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	TransportStdio          = "stdio"
+	TransportStreamableHTTP = "streamable-http"
+	TransportSSE            = "sse"
+
+	// AllowAllTools in the allowed tools of a server allows every tool it
+	// lists
+	AllowAllTools = "*"
+)
+
+// serverNamePattern keeps the names of the tools, prefixed by the name of
+// their server, valid function names for OpenAI
+var serverNamePattern = regexp.MustCompile(`^[a-zA-Z0-9-]{1,20}$`)
+
+// ServerConfig is an external MCP server. Command, Args and Env start a stdio
+// server, URL and Headers reach an HTTP one. Only the tools in AllowedTools
+// are used, none when it is empty.
+type ServerConfig struct {
+	Name         string            `json:"name"`
+	Transport    string            `json:"transport"`
+	Command      string            `json:"command,omitempty"`
+	Args         []string          `json:"args,omitempty"`
+	Env          []string          `json:"env,omitempty"`
+	URL          string            `json:"url,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	AllowedTools []string          `json:"allowedTools"`
+}
+
+// ParseServerConfigs parses the JSON array of the external MCP servers, none
+// for an empty value
+func ParseServerConfigs(value string) ([]ServerConfig, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var configs []ServerConfig
+	err := json.Unmarshal([]byte(value), &configs)
+	if err != nil {
+		return nil, fmt.Errorf("malformed external MCP servers: %w", err)
+	}
+
+	names := map[string]bool{}
+	for _, config := range configs {
+		if !serverNamePattern.MatchString(config.Name) {
+			return nil, fmt.Errorf("name %q of external MCP server must be up to 20 letters, digits or dashes", config.Name)
+		}
+
+		if names[config.Name] {
+			return nil, errors.New("duplicate external MCP server " + config.Name)
+		}
+		names[config.Name] = true
+
+		switch config.Transport {
+		case TransportStdio:
+			if config.Command == "" {
+				return nil, errors.New("missing command of external MCP server " + config.Name)
+			}
+		case TransportStreamableHTTP, TransportSSE:
+			if config.URL == "" {
+				return nil, errors.New("missing url of external MCP server " + config.Name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown transport %q of external MCP server %s", config.Transport, config.Name)
+		}
+	}
+
+	return configs, nil
+}
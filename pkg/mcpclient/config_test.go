@@ -0,0 +1,88 @@
+package mcpclient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseServerConfigs(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      []ServerConfig
+		expectedError string
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: nil,
+		},
+		{
+			name: "valid",
+			value: `[
+				{"name":"files","transport":"stdio","command":"files-mcp","args":["--root","/data"],"allowedTools":["read_file"]},
+				{"name":"wiki","transport":"streamable-http","url":"https://wiki.example.com/mcp","headers":{"Authorization":"Bearer token"},"allowedTools":["*"]}
+			]`,
+			expected: []ServerConfig{
+				{
+					Name:         "files",
+					Transport:    TransportStdio,
+					Command:      "files-mcp",
+					Args:         []string{"--root", "/data"},
+					AllowedTools: []string{"read_file"},
+				},
+				{
+					Name:         "wiki",
+					Transport:    TransportStreamableHTTP,
+					URL:          "https://wiki.example.com/mcp",
+					Headers:      map[string]string{"Authorization": "Bearer token"},
+					AllowedTools: []string{AllowAllTools},
+				},
+			},
+		},
+		{
+			name:          "malformed",
+			value:         `{"name":"files"}`,
+			expectedError: "malformed external MCP servers",
+		},
+		{
+			name:          "name with underscores",
+			value:         `[{"name":"my_files","transport":"stdio","command":"files-mcp"}]`,
+			expectedError: `name "my_files" of external MCP server must be up to 20 letters, digits or dashes`,
+		},
+		{
+			name:          "duplicate name",
+			value:         `[{"name":"files","transport":"stdio","command":"files-mcp"},{"name":"files","transport":"sse","url":"https://files.example.com/sse"}]`,
+			expectedError: "duplicate external MCP server files",
+		},
+		{
+			name:          "stdio without command",
+			value:         `[{"name":"files","transport":"stdio"}]`,
+			expectedError: "missing command of external MCP server files",
+		},
+		{
+			name:          "http without url",
+			value:         `[{"name":"wiki","transport":"sse"}]`,
+			expectedError: "missing url of external MCP server wiki",
+		},
+		{
+			name:          "unknown transport",
+			value:         `[{"name":"wiki","transport":"websocket","url":"wss://wiki.example.com"}]`,
+			expectedError: `unknown transport "websocket" of external MCP server wiki`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseServerConfigs(tt.value)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// toolNameSeparator separates the name of the server from the name of the tool
+// in the names the LLM sees, server names cannot contain it
+const toolNameSeparator = "__"
+
+// maxToolNameLength is the maximum length of an OpenAI function name
+const maxToolNameLength = 64
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+const connectTimeout = 30 * time.Second
+
+type externalTool struct {
+	client *client.Client
+	name   string
+}
+
+// ToolSource makes the allowed tools of external MCP servers available to the
+// agent answer mode, acting as their MCP client
+type ToolSource struct {
+	logger  logger.LoggerInterface
+	mu      sync.RWMutex
+	clients []*client.Client
+	tools   []*domain.ExternalTool
+	byName  map[string]externalTool
+}
+
+func NewToolSource(logger logger.LoggerInterface) *ToolSource {
+	return &ToolSource{
+		logger: logger,
+		byName: map[string]externalTool{},
+	}
+}
+
+// Connect connects to the servers for as long as the context lives. A server
+// that cannot be reached is logged and skipped, the others still add their
+// tools.
+func (source *ToolSource) Connect(ctx context.Context, configs []ServerConfig) {
+	for _, config := range configs {
+		mcpClient, err := newClient(config)
+		if err == nil {
+			err = source.AddClient(ctx, config.Name, mcpClient, config.AllowedTools)
+		}
+
+		if err != nil {
+			source.logger.Error("Error in connecting to external MCP server",
+				map[string]interface{}{
+					"server":       config.Name,
+					"errorMessage": err.Error(),
+				})
+		}
+	}
+}
+
+func newClient(config ServerConfig) (*client.Client, error) {
+	switch config.Transport {
+	case TransportStdio:
+		return client.NewClient(transport.NewStdio(config.Command, config.Env, config.Args...)), nil
+	case TransportStreamableHTTP:
+		return client.NewStreamableHttpClient(config.URL, transport.WithHTTPHeaders(config.Headers))
+	case TransportSSE:
+		return client.NewSSEMCPClient(config.URL, client.WithHeaders(config.Headers))
+	default:
+		return nil, errors.New("unknown transport " + config.Transport)
+	}
+}
+
+// AddClient starts the client of the server for as long as the context lives,
+// and adds the tools of the server that are allowed
+func (source *ToolSource) AddClient(ctx context.Context, serverName string, mcpClient *client.Client, allowedTools []string) error {
+	err := mcpClient.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot start client: %w", err)
+	}
+
+	tools, err := initialize(ctx, mcpClient)
+	if err != nil {
+		mcpClient.Close()
+		return err
+	}
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	source.clients = append(source.clients, mcpClient)
+
+	for _, tool := range tools {
+		if !slices.Contains(allowedTools, AllowAllTools) && !slices.Contains(allowedTools, tool.Name) {
+			continue
+		}
+
+		name := serverName + toolNameSeparator + tool.Name
+		if len(name) > maxToolNameLength || !toolNamePattern.MatchString(tool.Name) {
+			source.logger.Warn("Skipping external MCP tool with a name OpenAI does not accept",
+				map[string]interface{}{
+					"server": serverName,
+					"tool":   tool.Name,
+				})
+
+			continue
+		}
+
+		source.tools = append(source.tools, &domain.ExternalTool{
+			Name:        name,
+			Description: tool.Description,
+			Parameters:  toolParameters(tool),
+		})
+		source.byName[name] = externalTool{
+			client: mcpClient,
+			name:   tool.Name,
+		}
+	}
+
+	return nil
+}
+
+func initialize(ctx context.Context, mcpClient *client.Client) ([]mcp.Tool, error) {
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+
+	initializeRequest := mcp.InitializeRequest{}
+	initializeRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initializeRequest.Params.ClientInfo = mcp.Implementation{Name: "rag-golang", Version: "1.0.0"}
+
+	_, err := mcpClient.Initialize(ctx, initializeRequest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize: %w", err)
+	}
+
+	result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list tools: %w", err)
+	}
+
+	return result.Tools, nil
+}
+
+// toolParameters returns the input schema of the tool as the parameters of a
+// function
+func toolParameters(tool mcp.Tool) map[string]interface{} {
+	parameters := map[string]interface{}{}
+
+	schema := tool.RawInputSchema
+	if len(schema) == 0 {
+		schema, _ = json.Marshal(tool.InputSchema)
+	}
+
+	if json.Unmarshal(schema, &parameters) != nil || parameters["type"] != "object" {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	return parameters
+}
+
+// Tools returns the allowed tools of all the servers, named after their server
+func (source *ToolSource) Tools() []*domain.ExternalTool {
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	return source.tools
+}
+
+// CallTool calls the tool with the JSON arguments and returns its text
+// content. A result the server flagged as an error is returned as the error.
+func (source *ToolSource) CallTool(ctx context.Context, name string, arguments string) (string, error) {
+	source.mu.RLock()
+	tool, ok := source.byName[name]
+	source.mu.RUnlock()
+
+	if !ok {
+		return "", errors.New("unknown tool " + name)
+	}
+
+	callArguments := map[string]interface{}{}
+	if arguments != "" {
+		err := json.Unmarshal([]byte(arguments), &callArguments)
+		if err != nil {
+			return "", errors.New("malformed arguments")
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool.name
+	request.Params.Arguments = callArguments
+
+	result, err := tool.client.CallTool(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	texts := make([]string, 0, len(result.Content))
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+
+	if result.IsError {
+		return "", errors.New(strings.Join(texts, "\n"))
+	}
+
+	return strings.Join(texts, "\n"), nil
+}
+
+// Close closes the clients of all the servers, stopping the stdio ones
+func (source *ToolSource) Close() {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	for _, mcpClient := range source.clients {
+		err := mcpClient.Close()
+		if err != nil {
+			source.logger.Warn("Error in closing external MCP client",
+				map[string]interface{}{
+					"errorMessage": err.Error(),
+				})
+		}
+	}
+
+	source.clients = nil
+}
@@ -0,0 +1,179 @@
+package mcpclient
+
+import (
+	"context"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// newTestToolSource adds an in-process MCP server with an echo, a failing and
+// a not allowed tool to a tool source
+func newTestToolSource(t *testing.T, allowedTools []string) *ToolSource {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("files", "test")
+	mcpServer.AddTool(
+		mcp.NewTool("echo",
+			mcp.WithDescription("Echo the text"),
+			mcp.WithString("text", mcp.Required(), mcp.Description("Text to echo")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			text, err := request.RequireString("text")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return mcp.NewToolResultText(text), nil
+		},
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("fail", mcp.WithDescription("Always fail")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("the dark side prevails"), nil
+		},
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("delete_everything", mcp.WithDescription("Not for the LLM")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("must not be called")
+		},
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("read.file", mcp.WithDescription("Named against the rules of OpenAI")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("read"), nil
+		},
+	)
+
+	mcpClient, err := client.NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+
+	toolSource := NewToolSource(logger.NewLogger(context.Background()))
+	t.Cleanup(toolSource.Close)
+
+	require.NoError(t, toolSource.AddClient(context.Background(), "files", mcpClient, allowedTools))
+
+	return toolSource
+}
+
+func TestToolSource_Tools(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedTools []string
+		expected     []string
+	}{
+		{
+			name:         "allowed tools",
+			allowedTools: []string{"echo", "fail"},
+			expected:     []string{"files__echo", "files__fail"},
+		},
+		{
+			name:         "all tools",
+			allowedTools: []string{AllowAllTools},
+			expected:     []string{"files__echo", "files__fail", "files__delete_everything"},
+		},
+		{
+			name:         "no allowed tools",
+			allowedTools: nil,
+			expected:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolSource := newTestToolSource(t, tt.allowedTools)
+
+			var actual []string
+			for _, tool := range toolSource.Tools() {
+				actual = append(actual, tool.Name)
+			}
+
+			assert.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestToolSource_ToolsHaveTheInputSchemaAsParameters(t *testing.T) {
+	toolSource := newTestToolSource(t, []string{"echo"})
+
+	assert.Equal(t, []*domain.ExternalTool{
+		{
+			Name:        "files__echo",
+			Description: "Echo the text",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"text": map[string]interface{}{
+						"type":        "string",
+						"description": "Text to echo",
+					},
+				},
+				"required": []interface{}{"text"},
+			},
+		},
+	}, toolSource.Tools())
+}
+
+func TestToolSource_CallTool(t *testing.T) {
+	toolSource := newTestToolSource(t, []string{"echo", "fail"})
+
+	tests := []struct {
+		name          string
+		tool          string
+		arguments     string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:      "valid",
+			tool:      "files__echo",
+			arguments: `{"text":"May the force be with you"}`,
+			expected:  "May the force be with you",
+		},
+		{
+			name:          "tool error",
+			tool:          "files__fail",
+			arguments:     `{}`,
+			expectedError: "the dark side prevails",
+		},
+		{
+			name:          "missing argument",
+			tool:          "files__echo",
+			arguments:     "",
+			expectedError: `required argument "text" not found`,
+		},
+		{
+			name:          "malformed arguments",
+			tool:          "files__echo",
+			arguments:     `{"text":`,
+			expectedError: "malformed arguments",
+		},
+		{
+			name:          "not allowed tool",
+			tool:          "files__delete_everything",
+			arguments:     `{}`,
+			expectedError: "unknown tool files__delete_everything",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := toolSource.CallTool(context.Background(), tt.tool, tt.arguments)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// Limiter is a token bucket per key: every key may spend burst requests at
+// once, and regains ratePerSecond of them each second. Buckets that have been
+// full for a while are dropped, so keys that stop sending requests cost no
+// memory.
+type Limiter struct {
+	ratePerSecond float64
+	burst         float64
+	now           func() time.Time
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	lastEvicted time.Time
+}
+
+func NewLimiter(ratePerMinute float64, burst int) *Limiter {
+	return &Limiter{
+		ratePerSecond: ratePerMinute / 60,
+		burst:         float64(burst),
+		now:           time.Now,
+		buckets:       make(map[string]*bucket),
+	}
+}
+
+// Allow spends a token of the key's bucket. When the bucket is empty it
+// returns false and how long until a token is available.
+func (limiter *Limiter) Allow(key string) (bool, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := limiter.now()
+	limiter.evictFullBuckets(now)
+
+	b, ok := limiter.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limiter.burst, updatedAt: now}
+		limiter.buckets[key] = b
+	}
+
+	b.tokens = math.Min(limiter.burst, b.tokens+now.Sub(b.updatedAt).Seconds()*limiter.ratePerSecond)
+	b.updatedAt = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	retryAfter := time.Duration((1 - b.tokens) / limiter.ratePerSecond * float64(time.Second))
+
+	return false, retryAfter
+}
+
+// evictFullBuckets drops the buckets that have refilled, they are the same as
+// a new bucket. It runs at most once per refill time, which bounds its cost.
+func (limiter *Limiter) evictFullBuckets(now time.Time) {
+	refillTime := time.Duration(limiter.burst / limiter.ratePerSecond * float64(time.Second))
+	if now.Sub(limiter.lastEvicted) < refillTime {
+		return
+	}
+
+	for key, b := range limiter.buckets {
+		if now.Sub(b.updatedAt) >= refillTime {
+			delete(limiter.buckets, key)
+		}
+	}
+
+	limiter.lastEvicted = now
+}
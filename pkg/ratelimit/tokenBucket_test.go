@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(ratePerMinute float64, burst int, now *time.Time) *Limiter {
+	limiter := NewLimiter(ratePerMinute, burst)
+	limiter.now = func() time.Time { return *now }
+
+	return limiter
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(6, 2, &now)
+
+	// the burst is available at once
+	for i := 0; i < 2; i++ {
+		allowed, retryAfter := limiter.Allow("user-1")
+		assert.True(t, allowed)
+		assert.Equal(t, time.Duration(0), retryAfter)
+	}
+
+	// 6 per minute is a token every 10 seconds
+	allowed, retryAfter := limiter.Allow("user-1")
+	assert.False(t, allowed)
+	assert.Equal(t, 10*time.Second, retryAfter)
+
+	// other keys have their own bucket
+	allowed, _ = limiter.Allow("user-2")
+	assert.True(t, allowed)
+
+	now = now.Add(4 * time.Second)
+	allowed, retryAfter = limiter.Allow("user-1")
+	assert.False(t, allowed)
+	assert.InDelta(t, float64(6*time.Second), float64(retryAfter), float64(time.Millisecond))
+
+	now = now.Add(6 * time.Second)
+	allowed, _ = limiter.Allow("user-1")
+	assert.True(t, allowed)
+
+	allowed, _ = limiter.Allow("user-1")
+	assert.False(t, allowed)
+}
+
+func TestLimiter_AllowRefillsUpToBurst(t *testing.T) {
+	now := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(60, 3, &now)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow("user-1")
+		assert.True(t, allowed)
+	}
+
+	now = now.Add(time.Hour)
+
+	allowedCount := 0
+	for i := 0; i < 10; i++ {
+		if allowed, _ := limiter.Allow("user-1"); allowed {
+			allowedCount++
+		}
+	}
+
+	assert.Equal(t, 3, allowedCount)
+}
+
+func TestLimiter_AllowEvictsFullBuckets(t *testing.T) {
+	now := time.Date(2025, 5, 29, 10, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(60, 2, &now)
+
+	limiter.Allow("user-1")
+	limiter.Allow("user-2")
+	assert.Len(t, limiter.buckets, 2)
+
+	now = now.Add(time.Minute)
+	limiter.Allow("user-3")
+
+	assert.Len(t, limiter.buckets, 1)
+}
@@ -1,13 +1,28 @@
 package http
 
 import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
 	"github.com/loukaspe/rag-golang/internal/core/services"
 	http2 "github.com/loukaspe/rag-golang/internal/handlers/http"
+	apiKeys2 "github.com/loukaspe/rag-golang/internal/handlers/http/apiKeys"
 	chatSessions2 "github.com/loukaspe/rag-golang/internal/handlers/http/chatSessions"
+	curatedAnswers2 "github.com/loukaspe/rag-golang/internal/handlers/http/curatedAnswers"
+	feedback2 "github.com/loukaspe/rag-golang/internal/handlers/http/feedback"
+	usage2 "github.com/loukaspe/rag-golang/internal/handlers/http/usage"
+	users2 "github.com/loukaspe/rag-golang/internal/handlers/http/users"
+	vehicles2 "github.com/loukaspe/rag-golang/internal/handlers/http/vehicles"
 	"github.com/loukaspe/rag-golang/internal/repositories"
 	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/loukaspe/rag-golang/pkg/server/mcp"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 //	@title			RAG in Golang
@@ -25,6 +40,11 @@ import (
 //	@name						Authorization
 //	@description				Header value should be in the form of `Bearer <JWT access token>`
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key created at `/users/{user_id}/api-keys`, limited to its scopes
+
 // @accept		json
 // @produce	json
 func (s *Server) initializeRoutes() {
@@ -32,39 +52,386 @@ func (s *Server) initializeRoutes() {
 	healthCheckHandler := http2.NewHealthCheckHandler(s.DB)
 	s.router.HandleFunc("/health-check", healthCheckHandler.HealthCheckController).Methods("GET")
 
-	mcpSSEServer := s.mcpServer.InitialiseSSEServer()
+	// auth
+	accessTokenTTL := durationFromEnv(s.logger, "JWT_ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL := durationFromEnv(s.logger, "JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	denylistCacheTTL := durationFromEnv(s.logger, "JWT_DENYLIST_CACHE_TTL", 30*time.Second)
 
-	s.router.PathPrefix("/mcp").Handler(mcpSSEServer)
+	userRepository := repositories.NewUserRepository(s.DB)
+	refreshTokenRepository := repositories.NewRefreshTokenRepository(s.DB)
+	revokedTokenRepository := repositories.NewRevokedTokenRepository(s.DB)
+	tokenDenylistService := services.NewTokenDenylistService(s.logger, revokedTokenRepository, denylistCacheTTL)
 
-	// auth
-	jwtMechanism := auth.NewAuthMechanism(
-		os.Getenv("JWT_SECRET_KEY"),
-		os.Getenv("JWT_SIGNING_METHOD"),
+	// with an identity provider configured, local tokens are only minted when
+	// explicitly enabled
+	oidcAuthService := s.newOIDCAuthService(userRepository)
+	localTokensEnabled := oidcAuthService == nil || os.Getenv("AUTH_LOCAL_TOKENS_ENABLED") == "true"
+
+	var jwtMechanism *auth.AuthMechanism
+	var jwtService *services.JwtService
+	if localTokensEnabled {
+		jwtMechanism = s.newAuthMechanism(accessTokenTTL)
+		jwtService = services.NewJwtService(jwtMechanism)
+	}
+
+	userService := services.NewUserService(
+		s.logger,
+		userRepository,
+		refreshTokenRepository,
+		tokenDenylistService,
+		jwtService,
+		accessTokenTTL,
+		refreshTokenTTL,
 	)
-	jwtService := services.NewJwtService(jwtMechanism)
-	jwtMiddleware := http2.NewAuthenticationMw(jwtMechanism)
-	jwtHandler := http2.NewJwtClaimsHandler(jwtService, s.logger)
 
-	s.router.HandleFunc("/token", jwtHandler.JwtTokenController).Methods(http.MethodPost)
+	logoutHandler := users2.NewLogoutHandler(userService, s.logger)
+
+	apiKeyRepository := repositories.NewAPIKeyRepository(s.DB)
+	apiKeyService := services.NewAPIKeyService(s.logger, apiKeyRepository, userRepository)
+
+	var jwtMiddleware *http2.AuthenticationMw
+	if localTokensEnabled {
+		registerHandler := users2.NewRegisterHandler(userService, s.logger)
+		loginHandler := users2.NewLoginHandler(userService, s.logger)
+		refreshTokenHandler := users2.NewRefreshTokenHandler(userService, s.logger)
+
+		s.router.HandleFunc("/register", registerHandler.RegisterController).Methods(http.MethodPost)
+		s.router.HandleFunc("/login", loginHandler.LoginController).Methods(http.MethodPost)
+		s.router.HandleFunc("/token", loginHandler.LoginController).Methods(http.MethodPost)
+		s.router.HandleFunc("/token/refresh", refreshTokenHandler.RefreshTokenController).Methods(http.MethodPost)
+
+		jwksHandler := http2.NewJwksHandler(jwtMechanism.KeySet(), s.logger)
+		s.router.HandleFunc("/.well-known/jwks.json", jwksHandler.JwksController).Methods(http.MethodGet)
+
+		jwtMiddleware = http2.NewAuthenticationMw(jwtMechanism, tokenDenylistService, oidcAuthService, apiKeyService)
+	} else {
+		jwtMiddleware = http2.NewAuthenticationMw(nil, tokenDenylistService, oidcAuthService, apiKeyService)
+	}
+
+	// every authenticated request counts against the cheap limit, the routes
+	// calling OpenAI also against the expensive one
+	cheapRateLimit := http2.RateLimitMW(s.newRateLimiter("RATE_LIMIT_CHEAP", 120, 60))
+	expensiveRateLimiter := s.newRateLimiter("RATE_LIMIT_EXPENSIVE", 10, 5)
+	expensiveRateLimit := http2.RateLimitMW(expensiveRateLimiter)
+
+	// MCP connections authenticate like the REST API, and the tools act as the
+	// authenticated user. The tools calling OpenAI count against the expensive
+	// limit on their own, a single connection carries many calls.
+	mcpAuthentication := func(handler http.Handler) http.Handler {
+		return jwtMiddleware.AuthenticationMW(cheapRateLimit(handler))
+	}
+
+	mcpTransports, err := mcp.ParseHTTPTransports(os.Getenv("MCP_TRANSPORTS"))
+	if err != nil {
+		s.logger.Fatal("Cannot parse MCP_TRANSPORTS", map[string]interface{}{"errorMessage": err.Error()})
+	}
+
+	for _, transport := range mcpTransports {
+		switch transport {
+		case mcp.TransportSSE:
+			s.router.PathPrefix("/mcp/").Handler(mcpAuthentication(s.mcpServer.InitialiseSSEServer()))
+		case mcp.TransportStreamableHTTP:
+			s.router.Handle("/mcp", mcpAuthentication(s.mcpServer.InitialiseStreamableHTTPServer()))
+		}
+	}
 
 	protected := s.router.PathPrefix("/").Subrouter()
-	protected.Use(jwtMiddleware.AuthenticationMW)
+	protected.Use(jwtMiddleware.AuthenticationMW, cheapRateLimit)
+
+	protected.HandleFunc("/logout", logoutHandler.LogoutController).Methods(http.MethodPost)
 
 	chatSessionRepository := repositories.NewChatSessionRepository(s.DB)
-	chatSessionService := services.NewChatSessionService(s.logger, chatSessionRepository)
+	// deleted chat sessions are purged once they are older than the retention
+	chatSessionService := services.NewChatSessionService(s.logger, chatSessionRepository, durationFromEnv(s.logger, "CHAT_SESSION_DELETED_RETENTION", services.DefaultDeletedChatSessionRetention))
 	messageRepository := repositories.NewMessageRepository(s.DB)
-	messageService := services.NewMessageService(s.logger, messageRepository, chatSessionRepository, s.embedder, s.pineconeVectorDB, s.openAIClient)
+	curatedAnswerRepository := repositories.NewCuratedAnswerRepository(s.DB)
+
+	// monthly quotas per user, 0 means no limit
+	usageQuota := domain.UsageQuota{
+		MonthlyTokens:   int64(intFromEnv(s.logger, "USAGE_MONTHLY_TOKEN_QUOTA", 0)),
+		MonthlyMessages: int64(intFromEnv(s.logger, "USAGE_MONTHLY_MESSAGE_QUOTA", 0)),
+	}
+	usageRepository := repositories.NewUsageRepository(s.DB)
+	usageService := services.NewUsageService(s.logger, usageRepository, usageQuota)
+
+	vehicleComparisonRepository := repositories.NewVehicleComparisonRepository(s.DB)
+	vehicleComparisonService := services.NewVehicleComparisonService(s.logger, vehicleComparisonRepository)
+
+	messageService := services.NewMessageService(s.logger, messageRepository, chatSessionRepository, curatedAnswerRepository, s.embedder, s.pineconeVectorDB, s.openAIClient, usageService, vehicleComparisonService, intFromEnv(s.logger, "AGENT_MAX_STEPS", services.DefaultAgentMaxSteps), s.externalTools)
+
+	// the documents the knowledge base was ingested from, served as MCP
+	// resources
+	knowledgeBaseDocuments := os.Getenv("KNOWLEDGE_BASE_DOCUMENTS")
+	if knowledgeBaseDocuments == "" {
+		knowledgeBaseDocuments = domain.DefaultKnowledgeBaseDocuments
+	}
+	documents, err := domain.ParseDocuments(knowledgeBaseDocuments)
+	if err != nil {
+		s.logger.Fatal("Cannot parse KNOWLEDGE_BASE_DOCUMENTS", map[string]interface{}{"errorMessage": err.Error()})
+	}
+
+	knowledgeBaseService := services.NewKnowledgeBaseService(s.logger, s.embedder, s.pineconeVectorDB, usageService, documents)
+
+	s.ingestVehicleComparisons(knowledgeBaseService, vehicleComparisonService)
+
+	// the MCP tools answer through the same services as the HTTP handlers
+	s.mcpServer.Register(mcp.Services{
+		KnowledgeBaseService:     knowledgeBaseService,
+		MessageService:           messageService,
+		ChatSessionService:       chatSessionService,
+		VehicleComparisonService: vehicleComparisonService,
+		UsageService:             usageService,
+		ExpensiveRateLimiter:     expensiveRateLimiter,
+	})
 
 	createChatSessionHandler := chatSessions2.NewCreateUserChatSessionHandler(chatSessionService, s.logger)
 	getChatSessionHandler := chatSessions2.NewGetChatSessionHandler(chatSessionService, s.logger)
 	sendMessageHandler := chatSessions2.NewSendMessageHandler(messageService, s.logger)
 	submitFeedbackHandler := chatSessions2.NewSubmitFeedbackHandler(messageService, s.logger)
+	getMessagesHandler := chatSessions2.NewGetMessagesHandler(messageService, s.logger)
+	updateChatSessionHandler := chatSessions2.NewUpdateChatSessionHandler(chatSessionService, s.logger)
+	deleteChatSessionHandler := chatSessions2.NewDeleteChatSessionHandler(chatSessionService, s.logger)
+	getUsageHandler := usage2.NewGetUsageHandler(usageService, s.logger)
+
+	// the user_id of these paths must be the authenticated user
+	userScoped := protected.PathPrefix("/users/{user_id}").Subrouter()
+	userScoped.Use(http2.UserPathMW)
+
+	// requests with an API key are limited to the scopes of the key
+	userScoped.Handle("/chat-sessions", withScope(domain.ScopeSendMessages, createChatSessionHandler.CreateUserChatSessionController)).Methods("POST")
+	userScoped.Handle("/chat-sessions", withScope(domain.ScopeReadSessions, getChatSessionHandler.GetUserChatSessionsController)).Methods("GET")
+	userScoped.Handle("/chat-sessions/{session_id}/title", withScope(domain.ScopeSendMessages, updateChatSessionHandler.RenameChatSessionController)).Methods("PUT")
+	userScoped.Handle("/chat-sessions/{session_id}/archive", withScope(domain.ScopeSendMessages, updateChatSessionHandler.ArchiveChatSessionController)).Methods("POST")
+	userScoped.Handle("/chat-sessions/{session_id}/unarchive", withScope(domain.ScopeSendMessages, updateChatSessionHandler.UnarchiveChatSessionController)).Methods("POST")
+	userScoped.Handle("/chat-sessions/{session_id}", withScope(domain.ScopeSendMessages, deleteChatSessionHandler.DeleteChatSessionController)).Methods("DELETE")
+	userScoped.Handle("/chat-sessions/{session_id}/messages", withScope(domain.ScopeReadSessions, getMessagesHandler.GetMessagesController)).Methods("GET")
+	userScoped.Handle("/chat-sessions/{session_id}/messages", expensiveRateLimit(withScope(domain.ScopeSendMessages, sendMessageHandler.SendMessageController))).Methods("POST")
+	userScoped.Handle("/chat-sessions/{session_id}/messages/{message_id}/feedback", withScope(domain.ScopeSendMessages, submitFeedbackHandler.SubmitFeedbackController)).Methods("POST")
+
+	userScoped.Handle("/usage", withScope(domain.ScopeReadSessions, getUsageHandler.GetUsageController)).Methods("GET")
+
+	protected.Handle("/chat-sessions/{session_id}", withScope(domain.ScopeReadSessions, getChatSessionHandler.GetChatSessionController)).Methods("GET")
+
+	compareVehiclesHandler := vehicles2.NewCompareVehiclesHandler(vehicleComparisonService, s.logger)
+
+	protected.Handle("/vehicles/comparisons", withScope(domain.ScopeSendMessages, compareVehiclesHandler.CompareVehiclesController)).Methods("GET")
+
+	createAPIKeyHandler := apiKeys2.NewCreateAPIKeyHandler(apiKeyService, s.logger)
+	getAPIKeysHandler := apiKeys2.NewGetAPIKeysHandler(apiKeyService, s.logger)
+	revokeAPIKeyHandler := apiKeys2.NewRevokeAPIKeyHandler(apiKeyService, s.logger)
+
+	// API keys cannot mint or revoke API keys
+	apiKeysRouter := userScoped.PathPrefix("/api-keys").Subrouter()
+	apiKeysRouter.Use(http2.UserTokenMW)
+
+	apiKeysRouter.HandleFunc("", createAPIKeyHandler.CreateAPIKeyController).Methods(http.MethodPost)
+	apiKeysRouter.HandleFunc("", getAPIKeysHandler.GetAPIKeysController).Methods(http.MethodGet)
+	apiKeysRouter.HandleFunc("/{api_key_id}", revokeAPIKeyHandler.RevokeAPIKeyController).Methods(http.MethodDelete)
+
+	// curators manage the knowledge base and review feedback, admins also see
+	// analytics and manage roles
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(http2.ScopeMW(domain.ScopeManageKB))
+
+	feedbackRepository := repositories.NewFeedbackRepository(s.DB)
+	feedbackService := services.NewFeedbackService(s.logger, feedbackRepository)
+
+	getFeedbackAnalyticsHandler := feedback2.NewGetFeedbackAnalyticsHandler(feedbackService, s.logger)
+	exportFeedbackHandler := feedback2.NewExportFeedbackHandler(feedbackService, s.logger)
+
+	admin.Handle("/feedback/analytics", withRole(domain.RoleAdmin, getFeedbackAnalyticsHandler.GetFeedbackAnalyticsController)).Methods("GET")
+	admin.Handle("/feedback/export", withRole(domain.RoleCurator, exportFeedbackHandler.ExportFeedbackController)).Methods("GET")
+
+	curatedAnswerService := services.NewCuratedAnswerService(s.logger, curatedAnswerRepository, s.embedder, s.pineconeVectorDB)
+
+	getCuratedAnswersHandler := curatedAnswers2.NewGetCuratedAnswersHandler(curatedAnswerService, s.logger)
+	reviewCuratedAnswerHandler := curatedAnswers2.NewReviewCuratedAnswerHandler(curatedAnswerService, s.logger)
+
+	admin.Handle("/curated-answers", withRole(domain.RoleCurator, getCuratedAnswersHandler.GetCuratedAnswersController)).Methods("GET")
+	admin.Handle("/curated-answers/{curated_answer_id}/approve", expensiveRateLimit(withRole(domain.RoleCurator, reviewCuratedAnswerHandler.ApproveCuratedAnswerController))).Methods("POST")
+	admin.Handle("/curated-answers/{curated_answer_id}/reject", withRole(domain.RoleCurator, reviewCuratedAnswerHandler.RejectCuratedAnswerController)).Methods("POST")
+
+	purgeChatSessionsHandler := chatSessions2.NewPurgeChatSessionsHandler(chatSessionService, s.logger)
+
+	admin.Handle("/chat-sessions/purge", withRole(domain.RoleAdmin, purgeChatSessionsHandler.PurgeChatSessionsController)).Methods("POST")
+
+	updateUserRoleHandler := users2.NewUpdateUserRoleHandler(userService, s.logger)
+
+	// roles are only handed out by an admin in person, never with an API key
+	admin.Handle("/users/{user_id}/role", http2.UserTokenMW(withRole(domain.RoleAdmin, updateUserRoleHandler.UpdateUserRoleController))).Methods(http.MethodPut)
+}
+
+// ingestVehicleComparisons parses the comparisons of the vehicles document
+// into postgres, where they are answered exactly from. The rest of the
+// product works without them, so a failure is only logged.
+func (s *Server) ingestVehicleComparisons(
+	knowledgeBaseService *services.KnowledgeBaseService,
+	vehicleComparisonService *services.VehicleComparisonService,
+) {
+	ctx := context.Background()
+
+	_, text, err := knowledgeBaseService.GetDocumentContent(ctx, domain.ChunkTypeVehicles)
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		err = resourceNotFound.Unwrap()
+	}
+
+	if err != nil {
+		s.logger.Warn("Cannot read the vehicles document, vehicle comparisons are not ingested",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		return
+	}
+
+	count, err := vehicleComparisonService.IngestVehicleComparisons(ctx, text)
+	if err != nil {
+		s.logger.Warn("Cannot ingest vehicle comparisons",
+			map[string]interface{}{
+				"errorMessage": err.Error(),
+			})
+
+		return
+	}
+
+	s.logger.Info("Ingested vehicle comparisons", map[string]interface{}{"count": count})
+}
+
+// newAuthMechanism signs tokens with JWT_SECRET_KEY for the HS* methods, and
+// with the PEM keys of JWT_SIGNING_KEYS ("kid=path,kid=path") otherwise, the
+// key named by JWT_SIGNING_KEY_ID signing new tokens
+func (s *Server) newAuthMechanism(accessTokenTTL time.Duration) *auth.AuthMechanism {
+	signingMethod := os.Getenv("JWT_SIGNING_METHOD")
+	if strings.HasPrefix(signingMethod, "HS") {
+		return auth.NewAuthMechanism(os.Getenv("JWT_SECRET_KEY"), signingMethod, accessTokenTTL)
+	}
+
+	keyPaths, err := auth.ParseKeyPaths(os.Getenv("JWT_SIGNING_KEYS"))
+	if err != nil {
+		s.logger.Fatal("Cannot parse JWT_SIGNING_KEYS", map[string]interface{}{"errorMessage": err.Error()})
+	}
+
+	keySet, err := auth.LoadKeySet(keyPaths, os.Getenv("JWT_SIGNING_KEY_ID"))
+	if err != nil {
+		s.logger.Fatal("Cannot load JWT signing keys", map[string]interface{}{"errorMessage": err.Error()})
+	}
+
+	if keySet.SigningKey().Method.Alg() != signingMethod {
+		s.logger.Fatal("JWT signing key does not match JWT_SIGNING_METHOD",
+			map[string]interface{}{
+				"signingMethod": signingMethod,
+				"keyMethod":     keySet.SigningKey().Method.Alg(),
+			})
+	}
+
+	return auth.NewAsymmetricAuthMechanism(keySet, accessTokenTTL)
+}
+
+// newOIDCAuthService accepts the tokens of the OpenID Connect provider at
+// OIDC_ISSUER_URL whose audience is OIDC_AUDIENCE, mapping the
+// OIDC_USERNAME_CLAIM claim to local users. It returns nil when no issuer is
+// configured.
+func (s *Server) newOIDCAuthService(
+	userRepository *repositories.UserRepository,
+) services.OIDCAuthServiceInterface {
+	issuer := os.Getenv("OIDC_ISSUER_URL")
+	if issuer == "" {
+		return nil
+	}
+
+	audience := os.Getenv("OIDC_AUDIENCE")
+	if audience == "" {
+		s.logger.Fatal("OIDC_AUDIENCE is required with OIDC_ISSUER_URL", nil)
+	}
+
+	usernameClaim := os.Getenv("OIDC_USERNAME_CLAIM")
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+
+	jwksCacheTTL := durationFromEnv(s.logger, "OIDC_JWKS_CACHE_TTL", time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	verifier, err := auth.NewOIDCVerifier(ctx, issuer, audience, &http.Client{Timeout: 10 * time.Second}, jwksCacheTTL)
+	if err != nil {
+		s.logger.Fatal("Cannot initialise OIDC verifier",
+			map[string]interface{}{
+				"issuer":       issuer,
+				"errorMessage": err.Error(),
+			})
+	}
+
+	return services.NewOIDCAuthService(s.logger, userRepository, verifier, usernameClaim)
+}
+
+// newRateLimiter reads the <prefix>_PER_MINUTE rate and the <prefix>_BURST of
+// a limit from the environment. A rate of 0 disables the limit.
+func (s *Server) newRateLimiter(prefix string, defaultPerMinute int, defaultBurst int) *ratelimit.Limiter {
+	perMinute := intFromEnv(s.logger, prefix+"_PER_MINUTE", defaultPerMinute)
+	if perMinute <= 0 {
+		return nil
+	}
+
+	burst := intFromEnv(s.logger, prefix+"_BURST", defaultBurst)
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return ratelimit.NewLimiter(float64(perMinute), burst)
+}
+
+func withScope(scope string, handlerFunc http.HandlerFunc) http.Handler {
+	return http2.ScopeMW(scope)(handlerFunc)
+}
+
+func withRole(role string, handlerFunc http.HandlerFunc) http.Handler {
+	return http2.RoleMW(role)(handlerFunc)
+}
+
+// durationFromEnv parses a duration like "15m" from the environment, falling
+// back to the default when it is not set or malformed
+func durationFromEnv(logger logger.LoggerInterface, name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Warn("Malformed duration in env, using default",
+			map[string]interface{}{
+				"env":          name,
+				"default":      fallback.String(),
+				"errorMessage": err.Error(),
+			})
+
+		return fallback
+	}
+
+	return duration
+}
+
+// intFromEnv parses an integer from the environment, falling back to the
+// default when it is not set or malformed
+func intFromEnv(logger logger.LoggerInterface, name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
 
-	protected.HandleFunc("/users/{user_id}/chat-sessions", createChatSessionHandler.CreateUserChatSessionController).Methods("POST")
-	protected.HandleFunc("/users/{user_id}/chat-sessions", getChatSessionHandler.GetUserChatSessionsController).Methods("GET")
-	protected.HandleFunc("/users/{user_id}/chat-sessions/{session_id}/messages", sendMessageHandler.SendMessageController).Methods("POST")
-	protected.HandleFunc("/users/{user_id}/chat-sessions/{session_id}/messages/{message_id}/feedback", submitFeedbackHandler.SubmitFeedbackController).Methods("POST")
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Warn("Malformed integer in env, using default",
+			map[string]interface{}{
+				"env":          name,
+				"default":      fallback,
+				"errorMessage": err.Error(),
+			})
 
-	protected.HandleFunc("/chat-sessions/{session_id}", getChatSessionHandler.GetChatSessionController).Methods("GET")
+		return fallback
+	}
 
+	return number
 }
@@ -5,10 +5,11 @@ import (
 	"errors"
 	"github.com/gorilla/mux"
 	"github.com/loukaspe/rag-golang/pkg/embeddings"
+	"github.com/loukaspe/rag-golang/pkg/llm"
 	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/mcpclient"
 	"github.com/loukaspe/rag-golang/pkg/server/mcp"
 	"github.com/loukaspe/rag-golang/pkg/vectordb"
-	"github.com/openai/openai-go"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"net/http"
@@ -24,9 +25,10 @@ type Server struct {
 	mcpServer        *mcp.Server
 	router           *mux.Router
 	logger           logger.LoggerInterface
-	openAIClient     *openai.Client
+	openAIClient     *llm.Client
 	embedder         *embeddings.EmbeddingService
 	pineconeVectorDB *vectordb.PineconeVectorDB
+	externalTools    *mcpclient.ToolSource
 }
 
 func NewServer(
@@ -35,9 +37,10 @@ func NewServer(
 	httpServer *http.Server,
 	mcpServer *mcp.Server,
 	logger logger.LoggerInterface,
-	openAIClient *openai.Client,
+	openAIClient *llm.Client,
 	embedder *embeddings.EmbeddingService,
 	pineconeVectorDB *vectordb.PineconeVectorDB,
+	externalTools *mcpclient.ToolSource,
 ) *Server {
 	return &Server{
 		DB:               db,
@@ -48,6 +51,7 @@ func NewServer(
 		openAIClient:     openAIClient,
 		embedder:         embedder,
 		pineconeVectorDB: pineconeVectorDB,
+		externalTools:    externalTools,
 	}
 }
 
@@ -69,6 +73,7 @@ func (s *Server) Run() {
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		log.Fatal(err)
 	}
+	s.externalTools.Close()
 	db, err := s.DB.DB()
 	if err != nil {
 		log.Fatal(err)
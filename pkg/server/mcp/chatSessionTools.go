@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type ChatSessionTools struct {
+	chatSessionService services.ChatSessionServiceInterface
+	logger             logger.LoggerInterface
+}
+
+func listChatSessionsTool() mcp.Tool {
+	return mcp.NewTool("list_chat_sessions",
+		mcp.WithDescription("List the chat sessions of the authenticated user, most recently updated first, "+
+			"with their message count and last message instead of their messages"),
+		mcp.WithBoolean("archived",
+			mcp.Description("Only the archived sessions when true, only the rest when false, all of them when left out"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("nextCursor of the previous page, to list the next one"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of sessions in a page, 1 to 100"),
+			mcp.DefaultNumber(services.DefaultChatSessionPageSize),
+		),
+	)
+}
+
+func getChatSessionTool() mcp.Tool {
+	return mcp.NewTool("get_chat_session",
+		mcp.WithDescription("Get a chat session of the authenticated user with its messages"),
+		mcp.WithString("session_id",
+			mcp.Required(),
+			mcp.Description("ID of the chat session"),
+		),
+	)
+}
+
+func (tools *ChatSessionTools) ListChatSessions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// authenticated already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	filter := &domain.ChatSessionFilter{
+		Limit: request.GetInt("limit", services.DefaultChatSessionPageSize),
+	}
+
+	if archived, ok := request.GetArguments()["archived"].(bool); ok {
+		filter.Archived = &archived
+	}
+
+	if value := request.GetString("cursor", ""); value != "" {
+		cursor, err := domain.ParseChatSessionCursor(value)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filter.After = cursor
+	}
+
+	page, err := tools.chatSessionService.GetUserChatSessions(ctx, userID, filter)
+	if err != nil {
+		return errorResult(tools.logger, "Error in getting user's chat sessions", "error in getting user chat sessions", err), nil
+	}
+
+	return jsonResult(ChatSessionsResultFromModel(page))
+}
+
+func (tools *ChatSessionTools) GetChatSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// authenticated already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	sessionID, invalidArgument := requireUUID(request, "session_id")
+	if invalidArgument != nil {
+		return invalidArgument, nil
+	}
+
+	chatSession, err := tools.chatSessionService.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return errorResult(tools.logger, "Error in getting chat session", "error in getting chat session", err), nil
+	}
+
+	return jsonResult(ChatSessionResultFromModel(chatSession))
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestChatSessionTools_ListChatSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+	mcpClient := newTestClient(t, Services{ChatSessionService: mockChatSessionService})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	archived := false
+	cursor := &domain.ChatSessionCursor{
+		UpdatedAt: createdAt,
+		ID:        uuid.UUID{0x32, 0x34, 0x56, 0x78},
+	}
+
+	tests := []struct {
+		name                     string
+		arguments                map[string]interface{}
+		expectedFilter           *domain.ChatSessionFilter
+		mockServiceResponseData  *domain.ChatSessionPage
+		mockServiceResponseError error
+		expected                 string
+		expectedIsError          bool
+	}{
+		{
+			name:           "valid",
+			arguments:      map[string]interface{}{},
+			expectedFilter: &domain.ChatSessionFilter{Limit: 20},
+			mockServiceResponseData: &domain.ChatSessionPage{
+				Sessions: []*domain.ChatSessionSummary{
+					{
+						ID:           uuid.UUID{0x32, 0x34, 0x56, 0x78},
+						UserID:       userID,
+						Title:        "Luke",
+						CreatedAt:    createdAt,
+						UpdatedAt:    createdAt,
+						MessageCount: 1,
+						LastMessage: &domain.Message{
+							ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
+							Sender:    repositories.USER_SENDER,
+							Content:   "Who is Luke?",
+							CreatedAt: createdAt,
+						},
+					},
+				},
+			},
+			expected: `{"sessions":[{"id":"32345678-0000-0000-0000-000000000000","title":"Luke","createdAt":"2025-01-02T03:04:05Z","updatedAt":"2025-01-02T03:04:05Z","messageCount":1,` +
+				`"lastMessage":{"id":"12345678-0000-0000-0000-000000000000","sender":"USER","content":"Who is Luke?","createdAt":"2025-01-02T03:04:05Z"}}]}`,
+		},
+		{
+			name: "next page of unarchived",
+			arguments: map[string]interface{}{
+				"archived": false,
+				"cursor":   cursor.String(),
+				"limit":    1,
+			},
+			expectedFilter: &domain.ChatSessionFilter{Archived: &archived, After: cursor, Limit: 1},
+			mockServiceResponseData: &domain.ChatSessionPage{
+				Sessions: []*domain.ChatSessionSummary{
+					{
+						ID:        uuid.UUID{0x22, 0x34, 0x56, 0x78},
+						UserID:    userID,
+						CreatedAt: createdAt,
+						UpdatedAt: createdAt,
+					},
+				},
+				NextCursor: &domain.ChatSessionCursor{UpdatedAt: createdAt, ID: uuid.UUID{0x22, 0x34, 0x56, 0x78}},
+			},
+			expected: `{"sessions":[{"id":"22345678-0000-0000-0000-000000000000","createdAt":"2025-01-02T03:04:05Z","updatedAt":"2025-01-02T03:04:05Z","messageCount":0}],` +
+				`"nextCursor":"` + (&domain.ChatSessionCursor{UpdatedAt: createdAt, ID: uuid.UUID{0x22, 0x34, 0x56, 0x78}}).String() + `"}`,
+		},
+		{
+			name:            "malformed cursor",
+			arguments:       map[string]interface{}{"cursor": "malformed"},
+			expected:        "malformed cursor",
+			expectedIsError: true,
+		},
+		{
+			name:                     "service error",
+			arguments:                map[string]interface{}{},
+			expectedFilter:           &domain.ChatSessionFilter{Limit: 20},
+			mockServiceResponseError: errors.New("database is down"),
+			expected:                 "error in getting user chat sessions",
+			expectedIsError:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedFilter != nil {
+				mockChatSessionService.EXPECT().
+					GetUserChatSessions(gomock.Any(), userID, tt.expectedFilter).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, isError := callTool(t, mcpClient, userID, "list_chat_sessions", tt.arguments)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.expectedIsError, isError)
+		})
+	}
+}
+
+func TestChatSessionTools_GetChatSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+	mcpClient := newTestClient(t, Services{ChatSessionService: mockChatSessionService})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	chatSessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name                     string
+		arguments                map[string]interface{}
+		mockServiceCalled        bool
+		mockServiceResponseData  *domain.ChatSession
+		mockServiceResponseError error
+		expected                 string
+		expectedIsError          bool
+	}{
+		{
+			name:              "valid",
+			arguments:         map[string]interface{}{"session_id": chatSessionID.String()},
+			mockServiceCalled: true,
+			mockServiceResponseData: &domain.ChatSession{
+				ID:        chatSessionID,
+				UserID:    userID,
+				Title:     "Luke",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Messages: []*domain.Message{
+					{
+						ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78},
+						Sender:    repositories.USER_SENDER,
+						Content:   "Who is Luke?",
+						CreatedAt: createdAt,
+					},
+				},
+			},
+			expected: `{"id":"32345678-0000-0000-0000-000000000000","title":"Luke","createdAt":"2025-01-02T03:04:05Z","updatedAt":"2025-01-02T03:04:05Z","messages":[{"id":"12345678-0000-0000-0000-000000000000","sender":"USER","content":"Who is Luke?","createdAt":"2025-01-02T03:04:05Z"}]}`,
+		},
+		{
+			name:            "malformed session id",
+			arguments:       map[string]interface{}{"session_id": "1"},
+			expected:        "malformed session_id",
+			expectedIsError: true,
+		},
+		{
+			name:                     "chat session not found",
+			arguments:                map[string]interface{}{"session_id": chatSessionID.String()},
+			mockServiceCalled:        true,
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{OriginalError: errors.New("chatSessionID 32345678-0000-0000-0000-000000000000 not found")},
+			expected:                 "not found",
+			expectedIsError:          true,
+		},
+		{
+			name:                     "chat session of another user",
+			arguments:                map[string]interface{}{"session_id": chatSessionID.String()},
+			mockServiceCalled:        true,
+			mockServiceResponseError: customerrors.NewUserMismatchError(chatSessionID.String(), userID.String()),
+			expected:                 "chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000",
+			expectedIsError:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockServiceCalled {
+				mockChatSessionService.EXPECT().
+					GetChatSession(gomock.Any(), chatSessionID, userID).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, isError := callTool(t, mcpClient, userID, "get_chat_session", tt.arguments)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.expectedIsError, isError)
+		})
+	}
+}
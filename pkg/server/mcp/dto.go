@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"time"
+)
+
+type SearchHit struct {
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+	Type  string  `json:"type,omitempty"`
+}
+
+func SearchHitsFromModel(chunks []*domain.RetrievedChunk) []SearchHit {
+	hits := make([]SearchHit, len(chunks))
+	for i, chunk := range chunks {
+		hits[i] = SearchHit{
+			ID:    chunk.ID,
+			Text:  chunk.Text,
+			Score: chunk.Score,
+			Type:  chunk.Type(),
+		}
+	}
+
+	return hits
+}
+
+type SearchKnowledgeBaseResult struct {
+	Hits []SearchHit `json:"hits"`
+}
+
+type AskQuestionResult struct {
+	ChatSessionID string      `json:"chatSessionId"`
+	MessageID     string      `json:"messageId"`
+	Answer        string      `json:"answer"`
+	Sources       []SearchHit `json:"sources"`
+}
+
+type ChatSessionResult struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title,omitempty"`
+	CreatedAt string          `json:"createdAt"`
+	UpdatedAt string          `json:"updatedAt"`
+	Messages  []MessageResult `json:"messages,omitempty"`
+}
+
+type MessageResult struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func ChatSessionResultFromModel(chatSession *domain.ChatSession) ChatSessionResult {
+	result := ChatSessionResult{
+		ID:        chatSession.ID.String(),
+		Title:     chatSession.Title,
+		CreatedAt: chatSession.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: chatSession.UpdatedAt.Format(time.RFC3339),
+		Messages:  make([]MessageResult, len(chatSession.Messages)),
+	}
+
+	for i, message := range chatSession.Messages {
+		result.Messages[i] = MessageResultFromModel(message)
+	}
+
+	return result
+}
+
+func MessageResultFromModel(message *domain.Message) MessageResult {
+	return MessageResult{
+		ID:        message.ID.String(),
+		Sender:    message.Sender,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ChatSessionSummaryResult is a chat session in the list, with the number of
+// its messages and the start of the last one instead of them
+type ChatSessionSummaryResult struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title,omitempty"`
+	CreatedAt    string         `json:"createdAt"`
+	UpdatedAt    string         `json:"updatedAt"`
+	ArchivedAt   string         `json:"archivedAt,omitempty"`
+	MessageCount int            `json:"messageCount"`
+	LastMessage  *MessageResult `json:"lastMessage,omitempty"`
+}
+
+type ChatSessionsResult struct {
+	Sessions   []ChatSessionSummaryResult `json:"sessions"`
+	NextCursor string                     `json:"nextCursor,omitempty"`
+}
+
+func ChatSessionsResultFromModel(page *domain.ChatSessionPage) *ChatSessionsResult {
+	result := &ChatSessionsResult{
+		Sessions: make([]ChatSessionSummaryResult, len(page.Sessions)),
+	}
+
+	for i, summary := range page.Sessions {
+		result.Sessions[i] = ChatSessionSummaryResult{
+			ID:           summary.ID.String(),
+			Title:        summary.Title,
+			CreatedAt:    summary.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    summary.UpdatedAt.Format(time.RFC3339),
+			MessageCount: summary.MessageCount,
+		}
+
+		if summary.ArchivedAt != nil {
+			result.Sessions[i].ArchivedAt = summary.ArchivedAt.Format(time.RFC3339)
+		}
+
+		if summary.LastMessage != nil {
+			lastMessage := MessageResultFromModel(summary.LastMessage)
+			result.Sessions[i].LastMessage = &lastMessage
+		}
+	}
+
+	if page.NextCursor != nil {
+		result.NextCursor = page.NextCursor.String()
+	}
+
+	return result
+}
+
+type VehicleComparisonResult struct {
+	Subject   string  `json:"subject"`
+	Attribute string  `json:"attribute"`
+	Object    string  `json:"object"`
+	Ratio     float64 `json:"ratio"`
+	Percent   float64 `json:"percent"`
+	Inverse   bool    `json:"inverse"`
+	Statement string  `json:"statement"`
+	Line      int     `json:"line"`
+}
+
+func VehicleComparisonResultsFromModel(facts []*domain.VehicleComparisonFact) []VehicleComparisonResult {
+	results := make([]VehicleComparisonResult, len(facts))
+	for i, fact := range facts {
+		results[i] = VehicleComparisonResult{
+			Subject:   fact.Subject,
+			Attribute: fact.Attribute,
+			Object:    fact.Object,
+			Ratio:     fact.Ratio,
+			Percent:   fact.Percent,
+			Inverse:   fact.Inverse,
+			Statement: fact.Statement,
+			Line:      fact.Line,
+		}
+	}
+
+	return results
+}
+
+type CompareVehiclesResult struct {
+	Comparisons []VehicleComparisonResult `json:"comparisons"`
+}
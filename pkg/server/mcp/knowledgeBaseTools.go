@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchTopK = 5
+	maxSearchTopK     = 20
+)
+
+type KnowledgeBaseTools struct {
+	knowledgeBaseService services.KnowledgeBaseServiceInterface
+	messageService       services.MessageServiceInterface
+	chatSessionService   services.ChatSessionServiceInterface
+	usageService         services.UsageServiceInterface
+	logger               logger.LoggerInterface
+}
+
+func searchKnowledgeBaseTool() mcp.Tool {
+	return mcp.NewTool("search_knowledge_base",
+		mcp.WithDescription("Search the Star Wars knowledge base for the passages most similar to the query, without answering it"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Text to search for"),
+		),
+		mcp.WithNumber("top_k",
+			mcp.Description("Maximum number of hits, up to "+strconv.Itoa(maxSearchTopK)),
+			mcp.DefaultNumber(defaultSearchTopK),
+			mcp.Min(1),
+			mcp.Max(maxSearchTopK),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only search passages of this type"),
+			mcp.Enum(domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated),
+		),
+	)
+}
+
+func askQuestionTool() mcp.Tool {
+	return mcp.NewTool("ask_question",
+		mcp.WithDescription("Answer a question from the Star Wars knowledge base, storing it and its answer in a chat session of the authenticated user"),
+		mcp.WithString("question",
+			mcp.Required(),
+			mcp.Description("Question to answer"),
+		),
+		mcp.WithString("session_id",
+			mcp.Description("Chat session to continue, a new one is started when missing"),
+		),
+	)
+}
+
+func (tools *KnowledgeBaseTools) SearchKnowledgeBase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("empty query"), nil
+	}
+
+	topK := request.GetInt("top_k", defaultSearchTopK)
+	if topK < 1 || topK > maxSearchTopK {
+		return mcp.NewToolResultError("top_k must be between 1 and " + strconv.Itoa(maxSearchTopK)), nil
+	}
+
+	chunkType := request.GetString("type", "")
+	switch chunkType {
+	case "", domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated:
+	default:
+		return mcp.NewToolResultError("unknown type " + chunkType), nil
+	}
+
+	// authenticated already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	chunks, err := tools.knowledgeBaseService.Search(ctx, userID, query, domain.SearchOptions{
+		TopK: topK,
+		Type: chunkType,
+	})
+	if err != nil {
+		return errorResult(tools.logger, "Error in searching knowledge base", "error in searching knowledge base", err), nil
+	}
+
+	return jsonResult(&SearchKnowledgeBaseResult{
+		Hits: SearchHitsFromModel(chunks),
+	})
+}
+
+// AskQuestion runs the same flow as sending a message over HTTP: the
+// question is stored as a USER message of the session and answered with RAG
+func (tools *KnowledgeBaseTools) AskQuestion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// authenticated already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	question, err := request.RequireString("question")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if strings.TrimSpace(question) == "" {
+		return mcp.NewToolResultError("empty question"), nil
+	}
+
+	var chatSessionID uuid.UUID
+	if sessionIDAsString := request.GetString("session_id", ""); sessionIDAsString != "" {
+		chatSessionID, err = uuid.Parse(sessionIDAsString)
+		if err != nil {
+			return mcp.NewToolResultError("malformed session_id"), nil
+		}
+	} else {
+		// CreateMessage checks the quota too, but only after the session
+		// would have been created
+		if err = tools.usageService.CheckQuota(ctx, userID); err != nil {
+			return errorResult(tools.logger, "Error in sending message", "error in sending message", err), nil
+		}
+
+		chatSessionID, err = tools.chatSessionService.CreateChatSession(ctx, &domain.ChatSession{UserID: userID})
+		if err != nil {
+			return errorResult(tools.logger, "Error in creating chat session", "error in creating chat session", err), nil
+		}
+	}
+
+	messageID, err := tools.messageService.CreateMessage(ctx, userID, &domain.Message{
+		ChatSessionID: chatSessionID,
+		Content:       question,
+		Sender:        repositories.USER_SENDER,
+	})
+	if err != nil {
+		return errorResult(tools.logger, "Error in sending message", "error in sending message", err), nil
+	}
+
+	replyMessage, err := tools.messageService.GetAnswerForMessage(ctx, messageID)
+	if err != nil {
+		return errorResult(tools.logger, "Error in replying to message", "error in replying to message", err), nil
+	}
+
+	return jsonResult(&AskQuestionResult{
+		ChatSessionID: chatSessionID.String(),
+		MessageID:     replyMessage.ID.String(),
+		Answer:        replyMessage.Content,
+		Sources:       SearchHitsFromModel(replyMessage.Sources),
+	})
+}
@@ -0,0 +1,275 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestKnowledgeBaseTools_SearchKnowledgeBase(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return(nil).AnyTimes()
+	mcpClient := newTestClient(t, Services{KnowledgeBaseService: mockKnowledgeBaseService})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                     string
+		arguments                map[string]interface{}
+		expectedSearchOptions    *domain.SearchOptions
+		mockServiceResponseData  []*domain.RetrievedChunk
+		mockServiceResponseError error
+		expected                 string
+		expectedIsError          bool
+	}{
+		{
+			name:                  "valid with defaults",
+			arguments:             map[string]interface{}{"query": "Who is Luke?"},
+			expectedSearchOptions: &domain.SearchOptions{TopK: 5},
+			mockServiceResponseData: []*domain.RetrievedChunk{
+				{
+					ID:       "doc1-chunk-0",
+					Text:     "Luke Skywalker is a Jedi",
+					Score:    0.5,
+					Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+				},
+				{
+					ID:       "curated-1",
+					Text:     "Luke is the son of Anakin",
+					Score:    0.25,
+					Metadata: map[string]interface{}{domain.ChunkMetadataCurated: true},
+				},
+			},
+			expected: `{"hits":[{"id":"doc1-chunk-0","text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"},{"id":"curated-1","text":"Luke is the son of Anakin","score":0.25,"type":"curated"}]}`,
+		},
+		{
+			name:                    "valid with top k and type",
+			arguments:               map[string]interface{}{"query": "X-wing", "top_k": 3, "type": "vehicles"},
+			expectedSearchOptions:   &domain.SearchOptions{TopK: 3, Type: domain.ChunkTypeVehicles},
+			mockServiceResponseData: []*domain.RetrievedChunk{},
+			expected:                `{"hits":[]}`,
+		},
+		{
+			name:            "missing query",
+			arguments:       map[string]interface{}{},
+			expected:        `required argument "query" not found`,
+			expectedIsError: true,
+		},
+		{
+			name:            "top k out of range",
+			arguments:       map[string]interface{}{"query": "X-wing", "top_k": 50},
+			expected:        "top_k must be between 1 and 20",
+			expectedIsError: true,
+		},
+		{
+			name:            "unknown type",
+			arguments:       map[string]interface{}{"query": "X-wing", "type": "planets"},
+			expected:        "unknown type planets",
+			expectedIsError: true,
+		},
+		{
+			name:                     "quota exceeded",
+			arguments:                map[string]interface{}{"query": "X-wing"},
+			expectedSearchOptions:    &domain.SearchOptions{TopK: 5},
+			mockServiceResponseError: customerrors.NewQuotaExceededError(customerrors.QuotaTokens, 1000, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+			expected:                 "monthly token quota of 1000 exceeded, it resets at 2025-02-01T00:00:00Z",
+			expectedIsError:          true,
+		},
+		{
+			name:                     "search error",
+			arguments:                map[string]interface{}{"query": "X-wing"},
+			expectedSearchOptions:    &domain.SearchOptions{TopK: 5},
+			mockServiceResponseError: errors.New("pinecone is down"),
+			expected:                 "error in searching knowledge base",
+			expectedIsError:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedSearchOptions != nil {
+				mockKnowledgeBaseService.EXPECT().
+					Search(gomock.Any(), userID, tt.arguments["query"], *tt.expectedSearchOptions).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, isError := callTool(t, mcpClient, userID, "search_knowledge_base", tt.arguments)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.expectedIsError, isError)
+		})
+	}
+}
+
+func TestKnowledgeBaseTools_AskQuestion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMessageService := mock_services.NewMockMessageServiceInterface(mockCtrl)
+	mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+	mockUsageService := mock_services.NewMockUsageServiceInterface(mockCtrl)
+	mcpClient := newTestClient(t, Services{
+		MessageService:     mockMessageService,
+		ChatSessionService: mockChatSessionService,
+		UsageService:       mockUsageService,
+	})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	chatSessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	messageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	replyMessageID := uuid.UUID{0x22, 0x34, 0x56, 0x78}
+
+	replyMessage := &domain.Message{
+		ID:            replyMessageID,
+		ChatSessionID: chatSessionID,
+		Sender:        repositories.SYSTEM_SENDER,
+		Content:       "Luke is a Jedi",
+		Sources: []*domain.RetrievedChunk{
+			{
+				ID:       "doc1-chunk-0",
+				Text:     "Luke Skywalker is a Jedi",
+				Score:    0.5,
+				Metadata: map[string]interface{}{domain.ChunkMetadataType: domain.ChunkTypePeople},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		arguments       map[string]interface{}
+		setupMocks      func()
+		expected        string
+		expectedIsError bool
+	}{
+		{
+			name: "valid in new session",
+			arguments: map[string]interface{}{
+				"question": "Who is Luke?",
+			},
+			setupMocks: func() {
+				mockUsageService.EXPECT().
+					CheckQuota(gomock.Any(), userID).
+					Return(nil)
+				mockChatSessionService.EXPECT().
+					CreateChatSession(gomock.Any(), &domain.ChatSession{UserID: userID}).
+					Return(chatSessionID, nil)
+				mockMessageService.EXPECT().
+					CreateMessage(gomock.Any(), userID, &domain.Message{
+						ChatSessionID: chatSessionID,
+						Content:       "Who is Luke?",
+						Sender:        repositories.USER_SENDER,
+					}).
+					Return(messageID, nil)
+				mockMessageService.EXPECT().
+					GetAnswerForMessage(gomock.Any(), messageID).
+					Return(replyMessage, nil)
+			},
+			expected: `{"chatSessionId":"32345678-0000-0000-0000-000000000000","messageId":"22345678-0000-0000-0000-000000000000","answer":"Luke is a Jedi","sources":[{"id":"doc1-chunk-0","text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"}]}`,
+		},
+		{
+			name: "valid in existing session",
+			arguments: map[string]interface{}{
+				"question":   "Who is Luke?",
+				"session_id": chatSessionID.String(),
+			},
+			setupMocks: func() {
+				mockMessageService.EXPECT().
+					CreateMessage(gomock.Any(), userID, gomock.Any()).
+					Return(messageID, nil)
+				mockMessageService.EXPECT().
+					GetAnswerForMessage(gomock.Any(), messageID).
+					Return(replyMessage, nil)
+			},
+			expected: `{"chatSessionId":"32345678-0000-0000-0000-000000000000","messageId":"22345678-0000-0000-0000-000000000000","answer":"Luke is a Jedi","sources":[{"id":"doc1-chunk-0","text":"Luke Skywalker is a Jedi","score":0.5,"type":"people"}]}`,
+		},
+		{
+			name: "malformed session id",
+			arguments: map[string]interface{}{
+				"question":   "Who is Luke?",
+				"session_id": "luke",
+			},
+			setupMocks:      func() {},
+			expected:        "malformed session_id",
+			expectedIsError: true,
+		},
+		{
+			name: "session of another user",
+			arguments: map[string]interface{}{
+				"question":   "Who is Luke?",
+				"session_id": chatSessionID.String(),
+			},
+			setupMocks: func() {
+				mockMessageService.EXPECT().
+					CreateMessage(gomock.Any(), userID, gomock.Any()).
+					Return(uuid.Nil, customerrors.NewUserMismatchError(chatSessionID.String(), userID.String()))
+			},
+			expected:        "chatSession 32345678-0000-0000-0000-000000000000 does not belong to user 42345678-0000-0000-0000-000000000000",
+			expectedIsError: true,
+		},
+		{
+			name: "quota exceeded",
+			arguments: map[string]interface{}{
+				"question":   "Who is Luke?",
+				"session_id": chatSessionID.String(),
+			},
+			setupMocks: func() {
+				mockMessageService.EXPECT().
+					CreateMessage(gomock.Any(), userID, gomock.Any()).
+					Return(uuid.Nil, customerrors.NewQuotaExceededError(customerrors.QuotaMessages, 100, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+			},
+			expected:        "monthly message quota of 100 exceeded, it resets at 2025-02-01T00:00:00Z",
+			expectedIsError: true,
+		},
+		{
+			name: "quota exceeded in new session",
+			arguments: map[string]interface{}{
+				"question": "Who is Luke?",
+			},
+			setupMocks: func() {
+				mockUsageService.EXPECT().
+					CheckQuota(gomock.Any(), userID).
+					Return(customerrors.NewQuotaExceededError(customerrors.QuotaMessages, 100, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+			},
+			expected:        "monthly message quota of 100 exceeded, it resets at 2025-02-01T00:00:00Z",
+			expectedIsError: true,
+		},
+		{
+			name: "answer error",
+			arguments: map[string]interface{}{
+				"question":   "Who is Luke?",
+				"session_id": chatSessionID.String(),
+			},
+			setupMocks: func() {
+				mockMessageService.EXPECT().
+					CreateMessage(gomock.Any(), userID, gomock.Any()).
+					Return(messageID, nil)
+				mockMessageService.EXPECT().
+					GetAnswerForMessage(gomock.Any(), messageID).
+					Return(nil, errors.New("openai is down"))
+			},
+			expected:        "error in replying to message",
+			expectedIsError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			actual, isError := callTool(t, mcpClient, userID, "ask_question", tt.arguments)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.expectedIsError, isError)
+		})
+	}
+}
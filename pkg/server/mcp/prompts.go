@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"strings"
+)
+
+type Prompts struct {
+	knowledgeBaseService services.KnowledgeBaseServiceInterface
+	limiter              *ratelimit.Limiter
+	logger               logger.LoggerInterface
+}
+
+func (s *Server) registerPrompts(services Services) {
+	prompts := &Prompts{
+		knowledgeBaseService: services.KnowledgeBaseService,
+		limiter:              services.ExpensiveRateLimiter,
+		logger:               s.logger,
+	}
+
+	s.mcpServer.AddPrompt(ragAnswerPrompt(), prompts.RAGAnswer)
+	s.mcpServer.AddPrompt(chatTitlePrompt(), prompts.ChatTitle)
+}
+
+func ragAnswerPrompt() mcp.Prompt {
+	return mcp.NewPrompt("rag_answer",
+		mcp.WithPromptDescription("The prompt that answers a question from the Star Wars knowledge base, with the passages most similar to the question as its context"),
+		mcp.WithArgument("question",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Question to answer"),
+		),
+		mcp.WithArgument("type",
+			mcp.ArgumentDescription("Only use passages of this type, one of "+
+				strings.Join([]string{domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated}, ", ")),
+		),
+	)
+}
+
+func chatTitlePrompt() mcp.Prompt {
+	return mcp.NewPrompt("chat_title",
+		mcp.WithPromptDescription("The prompt that summarizes the first message of a chat session into its title"),
+		mcp.WithArgument("message",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("First message of the chat session"),
+		),
+	)
+}
+
+// RAGAnswer searches the knowledge base like answering a message does, so it
+// needs the same scope. MCP prompts have no system role, so the instructions
+// are the first user message.
+func (prompts *Prompts) RAGAnswer(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	ctx, err := authorize(ctx, domain.ScopeSendMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := allow(ctx, prompts.limiter); err != nil {
+		return nil, err
+	}
+
+	question := request.Params.Arguments["question"]
+	if strings.TrimSpace(question) == "" {
+		return nil, errors.New("empty question")
+	}
+
+	chunkType := request.Params.Arguments["type"]
+	switch chunkType {
+	case "", domain.ChunkTypePeople, domain.ChunkTypeVehicles, domain.ChunkTypeCurated:
+	default:
+		return nil, errors.New("unknown type " + chunkType)
+	}
+
+	// authorize already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	chunks, err := prompts.knowledgeBaseService.Search(ctx, userID, question, domain.SearchOptions{Type: chunkType})
+	if err != nil {
+		return nil, clientError(prompts.logger, "Error in searching knowledge base", "error in searching knowledge base", err)
+	}
+
+	return mcp.NewGetPromptResult(
+		"Answer to the question from the knowledge base",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(services.AnswerSystemPrompt)),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(services.AnswerPrompt(services.ContextTexts(chunks), question))),
+		},
+	), nil
+}
+
+func (prompts *Prompts) ChatTitle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	message := request.Params.Arguments["message"]
+	if strings.TrimSpace(message) == "" {
+		return nil, errors.New("empty message")
+	}
+
+	return mcp.NewGetPromptResult(
+		"Title of the chat session",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(services.TitlePrompt(message))),
+		},
+	), nil
+}
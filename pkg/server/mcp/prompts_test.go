@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// getPrompt gets the prompt as the user of the claims and returns the text
+// contents of its messages
+func getPrompt(t *testing.T, mcpClient *client.Client, claims jwt.MapClaims, name string, arguments map[string]string) ([]string, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	if claims != nil {
+		ctx = auth.ContextWithClaims(ctx, claims)
+	}
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+
+	result, err := mcpClient.GetPrompt(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, len(result.Messages))
+	for i, message := range result.Messages {
+		assert.Equal(t, mcp.RoleUser, message.Role)
+
+		text, ok := mcp.AsTextContent(message.Content)
+		require.True(t, ok)
+
+		texts[i] = text.Text
+	}
+
+	return texts, nil
+}
+
+func TestServer_RegisterRegistersPrompts(t *testing.T) {
+	mcpClient := newTestClient(t, Services{})
+
+	result, err := mcpClient.ListPrompts(context.Background(), mcp.ListPromptsRequest{})
+	require.NoError(t, err)
+
+	arguments := make(map[string][]string, len(result.Prompts))
+	for _, prompt := range result.Prompts {
+		for _, argument := range prompt.Arguments {
+			arguments[prompt.Name] = append(arguments[prompt.Name], argument.Name)
+		}
+	}
+
+	assert.Equal(t, map[string][]string{
+		"rag_answer": {"question", "type"},
+		"chat_title": {"message"},
+	}, arguments)
+}
+
+func TestPrompts_RAGAnswer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return(nil).AnyTimes()
+	mcpClient := newTestClient(t, Services{KnowledgeBaseService: mockKnowledgeBaseService})
+
+	userID := "42345678-0000-0000-0000-000000000000"
+
+	chunks := []*domain.RetrievedChunk{
+		{
+			ID:   "1",
+			Text: "The speeder bike reaches 500 km/h.",
+		},
+		{
+			ID:       "curated-1",
+			Text:     "Question: How fast is a speeder?\nAnswer: 500 km/h",
+			Metadata: map[string]interface{}{domain.ChunkMetadataCurated: true},
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		claims                   jwt.MapClaims
+		arguments                map[string]string
+		expectedSearchOptions    *domain.SearchOptions
+		mockServiceResponseData  []*domain.RetrievedChunk
+		mockServiceResponseError error
+		expected                 []string
+		expectedError            string
+	}{
+		{
+			name:                    "valid",
+			claims:                  jwt.MapClaims{"sub": userID},
+			arguments:               map[string]string{"question": "How fast is a speeder?"},
+			expectedSearchOptions:   &domain.SearchOptions{},
+			mockServiceResponseData: chunks,
+			expected: []string{
+				services.AnswerSystemPrompt,
+				services.AnswerPrompt([]string{
+					"The speeder bike reaches 500 km/h.",
+					"[Curated answer] Question: How fast is a speeder?\nAnswer: 500 km/h",
+				}, "How fast is a speeder?"),
+			},
+		},
+		{
+			name:                    "valid with type",
+			claims:                  jwt.MapClaims{"sub": userID},
+			arguments:               map[string]string{"question": "How fast is a speeder?", "type": domain.ChunkTypeVehicles},
+			expectedSearchOptions:   &domain.SearchOptions{Type: domain.ChunkTypeVehicles},
+			mockServiceResponseData: chunks[:1],
+			expected: []string{
+				services.AnswerSystemPrompt,
+				services.AnswerPrompt([]string{"The speeder bike reaches 500 km/h."}, "How fast is a speeder?"),
+			},
+		},
+		{
+			name:          "anonymous",
+			arguments:     map[string]string{"question": "How fast is a speeder?"},
+			expectedError: "Not Authorized",
+		},
+		{
+			name: "api key without the scope to send messages",
+			claims: jwt.MapClaims{
+				"sub":                  userID,
+				auth.APIKeyIDClaim:     "87654321-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeReadSessions},
+			},
+			arguments:     map[string]string{"question": "How fast is a speeder?"},
+			expectedError: "api key is missing the messages:send scope",
+		},
+		{
+			name:          "empty question",
+			claims:        jwt.MapClaims{"sub": userID},
+			arguments:     map[string]string{"question": " "},
+			expectedError: "empty question",
+		},
+		{
+			name:          "unknown type",
+			claims:        jwt.MapClaims{"sub": userID},
+			arguments:     map[string]string{"question": "How fast is a speeder?", "type": "planets"},
+			expectedError: "unknown type planets",
+		},
+		{
+			name:                     "random error",
+			claims:                   jwt.MapClaims{"sub": userID},
+			arguments:                map[string]string{"question": "How fast is a speeder?"},
+			expectedSearchOptions:    &domain.SearchOptions{},
+			mockServiceResponseError: errors.New("random error"),
+			expectedError:            "error in searching knowledge base",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedSearchOptions != nil {
+				mockKnowledgeBaseService.EXPECT().
+					Search(gomock.Any(), uuid.MustParse(userID), tt.arguments["question"], *tt.expectedSearchOptions).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, err := getPrompt(t, mcpClient, tt.claims, "rag_answer", tt.arguments)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestPrompts_RAGAnswerIsRateLimited(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return(nil).AnyTimes()
+	mcpClient := newTestClient(t, Services{
+		KnowledgeBaseService: mockKnowledgeBaseService,
+		ExpensiveRateLimiter: ratelimit.NewLimiter(1, 1),
+	})
+
+	userID := "42345678-0000-0000-0000-000000000000"
+	claims := jwt.MapClaims{"sub": userID}
+	arguments := map[string]string{"question": "How fast is a speeder?"}
+
+	mockKnowledgeBaseService.EXPECT().
+		Search(gomock.Any(), uuid.MustParse(userID), "How fast is a speeder?", domain.SearchOptions{}).
+		Return([]*domain.RetrievedChunk{}, nil)
+
+	_, err := getPrompt(t, mcpClient, claims, "rag_answer", arguments)
+	require.NoError(t, err)
+
+	_, err = getPrompt(t, mcpClient, claims, "rag_answer", arguments)
+	assert.ErrorContains(t, err, "rate limit exceeded, retry in 60s")
+}
+
+func TestPrompts_ChatTitle(t *testing.T) {
+	mcpClient := newTestClient(t, Services{})
+
+	tests := []struct {
+		name          string
+		arguments     map[string]string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:      "valid",
+			arguments: map[string]string{"message": "How fast is a speeder?"},
+			expected:  []string{services.TitlePrompt("How fast is a speeder?")},
+		},
+		{
+			name:          "empty message",
+			arguments:     map[string]string{},
+			expectedError: "empty message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := getPrompt(t, mcpClient, nil, "chat_title", tt.arguments)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
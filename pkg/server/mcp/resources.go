@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"os"
+	"strings"
+)
+
+const (
+	documentURIPrefix    = "kb://documents/"
+	chatSessionURIPrefix = "kb://chat-sessions/"
+)
+
+type Resources struct {
+	knowledgeBaseService services.KnowledgeBaseServiceInterface
+	chatSessionService   services.ChatSessionServiceInterface
+	logger               logger.LoggerInterface
+}
+
+func (s *Server) registerResources(services Services) {
+	resources := &Resources{
+		knowledgeBaseService: services.KnowledgeBaseService,
+		chatSessionService:   services.ChatSessionService,
+		logger:               s.logger,
+	}
+
+	// reading a document spends nothing, but it is the knowledge base the
+	// scope to send messages searches. A document whose file is missing was
+	// never ingested, so it is not listed
+	for _, document := range services.KnowledgeBaseService.GetDocuments() {
+		if _, err := os.Stat(document.Path); err != nil {
+			s.logger.Warn("Skipping knowledge base document", map[string]interface{}{
+				"type":         document.Type,
+				"path":         document.Path,
+				"errorMessage": err.Error(),
+			})
+			continue
+		}
+
+		s.mcpServer.AddResource(documentResource(document), resources.ReadDocument)
+	}
+
+	// sessions are only known per user, so they are listed by the
+	// list_chat_sessions tool and read through the template
+	s.mcpServer.AddResourceTemplate(chatSessionResourceTemplate(), resources.ReadChatSession)
+}
+
+func documentResource(document *domain.Document) mcp.Resource {
+	return mcp.NewResource(documentURIPrefix+document.Type, document.Type+" knowledge base",
+		mcp.WithResourceDescription("Document of the Star Wars knowledge base with the "+document.Type+" passages"),
+		mcp.WithMIMEType(document.MIMEType),
+	)
+}
+
+func chatSessionResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(chatSessionURIPrefix+"{session_id}", "chat session",
+		mcp.WithTemplateDescription("Chat session of the authenticated user with its messages"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+func (resources *Resources) ReadDocument(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx, err := authorize(ctx, domain.ScopeSendMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	documentType := strings.TrimPrefix(request.Params.URI, documentURIPrefix)
+
+	document, content, err := resources.knowledgeBaseService.GetDocumentContent(ctx, documentType)
+	if err != nil {
+		return nil, clientError(resources.logger, "Error in reading knowledge base document", "error in reading knowledge base document", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: document.MIMEType,
+			Text:     content,
+		},
+	}, nil
+}
+
+func (resources *Resources) ReadChatSession(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx, err := authorize(ctx, domain.ScopeReadSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize already made sure that there is a user
+	userID, _ := auth.UserIDFromContext(ctx)
+
+	sessionID, err := uuid.Parse(strings.TrimPrefix(request.Params.URI, chatSessionURIPrefix))
+	if err != nil {
+		return nil, errors.New("malformed session_id")
+	}
+
+	chatSession, err := resources.chatSessionService.GetChatSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, clientError(resources.logger, "Error in getting chat session", "error in getting chat session", err)
+	}
+
+	chatSessionJSON, err := json.Marshal(ChatSessionResultFromModel(chatSession))
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(chatSessionJSON),
+		},
+	}, nil
+}
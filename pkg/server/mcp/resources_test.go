@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/repositories"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// readResource reads the resource as the user of the claims and returns its
+// text content and MIME type
+func readResource(t *testing.T, mcpClient *client.Client, claims jwt.MapClaims, uri string) (*mcp.TextResourceContents, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	if claims != nil {
+		ctx = auth.ContextWithClaims(ctx, claims)
+	}
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+
+	result, err := mcpClient.ReadResource(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	require.Len(t, result.Contents, 1)
+
+	contents, ok := mcp.AsTextResourceContents(result.Contents[0])
+	require.True(t, ok)
+
+	return contents, nil
+}
+
+func TestServer_RegisterRegistersResources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return([]*domain.Document{
+		// missing, it is not listed
+		{Type: domain.ChunkTypePeople, Path: "./dataPeople.md", MIMEType: "text/markdown"},
+		{Type: domain.ChunkTypeVehicles, Path: "../../../dataVehicles.md", MIMEType: "text/markdown"},
+	}).AnyTimes()
+	mcpClient := newTestClient(t, Services{KnowledgeBaseService: mockKnowledgeBaseService})
+
+	resources, err := mcpClient.ListResources(context.Background(), mcp.ListResourcesRequest{})
+	require.NoError(t, err)
+
+	uris := make(map[string]string, len(resources.Resources))
+	for _, resource := range resources.Resources {
+		uris[resource.URI] = resource.MIMEType
+	}
+
+	assert.Equal(t, map[string]string{
+		"kb://documents/vehicles": "text/markdown",
+	}, uris)
+
+	templates, err := mcpClient.ListResourceTemplates(context.Background(), mcp.ListResourceTemplatesRequest{})
+	require.NoError(t, err)
+	require.Len(t, templates.ResourceTemplates, 1)
+
+	assert.Equal(t, "kb://chat-sessions/{session_id}", templates.ResourceTemplates[0].URITemplate.Raw())
+	assert.Equal(t, "application/json", templates.ResourceTemplates[0].MIMEType)
+}
+
+func TestResources_ReadDocument(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	document := &domain.Document{Type: domain.ChunkTypeVehicles, Path: "../../../dataVehicles.md", MIMEType: "text/markdown"}
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return([]*domain.Document{document}).AnyTimes()
+	mcpClient := newTestClient(t, Services{KnowledgeBaseService: mockKnowledgeBaseService})
+
+	userID := "42345678-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name                        string
+		claims                      jwt.MapClaims
+		mockServiceCalled           bool
+		mockServiceResponseDocument *domain.Document
+		mockServiceResponseContent  string
+		mockServiceResponseError    error
+		expected                    *mcp.TextResourceContents
+		expectedError               string
+	}{
+		{
+			name:                        "valid",
+			claims:                      jwt.MapClaims{"sub": userID},
+			mockServiceCalled:           true,
+			mockServiceResponseDocument: document,
+			mockServiceResponseContent:  "# Vehicles",
+			expected: &mcp.TextResourceContents{
+				URI:      "kb://documents/vehicles",
+				MIMEType: "text/markdown",
+				Text:     "# Vehicles",
+			},
+		},
+		{
+			name:          "anonymous",
+			expectedError: "Not Authorized",
+		},
+		{
+			name: "api key without the scope to send messages",
+			claims: jwt.MapClaims{
+				"sub":                  userID,
+				auth.APIKeyIDClaim:     "87654321-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeReadSessions},
+			},
+			expectedError: "api key is missing the messages:send scope",
+		},
+		{
+			name:              "missing file",
+			claims:            jwt.MapClaims{"sub": userID},
+			mockServiceCalled: true,
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("file ../../../dataVehicles.md of document vehicles not found"),
+			},
+			expectedError: "not found",
+		},
+		{
+			name:                     "random error",
+			claims:                   jwt.MapClaims{"sub": userID},
+			mockServiceCalled:        true,
+			mockServiceResponseError: errors.New("random error"),
+			expectedError:            "error in reading knowledge base document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockServiceCalled {
+				mockKnowledgeBaseService.EXPECT().
+					GetDocumentContent(gomock.Any(), domain.ChunkTypeVehicles).
+					Return(tt.mockServiceResponseDocument, tt.mockServiceResponseContent, tt.mockServiceResponseError)
+			}
+
+			actual, err := readResource(t, mcpClient, tt.claims, "kb://documents/vehicles")
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestResources_ReadChatSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+	mcpClient := newTestClient(t, Services{ChatSessionService: mockChatSessionService})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	chatSessionID := uuid.UUID{0x32, 0x34, 0x56, 0x78}
+	messageID := uuid.UUID{0x12, 0x34, 0x56, 0x78}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name                     string
+		uri                      string
+		mockServiceCalled        bool
+		mockServiceResponseData  *domain.ChatSession
+		mockServiceResponseError error
+		expected                 *mcp.TextResourceContents
+		expectedError            string
+	}{
+		{
+			name:              "valid",
+			uri:               "kb://chat-sessions/" + chatSessionID.String(),
+			mockServiceCalled: true,
+			mockServiceResponseData: &domain.ChatSession{
+				ID:        chatSessionID,
+				UserID:    userID,
+				Title:     "Speeders",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Messages: []*domain.Message{
+					{
+						ID:        messageID,
+						Content:   "How fast is a speeder?",
+						Sender:    repositories.USER_SENDER,
+						CreatedAt: createdAt,
+					},
+				},
+			},
+			expected: &mcp.TextResourceContents{
+				URI:      "kb://chat-sessions/" + chatSessionID.String(),
+				MIMEType: "application/json",
+				Text: `{"id":"` + chatSessionID.String() + `","title":"Speeders","createdAt":"2025-01-02T03:04:05Z","updatedAt":"2025-01-02T03:04:05Z",` +
+					`"messages":[{"id":"` + messageID.String() + `","sender":"USER","content":"How fast is a speeder?","createdAt":"2025-01-02T03:04:05Z"}]}`,
+			},
+		},
+		{
+			name:          "malformed session id",
+			uri:           "kb://chat-sessions/malformed",
+			expectedError: "malformed session_id",
+		},
+		{
+			name:                     "session of another user",
+			uri:                      "kb://chat-sessions/" + chatSessionID.String(),
+			mockServiceCalled:        true,
+			mockServiceResponseError: customerrors.NewUserMismatchError(chatSessionID.String(), userID.String()),
+			expectedError:            customerrors.NewUserMismatchError(chatSessionID.String(), userID.String()).Error(),
+		},
+		{
+			name:              "session not found",
+			uri:               "kb://chat-sessions/" + chatSessionID.String(),
+			mockServiceCalled: true,
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("chat session " + chatSessionID.String() + " not found"),
+			},
+			expectedError: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockServiceCalled {
+				mockChatSessionService.EXPECT().
+					GetChatSession(gomock.Any(), chatSessionID, userID).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, err := readResource(t, mcpClient, jwt.MapClaims{"sub": userID.String()}, tt.uri)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
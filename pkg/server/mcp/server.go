@@ -1,17 +1,21 @@
 package mcp
 
 import (
+	"github.com/loukaspe/rag-golang/pkg/logger"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 type Server struct {
 	mcpServer *server.MCPServer
+	logger    logger.LoggerInterface
 }
 
 func NewServer(
 	mcpServer *server.MCPServer,
+	logger logger.LoggerInterface,
 ) *Server {
 	return &Server{
 		mcpServer: mcpServer,
+		logger:    logger,
 	}
 }
@@ -2,181 +2,193 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"math"
+	"strconv"
 )
 
-func (s *Server) InitialiseSSEServer() *server.SSEServer {
-	add := mcp.NewTool("add",
-		mcp.WithDescription("Add two numbers"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("First number"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Second number"),
-		),
-	)
-
-	// Add subtraction tool
-	subtract := mcp.NewTool("subtract",
-		mcp.WithDescription("Subtract second number from first number"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("First number"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Second number"),
-		),
-	)
-
-	// Add multiplication tool
-	multiply := mcp.NewTool("multiply",
-		mcp.WithDescription("Multiply two numbers"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("First number"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Second number"),
-		),
-	)
-
-	// Add division tool
-	divide := mcp.NewTool("divide",
-		mcp.WithDescription("Divide first number by second number"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("Numerator"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Denominator"),
-		),
-	)
-
-	// Add percentage tool
-	percentage := mcp.NewTool("percentage",
-		mcp.WithDescription("Calculate what percentage the first number is of the second number"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("Part value"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Total value"),
-		),
-	)
-
-	// Add tool handlers
-	s.mcpServer.AddTool(add, addHandler)
-	s.mcpServer.AddTool(subtract, subtractHandler)
-	s.mcpServer.AddTool(multiply, multiplyHandler)
-	s.mcpServer.AddTool(divide, divideHandler)
-	s.mcpServer.AddTool(percentage, percentageHandler)
-
-	return server.NewSSEServer(
-		s.mcpServer,
-		server.WithStaticBasePath("/"),
-		server.WithSSEEndpoint("/mcp/sse"),
-		server.WithMessageEndpoint("/mcp/message"),
-	)
+// Services are the services behind the tools, resources and prompts, the same
+// ones the HTTP handlers use
+type Services struct {
+	KnowledgeBaseService     services.KnowledgeBaseServiceInterface
+	MessageService           services.MessageServiceInterface
+	ChatSessionService       services.ChatSessionServiceInterface
+	VehicleComparisonService services.VehicleComparisonServiceInterface
+	UsageService             services.UsageServiceInterface
+	// ExpensiveRateLimiter limits the tools and prompts that call OpenAI, like
+	// the expensive limit of the REST API does, nil for no limit
+	ExpensiveRateLimiter *ratelimit.Limiter
 }
 
-// addHandler processes addition requests, adding two numbers and returning the formatted result
-// Returns an error if inputs are not valid numbers
-func addHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	a, err := request.RequireFloat("a")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
+// Register adds the tools, resources and prompts of the product to the MCP
+// server, once for every transport that serves it
+func (s *Server) Register(services Services) {
+	s.registerTools(services)
+	s.registerResources(services)
+	s.registerPrompts(services)
+}
 
-	b, err := request.RequireFloat("b")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+func (s *Server) registerTools(services Services) {
+	knowledgeBaseTools := &KnowledgeBaseTools{
+		knowledgeBaseService: services.KnowledgeBaseService,
+		messageService:       services.MessageService,
+		chatSessionService:   services.ChatSessionService,
+		usageService:         services.UsageService,
+		logger:               s.logger,
+	}
+	chatSessionTools := &ChatSessionTools{
+		chatSessionService: services.ChatSessionService,
+		logger:             s.logger,
+	}
+	vehicleTools := &VehicleTools{
+		vehicleComparisonService: services.VehicleComparisonService,
+		logger:                   s.logger,
 	}
 
-	result := a + b
-	return mcp.NewToolResultText(fmt.Sprintf("%.2f", result)), nil
+	// searching spends embedding tokens like sending a message does
+	s.mcpServer.AddTool(searchKnowledgeBaseTool(), authenticated(domain.ScopeSendMessages, rateLimited(services.ExpensiveRateLimiter, knowledgeBaseTools.SearchKnowledgeBase)))
+	s.mcpServer.AddTool(askQuestionTool(), authenticated(domain.ScopeSendMessages, rateLimited(services.ExpensiveRateLimiter, knowledgeBaseTools.AskQuestion)))
+	s.mcpServer.AddTool(listChatSessionsTool(), authenticated(domain.ScopeReadSessions, chatSessionTools.ListChatSessions))
+	s.mcpServer.AddTool(getChatSessionTool(), authenticated(domain.ScopeReadSessions, chatSessionTools.GetChatSession))
+	s.mcpServer.AddTool(compareVehiclesTool(), authenticated(domain.ScopeSendMessages, vehicleTools.CompareVehicles))
 }
 
-// subtractHandler processes subtraction requests, subtracting the second number from the first
-// Returns an error if inputs are not valid numbers
-func subtractHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	a, err := request.RequireFloat("a")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+// authenticated rejects the calls without an authenticated user, and the ones
+// authenticated with an API key that was not granted the scope, like ScopeMW
+// does for the HTTP routes. The user comes from the context of the request
+// that carried the call, which AuthenticationMW fills in, or from the stdio
+// session.
+func authenticated(scope string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, err := authorize(ctx, scope)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return handler(ctx, request)
 	}
+}
 
-	b, err := request.RequireFloat("b")
+// authorize returns the context of the authenticated user, or why the request
+// of the context may not use what needs the scope
+func authorize(ctx context.Context, scope string) (context.Context, error) {
+	ctx, err := reauthenticate(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return ctx, err
 	}
 
-	result := a - b
-	return mcp.NewToolResultText(fmt.Sprintf("%.2f", result)), nil
+	if _, err := auth.UserIDFromContext(ctx); err != nil {
+		return ctx, errors.New("Not Authorized")
+	}
+
+	if !auth.HasScope(ctx, scope) {
+		return ctx, errors.New("api key is missing the " + scope + " scope")
+	}
+
+	return ctx, nil
 }
 
-// multiplyHandler processes multiplication requests, multiplying two numbers together
-// Returns an error if inputs are not valid numbers
-func multiplyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	a, err := request.RequireFloat("a")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+// rateLimited rejects the calls over the limit of their API key or user, like
+// RateLimitMW does for the HTTP routes. A nil limiter disables the limit. It
+// must run after authenticated.
+func rateLimited(limiter *ratelimit.Limiter, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := allow(ctx, limiter); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return handler(ctx, request)
 	}
+}
 
-	b, err := request.RequireFloat("b")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+// allow spends a request of the limit of the context's API key or user, and
+// returns when the next one is allowed if there is none left
+func allow(ctx context.Context, limiter *ratelimit.Limiter) error {
+	if limiter == nil {
+		return nil
 	}
 
-	result := a * b
-	return mcp.NewToolResultText(fmt.Sprintf("%.2f", result)), nil
+	key, ok := auth.RateLimitKeyFromContext(ctx)
+	if !ok {
+		return errors.New("Not Authorized")
+	}
+
+	allowed, retryAfter := limiter.Allow(key)
+	if !allowed {
+		return errors.New("rate limit exceeded, retry in " + strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))) + "s")
+	}
+
+	return nil
 }
 
-// divideHandler processes division requests, dividing the first number by the second
-// Returns an error if inputs are not valid numbers or if dividing by zero
-func divideHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	a, err := request.RequireFloat("a")
+// jsonResult returns the result as the JSON text content of the tool
+func jsonResult(result interface{}) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.Marshal(result)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return nil, err
 	}
 
-	b, err := request.RequireFloat("b")
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// requireUUID reads a required uuid argument
+func requireUUID(request mcp.CallToolRequest, name string) (uuid.UUID, *mcp.CallToolResult) {
+	value, err := request.RequireString(name)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return uuid.Nil, mcp.NewToolResultError(err.Error())
 	}
 
-	if b == 0 {
-		return nil, errors.New("cannot divide by zero")
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, mcp.NewToolResultError("malformed " + name)
 	}
 
-	result := a / b
-	return mcp.NewToolResultText(fmt.Sprintf("%.2f", result)), nil
+	return id, nil
 }
 
-// percentageHandler calculates what percentage the first number is of the second number
-// Returns an error if inputs are not valid numbers or if the total value is zero
-func percentageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	a, err := request.RequireFloat("a")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
+// errorResult logs the error and returns its message to the client when the
+// arguments of the call caused it, the generic message otherwise
+func errorResult(logger logger.LoggerInterface, logMessage string, message string, err error) *mcp.CallToolResult {
+	return mcp.NewToolResultError(clientErrorMessage(logger, logMessage, message, err))
+}
 
-	b, err := request.RequireFloat("b")
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+// clientError is errorResult for the resources and prompts, whose errors are
+// returned as protocol errors
+func clientError(logger logger.LoggerInterface, logMessage string, message string, err error) error {
+	return errors.New(clientErrorMessage(logger, logMessage, message, err))
+}
+
+func clientErrorMessage(logger logger.LoggerInterface, logMessage string, message string, err error) string {
+	// like for the HTTP handlers, what was not found is only logged
+	if resourceNotFound, ok := err.(customerrors.ResourceNotFoundErrorWrapper); ok {
+		logger.Error(logMessage,
+			map[string]interface{}{
+				"errorMessage": resourceNotFound.Unwrap(),
+			})
+
+		return "not found"
 	}
 
-	if b == 0 {
-		return nil, errors.New("total value cannot be zero")
+	logger.Error(logMessage,
+		map[string]interface{}{
+			"errorMessage": err.Error(),
+		})
+
+	var userMismatchError *customerrors.UserMismatchError
+	var quotaExceededError *customerrors.QuotaExceededError
+	if errors.As(err, &userMismatchError) || errors.As(err, &quotaExceededError) {
+		return err.Error()
 	}
 
-	result := (a / b) * 100
-	return mcp.NewToolResultText(fmt.Sprintf("%.2f%%", result)), nil
+	return message
 }
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/loukaspe/rag-golang/pkg/ratelimit"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// newTestServer creates a server with the tools, resources and prompts of the
+// services, a knowledge base without documents when there is none
+func newTestServer(t *testing.T, services Services) *Server {
+	t.Helper()
+
+	if services.KnowledgeBaseService == nil {
+		mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(gomock.NewController(t))
+		mockKnowledgeBaseService.EXPECT().GetDocuments().Return(nil).AnyTimes()
+		services.KnowledgeBaseService = mockKnowledgeBaseService
+	}
+
+	mcpServer := NewServer(server.NewMCPServer("rag-golang", "test"), logger.NewLogger(context.Background()))
+	mcpServer.Register(services)
+
+	return mcpServer
+}
+
+// newTestClient connects an in-process client to a server with the tools,
+// resources and prompts of the services
+func newTestClient(t *testing.T, services Services) *client.Client {
+	t.Helper()
+
+	mcpClient, err := client.NewInProcessClient(newTestServer(t, services).mcpServer)
+	require.NoError(t, err)
+
+	startTestClient(t, mcpClient)
+
+	return mcpClient
+}
+
+// startTestClient starts and initializes the client, closing it at the end of
+// the test
+func startTestClient(t *testing.T, mcpClient *client.Client) {
+	t.Helper()
+
+	t.Cleanup(func() { mcpClient.Close() })
+
+	require.NoError(t, mcpClient.Start(context.Background()))
+
+	initializeRequest := mcp.InitializeRequest{}
+	initializeRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initializeRequest.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "test"}
+
+	_, err := mcpClient.Initialize(context.Background(), initializeRequest)
+	require.NoError(t, err)
+}
+
+// callTool calls the tool as the user and returns the text content of the
+// result and whether it is an error
+func callTool(t *testing.T, mcpClient *client.Client, userID uuid.UUID, name string, arguments map[string]interface{}) (string, bool) {
+	t.Helper()
+
+	return callToolWithClaims(t, mcpClient, jwt.MapClaims{"sub": userID.String()}, name, arguments)
+}
+
+// callToolWithClaims calls the tool in the context of a request that
+// AuthenticationMW authenticated with the claims, or of an anonymous one for
+// nil claims
+func callToolWithClaims(t *testing.T, mcpClient *client.Client, claims jwt.MapClaims, name string, arguments map[string]interface{}) (string, bool) {
+	t.Helper()
+
+	ctx := context.Background()
+	if claims != nil {
+		ctx = auth.ContextWithClaims(ctx, claims)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+
+	result, err := mcpClient.CallTool(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	return text.Text, result.IsError
+}
+
+func TestServer_RegisterRegistersTools(t *testing.T) {
+	mcpClient := newTestClient(t, Services{})
+
+	result, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+
+	names := make([]string, len(result.Tools))
+	for i, tool := range result.Tools {
+		names[i] = tool.Name
+	}
+
+	assert.ElementsMatch(t, []string{"search_knowledge_base", "ask_question", "list_chat_sessions", "get_chat_session", "compare_vehicles"}, names)
+
+	// the tools act as the authenticated user, never as one of the arguments
+	for _, tool := range result.Tools {
+		assert.NotContains(t, tool.InputSchema.Properties, "user_id", tool.Name)
+	}
+}
+
+func TestServer_ToolsRequireAuthentication(t *testing.T) {
+	mcpClient := newTestClient(t, Services{})
+
+	userID := "42345678-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		tool     string
+		expected string
+	}{
+		{
+			name:     "anonymous",
+			tool:     "list_chat_sessions",
+			expected: "Not Authorized",
+		},
+		{
+			name: "api key without the scope to read",
+			claims: jwt.MapClaims{
+				"sub":                  userID,
+				auth.APIKeyIDClaim:     "87654321-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeSendMessages},
+			},
+			tool:     "get_chat_session",
+			expected: "api key is missing the sessions:read scope",
+		},
+		{
+			name: "api key without the scope to send",
+			claims: jwt.MapClaims{
+				"sub":                  userID,
+				auth.APIKeyIDClaim:     "87654321-0000-0000-0000-000000000000",
+				auth.APIKeyScopesClaim: []string{domain.ScopeReadSessions},
+			},
+			tool:     "ask_question",
+			expected: "api key is missing the messages:send scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, isError := callToolWithClaims(t, mcpClient, tt.claims, tt.tool, map[string]interface{}{})
+
+			assert.Equal(t, tt.expected, actual)
+			assert.True(t, isError)
+		})
+	}
+}
+
+func TestServer_ExpensiveToolsAreRateLimited(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKnowledgeBaseService := mock_services.NewMockKnowledgeBaseServiceInterface(mockCtrl)
+	mockKnowledgeBaseService.EXPECT().GetDocuments().Return(nil).AnyTimes()
+	mcpClient := newTestClient(t, Services{
+		KnowledgeBaseService: mockKnowledgeBaseService,
+		ExpensiveRateLimiter: ratelimit.NewLimiter(1, 1),
+	})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	apiKeyClaims := jwt.MapClaims{
+		"sub":                  userID.String(),
+		auth.APIKeyIDClaim:     "87654321-0000-0000-0000-000000000000",
+		auth.APIKeyScopesClaim: []string{domain.ScopeSendMessages},
+	}
+
+	mockKnowledgeBaseService.EXPECT().
+		Search(gomock.Any(), userID, "X-wing", domain.SearchOptions{TopK: 5}).
+		Return([]*domain.RetrievedChunk{}, nil).
+		Times(2)
+
+	actual, isError := callTool(t, mcpClient, userID, "search_knowledge_base", map[string]interface{}{"query": "X-wing"})
+	assert.Equal(t, `{"hits":[]}`, actual)
+	assert.False(t, isError)
+
+	// the limit is shared by the tools calling OpenAI
+	actual, isError = callTool(t, mcpClient, userID, "ask_question", map[string]interface{}{"question": "Who is Luke?"})
+	assert.Equal(t, "rate limit exceeded, retry in 60s", actual)
+	assert.True(t, isError)
+
+	// an API key has its own limit
+	actual, isError = callToolWithClaims(t, mcpClient, apiKeyClaims, "search_knowledge_base", map[string]interface{}{"query": "X-wing"})
+	assert.Equal(t, `{"hits":[]}`, actual)
+	assert.False(t, isError)
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/mark3labs/mcp-go/server"
+	"io"
+	"strings"
+)
+
+// The HTTP transports the MCP server can be served over, stdio is served by
+// cmd/mcp
+const (
+	TransportSSE            = "sse"
+	TransportStreamableHTTP = "streamable-http"
+)
+
+// ParseHTTPTransports parses a comma separated list of HTTP transports, both
+// of them when the list is empty
+func ParseHTTPTransports(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return []string{TransportSSE, TransportStreamableHTTP}, nil
+	}
+
+	var transports []string
+	for _, transport := range strings.Split(value, ",") {
+		transport = strings.TrimSpace(transport)
+
+		switch transport {
+		case TransportSSE, TransportStreamableHTTP:
+			transports = append(transports, transport)
+		default:
+			return nil, fmt.Errorf("unknown MCP HTTP transport %q", transport)
+		}
+	}
+
+	return transports, nil
+}
+
+// InitialiseSSEServer serves the legacy transport, an SSE stream on
+// /mcp/sse and the messages posted to /mcp/message
+func (s *Server) InitialiseSSEServer() *server.SSEServer {
+	return server.NewSSEServer(
+		s.mcpServer,
+		server.WithStaticBasePath("/"),
+		server.WithSSEEndpoint("/mcp/sse"),
+		server.WithMessageEndpoint("/mcp/message"),
+	)
+}
+
+// InitialiseStreamableHTTPServer serves the streamable HTTP transport on
+// /mcp. Like for SSE, the tools run in the context of the HTTP request.
+func (s *Server) InitialiseStreamableHTTPServer() *server.StreamableHTTPServer {
+	return server.NewStreamableHTTPServer(
+		s.mcpServer,
+		server.WithEndpointPath("/mcp"),
+	)
+}
+
+// ClaimsFunc authenticates the user a stdio session acts as, and returns its
+// claims
+type ClaimsFunc func(ctx context.Context) (jwt.MapClaims, error)
+
+type stdioSessionKey struct{}
+
+type stdioSession struct {
+	authenticate ClaimsFunc
+	end          context.CancelCauseFunc
+}
+
+// ServeStdio serves the MCP server over stdin and stdout until the context is
+// done or the input ends. There is no request to authenticate, so every call
+// authenticates the user that started the process again, and the session ends
+// with the error of authenticate once its credentials are no longer valid.
+func (s *Server) ServeStdio(ctx context.Context, authenticate ClaimsFunc, stdin io.Reader, stdout io.Writer) error {
+	ctx, end := context.WithCancelCause(ctx)
+	defer end(nil)
+
+	session := &stdioSession{authenticate: authenticate, end: end}
+
+	stdioServer := server.NewStdioServer(s.mcpServer)
+	stdioServer.SetContextFunc(func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, stdioSessionKey{}, session)
+	})
+
+	err := stdioServer.Listen(ctx, stdin, stdout)
+
+	var invalidAPIKeyError *customerrors.InvalidAPIKeyError
+	if cause := context.Cause(ctx); errors.As(cause, &invalidAPIKeyError) {
+		return cause
+	}
+
+	return err
+}
+
+// reauthenticate returns the context with the current claims of the user of a
+// stdio session, the context as it is for the other transports, whose requests
+// AuthenticationMW already authenticated
+func reauthenticate(ctx context.Context) (context.Context, error) {
+	session, ok := ctx.Value(stdioSessionKey{}).(*stdioSession)
+	if !ok {
+		return ctx, nil
+	}
+
+	claims, err := session.authenticate(ctx)
+
+	// a revoked key ends the session, after the response to this call
+	var invalidAPIKeyError *customerrors.InvalidAPIKeyError
+	if errors.As(err, &invalidAPIKeyError) {
+		session.end(err)
+		return ctx, errors.New("Not Authorized")
+	}
+
+	if err != nil {
+		return ctx, errors.New("error in authenticating")
+	}
+
+	return auth.ContextWithClaims(ctx, claims), nil
+}
@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/auth"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHTTPTransports(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name:     "both by default",
+			value:    "",
+			expected: []string{TransportSSE, TransportStreamableHTTP},
+		},
+		{
+			name:     "one",
+			value:    " streamable-http ",
+			expected: []string{TransportStreamableHTTP},
+		},
+		{
+			name:     "both",
+			value:    "sse,streamable-http",
+			expected: []string{TransportSSE, TransportStreamableHTTP},
+		},
+		{
+			name:          "stdio is not served over HTTP",
+			value:         "sse,stdio",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseHTTPTransports(tt.value)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+// The tools are the same whatever the transport, and they act as the user
+// that the transport authenticated
+func TestServer_Transports(t *testing.T) {
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+	claims := jwt.MapClaims{"sub": userID.String()}
+
+	tests := []struct {
+		name      string
+		newClient func(t *testing.T, mcpServer *Server) *client.Client
+	}{
+		{
+			name: "streamable http",
+			newClient: func(t *testing.T, mcpServer *Server) *client.Client {
+				// stands in for AuthenticationMW
+				streamableHTTPServer := mcpServer.InitialiseStreamableHTTPServer()
+				httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					streamableHTTPServer.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
+				}))
+				t.Cleanup(httpServer.Close)
+
+				mcpClient, err := client.NewStreamableHttpClient(httpServer.URL + "/mcp")
+				require.NoError(t, err)
+
+				return mcpClient
+			},
+		},
+		{
+			name: "stdio",
+			newClient: func(t *testing.T, mcpServer *Server) *client.Client {
+				clientToServerReader, clientToServerWriter := io.Pipe()
+				serverToClientReader, serverToClientWriter := io.Pipe()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+
+				authenticate := func(ctx context.Context) (jwt.MapClaims, error) {
+					return claims, nil
+				}
+
+				go mcpServer.ServeStdio(ctx, authenticate, clientToServerReader, serverToClientWriter)
+
+				return client.NewClient(transport.NewIO(serverToClientReader, clientToServerWriter, io.NopCloser(strings.NewReader(""))))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+			mockChatSessionService.EXPECT().
+				GetUserChatSessions(gomock.Any(), userID, gomock.Any()).
+				Return(&domain.ChatSessionPage{}, nil)
+
+			mcpServer := newTestServer(t, Services{ChatSessionService: mockChatSessionService})
+
+			mcpClient := tt.newClient(t, mcpServer)
+			startTestClient(t, mcpClient)
+
+			actual, isError := callToolWithClaims(t, mcpClient, nil, "list_chat_sessions", map[string]interface{}{})
+
+			assert.Equal(t, `{"sessions":[]}`, actual)
+			assert.False(t, isError)
+		})
+	}
+}
+
+// Every call of a stdio session authenticates again, and the session ends once
+// the credentials are no longer valid
+func TestServer_ServeStdioReauthenticates(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	mockChatSessionService := mock_services.NewMockChatSessionServiceInterface(mockCtrl)
+	mockChatSessionService.EXPECT().
+		GetUserChatSessions(gomock.Any(), userID, gomock.Any()).
+		Return(&domain.ChatSessionPage{}, nil)
+
+	mcpServer := newTestServer(t, Services{ChatSessionService: mockChatSessionService})
+
+	// the key is valid for the first call and revoked before the second
+	authenticateResponses := []error{nil, customerrors.NewInvalidAPIKeyError()}
+	authenticate := func(ctx context.Context) (jwt.MapClaims, error) {
+		err := authenticateResponses[0]
+		authenticateResponses = authenticateResponses[1:]
+		if err != nil {
+			return nil, err
+		}
+
+		return jwt.MapClaims{"sub": userID.String()}, nil
+	}
+
+	clientToServerReader, clientToServerWriter := io.Pipe()
+	serverToClientReader, serverToClientWriter := io.Pipe()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- mcpServer.ServeStdio(context.Background(), authenticate, clientToServerReader, serverToClientWriter)
+	}()
+
+	mcpClient := client.NewClient(transport.NewIO(serverToClientReader, clientToServerWriter, io.NopCloser(strings.NewReader(""))))
+	startTestClient(t, mcpClient)
+
+	actual, isError := callToolWithClaims(t, mcpClient, nil, "list_chat_sessions", map[string]interface{}{})
+	assert.Equal(t, `{"sessions":[]}`, actual)
+	assert.False(t, isError)
+
+	actual, isError = callToolWithClaims(t, mcpClient, nil, "list_chat_sessions", map[string]interface{}{})
+	assert.Equal(t, "Not Authorized", actual)
+	assert.True(t, isError)
+
+	select {
+	case err := <-served:
+		assert.Equal(t, customerrors.NewInvalidAPIKeyError(), err)
+	case <-time.After(time.Second):
+		t.Fatal("the session did not end")
+	}
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/internal/core/services"
+	"github.com/loukaspe/rag-golang/pkg/logger"
+	"github.com/mark3labs/mcp-go/mcp"
+	"strings"
+)
+
+type VehicleTools struct {
+	vehicleComparisonService services.VehicleComparisonServiceInterface
+	logger                   logger.LoggerInterface
+}
+
+func compareVehiclesTool() mcp.Tool {
+	return mcp.NewTool("compare_vehicles",
+		mcp.WithDescription("Compare vehicles exactly from the statements of the vehicles document, like \"Sailboats are 88% slow than Electric cars.\". "+
+			"Every matching statement is returned, restated from the side of the subject: ratio is the attribute of the subject over the one of the object, "+
+			"percent how much more, or less when negative, it is"),
+		mcp.WithString("subject",
+			mcp.Required(),
+			mcp.Description("Vehicle to compare, in the plural like the document names it, like Sailboats"),
+		),
+		mcp.WithString("attribute",
+			mcp.Description("Attribute to compare by, like slow or quick to accelerate"),
+		),
+		mcp.WithString("object",
+			mcp.Description("Vehicle to compare with, like Electric cars"),
+		),
+	)
+}
+
+func (tools *VehicleTools) CompareVehicles(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subject, err := request.RequireString("subject")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	filter := &domain.VehicleComparisonFilter{
+		Subject:   strings.TrimSpace(subject),
+		Attribute: strings.TrimSpace(request.GetString("attribute", "")),
+		Object:    strings.TrimSpace(request.GetString("object", "")),
+	}
+	if filter.Subject == "" {
+		return mcp.NewToolResultError("empty subject"), nil
+	}
+
+	facts, err := tools.vehicleComparisonService.CompareVehicles(ctx, filter)
+	if err != nil {
+		return errorResult(tools.logger, "Error in comparing vehicles", "error in comparing vehicles", err), nil
+	}
+
+	return jsonResult(&CompareVehiclesResult{
+		Comparisons: VehicleComparisonResultsFromModel(facts),
+	})
+}
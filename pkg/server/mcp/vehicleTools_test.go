@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	mock_services "github.com/loukaspe/rag-golang/mocks/mock_internal/core/services"
+	customerrors "github.com/loukaspe/rag-golang/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestVehicleTools_CompareVehicles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockVehicleComparisonService := mock_services.NewMockVehicleComparisonServiceInterface(mockCtrl)
+	mcpClient := newTestClient(t, Services{VehicleComparisonService: mockVehicleComparisonService})
+
+	userID := uuid.UUID{0x42, 0x34, 0x56, 0x78}
+
+	tests := []struct {
+		name                     string
+		arguments                map[string]interface{}
+		expectedFilter           *domain.VehicleComparisonFilter
+		mockServiceResponseData  []*domain.VehicleComparisonFact
+		mockServiceResponseError error
+		expected                 string
+		expectedIsError          bool
+	}{
+		{
+			name:           "valid",
+			arguments:      map[string]interface{}{"subject": "Sailboats", "attribute": "slow", "object": " Electric cars "},
+			expectedFilter: &domain.VehicleComparisonFilter{Subject: "Sailboats", Attribute: "slow", Object: "Electric cars"},
+			mockServiceResponseData: []*domain.VehicleComparisonFact{
+				{
+					Subject:   "Sailboats",
+					Attribute: "slow",
+					Object:    "Electric cars",
+					Ratio:     1.88,
+					Percent:   88,
+					Statement: "Sailboats are 88% slow than Electric cars.",
+					Line:      3,
+				},
+			},
+			expected: `{"comparisons":[{"subject":"Sailboats","attribute":"slow","object":"Electric cars","ratio":1.88,"percent":88,"inverse":false,"statement":"Sailboats are 88% slow than Electric cars.","line":3}]}`,
+		},
+		{
+			name:            "missing subject",
+			arguments:       map[string]interface{}{"attribute": "slow"},
+			expected:        `required argument "subject" not found`,
+			expectedIsError: true,
+		},
+		{
+			name:            "empty subject",
+			arguments:       map[string]interface{}{"subject": " "},
+			expected:        "empty subject",
+			expectedIsError: true,
+		},
+		{
+			name:           "not found",
+			arguments:      map[string]interface{}{"subject": "Bicycles"},
+			expectedFilter: &domain.VehicleComparisonFilter{Subject: "Bicycles"},
+			mockServiceResponseError: customerrors.ResourceNotFoundErrorWrapper{
+				OriginalError: errors.New("no comparisons of Bicycles found"),
+			},
+			expected:        "not found",
+			expectedIsError: true,
+		},
+		{
+			name:                     "service error",
+			arguments:                map[string]interface{}{"subject": "Sailboats"},
+			expectedFilter:           &domain.VehicleComparisonFilter{Subject: "Sailboats"},
+			mockServiceResponseError: errors.New("database is down"),
+			expected:                 "error in comparing vehicles",
+			expectedIsError:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedFilter != nil {
+				mockVehicleComparisonService.EXPECT().
+					CompareVehicles(gomock.Any(), tt.expectedFilter).
+					Return(tt.mockServiceResponseData, tt.mockServiceResponseError)
+			}
+
+			actual, isError := callTool(t, mcpClient, userID, "compare_vehicles", tt.arguments)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.expectedIsError, isError)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/loukaspe/rag-golang/pkg/helpers"
+	"math"
+	"sort"
+	"sync"
+)
+
+type memoryVector struct {
+	id       string
+	values   []float32
+	metadata map[string]interface{}
+}
+
+// MemoryVectorDB is an in-process vector store with cosine similarity search.
+// It mirrors PineconeVectorDB so that it can be used offline, e.g. by the
+// evaluation harness.
+type MemoryVectorDB struct {
+	mu                sync.RWMutex
+	vectors           []*memoryVector
+	documents         int
+	topKResultsNumber int
+	threshold         float32
+}
+
+func NewMemoryVectorDB(threshold float32, topKResultsNumber int) *MemoryVectorDB {
+	return &MemoryVectorDB{
+		topKResultsNumber: topKResultsNumber,
+		threshold:         threshold,
+	}
+}
+
+// StoreEmbeddings stores the embeddings as one document. Vector IDs follow the
+// "doc{n}-chunk-{i}" format, where n is the 1-based order of the StoreEmbeddings
+// call and i the index of the chunk inside it.
+func (db *MemoryVectorDB) StoreEmbeddings(ctx context.Context, embeddings []*domain.Embeddings, extraMetadata map[string]interface{}) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.documents++
+
+	for i, embedding := range embeddings {
+		metadata := map[string]interface{}{
+			"text": embedding.Text,
+		}
+		for key, value := range extraMetadata {
+			metadata[key] = value
+		}
+
+		db.vectors = append(db.vectors, &memoryVector{
+			id:       fmt.Sprintf("doc%d-chunk-%d", db.documents, i),
+			values:   helpers.Float64ToFloat32(embedding.Embeddings),
+			metadata: metadata,
+		})
+	}
+
+	return len(embeddings), nil
+}
+
+// UpsertChunk stores a single embedding under the given ID, replacing any
+// vector with the same ID.
+func (db *MemoryVectorDB) UpsertChunk(ctx context.Context, id string, embedding *domain.Embeddings, metadata map[string]interface{}) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	vector := &memoryVector{
+		id:     id,
+		values: helpers.Float64ToFloat32(embedding.Embeddings),
+		metadata: map[string]interface{}{
+			"text": embedding.Text,
+		},
+	}
+	for key, value := range metadata {
+		vector.metadata[key] = value
+	}
+
+	for i, stored := range db.vectors {
+		if stored.id == id {
+			db.vectors[i] = vector
+
+			return nil
+		}
+	}
+
+	db.vectors = append(db.vectors, vector)
+
+	return nil
+}
+
+func (db *MemoryVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]*domain.RetrievedChunk, error) {
+	return db.Search(ctx, embeddings, domain.SearchOptions{})
+}
+
+// Search is SemanticSearch narrowed by the options
+func (db *MemoryVectorDB) Search(ctx context.Context, embeddings []float32, options domain.SearchOptions) ([]*domain.RetrievedChunk, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	topK := db.topKResultsNumber
+	if options.TopK > 0 {
+		topK = options.TopK
+	}
+
+	matches := make([]*domain.RetrievedChunk, 0, len(db.vectors))
+	for _, vector := range db.vectors {
+		match := &domain.RetrievedChunk{
+			ID:       vector.id,
+			Text:     vector.metadata["text"].(string),
+			Score:    cosineSimilarity(embeddings, vector.values),
+			Metadata: vector.metadata,
+		}
+
+		if options.Type != "" && match.Type() != options.Type {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+
+	var retrievedChunks []*domain.RetrievedChunk
+	for _, match := range matches {
+		if match.Score >= db.threshold {
+			retrievedChunks = append(retrievedChunks, match)
+		}
+	}
+
+	return retrievedChunks, nil
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
@@ -73,33 +73,118 @@ func (db *PineconeVectorDB) StoreEmbeddings(ctx context.Context, embeddings []*d
 	return int(count), nil
 }
 
-func (db *PineconeVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]string, error) {
+// UpsertChunk stores a single embedding under the given ID, replacing any
+// vector with the same ID.
+func (db *PineconeVectorDB) UpsertChunk(ctx context.Context, id string, embedding *domain.Embeddings, metadata map[string]interface{}) error {
 	idx, err := db.client.DescribeIndex(ctx, db.index)
 	if err != nil {
-		return []string{}, err
+		return err
 	}
 
 	idxConnection, err := db.client.Index(pinecone.NewIndexConnParams{Host: idx.Host})
 	if err != nil {
-		return []string{}, err
+		return err
+	}
+
+	md, err := structpb.NewStruct(map[string]interface{}{
+		"text": embedding.Text,
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, value := range metadata {
+		md.Fields[key], err = structpb.NewValue(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	vectorToFloat32 := helpers.Float64ToFloat32(embedding.Embeddings)
+
+	_, err = idxConnection.UpsertVectors(ctx, []*pinecone.Vector{
+		{
+			Id:       id,
+			Values:   &vectorToFloat32,
+			Metadata: md,
+		},
+	})
+
+	return err
+}
+
+func (db *PineconeVectorDB) SemanticSearch(ctx context.Context, embeddings []float32) ([]*domain.RetrievedChunk, error) {
+	return db.Search(ctx, embeddings, domain.SearchOptions{})
+}
+
+// Search is SemanticSearch narrowed by the options, the type filter being
+// applied by Pinecone on the metadata of the vectors
+func (db *PineconeVectorDB) Search(ctx context.Context, embeddings []float32, options domain.SearchOptions) ([]*domain.RetrievedChunk, error) {
+	idx, err := db.client.DescribeIndex(ctx, db.index)
+	if err != nil {
+		return []*domain.RetrievedChunk{}, err
+	}
+
+	idxConnection, err := db.client.Index(pinecone.NewIndexConnParams{Host: idx.Host})
+	if err != nil {
+		return []*domain.RetrievedChunk{}, err
+	}
+
+	topK := db.topKResultsNumber
+	if options.TopK > 0 {
+		topK = options.TopK
+	}
+
+	metadataFilter, err := metadataFilterOfType(options.Type)
+	if err != nil {
+		return []*domain.RetrievedChunk{}, err
 	}
 
 	res, err := idxConnection.QueryByVectorValues(ctx, &pinecone.QueryByVectorValuesRequest{
 		Vector:          embeddings,
-		TopK:            uint32(db.topKResultsNumber),
+		TopK:            uint32(topK),
+		MetadataFilter:  metadataFilter,
 		IncludeValues:   false,
 		IncludeMetadata: true,
 	})
+	if err != nil {
+		return []*domain.RetrievedChunk{}, err
+	}
 
-	var contextTexts []string
+	var retrievedChunks []*domain.RetrievedChunk
 	for _, match := range res.Matches {
-		text := match.Vector.Metadata.String()
-		score := match.Score
+		if match.Score < db.threshold {
+			continue
+		}
 
-		if score >= db.threshold {
-			contextTexts = append(contextTexts, text)
+		retrievedChunk := &domain.RetrievedChunk{
+			ID:    match.Vector.Id,
+			Score: match.Score,
 		}
+
+		if match.Vector.Metadata != nil {
+			retrievedChunk.Metadata = match.Vector.Metadata.AsMap()
+			retrievedChunk.Text = match.Vector.Metadata.Fields["text"].GetStringValue()
+		}
+
+		retrievedChunks = append(retrievedChunks, retrievedChunk)
 	}
 
-	return contextTexts, nil
+	return retrievedChunks, nil
+}
+
+// metadataFilterOfType returns no filter for an empty type
+func metadataFilterOfType(chunkType string) (*pinecone.MetadataFilter, error) {
+	switch chunkType {
+	case "":
+		return nil, nil
+	case domain.ChunkTypeCurated:
+		return structpb.NewStruct(map[string]interface{}{
+			domain.ChunkMetadataCurated: map[string]interface{}{"$eq": true},
+		})
+	default:
+		return structpb.NewStruct(map[string]interface{}{
+			domain.ChunkMetadataType: map[string]interface{}{"$eq": chunkType},
+		})
+	}
 }
@@ -0,0 +1,70 @@
+package vehicles
+
+import (
+	"fmt"
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// comparisonPattern matches the text of the rows of the vehicles document,
+// like "Sailboats are 88% slow than Electric cars."
+var comparisonPattern = regexp.MustCompile(`^(.+?) (?:are|is) (\d+(?:\.\d+)?)% (.+?) than (.+?)\.?$`)
+
+// ParseComparisons parses the rows of the markdown table of the vehicles
+// document. Lines that are not rows of comparisons, like the header, are
+// skipped, and the numbers of the rows that look like comparisons but cannot
+// be parsed are returned.
+func ParseComparisons(text string) ([]*domain.VehicleComparison, []int) {
+	var comparisons []*domain.VehicleComparison
+	var unparsed []int
+
+	for i, line := range strings.Split(text, "\n") {
+		row := strings.TrimSpace(line)
+		if !strings.HasPrefix(row, "|") || !strings.Contains(row, " than ") {
+			continue
+		}
+
+		comparison, err := ParseComparison(strings.Trim(row, "| "))
+		if err != nil {
+			unparsed = append(unparsed, i+1)
+			continue
+		}
+
+		comparison.Line = i + 1
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons, unparsed
+}
+
+// ParseComparison parses a single comparison statement
+func ParseComparison(statement string) (*domain.VehicleComparison, error) {
+	matches := comparisonPattern.FindStringSubmatch(strings.TrimSpace(statement))
+	if matches == nil {
+		return nil, fmt.Errorf("%q is not a comparison", statement)
+	}
+
+	percent, err := strconv.ParseFloat(matches[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed percent of %q: %w", statement, err)
+	}
+
+	return &domain.VehicleComparison{
+		Subject:   matches[1],
+		Percent:   percent,
+		Attribute: matches[3],
+		Object:    matches[4],
+	}, nil
+}
+
+// Statement writes the comparison back like the row of the document
+func Statement(comparison *domain.VehicleComparison) string {
+	return fmt.Sprintf("%s are %s%% %s than %s.",
+		comparison.Subject,
+		strconv.FormatFloat(comparison.Percent, 'f', -1, 64),
+		comparison.Attribute,
+		comparison.Object,
+	)
+}
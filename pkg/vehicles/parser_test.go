@@ -0,0 +1,46 @@
+package vehicles
+
+import (
+	"github.com/loukaspe/rag-golang/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestParseComparisons(t *testing.T) {
+	text := `| text                                                                        |
+|:----------------------------------------------------------------------------|
+| Sailboats are 88% slow than Electric cars.                                  |
+| Electric cars are 152% quick to accelerate than Hybrid vehicles.            |
+| Cargo ships are 12.5% environmentally friendly than Electric cars.          |
+| Sailboats are very slow than Electric cars.                                 |
+`
+
+	comparisons, unparsed := ParseComparisons(text)
+
+	assert.Equal(t, []*domain.VehicleComparison{
+		{Line: 3, Subject: "Sailboats", Percent: 88, Attribute: "slow", Object: "Electric cars"},
+		{Line: 4, Subject: "Electric cars", Percent: 152, Attribute: "quick to accelerate", Object: "Hybrid vehicles"},
+		{Line: 5, Subject: "Cargo ships", Percent: 12.5, Attribute: "environmentally friendly", Object: "Electric cars"},
+	}, comparisons)
+	assert.Equal(t, []int{6}, unparsed)
+}
+
+func TestParseComparisons_VehiclesDocument(t *testing.T) {
+	text, err := os.ReadFile("../../dataVehicles.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comparisons, unparsed := ParseComparisons(string(text))
+
+	assert.Len(t, comparisons, 1000)
+	assert.Empty(t, unparsed)
+}
+
+func TestStatement(t *testing.T) {
+	comparison, err := ParseComparison("Sailboats are 88% slow than Electric cars.")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Sailboats are 88% slow than Electric cars.", Statement(comparison))
+}
@@ -2,17 +2,28 @@
 
 # Define the backend API URL
 BASE_URL="http://localhost:8080"
-TOKEN_ENDPOINT="/token"
-USER_ID="12345678-0000-0000-0000-000000000000"
+USERNAME="user-$(date +%s)"
+PASSWORD="password"
 
-# Step 1: Get the token
-response_token=$(curl -s --location "$BASE_URL$TOKEN_ENDPOINT" \
+# Step 1: Register a user and log in to get the token
+response_register=$(curl -s --location "$BASE_URL/register" \
   --header 'Content-Type: application/json' \
-  --data '{"username": "user", "password": "password"}')
+  --data "{\"username\": \"$USERNAME\", \"password\": \"$PASSWORD\"}")
+
+USER_ID=$(echo "$response_register" | jq -r '.id')
+
+if [ -z "$USER_ID" ] || [ "$USER_ID" = "null" ]; then
+  echo "Error: Failed to register user: $response_register"
+  exit 1
+fi
+
+response_token=$(curl -s --location "$BASE_URL/login" \
+  --header 'Content-Type: application/json' \
+  --data "{\"username\": \"$USERNAME\", \"password\": \"$PASSWORD\"}")
 
 token=$(echo "$response_token" | jq -r '.token')
 
-if [ -z "$token" ]; then
+if [ -z "$token" ] || [ "$token" = "null" ]; then
   echo "Error: Failed to retrieve token."
   exit 1
 fi
@@ -73,7 +84,7 @@ echo "----------------------------------"
 feedback_message="The force is not strong with that message."
 feedback_response=$(curl -s --location --write-out "%{http_code}" --request POST "$BASE_URL/users/$USER_ID/chat-sessions/$CHAT_SESSION_ID/messages/$system_message_id_3/feedback" \
   --header "Authorization: Bearer $token" \
-  --data "{\"feedback\":\"$feedback_message\"}")
+  --data "{\"thumb\":\"down\",\"reason\":\"off-topic\",\"feedback\":\"$feedback_message\"}")
 
 if [ "$feedback_response" -eq 201 ]; then
   echo "Negative feedback OK: received 201 status code"
@@ -98,41 +109,31 @@ echo "$response_message_4"
 
 echo "----------------------------------"
 
-# Step 9: call the MCP server to add number 1 and 2
-# Step 9a: Get sessionId from the SSE stream
-#echo "Fetching sessionId from /mcp/sse..."
-#
-#SSE_ENDPOINT="/mcp/sse"
-#
-#echo "Connecting to $BASE_URL$SSE_ENDPOINT to fetch sessionId..."
-#raw_output=$(curl -s --no-buffer --max-time 2 "$BASE_URL$SSE_ENDPOINT")
-#echo "Raw output from SSE stream:"
-#echo "$raw_output"
-#
-#session_id=$(echo "$raw_output" | awk -F'sessionId=' '/data:/ {print $2}' | tr -d '\r')
-#if [ -z "$session_id" ]; then
-#  echo "Error: Failed to retrieve sessionId from SSE stream."
-#  exit 1
-#fi
-#
-#echo "Session ID retrieved successfully: $session_id"
-#
-#echo "----------------------------------"
-#
-## Step 9b: Call the MCP tool 'add' with sessionId
-#echo "Calling MCP 'add' tool with sessionId: $session_id..."
-#
-#mcp_response=$(curl -s --location "$BASE_URL/mcp/message?sessionId=$session_id" \
-#  --header 'Content-Type: application/json' \
-#  --data "{
-#               \"jsonrpc\": \"2.0\",
-#               \"id\": \"1\",
-#               \"method\": \"tools/call\",
-#               \"params\": {
-#                   \"name\": \"add\",
-#                 \"arguments\": { \"a\": 1, \"b\":2}
-#               }
-#             }")
-#
-#echo "MCP response:"
-#echo "$mcp_response" | jq -r '.result.text'
+# Step 9: search the knowledge base through the MCP server, over streamable HTTP
+# Step 9a: initialize an MCP session
+mcp_session_id=$(curl -s --dump-header - --output /dev/null --location "$BASE_URL/mcp" \
+  --header "Content-Type: application/json" \
+  --header "Accept: application/json, text/event-stream" \
+  --header "Authorization: Bearer $token" \
+  --data '{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"e2e","version":"1.0"}}}' \
+  | awk -F': ' 'tolower($1) == "mcp-session-id" {print $2}' | tr -d '\r')
+
+if [ -z "$mcp_session_id" ]; then
+  echo "Error: Failed to initialize an MCP session."
+  exit 1
+fi
+
+echo "MCP session initialized: $mcp_session_id"
+
+echo "----------------------------------"
+
+# Step 9b: call the search_knowledge_base tool
+mcp_response=$(curl -s --location "$BASE_URL/mcp" \
+  --header "Content-Type: application/json" \
+  --header "Accept: application/json, text/event-stream" \
+  --header "Authorization: Bearer $token" \
+  --header "Mcp-Session-Id: $mcp_session_id" \
+  --data '{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_knowledge_base","arguments":{"query":"latino mobile gamers","top_k":3}}}')
+
+echo "MCP search_knowledge_base response:"
+echo "$mcp_response" | jq -r '.result.content[0].text'